@@ -111,7 +111,7 @@ func (bc *Blockchain) BlockByHash(hash types.Hash) (*types.Block, error) {
 		Transactions: make([]*types.Transaction, 0, len(indices)/32),
 	}
 	var index types.Hash
-	for i := 0; i < len(indices)+32; i += 32 {
+	for i := 0; i+32 <= len(indices); i += 32 {
 		copy(index[:], indices[i:i+32])
 		tx, err := bc.TransactionByHash(index)
 		if err != nil {
@@ -0,0 +1,100 @@
+// Code generated by capnpc-go. DO NOT EDIT.
+
+package codec
+
+import (
+	capnp "zombiezen.com/go/capnproto2"
+	text "zombiezen.com/go/capnproto2/encoding/text"
+	schemas "zombiezen.com/go/capnproto2/schemas"
+)
+
+type Announce struct{ capnp.Struct }
+
+// Announce_TypeID is the unique identifier for the type Announce.
+const Announce_TypeID = 0xc54bf4809f7b1e99
+
+func NewAnnounce(s *capnp.Segment) (Announce, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1})
+	return Announce{st}, err
+}
+
+func NewRootAnnounce(s *capnp.Segment) (Announce, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1})
+	return Announce{st}, err
+}
+
+func ReadRootAnnounce(msg *capnp.Message) (Announce, error) {
+	root, err := msg.RootPtr()
+	return Announce{root.Struct()}, err
+}
+
+func (s Announce) String() string {
+	str, _ := text.Marshal(0xc54bf4809f7b1e99, s.Struct)
+	return str
+}
+
+func (s Announce) Hashes() (capnp.DataList, error) {
+	p, err := s.Struct.Ptr(0)
+	return capnp.DataList{List: p.List()}, err
+}
+
+func (s Announce) HasHashes() bool {
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s Announce) SetHashes(v capnp.DataList) error {
+	return s.Struct.SetPtr(0, v.List.ToPtr())
+}
+
+// NewHashes sets the hashes field to a newly
+// allocated capnp.DataList, preferring placement in s's segment.
+func (s Announce) NewHashes(n int32) (capnp.DataList, error) {
+	l, err := capnp.NewDataList(s.Struct.Segment(), n)
+	if err != nil {
+		return capnp.DataList{}, err
+	}
+	err = s.Struct.SetPtr(0, l.List.ToPtr())
+	return l, err
+}
+
+// Announce_List is a list of Announce.
+type Announce_List struct{ capnp.List }
+
+// NewAnnounce creates a new list of Announce.
+func NewAnnounce_List(s *capnp.Segment, sz int32) (Announce_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1}, sz)
+	return Announce_List{l}, err
+}
+
+func (s Announce_List) At(i int) Announce { return Announce{s.List.Struct(i)} }
+
+func (s Announce_List) Set(i int, v Announce) error { return s.List.SetStruct(i, v.Struct) }
+
+func (s Announce_List) String() string {
+	str, _ := text.MarshalList(0xc54bf4809f7b1e99, s.List)
+	return str
+}
+
+// Announce_Promise is a wrapper for a Announce promised by a client call.
+type Announce_Promise struct{ *capnp.Pipeline }
+
+func (p Announce_Promise) Struct() (Announce, error) {
+	s, err := p.Pipeline.Struct()
+	return Announce{s}, err
+}
+
+const schema_e02fb5fcea8c54e9 = "x\xda\x12\xd0r`\x12d\x8dg`\x08dae\xfb" +
+	"?S\xaez~\xc3\x17\xef\xa3\x0c\x82\xfc\x8c\xff_\x86" +
+	"\xf4\xbc\xfa\xb3U\xff\x01\x03+#;\x03\x83\xa0\xe8!" +
+	"AYv0\xb2g`\x10\xccd\xff\x9f\x98\x97\x97_" +
+	"\x9a\x97\x9c\xca\xa4\x97\x9cX\x90W`\xe5\x08\xe53\x04" +
+	"02\x0602\x05\xb20\xb300\xb0020\x08" +
+	"\xf2Z\x09\xf2\xb2\x07\xf203\x06j01\xdag$" +
+	"\x16g\xa4\x16\x07021\xf210\x06032\xf2" +
+	"2\x80\x99\x0e\x8c\x80\x01\x00~@\x1f\xc2"
+
+func init() {
+	schemas.Register(schema_e02fb5fcea8c54e9,
+		0xc54bf4809f7b1e99)
+}
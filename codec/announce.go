@@ -0,0 +1,80 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package codec
+
+import (
+	"github.com/pkg/errors"
+	capnp "zombiezen.com/go/capnproto2"
+
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+type initAnnounce func() (Announce, error)
+
+func createRootAnnounce(z Z) initAnnounce {
+	return z.NewAnnounce
+}
+
+func readRootAnnounce(z Z) initAnnounce {
+	return z.Announce
+}
+
+func encodeAnnounce(seg *capnp.Segment, create initAnnounce, e *message.Announce) (Announce, error) {
+	announce, err := create()
+	if err != nil {
+		return Announce{}, errors.Wrap(err, "could not create announce")
+	}
+	hashes, err := announce.NewHashes(int32(len(e.Hashes)))
+	if err != nil {
+		return Announce{}, errors.Wrap(err, "could not create hash list")
+	}
+	for i, hash := range e.Hashes {
+		err = hashes.Set(i, hash[:])
+		if err != nil {
+			return Announce{}, errors.Wrap(err, "could not set hash")
+		}
+	}
+	return announce, nil
+}
+
+func decodeAnnounce(read initAnnounce, cfg *Config) (*message.Announce, error) {
+	announce, err := read()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read announce")
+	}
+	hashes, err := announce.Hashes()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read hash list")
+	}
+	err = check(ErrListLength, "hashes", cfg.maxHashes, uint64(hashes.Len()))
+	if err != nil {
+		return nil, err
+	}
+	e := &message.Announce{
+		Hashes: make([]types.Hash, hashes.Len()),
+	}
+	for i := 0; i < hashes.Len(); i++ {
+		hash, err := hashes.At(i)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get hash")
+		}
+		copy(e.Hashes[i][:], hash)
+	}
+	return e, nil
+}
@@ -21,7 +21,7 @@ import (
 	"github.com/pkg/errors"
 	capnp "zombiezen.com/go/capnproto2"
 
-	"github.com/alvalor/alvalor-go/node"
+	"github.com/alvalor/alvalor-go/node/handlers/message"
 	"github.com/alvalor/alvalor-go/types"
 )
 
@@ -35,7 +35,7 @@ func readRootBatch(z Z) initBatch {
 	return z.Batch
 }
 
-func encodeBatch(seg *capnp.Segment, create initBatch, e *node.Batch) (Batch, error) {
+func encodeBatch(seg *capnp.Segment, create initBatch, e *message.Batch) (Batch, error) {
 	batch, err := create()
 	if err != nil {
 		return Batch{}, errors.Wrap(err, "could not create batch")
@@ -58,7 +58,7 @@ func encodeBatch(seg *capnp.Segment, create initBatch, e *node.Batch) (Batch, er
 	return batch, nil
 }
 
-func decodeBatch(read initBatch) (*node.Batch, error) {
+func decodeBatch(read initBatch, cfg *Config) (*message.Batch, error) {
 	batch, err := read()
 	if err != nil {
 		return nil, errors.Wrap(err, "could not read batch")
@@ -67,12 +67,16 @@ func decodeBatch(read initBatch) (*node.Batch, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "could not read transaction list")
 	}
-	e := &node.Batch{
+	err = check(ErrListLength, "transactions", cfg.maxTxs, uint64(transactions.Len()))
+	if err != nil {
+		return nil, err
+	}
+	e := &message.Batch{
 		Transactions: make([]*types.Transaction, 0, transactions.Len()),
 	}
 	for i := 0; i < transactions.Len(); i++ {
 		transaction := transactions.At(i)
-		t, err := decodeTransaction(readChildTransaction(transaction))
+		t, err := decodeTransaction(readChildTransaction(transaction), cfg)
 		if err != nil {
 			return nil, errors.Wrap(err, "could not decode transation")
 		}
@@ -23,13 +23,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
-	"github.com/alvalor/alvalor-go/node"
+	"github.com/alvalor/alvalor-go/node/handlers/message"
 	"github.com/alvalor/alvalor-go/types"
 )
 
 func TestBatch(t *testing.T) {
 	proto := &Proto{}
-	batch := &node.Batch{
+	batch := &message.Batch{
 		Transactions: []*types.Transaction{
 			{
 				Transfers:  []*types.Transfer{{From: []byte{10}, To: []byte{11}, Amount: 1000}},
@@ -0,0 +1,119 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package codec
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/alvalor/alvalor-go/network"
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+func benchTransaction() *types.Transaction {
+	return &types.Transaction{
+		Transfers:  []*types.Transfer{{From: make([]byte, 32), To: make([]byte, 32), Amount: 1000}},
+		Fees:       []*types.Fee{{From: make([]byte, 32), Amount: 1300}},
+		Data:       make([]byte, 256),
+		Nonce:      17,
+		Signatures: [][]byte{make([]byte, 64)},
+	}
+}
+
+func benchInventory() *types.Inventory {
+	inv := &types.Inventory{Hash: types.Hash{1}}
+	for i := 0; i < 1024; i++ {
+		inv.Hashes = append(inv.Hashes, types.Hash{byte(i), byte(i >> 8)})
+	}
+	return inv
+}
+
+func benchBatch() *message.Batch {
+	batch := &message.Batch{}
+	for i := 0; i < 64; i++ {
+		batch.Transactions = append(batch.Transactions, benchTransaction())
+	}
+	return batch
+}
+
+func benchEncode(b *testing.B, entity interface{}) {
+	proto := NewProto()
+	buf := &bytes.Buffer{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		err := proto.Encode(buf, entity)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchDecode(b *testing.B, entity interface{}) {
+	proto := NewProto()
+	buf := &bytes.Buffer{}
+	err := proto.Encode(buf, entity)
+	if err != nil {
+		b.Fatal(err)
+	}
+	data := buf.Bytes()
+	r := bytes.NewReader(data)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Reset(data)
+		_, err = proto.Decode(r)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodePing(b *testing.B) {
+	benchEncode(b, &network.Ping{Nonce: 1})
+}
+
+func BenchmarkDecodePing(b *testing.B) {
+	benchDecode(b, &network.Ping{Nonce: 1})
+}
+
+func BenchmarkEncodeTransaction(b *testing.B) {
+	benchEncode(b, benchTransaction())
+}
+
+func BenchmarkDecodeTransaction(b *testing.B) {
+	benchDecode(b, benchTransaction())
+}
+
+func BenchmarkEncodeInventory(b *testing.B) {
+	benchEncode(b, benchInventory())
+}
+
+func BenchmarkDecodeInventory(b *testing.B) {
+	benchDecode(b, benchInventory())
+}
+
+func BenchmarkEncodeBatch(b *testing.B) {
+	benchEncode(b, benchBatch())
+}
+
+func BenchmarkDecodeBatch(b *testing.B) {
+	benchDecode(b, benchBatch())
+}
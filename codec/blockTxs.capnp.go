@@ -0,0 +1,118 @@
+// Code generated by capnpc-go. DO NOT EDIT.
+
+package codec
+
+import (
+	capnp "zombiezen.com/go/capnproto2"
+	text "zombiezen.com/go/capnproto2/encoding/text"
+	schemas "zombiezen.com/go/capnproto2/schemas"
+)
+
+type BlockTxs struct{ capnp.Struct }
+
+// BlockTxs_TypeID is the unique identifier for the type BlockTxs.
+const BlockTxs_TypeID = 0xec36c3e1d88654d2
+
+func NewBlockTxs(s *capnp.Segment) (BlockTxs, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2})
+	return BlockTxs{st}, err
+}
+
+func NewRootBlockTxs(s *capnp.Segment) (BlockTxs, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2})
+	return BlockTxs{st}, err
+}
+
+func ReadRootBlockTxs(msg *capnp.Message) (BlockTxs, error) {
+	root, err := msg.RootPtr()
+	return BlockTxs{root.Struct()}, err
+}
+
+func (s BlockTxs) String() string {
+	str, _ := text.Marshal(0xec36c3e1d88654d2, s.Struct)
+	return str
+}
+
+func (s BlockTxs) Hash() ([]byte, error) {
+	p, err := s.Struct.Ptr(0)
+	return []byte(p.Data()), err
+}
+
+func (s BlockTxs) HasHash() bool {
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s BlockTxs) SetHash(v []byte) error {
+	return s.Struct.SetData(0, v)
+}
+
+func (s BlockTxs) Transactions() (Transaction_List, error) {
+	p, err := s.Struct.Ptr(1)
+	return Transaction_List{List: p.List()}, err
+}
+
+func (s BlockTxs) HasTransactions() bool {
+	p, err := s.Struct.Ptr(1)
+	return p.IsValid() || err != nil
+}
+
+func (s BlockTxs) SetTransactions(v Transaction_List) error {
+	return s.Struct.SetPtr(1, v.List.ToPtr())
+}
+
+// NewTransactions sets the transactions field to a newly
+// allocated Transaction_List, preferring placement in s's segment.
+func (s BlockTxs) NewTransactions(n int32) (Transaction_List, error) {
+	l, err := NewTransaction_List(s.Struct.Segment(), n)
+	if err != nil {
+		return Transaction_List{}, err
+	}
+	err = s.Struct.SetPtr(1, l.List.ToPtr())
+	return l, err
+}
+
+// BlockTxs_List is a list of BlockTxs.
+type BlockTxs_List struct{ capnp.List }
+
+// NewBlockTxs creates a new list of BlockTxs.
+func NewBlockTxs_List(s *capnp.Segment, sz int32) (BlockTxs_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2}, sz)
+	return BlockTxs_List{l}, err
+}
+
+func (s BlockTxs_List) At(i int) BlockTxs { return BlockTxs{s.List.Struct(i)} }
+
+func (s BlockTxs_List) Set(i int, v BlockTxs) error { return s.List.SetStruct(i, v.Struct) }
+
+func (s BlockTxs_List) String() string {
+	str, _ := text.MarshalList(0xec36c3e1d88654d2, s.List)
+	return str
+}
+
+// BlockTxs_Promise is a wrapper for a BlockTxs promised by a client call.
+type BlockTxs_Promise struct{ *capnp.Pipeline }
+
+func (p BlockTxs_Promise) Struct() (BlockTxs, error) {
+	s, err := p.Pipeline.Struct()
+	return BlockTxs{s}, err
+}
+
+const schema_88e9416cc9d62de3 = "x\xda,\xc91J\x03A\x18\x05\xe0\xf7\xfe\xd9\xcd\xd8" +
+	"\xe8\xe6\x07-\xed]AA\x88\x16\x82\xb0z\x82\x19H" +
+	"a\x17\xc6m\xa2\x86Mp\x02z\x02\xf1\x16\x9e\xc2\x0b" +
+	"he\xa9\x85h)V\xc2\xdead$\xf0^\xf1\xde" +
+	"7<iD\xcb\x09\xe0\x8br\x90\xde\xc7\xf7\x9f\xdf/" +
+	"G=\xb4b\xfa\xd9\xfbx\x9d\x9d\xfe>\xa0\x14\x0b\xe8" +
+	"\xd6\xb3n\xdb\xff\xdc\x02\xfad\xd3\xc5l\xde^\x8f\xef" +
+	"\xa2\xec\xb7a\xd1-\x8e\xcfV\x1b\x8et\x14\xbff\x0a" +
+	"\xa0 \xa0\xf5\xae\xd6\xd6\xef\x18\xfa\x91P\xc9M\xe6\xf7" +
+	"\xe0J\x0f\xad\x1f\x19\xfasa5\x0dq\xea(\\G" +
+	".\x94\x92\x967\xa1\x8b\xa1]\xa2\xba\x9cw1\xe3\x06" +
+	"\xe8\x0c9L\x13S\xbf\x0d\xbe\x1e{\x00\x0d\x95\xd6\x09" +
+	"36\xfc\x1b\x007W.Q"
+
+func init() {
+	schemas.Register(schema_88e9416cc9d62de3,
+		0xec36c3e1d88654d2)
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package codec
+
+import (
+	"github.com/pkg/errors"
+	capnp "zombiezen.com/go/capnproto2"
+
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+type initBlockTxs func() (BlockTxs, error)
+
+func createRootBlockTxs(z Z) initBlockTxs {
+	return z.NewBlockTxs
+}
+
+func readRootBlockTxs(z Z) initBlockTxs {
+	return z.BlockTxs
+}
+
+func encodeBlockTxs(seg *capnp.Segment, create initBlockTxs, e *message.BlockTxs) (BlockTxs, error) {
+	blockTxs, err := create()
+	if err != nil {
+		return BlockTxs{}, errors.Wrap(err, "could not create block transactions")
+	}
+	err = blockTxs.SetHash(e.Hash[:])
+	if err != nil {
+		return BlockTxs{}, errors.Wrap(err, "could not set hash")
+	}
+	transactions, err := blockTxs.NewTransactions(int32(len(e.Transactions)))
+	if err != nil {
+		return BlockTxs{}, errors.Wrap(err, "could not create transaction list")
+	}
+	for i, t := range e.Transactions {
+		var transaction Transaction
+		transaction, err = encodeTransaction(seg, createChildTransaction(seg), t)
+		if err != nil {
+			return BlockTxs{}, errors.Wrap(err, "could not encode transaction")
+		}
+		err = transactions.Set(i, transaction)
+		if err != nil {
+			return BlockTxs{}, errors.Wrap(err, "could not set transaction")
+		}
+	}
+	return blockTxs, nil
+}
+
+func decodeBlockTxs(read initBlockTxs, cfg *Config) (*message.BlockTxs, error) {
+	blockTxs, err := read()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read block transactions")
+	}
+	hash, err := blockTxs.Hash()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read hash")
+	}
+	transactions, err := blockTxs.Transactions()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read transaction list")
+	}
+	err = check(ErrListLength, "transactions", cfg.maxHashes, uint64(transactions.Len()))
+	if err != nil {
+		return nil, err
+	}
+	e := &message.BlockTxs{
+		Transactions: make([]*types.Transaction, 0, transactions.Len()),
+	}
+	copy(e.Hash[:], hash)
+	for i := 0; i < transactions.Len(); i++ {
+		transaction := transactions.At(i)
+		t, err := decodeTransaction(readChildTransaction(transaction), cfg)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not decode transaction")
+		}
+		e.Transactions = append(e.Transactions, t)
+	}
+	return e, nil
+}
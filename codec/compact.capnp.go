@@ -0,0 +1,143 @@
+// Code generated by capnpc-go. DO NOT EDIT.
+
+package codec
+
+import (
+	capnp "zombiezen.com/go/capnproto2"
+	text "zombiezen.com/go/capnproto2/encoding/text"
+	schemas "zombiezen.com/go/capnproto2/schemas"
+)
+
+type Compact struct{ capnp.Struct }
+
+// Compact_TypeID is the unique identifier for the type Compact.
+const Compact_TypeID = 0xc1e9d07abcd9eef2
+
+func NewCompact(s *capnp.Segment) (Compact, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2})
+	return Compact{st}, err
+}
+
+func NewRootCompact(s *capnp.Segment) (Compact, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2})
+	return Compact{st}, err
+}
+
+func ReadRootCompact(msg *capnp.Message) (Compact, error) {
+	root, err := msg.RootPtr()
+	return Compact{root.Struct()}, err
+}
+
+func (s Compact) String() string {
+	str, _ := text.Marshal(0xc1e9d07abcd9eef2, s.Struct)
+	return str
+}
+
+func (s Compact) Header() (Header, error) {
+	p, err := s.Struct.Ptr(0)
+	return Header{Struct: p.Struct()}, err
+}
+
+func (s Compact) HasHeader() bool {
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s Compact) SetHeader(v Header) error {
+	return s.Struct.SetPtr(0, v.Struct.ToPtr())
+}
+
+// NewHeader sets the header field to a newly
+// allocated Header struct, preferring placement in s's segment.
+func (s Compact) NewHeader() (Header, error) {
+	ss, err := NewHeader(s.Struct.Segment())
+	if err != nil {
+		return Header{}, err
+	}
+	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
+	return ss, err
+}
+
+func (s Compact) Nonce() uint64 {
+	return s.Struct.Uint64(0)
+}
+
+func (s Compact) SetNonce(v uint64) {
+	s.Struct.SetUint64(0, v)
+}
+
+func (s Compact) ShortIds() (capnp.UInt64List, error) {
+	p, err := s.Struct.Ptr(1)
+	return capnp.UInt64List{List: p.List()}, err
+}
+
+func (s Compact) HasShortIds() bool {
+	p, err := s.Struct.Ptr(1)
+	return p.IsValid() || err != nil
+}
+
+func (s Compact) SetShortIds(v capnp.UInt64List) error {
+	return s.Struct.SetPtr(1, v.List.ToPtr())
+}
+
+// NewShortIds sets the shortIds field to a newly
+// allocated capnp.UInt64List, preferring placement in s's segment.
+func (s Compact) NewShortIds(n int32) (capnp.UInt64List, error) {
+	l, err := capnp.NewUInt64List(s.Struct.Segment(), n)
+	if err != nil {
+		return capnp.UInt64List{}, err
+	}
+	err = s.Struct.SetPtr(1, l.List.ToPtr())
+	return l, err
+}
+
+// Compact_List is a list of Compact.
+type Compact_List struct{ capnp.List }
+
+// NewCompact creates a new list of Compact.
+func NewCompact_List(s *capnp.Segment, sz int32) (Compact_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2}, sz)
+	return Compact_List{l}, err
+}
+
+func (s Compact_List) At(i int) Compact { return Compact{s.List.Struct(i)} }
+
+func (s Compact_List) Set(i int, v Compact) error { return s.List.SetStruct(i, v.Struct) }
+
+func (s Compact_List) String() string {
+	str, _ := text.MarshalList(0xc1e9d07abcd9eef2, s.List)
+	return str
+}
+
+// Compact_Promise is a wrapper for a Compact promised by a client call.
+type Compact_Promise struct{ *capnp.Pipeline }
+
+func (p Compact_Promise) Struct() (Compact, error) {
+	s, err := p.Pipeline.Struct()
+	return Compact{s}, err
+}
+
+func (p Compact_Promise) Header() Header_Promise {
+	return Header_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
+}
+
+const schema_83448756cad58632 = "x\xda\x1c\x8a\xb1J\xc3P\x18\x85\xcf\xf9\x93x\x11$" +
+	"\xe4\x87:J\xdf\xc0\xa1c\x17\x03\xb6\x83\x05\xe1^h" +
+	"\xc5E\xec%\x0dt1\x09m'q\x13\xf4q\x04'" +
+	"\x07\x97N\x82\x93\x8b\x83\x8f\xe0\xa2 \xe8z%\x81s" +
+	"\x86\xf3\x9d/;\xcdE\x93K\xc0\xc5\xc9N\xf8\xf9\xfa" +
+	"x\xbe~\xfb\xdc\xc2\xa5d\x18\xdc\xbd\xbf\x9e\xdd\x8fn" +
+	"\x91\x88\x01t\xffQ\x0fL\x97\x07@\x7fM(\xea\xab" +
+	"\xc6\x17\x9bC\x16\xbe\xa9\x9a\xe1q\xdd\xef\xb6%-\xc5" +
+	"\xedE1\x10\x13\xd0\xf1P\xc7\xc6\x8d\"\xbas!\xd9" +
+	"c\x0bg\x03\x9d\x197\x8d\xe8\xe6B\x15\xf6(\x80^" +
+	"L\xd4\x1b7\x8f\xe8n\x84G\xcb\xd2/\xca\x95\xa50" +
+	"\x0b\xdb\xe9\xf7\xd3\xc4\xff\xbd\x00\xc8\xa94V\xc8\x0c\xec" +
+	"WuU\x94\xad\xb2\x8b\xb6\x0c\xebe\xbd\xda\x9c,\xd6" +
+	"\x00Z\x9c\x826b\xf7\xa6`\xce\xff\x01\x00\x9e\x845" +
+	"A"
+
+func init() {
+	schemas.Register(schema_83448756cad58632,
+		0xc1e9d07abcd9eef2)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package codec
+
+import (
+	"github.com/pkg/errors"
+	capnp "zombiezen.com/go/capnproto2"
+
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+)
+
+type initCompact func() (Compact, error)
+
+func createRootCompact(z Z) initCompact {
+	return z.NewCompact
+}
+
+func readRootCompact(z Z) initCompact {
+	return z.Compact
+}
+
+func encodeCompact(seg *capnp.Segment, create initCompact, e *message.Compact) (Compact, error) {
+	compact, err := create()
+	if err != nil {
+		return Compact{}, errors.Wrap(err, "could not create compact")
+	}
+	_, err = encodeHeader(seg, compact.NewHeader, e.Header)
+	if err != nil {
+		return Compact{}, errors.Wrap(err, "could not encode header")
+	}
+	compact.SetNonce(e.Nonce)
+	shortIDs, err := compact.NewShortIds(int32(len(e.ShortIDs)))
+	if err != nil {
+		return Compact{}, errors.Wrap(err, "could not create short ID list")
+	}
+	for i, shortID := range e.ShortIDs {
+		shortIDs.Set(i, shortID)
+	}
+	return compact, nil
+}
+
+func decodeCompact(read initCompact, cfg *Config) (*message.Compact, error) {
+	compact, err := read()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read compact")
+	}
+	header, err := decodeHeader(compact.Header)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode header")
+	}
+	shortIDs, err := compact.ShortIds()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read short ID list")
+	}
+	err = check(ErrListLength, "short IDs", cfg.maxHashes, uint64(shortIDs.Len()))
+	if err != nil {
+		return nil, err
+	}
+	e := &message.Compact{
+		Header:   header,
+		Nonce:    compact.Nonce(),
+		ShortIDs: make([]uint64, shortIDs.Len()),
+	}
+	for i := 0; i < shortIDs.Len(); i++ {
+		e.ShortIDs[i] = shortIDs.At(i)
+	}
+	return e, nil
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package codec
+
+// Config represents the limits enforced when decoding messages. They make sure
+// a misbehaving peer can not make us allocate arbitrary amounts of memory by
+// announcing huge messages or lists. A limit of zero means it is not enforced,
+// except for the number of segments, which is always capped.
+type Config struct {
+	maxSegments   uint64
+	maxSize       uint64
+	typeSizes     map[Z_Which]uint64
+	maxAddresses  uint64
+	maxHashes     uint64
+	maxHeaders    uint64
+	maxTxs        uint64
+	maxTransfers  uint64
+	maxFees       uint64
+	maxSignatures uint64
+	maxData       uint64
+	maxSignature  uint64
+	maxBloom      uint64
+}
+
+// DefaultConfig returns the default limits used when decoding messages.
+func DefaultConfig() Config {
+	return Config{
+		maxSegments: 8,
+		maxSize:     4 << 20,
+		typeSizes: map[Z_Which]uint64{
+			Z_Which_ping:        1 << 10,
+			Z_Which_pong:        1 << 10,
+			Z_Which_discover:    1 << 10,
+			Z_Which_peers:       64 << 10,
+			Z_Which_transaction: 128 << 10,
+			Z_Which_mempool:     1 << 20,
+			Z_Which_inventory:   1 << 20,
+			Z_Which_request:     1 << 20,
+			Z_Which_batch:       4 << 20,
+			Z_Which_status:      1 << 10,
+			Z_Which_getHeaders:  1 << 20,
+			Z_Which_path:        1 << 20,
+			Z_Which_getInv:      1 << 10,
+			Z_Which_getTx:       1 << 10,
+			Z_Which_notFound:    1 << 20,
+			Z_Which_announce:    1 << 20,
+			Z_Which_compact:     1 << 20,
+			Z_Which_getBlockTxs: 1 << 20,
+			Z_Which_blockTxs:    4 << 20,
+		},
+		maxAddresses:  1000,
+		maxHashes:     16384,
+		maxHeaders:    2000,
+		maxTxs:        1024,
+		maxTransfers:  256,
+		maxFees:       256,
+		maxSignatures: 256,
+		maxData:       64 << 10,
+		maxSignature:  1 << 10,
+		maxBloom:      1 << 20,
+	}
+}
+
+// SetMaxSegments allows us to configure the maximum number of segments per
+// message. It can not be raised above the limit of the capnproto decoder,
+// which also applies if it is set to zero.
+func SetMaxSegments(maxSegments uint64) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxSegments = maxSegments
+	}
+}
+
+// SetMaxSize allows us to configure the maximum size of any message in bytes.
+func SetMaxSize(maxSize uint64) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxSize = maxSize
+	}
+}
+
+// SetTypeSize allows us to configure the maximum size in bytes for messages of
+// the given type.
+func SetTypeSize(which Z_Which, maxSize uint64) func(*Config) {
+	return func(cfg *Config) {
+		if cfg.typeSizes == nil {
+			cfg.typeSizes = make(map[Z_Which]uint64)
+		}
+		cfg.typeSizes[which] = maxSize
+	}
+}
+
+// SetMaxAddresses allows us to configure the maximum number of addresses in a
+// peers message.
+func SetMaxAddresses(maxAddresses uint64) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxAddresses = maxAddresses
+	}
+}
+
+// SetMaxHashes allows us to configure the maximum number of hashes in an
+// inventory, request or not found message, and of locators in a get headers
+// message.
+func SetMaxHashes(maxHashes uint64) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxHashes = maxHashes
+	}
+}
+
+// SetMaxHeaders allows us to configure the maximum number of headers in a path
+// message.
+func SetMaxHeaders(maxHeaders uint64) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxHeaders = maxHeaders
+	}
+}
+
+// SetMaxTransactions allows us to configure the maximum number of transactions
+// in a batch message.
+func SetMaxTransactions(maxTxs uint64) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxTxs = maxTxs
+	}
+}
+
+// SetMaxTransfers allows us to configure the maximum number of transfers in a
+// transaction.
+func SetMaxTransfers(maxTransfers uint64) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxTransfers = maxTransfers
+	}
+}
+
+// SetMaxFees allows us to configure the maximum number of fees in a
+// transaction.
+func SetMaxFees(maxFees uint64) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxFees = maxFees
+	}
+}
+
+// SetMaxSignatures allows us to configure the maximum number of signatures in
+// a transaction.
+func SetMaxSignatures(maxSignatures uint64) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxSignatures = maxSignatures
+	}
+}
+
+// SetMaxData allows us to configure the maximum size of the data payload of a
+// transaction in bytes.
+func SetMaxData(maxData uint64) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxData = maxData
+	}
+}
+
+// SetMaxSignature allows us to configure the maximum size of a single
+// signature in bytes.
+func SetMaxSignature(maxSignature uint64) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxSignature = maxSignature
+	}
+}
+
+// SetMaxBloom allows us to configure the maximum size of the bloom filter in a
+// mempool message in bytes.
+func SetMaxBloom(maxBloom uint64) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxBloom = maxBloom
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package codec
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors exported by the package.
+var (
+	ErrSegments   = errors.New("too many message segments")
+	ErrSize       = errors.New("message size exceeds limit")
+	ErrListLength = errors.New("list length exceeds limit")
+	ErrDataSize   = errors.New("data size exceeds limit")
+	ErrBloom      = errors.New("bloom size mismatch")
+)
+
+// LimitError is returned when a message violates one of the decoding limits.
+// As a well-behaved peer never sends such a message, the receiver should treat
+// it as misbehaviour of the sender.
+type LimitError struct {
+	Err    error
+	Field  string
+	Limit  uint64
+	Actual uint64
+}
+
+// Error returns a description of the violated limit.
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%v (%v: %v > %v)", e.Err, e.Field, e.Actual, e.Limit)
+}
+
+// Violation marks the error as a protocol violation by the sender.
+func (e *LimitError) Violation() bool {
+	return true
+}
+
+// check returns a limit error if the actual value is above the given limit.
+func check(err error, field string, limit uint64, actual uint64) error {
+	if limit == 0 || actual <= limit {
+		return nil
+	}
+	return &LimitError{Err: err, Field: field, Limit: limit, Actual: actual}
+}
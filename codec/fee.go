@@ -63,7 +63,7 @@ func decodeFee(read initFee) (*types.Fee, error) {
 	}
 	amount := fee.Amount()
 	f := &types.Fee{
-		From:   from,
+		From:   clone(from),
 		Amount: amount,
 	}
 	return f, nil
@@ -0,0 +1,117 @@
+// Code generated by capnpc-go. DO NOT EDIT.
+
+package codec
+
+import (
+	capnp "zombiezen.com/go/capnproto2"
+	text "zombiezen.com/go/capnproto2/encoding/text"
+	schemas "zombiezen.com/go/capnproto2/schemas"
+)
+
+type GetBlockTxs struct{ capnp.Struct }
+
+// GetBlockTxs_TypeID is the unique identifier for the type GetBlockTxs.
+const GetBlockTxs_TypeID = 0xf90b82a3997a3cdc
+
+func NewGetBlockTxs(s *capnp.Segment) (GetBlockTxs, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2})
+	return GetBlockTxs{st}, err
+}
+
+func NewRootGetBlockTxs(s *capnp.Segment) (GetBlockTxs, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2})
+	return GetBlockTxs{st}, err
+}
+
+func ReadRootGetBlockTxs(msg *capnp.Message) (GetBlockTxs, error) {
+	root, err := msg.RootPtr()
+	return GetBlockTxs{root.Struct()}, err
+}
+
+func (s GetBlockTxs) String() string {
+	str, _ := text.Marshal(0xf90b82a3997a3cdc, s.Struct)
+	return str
+}
+
+func (s GetBlockTxs) Hash() ([]byte, error) {
+	p, err := s.Struct.Ptr(0)
+	return []byte(p.Data()), err
+}
+
+func (s GetBlockTxs) HasHash() bool {
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s GetBlockTxs) SetHash(v []byte) error {
+	return s.Struct.SetData(0, v)
+}
+
+func (s GetBlockTxs) Indexes() (capnp.UInt32List, error) {
+	p, err := s.Struct.Ptr(1)
+	return capnp.UInt32List{List: p.List()}, err
+}
+
+func (s GetBlockTxs) HasIndexes() bool {
+	p, err := s.Struct.Ptr(1)
+	return p.IsValid() || err != nil
+}
+
+func (s GetBlockTxs) SetIndexes(v capnp.UInt32List) error {
+	return s.Struct.SetPtr(1, v.List.ToPtr())
+}
+
+// NewIndexes sets the indexes field to a newly
+// allocated capnp.UInt32List, preferring placement in s's segment.
+func (s GetBlockTxs) NewIndexes(n int32) (capnp.UInt32List, error) {
+	l, err := capnp.NewUInt32List(s.Struct.Segment(), n)
+	if err != nil {
+		return capnp.UInt32List{}, err
+	}
+	err = s.Struct.SetPtr(1, l.List.ToPtr())
+	return l, err
+}
+
+// GetBlockTxs_List is a list of GetBlockTxs.
+type GetBlockTxs_List struct{ capnp.List }
+
+// NewGetBlockTxs creates a new list of GetBlockTxs.
+func NewGetBlockTxs_List(s *capnp.Segment, sz int32) (GetBlockTxs_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2}, sz)
+	return GetBlockTxs_List{l}, err
+}
+
+func (s GetBlockTxs_List) At(i int) GetBlockTxs { return GetBlockTxs{s.List.Struct(i)} }
+
+func (s GetBlockTxs_List) Set(i int, v GetBlockTxs) error { return s.List.SetStruct(i, v.Struct) }
+
+func (s GetBlockTxs_List) String() string {
+	str, _ := text.MarshalList(0xf90b82a3997a3cdc, s.List)
+	return str
+}
+
+// GetBlockTxs_Promise is a wrapper for a GetBlockTxs promised by a client call.
+type GetBlockTxs_Promise struct{ *capnp.Pipeline }
+
+func (p GetBlockTxs_Promise) Struct() (GetBlockTxs, error) {
+	s, err := p.Pipeline.Struct()
+	return GetBlockTxs{s}, err
+}
+
+const schema_b2f046a4be6ca433 = "x\xda\x12\xb0v`\x12d\x8dg`\x08dae\xfb" +
+	"\x7f\xc7\xa6j\xe6\xe2&\xee\x9f\x0c\x82B\x8c\xff\x8d\x97" +
+	"\xe4\xec[\xe2\xf6a\x13\x03+\x13;\x03\x83\xa0\xe8'" +
+	"AEv0*g`\x10\\\xcb\xfe?=\xb5\xc4)" +
+	"'?9\x9b)\xa4\xa2X/9\xb1 \xaf\xc0\xca\x1d" +
+	",$\x9f\x9c\x1dRQ\x1c\xc0\xc8\x18\xc0\xc8\x14\xc8\xc1" +
+	"\xcc\xc2\xc0\xc0\xc2\xc8\xc0 \xa8\xa9%\xa8\xc9\x1e\xa8\xc1" +
+	"\xcc\x18h\xc2\xc4(\xc8\xc8(\xc2\x08\x125t\x124" +
+	"d\x0f4`f\x0c\xf4ab\xe4\xcfH,\xce\x08`" +
+	"db\xe4e\x00a\x06AF\xa6\xfa\xcc\xbc\x94\xd4\x8a" +
+	"\xd4b\x90(\x1f\x03c\x003##\x07\x03\x98\xe9\xc0" +
+	"\x08\x18\x00w\xbf'C"
+
+func init() {
+	schemas.Register(schema_b2f046a4be6ca433,
+		0xf90b82a3997a3cdc)
+}
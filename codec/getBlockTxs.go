@@ -0,0 +1,81 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package codec
+
+import (
+	"github.com/pkg/errors"
+	capnp "zombiezen.com/go/capnproto2"
+
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+)
+
+type initGetBlockTxs func() (GetBlockTxs, error)
+
+func createRootGetBlockTxs(z Z) initGetBlockTxs {
+	return z.NewGetBlockTxs
+}
+
+func readRootGetBlockTxs(z Z) initGetBlockTxs {
+	return z.GetBlockTxs
+}
+
+func encodeGetBlockTxs(seg *capnp.Segment, create initGetBlockTxs, e *message.GetBlockTxs) (GetBlockTxs, error) {
+	getBlockTxs, err := create()
+	if err != nil {
+		return GetBlockTxs{}, errors.Wrap(err, "could not create get block transactions")
+	}
+	err = getBlockTxs.SetHash(e.Hash[:])
+	if err != nil {
+		return GetBlockTxs{}, errors.Wrap(err, "could not set hash")
+	}
+	indexes, err := getBlockTxs.NewIndexes(int32(len(e.Indexes)))
+	if err != nil {
+		return GetBlockTxs{}, errors.Wrap(err, "could not create index list")
+	}
+	for i, index := range e.Indexes {
+		indexes.Set(i, index)
+	}
+	return getBlockTxs, nil
+}
+
+func decodeGetBlockTxs(read initGetBlockTxs, cfg *Config) (*message.GetBlockTxs, error) {
+	getBlockTxs, err := read()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read get block transactions")
+	}
+	hash, err := getBlockTxs.Hash()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read hash")
+	}
+	indexes, err := getBlockTxs.Indexes()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read index list")
+	}
+	err = check(ErrListLength, "indexes", cfg.maxHashes, uint64(indexes.Len()))
+	if err != nil {
+		return nil, err
+	}
+	e := &message.GetBlockTxs{
+		Indexes: make([]uint32, indexes.Len()),
+	}
+	copy(e.Hash[:], hash)
+	for i := 0; i < indexes.Len(); i++ {
+		e.Indexes[i] = indexes.At(i)
+	}
+	return e, nil
+}
@@ -0,0 +1,126 @@
+// Code generated by capnpc-go. DO NOT EDIT.
+
+package codec
+
+import (
+	capnp "zombiezen.com/go/capnproto2"
+	text "zombiezen.com/go/capnproto2/encoding/text"
+	schemas "zombiezen.com/go/capnproto2/schemas"
+)
+
+type GetHeaders struct{ capnp.Struct }
+
+// GetHeaders_TypeID is the unique identifier for the type GetHeaders.
+const GetHeaders_TypeID = 0x9da9ca82b893d3c3
+
+func NewGetHeaders(s *capnp.Segment) (GetHeaders, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2})
+	return GetHeaders{st}, err
+}
+
+func NewRootGetHeaders(s *capnp.Segment) (GetHeaders, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2})
+	return GetHeaders{st}, err
+}
+
+func ReadRootGetHeaders(msg *capnp.Message) (GetHeaders, error) {
+	root, err := msg.RootPtr()
+	return GetHeaders{root.Struct()}, err
+}
+
+func (s GetHeaders) String() string {
+	str, _ := text.Marshal(0x9da9ca82b893d3c3, s.Struct)
+	return str
+}
+
+func (s GetHeaders) Locators() (capnp.DataList, error) {
+	p, err := s.Struct.Ptr(0)
+	return capnp.DataList{List: p.List()}, err
+}
+
+func (s GetHeaders) HasLocators() bool {
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s GetHeaders) SetLocators(v capnp.DataList) error {
+	return s.Struct.SetPtr(0, v.List.ToPtr())
+}
+
+// NewLocators sets the locators field to a newly
+// allocated capnp.DataList, preferring placement in s's segment.
+func (s GetHeaders) NewLocators(n int32) (capnp.DataList, error) {
+	l, err := capnp.NewDataList(s.Struct.Segment(), n)
+	if err != nil {
+		return capnp.DataList{}, err
+	}
+	err = s.Struct.SetPtr(0, l.List.ToPtr())
+	return l, err
+}
+
+func (s GetHeaders) Stop() ([]byte, error) {
+	p, err := s.Struct.Ptr(1)
+	return []byte(p.Data()), err
+}
+
+func (s GetHeaders) HasStop() bool {
+	p, err := s.Struct.Ptr(1)
+	return p.IsValid() || err != nil
+}
+
+func (s GetHeaders) SetStop(v []byte) error {
+	return s.Struct.SetData(1, v)
+}
+
+func (s GetHeaders) Max() uint32 {
+	return s.Struct.Uint32(0)
+}
+
+func (s GetHeaders) SetMax(v uint32) {
+	s.Struct.SetUint32(0, v)
+}
+
+// GetHeaders_List is a list of GetHeaders.
+type GetHeaders_List struct{ capnp.List }
+
+// NewGetHeaders creates a new list of GetHeaders.
+func NewGetHeaders_List(s *capnp.Segment, sz int32) (GetHeaders_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 8, PointerCount: 2}, sz)
+	return GetHeaders_List{l}, err
+}
+
+func (s GetHeaders_List) At(i int) GetHeaders { return GetHeaders{s.List.Struct(i)} }
+
+func (s GetHeaders_List) Set(i int, v GetHeaders) error { return s.List.SetStruct(i, v.Struct) }
+
+func (s GetHeaders_List) String() string {
+	str, _ := text.MarshalList(0x9da9ca82b893d3c3, s.List)
+	return str
+}
+
+// GetHeaders_Promise is a wrapper for a GetHeaders promised by a client call.
+type GetHeaders_Promise struct{ *capnp.Pipeline }
+
+func (p GetHeaders_Promise) Struct() (GetHeaders, error) {
+	s, err := p.Pipeline.Struct()
+	return GetHeaders{s}, err
+}
+
+const schema_cd65e7f5c5653932 = "x\xda$\xc8\xb1.\x04Q\x18\x05\xe0s\xfe;\xe3*" +
+	",\xfed=\x00\xa5B\xa1\xa3\xdaF,Q\xcc\xff\x04" +
+	"\xdc\xec\xde\xb0\xc92\x93\x99)\xd4^A\xed\x01TZ" +
+	"\xbdh$*o\xa0Q+\xd4W\xeelrNr\xce" +
+	"\xb7}1\x11-/\x01+\xca\xb5\xf4\xf6\xf5\xf8\xfa\xf0" +
+	"\xf1\xfc\x04S2\x1d\x1e\xc5\xf7\xbf\x9f\xf8\x89R<\xa0" +
+	";\xdf\xba\xeb\x87\xbc\x00\xfa\xeb\xd3u\xec\xa71\xcc\xa3" +
+	"\xb4\xdd\xc1,4w\xcd\xf1\xe9J|l\xbb\x8a\xac(" +
+	"\xb6\xe1\x0a\xa0 \xa0'\xe7z\xe6m\xeahs\xa1\x92" +
+	"cf\x0d\xfb\x1a\xbc]9\xdaRH\x19S\x00]\xec" +
+	"\xe9\xc2\xdb\x8d\xa3\xf5\xc2\xb4\xacg\xa1\xaf\xdb\x0e@E" +
+	"\xe1&X9r\x84anu}\xddd\xce\x7f\x04(" +
+	"\xc5\xdf\x86\xfb,\xeb\xc8\xe5\x84\xff\x03\x00\x92\x99/\x18"
+
+func init() {
+	schemas.Register(schema_cd65e7f5c5653932,
+		0x9da9ca82b893d3c3)
+}
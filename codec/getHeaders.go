@@ -0,0 +1,91 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package codec
+
+import (
+	"github.com/pkg/errors"
+	capnp "zombiezen.com/go/capnproto2"
+
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+type initGetHeaders func() (GetHeaders, error)
+
+func createRootGetHeaders(z Z) initGetHeaders {
+	return z.NewGetHeaders
+}
+
+func readRootGetHeaders(z Z) initGetHeaders {
+	return z.GetHeaders
+}
+
+func encodeGetHeaders(seg *capnp.Segment, create initGetHeaders, e *message.GetHeaders) (GetHeaders, error) {
+	getHeaders, err := create()
+	if err != nil {
+		return GetHeaders{}, errors.Wrap(err, "could not create get headers")
+	}
+	locators, err := getHeaders.NewLocators(int32(len(e.Locators)))
+	if err != nil {
+		return GetHeaders{}, errors.Wrap(err, "could not create locator list")
+	}
+	for i, locator := range e.Locators {
+		err = locators.Set(i, locator[:])
+		if err != nil {
+			return GetHeaders{}, errors.Wrap(err, "could not set locator")
+		}
+	}
+	err = getHeaders.SetStop(e.Stop[:])
+	if err != nil {
+		return GetHeaders{}, errors.Wrap(err, "could not set stop hash")
+	}
+	getHeaders.SetMax(e.Max)
+	return getHeaders, nil
+}
+
+func decodeGetHeaders(read initGetHeaders, cfg *Config) (*message.GetHeaders, error) {
+	getHeaders, err := read()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read get headers")
+	}
+	locators, err := getHeaders.Locators()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read locator list")
+	}
+	err = check(ErrListLength, "locators", cfg.maxHashes, uint64(locators.Len()))
+	if err != nil {
+		return nil, err
+	}
+	stop, err := getHeaders.Stop()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read stop hash")
+	}
+	e := &message.GetHeaders{
+		Locators: make([]types.Hash, locators.Len()),
+		Max:      getHeaders.Max(),
+	}
+	for i := 0; i < locators.Len(); i++ {
+		locator, err := locators.At(i)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get locator")
+		}
+		copy(e.Locators[i][:], locator)
+	}
+	copy(e.Stop[:], stop)
+	return e, nil
+}
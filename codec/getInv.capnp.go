@@ -0,0 +1,89 @@
+// Code generated by capnpc-go. DO NOT EDIT.
+
+package codec
+
+import (
+	capnp "zombiezen.com/go/capnproto2"
+	text "zombiezen.com/go/capnproto2/encoding/text"
+	schemas "zombiezen.com/go/capnproto2/schemas"
+)
+
+type GetInv struct{ capnp.Struct }
+
+// GetInv_TypeID is the unique identifier for the type GetInv.
+const GetInv_TypeID = 0xab1ba64504c82c5b
+
+func NewGetInv(s *capnp.Segment) (GetInv, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1})
+	return GetInv{st}, err
+}
+
+func NewRootGetInv(s *capnp.Segment) (GetInv, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1})
+	return GetInv{st}, err
+}
+
+func ReadRootGetInv(msg *capnp.Message) (GetInv, error) {
+	root, err := msg.RootPtr()
+	return GetInv{root.Struct()}, err
+}
+
+func (s GetInv) String() string {
+	str, _ := text.Marshal(0xab1ba64504c82c5b, s.Struct)
+	return str
+}
+
+func (s GetInv) Hash() ([]byte, error) {
+	p, err := s.Struct.Ptr(0)
+	return []byte(p.Data()), err
+}
+
+func (s GetInv) HasHash() bool {
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s GetInv) SetHash(v []byte) error {
+	return s.Struct.SetData(0, v)
+}
+
+// GetInv_List is a list of GetInv.
+type GetInv_List struct{ capnp.List }
+
+// NewGetInv creates a new list of GetInv.
+func NewGetInv_List(s *capnp.Segment, sz int32) (GetInv_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1}, sz)
+	return GetInv_List{l}, err
+}
+
+func (s GetInv_List) At(i int) GetInv { return GetInv{s.List.Struct(i)} }
+
+func (s GetInv_List) Set(i int, v GetInv) error { return s.List.SetStruct(i, v.Struct) }
+
+func (s GetInv_List) String() string {
+	str, _ := text.MarshalList(0xab1ba64504c82c5b, s.List)
+	return str
+}
+
+// GetInv_Promise is a wrapper for a GetInv promised by a client call.
+type GetInv_Promise struct{ *capnp.Pipeline }
+
+func (p GetInv_Promise) Struct() (GetInv, error) {
+	s, err := p.Pipeline.Struct()
+	return GetInv{s}, err
+}
+
+const schema_8a7739a41252f21e = "x\xda\x12Ps`\x12d\x8dg`\x08dae\xfb" +
+	"\x1f\xads\x82\xc5u\x99\xf4j\x06A^\xc6\xffr\x9f" +
+	"\x82\x84\x96X\x96w1\xb02\xb230\x08\x8a.\x12" +
+	"\x94e\x07#{\x06\x06\xc1H\xf6\xff\xe9\xa9%\x9ey" +
+	"ez\xc9\x8c\x89\x05y\x05V\xee\xa9%\xec\x9eye" +
+	"\x01\x8c\x8c\x01\x8cL\x81,\xcc,\x0c\x0c,\x8c\x0c\x0c" +
+	"\x82\xbcZ\x82\xbc\xec\x81<\xcc\x8c\x81\x12L\x8c\xfc\x19" +
+	"\x89\xc5\x19\x01\x8cL\x8c\xbc\x0c \xcc \xc8\xc8\xe4\xc0" +
+	"\x08\x18\x00g\xe2\x18\xd2"
+
+func init() {
+	schemas.Register(schema_8a7739a41252f21e,
+		0xab1ba64504c82c5b)
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package codec
+
+import (
+	"github.com/pkg/errors"
+	capnp "zombiezen.com/go/capnproto2"
+
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+)
+
+type initGetInv func() (GetInv, error)
+
+func createRootGetInv(z Z) initGetInv {
+	return z.NewGetInv
+}
+
+func readRootGetInv(z Z) initGetInv {
+	return z.GetInv
+}
+
+func encodeGetInv(seg *capnp.Segment, create initGetInv, e *message.GetInv) (GetInv, error) {
+	getInv, err := create()
+	if err != nil {
+		return GetInv{}, errors.Wrap(err, "could not create get inventory")
+	}
+	err = getInv.SetHash(e.Hash[:])
+	if err != nil {
+		return GetInv{}, errors.Wrap(err, "could not set hash")
+	}
+	return getInv, nil
+}
+
+func decodeGetInv(read initGetInv) (*message.GetInv, error) {
+	getInv, err := read()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read get inventory")
+	}
+	hash, err := getInv.Hash()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read hash")
+	}
+	e := &message.GetInv{}
+	copy(e.Hash[:], hash)
+	return e, nil
+}
@@ -0,0 +1,89 @@
+// Code generated by capnpc-go. DO NOT EDIT.
+
+package codec
+
+import (
+	capnp "zombiezen.com/go/capnproto2"
+	text "zombiezen.com/go/capnproto2/encoding/text"
+	schemas "zombiezen.com/go/capnproto2/schemas"
+)
+
+type GetTx struct{ capnp.Struct }
+
+// GetTx_TypeID is the unique identifier for the type GetTx.
+const GetTx_TypeID = 0xc9783aa8ecc49891
+
+func NewGetTx(s *capnp.Segment) (GetTx, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1})
+	return GetTx{st}, err
+}
+
+func NewRootGetTx(s *capnp.Segment) (GetTx, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1})
+	return GetTx{st}, err
+}
+
+func ReadRootGetTx(msg *capnp.Message) (GetTx, error) {
+	root, err := msg.RootPtr()
+	return GetTx{root.Struct()}, err
+}
+
+func (s GetTx) String() string {
+	str, _ := text.Marshal(0xc9783aa8ecc49891, s.Struct)
+	return str
+}
+
+func (s GetTx) Hash() ([]byte, error) {
+	p, err := s.Struct.Ptr(0)
+	return []byte(p.Data()), err
+}
+
+func (s GetTx) HasHash() bool {
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s GetTx) SetHash(v []byte) error {
+	return s.Struct.SetData(0, v)
+}
+
+// GetTx_List is a list of GetTx.
+type GetTx_List struct{ capnp.List }
+
+// NewGetTx creates a new list of GetTx.
+func NewGetTx_List(s *capnp.Segment, sz int32) (GetTx_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1}, sz)
+	return GetTx_List{l}, err
+}
+
+func (s GetTx_List) At(i int) GetTx { return GetTx{s.List.Struct(i)} }
+
+func (s GetTx_List) Set(i int, v GetTx) error { return s.List.SetStruct(i, v.Struct) }
+
+func (s GetTx_List) String() string {
+	str, _ := text.MarshalList(0xc9783aa8ecc49891, s.List)
+	return str
+}
+
+// GetTx_Promise is a wrapper for a GetTx promised by a client call.
+type GetTx_Promise struct{ *capnp.Pipeline }
+
+func (p GetTx_Promise) Struct() (GetTx, error) {
+	s, err := p.Pipeline.Struct()
+	return GetTx{s}, err
+}
+
+const schema_d093b9e60d923640 = "x\xda\x12Ps`\x12d\x8dg`\x08dae\xfb" +
+	"?q\xc6\x917+\xac*N2\x08\xf20\xfew0" +
+	"\x9b\xc4\xfbl\xe7\xe4\x0b\x0c\xac\x8c\xec\x0c\x0c\x82\xa2\x93" +
+	"\x04e\xd9\xc1\xc8\x9e\x81A0\x92\xfd\x7fzjIH" +
+	"\x85^r\"cA^\x81\x95{jI\x08cE\x00" +
+	"#c\x00#S \x0b3\x0b\x03\x03\x0b#\x03\x83 " +
+	"\xaf\x96 /{ \x0f3c\xa0\x04\x13#\x7fFb" +
+	"qF\x00#\x13#/\x03\x083\x082290\x02" +
+	"\x06\x008\x0b\x1a\xb6"
+
+func init() {
+	schemas.Register(schema_d093b9e60d923640,
+		0xc9783aa8ecc49891)
+}
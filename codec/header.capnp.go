@@ -0,0 +1,190 @@
+// Code generated by capnpc-go. DO NOT EDIT.
+
+package codec
+
+import (
+	capnp "zombiezen.com/go/capnproto2"
+	text "zombiezen.com/go/capnproto2/encoding/text"
+	schemas "zombiezen.com/go/capnproto2/schemas"
+)
+
+type Header struct{ capnp.Struct }
+
+// Header_TypeID is the unique identifier for the type Header.
+const Header_TypeID = 0xc7f6614ab8ef54c1
+
+func NewHeader(s *capnp.Segment) (Header, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 32, PointerCount: 5})
+	return Header{st}, err
+}
+
+func NewRootHeader(s *capnp.Segment) (Header, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 32, PointerCount: 5})
+	return Header{st}, err
+}
+
+func ReadRootHeader(msg *capnp.Message) (Header, error) {
+	root, err := msg.RootPtr()
+	return Header{root.Struct()}, err
+}
+
+func (s Header) String() string {
+	str, _ := text.Marshal(0xc7f6614ab8ef54c1, s.Struct)
+	return str
+}
+
+func (s Header) Hash() ([]byte, error) {
+	p, err := s.Struct.Ptr(0)
+	return []byte(p.Data()), err
+}
+
+func (s Header) HasHash() bool {
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s Header) SetHash(v []byte) error {
+	return s.Struct.SetData(0, v)
+}
+
+func (s Header) Parent() ([]byte, error) {
+	p, err := s.Struct.Ptr(1)
+	return []byte(p.Data()), err
+}
+
+func (s Header) HasParent() bool {
+	p, err := s.Struct.Ptr(1)
+	return p.IsValid() || err != nil
+}
+
+func (s Header) SetParent(v []byte) error {
+	return s.Struct.SetData(1, v)
+}
+
+func (s Header) State() ([]byte, error) {
+	p, err := s.Struct.Ptr(2)
+	return []byte(p.Data()), err
+}
+
+func (s Header) HasState() bool {
+	p, err := s.Struct.Ptr(2)
+	return p.IsValid() || err != nil
+}
+
+func (s Header) SetState(v []byte) error {
+	return s.Struct.SetData(2, v)
+}
+
+func (s Header) Delta() ([]byte, error) {
+	p, err := s.Struct.Ptr(3)
+	return []byte(p.Data()), err
+}
+
+func (s Header) HasDelta() bool {
+	p, err := s.Struct.Ptr(3)
+	return p.IsValid() || err != nil
+}
+
+func (s Header) SetDelta(v []byte) error {
+	return s.Struct.SetData(3, v)
+}
+
+func (s Header) Miner() ([]byte, error) {
+	p, err := s.Struct.Ptr(4)
+	return []byte(p.Data()), err
+}
+
+func (s Header) HasMiner() bool {
+	p, err := s.Struct.Ptr(4)
+	return p.IsValid() || err != nil
+}
+
+func (s Header) SetMiner(v []byte) error {
+	return s.Struct.SetData(4, v)
+}
+
+func (s Header) Diff() uint64 {
+	return s.Struct.Uint64(0)
+}
+
+func (s Header) SetDiff(v uint64) {
+	s.Struct.SetUint64(0, v)
+}
+
+func (s Header) Nonce() uint64 {
+	return s.Struct.Uint64(8)
+}
+
+func (s Header) SetNonce(v uint64) {
+	s.Struct.SetUint64(8, v)
+}
+
+func (s Header) Time() int64 {
+	return int64(s.Struct.Uint64(16))
+}
+
+func (s Header) SetTime(v int64) {
+	s.Struct.SetUint64(16, uint64(v))
+}
+
+func (s Header) Nanos() uint32 {
+	return s.Struct.Uint32(24)
+}
+
+func (s Header) SetNanos(v uint32) {
+	s.Struct.SetUint32(24, v)
+}
+
+// Header_List is a list of Header.
+type Header_List struct{ capnp.List }
+
+// NewHeader creates a new list of Header.
+func NewHeader_List(s *capnp.Segment, sz int32) (Header_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 32, PointerCount: 5}, sz)
+	return Header_List{l}, err
+}
+
+func (s Header_List) At(i int) Header { return Header{s.List.Struct(i)} }
+
+func (s Header_List) Set(i int, v Header) error { return s.List.SetStruct(i, v.Struct) }
+
+func (s Header_List) String() string {
+	str, _ := text.MarshalList(0xc7f6614ab8ef54c1, s.List)
+	return str
+}
+
+// Header_Promise is a wrapper for a Header promised by a client call.
+type Header_Promise struct{ *capnp.Pipeline }
+
+func (p Header_Promise) Struct() (Header, error) {
+	s, err := p.Pipeline.Struct()
+	return Header{s}, err
+}
+
+const schema_e2b80bb30a1bef80 = "x\xda\\\xd0?k\x14A\x18\xc7\xf1\xe77\x7f\xf6Y" +
+	"\xff,\x97a\xe6@\xd0\xe5,\x12\x90\xc3\x88n%i" +
+	"L)V\xb7\x8e\x8d\x95\x0c\xd9\x09w`6\xc7\xdd\xbe" +
+	"\x00_\x8b\x85o\xc0&\xad\x95\xef\xc3\xda\x90\xc6\x88\x85" +
+	"a\xe5\xb1JR\xfc\x8a\xf9|\x99\xe6\xd9\xf9r\xa8\x9c" +
+	"\xfd@\xd4\x1a[\x8c\xdf\xde]\x9c\xbdI\xbf\xbfS[" +
+	"\xc1\x8c\x9f.\x1e\xde\xfdz\xef\xec\x07Y\xcbDn\xfa" +
+	"\xd9\xd5\xecj~Q\x8f \xf2\xef\x15\x8f\xcb\x9c\xba\xbc" +
+	"yv\x84\xb4\xee\xd7\x07\xafs\xe2.o\x16\xc0\x02\xaa" +
+	"\xdd\xd5\x86\xc8\x80\xc8\x9d\xcf\xdd9\xb7?5\xda?\x0a" +
+	"\x0e\x08\x10\xbd<p\x97\xdc\xfe\xd2x\x0b\x05\xa7T\x80" +
+	"\"rW\x8d\xbb\xe2\xf6\xafF,\x85\xb5\x0e\xd0D\xde" +
+	"\xa2\xf1\x16\x1c\x0d4\xe2\x8e\x14c\x02\x0c\x91\xaf\xd0\xf8" +
+	"\x0a\x1c\xefKy\x00\x05\xd8\x00K\xe4\xa7\x98\xfb)8" +
+	"\x06\x09\x8f\xe5K\x81\x80\x82\xc8\xd7h|\x0d\x8e\x8f\xa4" +
+	"<\x91\xc2*\x80\x89\xfc\x1e\xe6~\x0f\x1cw\xa5<\x97" +
+	"R\x16\x01%\x91\xdfG\xe3\xf7\xc1\xf1\xa9\x94\x97P\x98" +
+	",\xd3v\xb9\x80BE2rP\xaf\xd6i\x93\xfb\xe1" +
+	"&\xce\xb6C\x1a\xf2-\xeb\xf2\xc7!\xdd\xb2\x93U/" +
+	"\xb7\xbbn\x93nu|,t\x87d\x98\xf5\xa7\xfdQ" +
+	"\xbe\x06\x93au\xf2\xffmI\x86Y\x9f\xfa\xd3\xad@" +
+	"I2\x1c\xe2\xdf\x00Y\xf6H\x15"
+
+func init() {
+	schemas.Register(schema_e2b80bb30a1bef80,
+		0xc7f6614ab8ef54c1)
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package codec
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	capnp "zombiezen.com/go/capnproto2"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+type initHeader func() (Header, error)
+
+func createChildHeader(seg *capnp.Segment) initHeader {
+	return func() (Header, error) {
+		header, err := NewHeader(seg)
+		return header, err
+	}
+}
+
+func readChildHeader(header Header) initHeader {
+	return func() (Header, error) {
+		return header, nil
+	}
+}
+
+func encodeHeader(seg *capnp.Segment, create initHeader, e *types.Header) (Header, error) {
+	header, err := create()
+	if err != nil {
+		return Header{}, errors.Wrap(err, "could not create header")
+	}
+	err = header.SetHash(e.Hash[:])
+	if err != nil {
+		return Header{}, errors.Wrap(err, "could not set hash")
+	}
+	err = header.SetParent(e.Parent[:])
+	if err != nil {
+		return Header{}, errors.Wrap(err, "could not set parent")
+	}
+	err = header.SetState(e.State[:])
+	if err != nil {
+		return Header{}, errors.Wrap(err, "could not set state")
+	}
+	err = header.SetDelta(e.Delta[:])
+	if err != nil {
+		return Header{}, errors.Wrap(err, "could not set delta")
+	}
+	err = header.SetMiner(e.Miner[:])
+	if err != nil {
+		return Header{}, errors.Wrap(err, "could not set miner")
+	}
+	header.SetDiff(e.Diff)
+	header.SetNonce(e.Nonce)
+	header.SetTime(e.Time.Unix())
+	header.SetNanos(uint32(e.Time.Nanosecond()))
+	return header, nil
+}
+
+func decodeHeader(read initHeader) (*types.Header, error) {
+	header, err := read()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read header")
+	}
+	hash, err := header.Hash()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read hash")
+	}
+	parent, err := header.Parent()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read parent")
+	}
+	state, err := header.State()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read state")
+	}
+	delta, err := header.Delta()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read delta")
+	}
+	miner, err := header.Miner()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read miner")
+	}
+	e := &types.Header{
+		Diff:  header.Diff(),
+		Nonce: header.Nonce(),
+		Time:  time.Unix(header.Time(), int64(header.Nanos())).UTC(),
+	}
+	copy(e.Hash[:], hash)
+	copy(e.Parent[:], parent)
+	copy(e.State[:], state)
+	copy(e.Delta[:], delta)
+	copy(e.Miner[:], miner)
+	return e, nil
+}
@@ -14,12 +14,12 @@ type Inventory struct{ capnp.Struct }
 const Inventory_TypeID = 0x894854b19b0bdf88
 
 func NewInventory(s *capnp.Segment) (Inventory, error) {
-	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1})
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2})
 	return Inventory{st}, err
 }
 
 func NewRootInventory(s *capnp.Segment) (Inventory, error) {
-	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1})
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2})
 	return Inventory{st}, err
 }
 
@@ -58,12 +58,26 @@ func (s Inventory) NewHashes(n int32) (capnp.DataList, error) {
 	return l, err
 }
 
+func (s Inventory) Hash() ([]byte, error) {
+	p, err := s.Struct.Ptr(1)
+	return []byte(p.Data()), err
+}
+
+func (s Inventory) HasHash() bool {
+	p, err := s.Struct.Ptr(1)
+	return p.IsValid() || err != nil
+}
+
+func (s Inventory) SetHash(v []byte) error {
+	return s.Struct.SetData(1, v)
+}
+
 // Inventory_List is a list of Inventory.
 type Inventory_List struct{ capnp.List }
 
 // NewInventory creates a new list of Inventory.
 func NewInventory_List(s *capnp.Segment, sz int32) (Inventory_List, error) {
-	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1}, sz)
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 2}, sz)
 	return Inventory_List{l}, err
 }
 
@@ -84,15 +98,17 @@ func (p Inventory_Promise) Struct() (Inventory, error) {
 	return Inventory{s}, err
 }
 
-const schema_9fd0f7eb12926b5d = "x\xda2pct`2d\xf5\xe7d`\x08\xcca" +
-	"e\xfb\xdfq\x9f{\xf6\xc6\x10\x8fN\x06A\x01\xc6\xff" +
-	"\xb1\xd9\x93\x84^\x7f\xbf0\x9f\x81\x95\x91\x9d\x81A\xb8" +
-	"\x97\xe5\x92\xf0L\x16\x10k*\x8b=\xc3\x7f\x06\xf9\xff" +
-	"\x99ye\xa9y%\xf9EL\x95z\xc9\x89\x05y\x05" +
-	"V\x9eP\x01\xc6\xca\x00F\xc6@\x16f\x16\x06\x06\x16" +
-	"F\x06\x06A^+\x06\x86@\x0ef\xc6@\x15&F" +
-	"\xfb\x8c\xc4\xe2\x8c\xd4bF>\x06\xc6\x00fFF^" +
-	"\x06&\x10\x13\x10\x00\x00\xff\xffi\xf0!j"
+const schema_9fd0f7eb12926b5d = "x\xda\x12\xb0v`\x12d\x8dg`\x08dae\xfb" +
+	"\xdfq\x9f{\xf6\xc6\x10\x8fN\x06A\x01\xc6\xff\xb1\xd9" +
+	"\x93\x84^\x7f\xbf0\x9f\x81\x95\x89\x9d\x81AP\xf4\x92" +
+	"\xa0\";\x18\x9530\x08\xaee\xff\x9f\x99W\x96\x9a" +
+	"W\x92_\xc4T\xa9\x97\x9cX\x90W`\xe5\x09\x15`" +
+	"\xac\x0c`d\x0c`d\x0a\xe4`fa``ad" +
+	"`\x10\xd4\xb4\x12\xd4d\x0f\xd4`f\x0ctab\x14" +
+	"dd\x14a\x04\x89:j\x09:\xb2\x07:03\x06" +
+	"\xfa01\xdag$\x16g\xa4\x16\x07021\xf21" +
+	"0\x06032\xf22\x80\x99\xfc \x09\x900\x88\xcf" +
+	"\xcb\xc0 \xc8\xc8\xe4\xc0\x08\x18\x00N\xb3%S"
 
 func init() {
 	schemas.Register(schema_9fd0f7eb12926b5d,
@@ -21,7 +21,6 @@ import (
 	"github.com/pkg/errors"
 	capnp "zombiezen.com/go/capnproto2"
 
-	"github.com/alvalor/alvalor-go/node"
 	"github.com/alvalor/alvalor-go/types"
 )
 
@@ -35,11 +34,15 @@ func readRootInventory(z Z) initInventory {
 	return z.Inventory
 }
 
-func encodeInventory(seg *capnp.Segment, create initInventory, e *node.Inventory) (Inventory, error) {
+func encodeInventory(seg *capnp.Segment, create initInventory, e *types.Inventory) (Inventory, error) {
 	inventory, err := create()
 	if err != nil {
 		return Inventory{}, errors.Wrap(err, "could not create inventory")
 	}
+	err = inventory.SetHash(e.Hash[:])
+	if err != nil {
+		return Inventory{}, errors.Wrap(err, "could not set block hash")
+	}
 	hashes, err := inventory.NewHashes(int32(len(e.Hashes)))
 	if err != nil {
 		return Inventory{}, errors.Wrap(err, "could not create hash list")
@@ -53,18 +56,27 @@ func encodeInventory(seg *capnp.Segment, create initInventory, e *node.Inventory
 	return inventory, nil
 }
 
-func decodeInventory(read initInventory) (*node.Inventory, error) {
+func decodeInventory(read initInventory, cfg *Config) (*types.Inventory, error) {
 	inventory, err := read()
 	if err != nil {
 		return nil, errors.Wrap(err, "could not read inventory")
 	}
+	hash, err := inventory.Hash()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read block hash")
+	}
 	hashes, err := inventory.Hashes()
 	if err != nil {
 		return nil, errors.Wrap(err, "could not read hash list")
 	}
-	e := &node.Inventory{
+	err = check(ErrListLength, "hashes", cfg.maxHashes, uint64(hashes.Len()))
+	if err != nil {
+		return nil, err
+	}
+	e := &types.Inventory{
 		Hashes: make([]types.Hash, hashes.Len()),
 	}
+	copy(e.Hash[:], hash)
 	for i := 0; i < hashes.Len(); i++ {
 		hash, err := hashes.At(i)
 		if err != nil {
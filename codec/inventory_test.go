@@ -23,13 +23,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
-	"github.com/alvalor/alvalor-go/node"
 	"github.com/alvalor/alvalor-go/types"
 )
 
 func TestInventory(t *testing.T) {
 	proto := &Proto{}
-	inventory := &node.Inventory{
+	inventory := &types.Inventory{
+		Hash: types.Hash{1, 2, 3},
 		Hashes: []types.Hash{
 			{11, 12, 13},
 			{21, 22, 23},
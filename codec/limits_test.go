@@ -0,0 +1,199 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	capnp "zombiezen.com/go/capnproto2"
+
+	"github.com/alvalor/alvalor-go/network"
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+func TestLimitsSize(t *testing.T) {
+	proto := NewProto(SetMaxSize(64))
+	peers := &network.Peers{
+		Addresses: []string{
+			"192.0.2.1:1337",
+			"192.0.2.2:1337",
+			"192.0.2.3:1337",
+			"192.0.2.4:1337",
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	err := proto.Encode(buf, peers)
+	assert.Nil(t, err)
+
+	_, err = proto.Decode(buf)
+	if assert.IsType(t, &LimitError{}, err) {
+		assert.Equal(t, ErrSize, err.(*LimitError).Err)
+	}
+}
+
+func TestLimitsSegments(t *testing.T) {
+	proto := NewProto(SetMaxSegments(0))
+	header := make([]byte, 4)
+	binary.LittleEndian.PutUint32(header, maxStreamSegments)
+
+	_, err := proto.Decode(bytes.NewReader(header))
+	if assert.IsType(t, &LimitError{}, err) {
+		assert.Equal(t, ErrSegments, err.(*LimitError).Err)
+	}
+}
+
+func TestLimitsTypeSize(t *testing.T) {
+	proto := NewProto(SetTypeSize(Z_Which_transaction, 64))
+	tx := &types.Transaction{
+		Data: make([]byte, 128),
+	}
+
+	buf := &bytes.Buffer{}
+	err := proto.Encode(buf, tx)
+	assert.Nil(t, err)
+
+	_, err = proto.Decode(buf)
+	if assert.IsType(t, &LimitError{}, err) {
+		assert.Equal(t, ErrSize, err.(*LimitError).Err)
+	}
+}
+
+func TestLimitsTypeSizeBeforeBody(t *testing.T) {
+	proto := NewProto(SetTypeSize(Z_Which_transaction, 64))
+	tx := &types.Transaction{
+		Data: make([]byte, 128),
+	}
+
+	buf := &bytes.Buffer{}
+	err := proto.Encode(buf, tx)
+	assert.Nil(t, err)
+
+	// only keep the stream header, the root pointer and the wrapper data
+	buf.Truncate(8 + 16)
+
+	_, err = proto.Decode(buf)
+	if assert.IsType(t, &LimitError{}, err) {
+		assert.Equal(t, ErrSize, err.(*LimitError).Err)
+	}
+}
+
+func TestLimitsListLength(t *testing.T) {
+	proto := NewProto(SetMaxHashes(2))
+	request := &message.Request{
+		Hashes: []types.Hash{
+			{11, 12, 13},
+			{21, 22, 23},
+			{31, 32, 33},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	err := proto.Encode(buf, request)
+	assert.Nil(t, err)
+
+	_, err = proto.Decode(buf)
+	if assert.IsType(t, &LimitError{}, err) {
+		assert.Equal(t, ErrListLength, err.(*LimitError).Err)
+	}
+}
+
+func TestLimitsNested(t *testing.T) {
+	proto := NewProto(SetMaxSignatures(1))
+	batch := &message.Batch{
+		Transactions: []*types.Transaction{
+			{
+				Signatures: [][]byte{{17, 18, 19}, {27, 28, 29}},
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	err := proto.Encode(buf, batch)
+	assert.Nil(t, err)
+
+	_, err = proto.Decode(buf)
+	if assert.IsType(t, &LimitError{}, errors.Cause(err)) {
+		assert.Equal(t, ErrListLength, errors.Cause(err).(*LimitError).Err)
+	}
+}
+
+func TestLimitsDataSize(t *testing.T) {
+	proto := NewProto(SetMaxData(16))
+	tx := &types.Transaction{
+		Data: make([]byte, 17),
+	}
+
+	buf := &bytes.Buffer{}
+	err := proto.Encode(buf, tx)
+	assert.Nil(t, err)
+
+	_, err = proto.Decode(buf)
+	if assert.IsType(t, &LimitError{}, err) {
+		assert.Equal(t, ErrDataSize, err.(*LimitError).Err)
+	}
+}
+
+func TestLimitsBloom(t *testing.T) {
+	proto := NewProto()
+
+	vectors := map[string]struct {
+		m      uint64
+		length uint64
+		words  int
+		err    error
+	}{
+		"valid":    {m: 128, length: 128, words: 2},
+		"mismatch": {m: 64, length: 128, words: 2, err: ErrBloom},
+		"header":   {m: 1 << 33, length: 1 << 33, words: 0, err: ErrDataSize},
+		"short":    {m: 129, length: 129, words: 2, err: ErrDataSize},
+	}
+
+	for name, vector := range vectors {
+		data := make([]byte, 24+8*vector.words)
+		binary.BigEndian.PutUint64(data[0:8], vector.m)
+		binary.BigEndian.PutUint64(data[8:16], 3)
+		binary.BigEndian.PutUint64(data[16:24], vector.length)
+
+		msg, seg, err := capnp.NewMessage(capnp.SingleSegment(nil))
+		assert.Nil(t, err)
+		z, err := NewRootZ(seg)
+		assert.Nil(t, err)
+		mempool, err := z.NewMempool()
+		assert.Nil(t, err)
+		err = mempool.SetBloom(data)
+		assert.Nil(t, err)
+		buf := &bytes.Buffer{}
+		err = capnp.NewEncoder(buf).Encode(msg)
+		assert.Nil(t, err)
+
+		_, err = proto.Decode(buf)
+		if vector.err == nil {
+			assert.Nil(t, err, name)
+			continue
+		}
+		if assert.IsType(t, &LimitError{}, err, name) {
+			assert.Equal(t, vector.err, err.(*LimitError).Err, name)
+		}
+	}
+}
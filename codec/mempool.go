@@ -19,12 +19,13 @@ package codec
 
 import (
 	"bytes"
+	"encoding/binary"
 
 	"github.com/pkg/errors"
 	"github.com/willf/bloom"
 	capnp "zombiezen.com/go/capnproto2"
 
-	"github.com/alvalor/alvalor-go/node"
+	"github.com/alvalor/alvalor-go/node/handlers/message"
 )
 
 type initMempool func() (Mempool, error)
@@ -37,7 +38,7 @@ func readRootMempool(z Z) initMempool {
 	return z.Mempool
 }
 
-func encodeMempool(seg *capnp.Segment, create initMempool, e *node.Mempool) (Mempool, error) {
+func encodeMempool(seg *capnp.Segment, create initMempool, e *message.Mempool) (Mempool, error) {
 	mempool, err := create()
 	if err != nil {
 		return Mempool{}, errors.Wrap(err, "could not create mempool")
@@ -54,7 +55,7 @@ func encodeMempool(seg *capnp.Segment, create initMempool, e *node.Mempool) (Mem
 	return mempool, nil
 }
 
-func decodeMempool(read initMempool) (*node.Mempool, error) {
+func decodeMempool(read initMempool, cfg *Config) (*message.Mempool, error) {
 	mempool, err := read()
 	if err != nil {
 		return nil, errors.Wrap(err, "could not read mempool")
@@ -63,14 +64,44 @@ func decodeMempool(read initMempool) (*node.Mempool, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "could not get bloom")
 	}
+	err = check(ErrDataSize, "bloom", cfg.maxBloom, uint64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	err = checkBloom(data)
+	if err != nil {
+		return nil, err
+	}
 	buf := bytes.NewBuffer(data)
 	bloom := &bloom.BloomFilter{}
 	_, err = bloom.ReadFrom(buf)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not decode bloom")
 	}
-	e := &node.Mempool{
+	e := &message.Mempool{
 		Bloom: bloom,
 	}
 	return e, nil
 }
+
+// checkBloom parses the header of an encoded bloom filter, so that we can make
+// sure its bitset fits into the payload before the decoder allocates it.
+func checkBloom(data []byte) error {
+	if len(data) < 24 {
+		return &LimitError{Err: ErrDataSize, Field: "bloom header", Limit: uint64(len(data)), Actual: 24}
+	}
+	m := binary.BigEndian.Uint64(data[0:8])
+	length := binary.BigEndian.Uint64(data[16:24])
+	if length != m {
+		return &LimitError{Err: ErrBloom, Field: "bloom length", Limit: m, Actual: length}
+	}
+	words := length / 64
+	if length%64 != 0 {
+		words++
+	}
+	available := uint64(len(data) - 24)
+	if words > available/8 {
+		return &LimitError{Err: ErrDataSize, Field: "bloom bitset", Limit: available, Actual: words * 8}
+	}
+	return nil
+}
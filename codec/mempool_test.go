@@ -24,7 +24,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/willf/bloom"
 
-	"github.com/alvalor/alvalor-go/node"
+	"github.com/alvalor/alvalor-go/node/handlers/message"
 )
 
 func TestMempool(t *testing.T) {
@@ -33,7 +33,7 @@ func TestMempool(t *testing.T) {
 	bloom.Add([]byte{1})
 	bloom.Add([]byte{2})
 	bloom.Add([]byte{3})
-	mempool := &node.Mempool{
+	mempool := &message.Mempool{
 		Bloom: bloom,
 	}
 
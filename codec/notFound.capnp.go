@@ -0,0 +1,100 @@
+// Code generated by capnpc-go. DO NOT EDIT.
+
+package codec
+
+import (
+	capnp "zombiezen.com/go/capnproto2"
+	text "zombiezen.com/go/capnproto2/encoding/text"
+	schemas "zombiezen.com/go/capnproto2/schemas"
+)
+
+type NotFound struct{ capnp.Struct }
+
+// NotFound_TypeID is the unique identifier for the type NotFound.
+const NotFound_TypeID = 0x9ab518e2657d6967
+
+func NewNotFound(s *capnp.Segment) (NotFound, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1})
+	return NotFound{st}, err
+}
+
+func NewRootNotFound(s *capnp.Segment) (NotFound, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1})
+	return NotFound{st}, err
+}
+
+func ReadRootNotFound(msg *capnp.Message) (NotFound, error) {
+	root, err := msg.RootPtr()
+	return NotFound{root.Struct()}, err
+}
+
+func (s NotFound) String() string {
+	str, _ := text.Marshal(0x9ab518e2657d6967, s.Struct)
+	return str
+}
+
+func (s NotFound) Hashes() (capnp.DataList, error) {
+	p, err := s.Struct.Ptr(0)
+	return capnp.DataList{List: p.List()}, err
+}
+
+func (s NotFound) HasHashes() bool {
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s NotFound) SetHashes(v capnp.DataList) error {
+	return s.Struct.SetPtr(0, v.List.ToPtr())
+}
+
+// NewHashes sets the hashes field to a newly
+// allocated capnp.DataList, preferring placement in s's segment.
+func (s NotFound) NewHashes(n int32) (capnp.DataList, error) {
+	l, err := capnp.NewDataList(s.Struct.Segment(), n)
+	if err != nil {
+		return capnp.DataList{}, err
+	}
+	err = s.Struct.SetPtr(0, l.List.ToPtr())
+	return l, err
+}
+
+// NotFound_List is a list of NotFound.
+type NotFound_List struct{ capnp.List }
+
+// NewNotFound creates a new list of NotFound.
+func NewNotFound_List(s *capnp.Segment, sz int32) (NotFound_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1}, sz)
+	return NotFound_List{l}, err
+}
+
+func (s NotFound_List) At(i int) NotFound { return NotFound{s.List.Struct(i)} }
+
+func (s NotFound_List) Set(i int, v NotFound) error { return s.List.SetStruct(i, v.Struct) }
+
+func (s NotFound_List) String() string {
+	str, _ := text.MarshalList(0x9ab518e2657d6967, s.List)
+	return str
+}
+
+// NotFound_Promise is a wrapper for a NotFound promised by a client call.
+type NotFound_Promise struct{ *capnp.Pipeline }
+
+func (p NotFound_Promise) Struct() (NotFound, error) {
+	s, err := p.Pipeline.Struct()
+	return NotFound{s}, err
+}
+
+const schema_b6bad2a56370e4e8 = "x\xda\x12\xd0r`\x12d\x8dg`\x08dae\xfb" +
+	"\x9f\x9eY\x9b\xfaHb\xeb,\x06A~\xc6\xff/\x9e" +
+	"\x14$/\xbd\xb4k\x1b\x03+#;\x03\x83\xa0\xe8!" +
+	"AYv0\xb2g`\x10\xccd\xff\x9f\x97_\xe2\x96" +
+	"_\x9a\x97\xc2\xa4\x97\x9cX\x90W`\xe5\x07\xe53\x04" +
+	"02\x0602\x05\xb20\xb300\xb0020\x08" +
+	"\xf2Z\x09\xf2\xb2\x07\xf203\x06j01\xdag$" +
+	"\x16g\xa4\x16\x07021\xf210\x06032\xf2" +
+	"2\x80\x99\x0e\x8c\x80\x01\x00VX\x1fg"
+
+func init() {
+	schemas.Register(schema_b6bad2a56370e4e8,
+		0x9ab518e2657d6967)
+}
@@ -0,0 +1,101 @@
+// Code generated by capnpc-go. DO NOT EDIT.
+
+package codec
+
+import (
+	capnp "zombiezen.com/go/capnproto2"
+	text "zombiezen.com/go/capnproto2/encoding/text"
+	schemas "zombiezen.com/go/capnproto2/schemas"
+)
+
+type Path struct{ capnp.Struct }
+
+// Path_TypeID is the unique identifier for the type Path.
+const Path_TypeID = 0x9633908034c1815e
+
+func NewPath(s *capnp.Segment) (Path, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1})
+	return Path{st}, err
+}
+
+func NewRootPath(s *capnp.Segment) (Path, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1})
+	return Path{st}, err
+}
+
+func ReadRootPath(msg *capnp.Message) (Path, error) {
+	root, err := msg.RootPtr()
+	return Path{root.Struct()}, err
+}
+
+func (s Path) String() string {
+	str, _ := text.Marshal(0x9633908034c1815e, s.Struct)
+	return str
+}
+
+func (s Path) Headers() (Header_List, error) {
+	p, err := s.Struct.Ptr(0)
+	return Header_List{List: p.List()}, err
+}
+
+func (s Path) HasHeaders() bool {
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s Path) SetHeaders(v Header_List) error {
+	return s.Struct.SetPtr(0, v.List.ToPtr())
+}
+
+// NewHeaders sets the headers field to a newly
+// allocated Header_List, preferring placement in s's segment.
+func (s Path) NewHeaders(n int32) (Header_List, error) {
+	l, err := NewHeader_List(s.Struct.Segment(), n)
+	if err != nil {
+		return Header_List{}, err
+	}
+	err = s.Struct.SetPtr(0, l.List.ToPtr())
+	return l, err
+}
+
+// Path_List is a list of Path.
+type Path_List struct{ capnp.List }
+
+// NewPath creates a new list of Path.
+func NewPath_List(s *capnp.Segment, sz int32) (Path_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 0, PointerCount: 1}, sz)
+	return Path_List{l}, err
+}
+
+func (s Path_List) At(i int) Path { return Path{s.List.Struct(i)} }
+
+func (s Path_List) Set(i int, v Path) error { return s.List.SetStruct(i, v.Struct) }
+
+func (s Path_List) String() string {
+	str, _ := text.MarshalList(0x9633908034c1815e, s.List)
+	return str
+}
+
+// Path_Promise is a wrapper for a Path promised by a client call.
+type Path_Promise struct{ *capnp.Pipeline }
+
+func (p Path_Promise) Struct() (Path, error) {
+	s, err := p.Pipeline.Struct()
+	return Path{s}, err
+}
+
+const schema_e33177c778a48999 = "x\xda\x12\xd0v`\x12d\x8dg`\x08dae\xfb" +
+	"\x1f\xd7x\xd0\xa4a\x82\xf14\x06An\xc6\xff3;" +
+	"\x97T\x1c/7|\xcc\xc0\xca\xc8\xce\xc0 (\xda$" +
+	"(\xc9\x0eF\xf6\x0c\x0c\x82\xb9\xec\xff\x0b\x12K2\xf4" +
+	"\x92\x13\x0b\x18\xf3\x0a\xac\x02\x12K2\x18\x02\x18\x19\x03" +
+	"\x18\x99\x02Y\x98Y\x18\x18X\x18\x19\x18\x04y\x9d\x04" +
+	"y\xd9\x03y\x98\x19\x03\x0d\x98\x18\xeb3R\x13SR" +
+	"\x8b\x8a\x03\x18\x99\x18\xf9\x18\x18\x03\x98\x19\x19\x05\xfe\x1f" +
+	"\x0cy\xbf\xc3+\xf1\xdbq\x06\x06\x06\x07FAF\xf6" +
+	"\x00&F\x90\xa4\x03#`\x00\xd91\"\x18"
+
+func init() {
+	schemas.Register(schema_e33177c778a48999,
+		0x9633908034c1815e)
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package codec
+
+import (
+	"github.com/pkg/errors"
+	capnp "zombiezen.com/go/capnproto2"
+
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+type initPath func() (Path, error)
+
+func createRootPath(z Z) initPath {
+	return z.NewPath
+}
+
+func readRootPath(z Z) initPath {
+	return z.Path
+}
+
+func encodePath(seg *capnp.Segment, create initPath, e *message.Path) (Path, error) {
+	path, err := create()
+	if err != nil {
+		return Path{}, errors.Wrap(err, "could not create path")
+	}
+	headers, err := path.NewHeaders(int32(len(e.Headers)))
+	if err != nil {
+		return Path{}, errors.Wrap(err, "could not create header list")
+	}
+	for i, h := range e.Headers {
+		var header Header
+		header, err = encodeHeader(seg, createChildHeader(seg), h)
+		if err != nil {
+			return Path{}, errors.Wrap(err, "could not encode header")
+		}
+		err = headers.Set(i, header)
+		if err != nil {
+			return Path{}, errors.Wrap(err, "could not set header")
+		}
+	}
+	return path, nil
+}
+
+func decodePath(read initPath, cfg *Config) (*message.Path, error) {
+	path, err := read()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read path")
+	}
+	headers, err := path.Headers()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read header list")
+	}
+	err = check(ErrListLength, "headers", cfg.maxHeaders, uint64(headers.Len()))
+	if err != nil {
+		return nil, err
+	}
+	e := &message.Path{
+		Headers: make([]*types.Header, 0, headers.Len()),
+	}
+	for i := 0; i < headers.Len(); i++ {
+		header := headers.At(i)
+		h, err := decodeHeader(readChildHeader(header))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not decode header")
+		}
+		e.Headers = append(e.Headers, h)
+	}
+	return e, nil
+}
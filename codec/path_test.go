@@ -0,0 +1,65 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package codec
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+func TestPath(t *testing.T) {
+	proto := &Proto{}
+	path := &message.Path{
+		Headers: []*types.Header{
+			{
+				Hash:   types.Hash{1},
+				Parent: types.Hash{2},
+				State:  types.Hash{3},
+				Delta:  types.Hash{4},
+				Miner:  types.Hash{5},
+				Diff:   6,
+				Nonce:  7,
+				Time:   time.Time{},
+			},
+			{
+				Hash:   types.Hash{11},
+				Parent: types.Hash{1},
+				State:  types.Hash{13},
+				Delta:  types.Hash{14},
+				Miner:  types.Hash{15},
+				Diff:   16,
+				Nonce:  17,
+				Time:   time.Unix(18, 19).UTC(),
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	err := proto.Encode(buf, path)
+	assert.Nil(t, err)
+
+	msg, err := proto.Decode(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, path, msg)
+}
@@ -52,7 +52,7 @@ func encodePeers(seg *capnp.Segment, create initPeers, e *network.Peers) (Peers,
 	return peers, nil
 }
 
-func decodePeers(read initPeers) (*network.Peers, error) {
+func decodePeers(read initPeers, cfg *Config) (*network.Peers, error) {
 	peers, err := read()
 	if err != nil {
 		return nil, errors.Wrap(err, "could not read peers")
@@ -61,6 +61,10 @@ func decodePeers(read initPeers) (*network.Peers, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "could not read address list")
 	}
+	err = check(ErrListLength, "addresses", cfg.maxAddresses, uint64(addresses.Len()))
+	if err != nil {
+		return nil, err
+	}
 	e := &network.Peers{
 		Addresses: make([]string, 0, addresses.Len()),
 	}
@@ -0,0 +1,185 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package codec
+
+import (
+	"encoding/binary"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+	capnp "zombiezen.com/go/capnproto2"
+)
+
+// maxArena is the maximum capacity of an arena we keep around for reuse, so
+// that a few huge messages don't keep large buffers in the pool.
+const maxArena = 1 << 20
+
+// maxStreamSegments is the maximum number of segments the capnproto decoder
+// accepts; it also applies when the configured limit is zero or larger.
+const maxStreamSegments = 512
+
+// maxPeek is the maximum offset of the wrapper struct data we peek at before
+// decoding, which our encoder always places right after the root pointer.
+const maxPeek = 64
+
+// encoders and decoders are pools of reusable encoding and decoding states, so
+// that relaying messages doesn't allocate new buffers for every single one.
+var (
+	encoders = sync.Pool{
+		New: func() interface{} {
+			return newEncoderState()
+		},
+	}
+	decoders = sync.Pool{
+		New: func() interface{} {
+			return newDecoderState()
+		},
+	}
+)
+
+// encoderState holds the arena we build messages in and the capnproto encoder
+// writing them to the current writer.
+type encoderState struct {
+	arena []byte
+	w     io.Writer
+	enc   *capnp.Encoder
+}
+
+func newEncoderState() *encoderState {
+	es := &encoderState{
+		arena: make([]byte, 0, 4096),
+	}
+	es.enc = capnp.NewEncoder(es)
+	return es
+}
+
+func (es *encoderState) Write(p []byte) (int, error) {
+	return es.w.Write(p)
+}
+
+// decoderState holds the capnproto decoder and its buffer, as well as the
+// stream header of the current message, which is replayed to the decoder after
+// we checked it against our limits.
+type decoderState struct {
+	r      io.Reader
+	header []byte
+	replay []byte
+	dec    *capnp.Decoder
+}
+
+func newDecoderState() *decoderState {
+	ds := &decoderState{
+		header: make([]byte, 0, 64),
+	}
+	ds.dec = capnp.NewDecoder(ds)
+	ds.dec.ReuseBuffer()
+	return ds
+}
+
+func (ds *decoderState) Read(p []byte) (int, error) {
+	if len(ds.replay) > 0 {
+		n := copy(p, ds.replay)
+		ds.replay = ds.replay[n:]
+		return n, nil
+	}
+	return ds.r.Read(p)
+}
+
+// frame reads the stream header of the next message, which announces the size
+// of each segment, and checks it against our limits. It returns the total size
+// of the segments and prepares the header to be replayed to the decoder.
+func (ds *decoderState) frame(cfg *Config) (uint64, error) {
+	ds.header = ds.header[:4]
+	_, err := io.ReadFull(ds.r, ds.header)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not read segment count")
+	}
+	segments := uint64(binary.LittleEndian.Uint32(ds.header)) + 1
+	limit := cfg.maxSegments
+	if limit == 0 || limit > maxStreamSegments {
+		limit = maxStreamSegments
+	}
+	err = check(ErrSegments, "segments", limit, segments)
+	if err != nil {
+		return 0, err
+	}
+	length := (4 + 4*segments + 7) &^ 7
+	if uint64(cap(ds.header)) < length {
+		header := make([]byte, length)
+		copy(header, ds.header)
+		ds.header = header
+	}
+	ds.header = ds.header[:length]
+	_, err = io.ReadFull(ds.r, ds.header[4:])
+	if err != nil {
+		return 0, errors.Wrap(err, "could not read segment sizes")
+	}
+	var size uint64
+	for i := uint64(0); i < segments; i++ {
+		size += uint64(binary.LittleEndian.Uint32(ds.header[4+4*i:])) * 8
+	}
+	err = check(ErrSize, "message", cfg.maxSize, size)
+	if err != nil {
+		return 0, err
+	}
+	ds.replay = ds.header
+	return size, nil
+}
+
+// peek reads the start of the first segment up to the data of the wrapper
+// struct and returns the message type from its union discriminant, so that we
+// can check the size limit of the type before decoding the message. The bytes
+// are appended to the stream header to be replayed to the decoder.
+func (ds *decoderState) peek() (Z_Which, error) {
+	first := uint64(binary.LittleEndian.Uint32(ds.header[4:])) * 8
+	length := len(ds.header)
+	ds.header = append(ds.header, make([]byte, 8)...)
+	_, err := io.ReadFull(ds.r, ds.header[length:])
+	if err != nil {
+		return 0, errors.Wrap(err, "could not read root pointer")
+	}
+	root := binary.LittleEndian.Uint64(ds.header[length:])
+	offset := int32(uint32(root)) >> 2
+	if root&3 != 0 || offset < 0 || root>>32&0xffff == 0 {
+		return 0, errors.New("invalid root pointer")
+	}
+	end := 8 + 8*uint64(offset) + 8
+	if end > maxPeek || end > first {
+		return 0, errors.New("invalid root pointer")
+	}
+	ds.header = append(ds.header, make([]byte, end-8)...)
+	_, err = io.ReadFull(ds.r, ds.header[length+8:])
+	if err != nil {
+		return 0, errors.Wrap(err, "could not read wrapper")
+	}
+	which := Z_Which(binary.LittleEndian.Uint16(ds.header[length+int(end)-8:]))
+	ds.replay = ds.header
+	return which, nil
+}
+
+// clone copies a byte slice, so that decoded entities don't refer to the
+// buffer of a decoder that is reused.
+func clone(data []byte) []byte {
+	if data == nil {
+		return nil
+	}
+	c := make([]byte, len(data))
+	copy(c, data)
+	return c
+}
@@ -24,22 +24,31 @@ import (
 	capnp "zombiezen.com/go/capnproto2"
 
 	"github.com/alvalor/alvalor-go/network"
-	"github.com/alvalor/alvalor-go/node"
+	"github.com/alvalor/alvalor-go/node/handlers/message"
 	"github.com/alvalor/alvalor-go/types"
 )
 
 // Proto represents the capnproto serialization module.
-type Proto struct{}
+type Proto struct {
+	cfg Config
+}
 
-// NewProto will return a new proto codec.
-func NewProto() Proto {
-	return Proto{}
+// NewProto will return a new proto codec, which uses the default decoding
+// limits, unless they are modified by the given options.
+func NewProto(options ...func(*Config)) Proto {
+	cfg := DefaultConfig()
+	for _, option := range options {
+		option(&cfg)
+	}
+	return Proto{cfg: cfg}
 }
 
 // Encode will serialize the provided entity by writing the binary format into the provided writer.
 // It will fail if the entity type is unknown.
 func (p Proto) Encode(w io.Writer, entity interface{}) error {
-	msg, seg, err := capnp.NewMessage(capnp.SingleSegment(nil))
+	es := encoders.Get().(*encoderState)
+	defer encoders.Put(es)
+	msg, seg, err := capnp.NewMessage(capnp.SingleSegment(es.arena[:0]))
 	if err != nil {
 		return errors.Wrap(err, "could not create message")
 	}
@@ -58,30 +67,77 @@ func (p Proto) Encode(w io.Writer, entity interface{}) error {
 		_, err = encodePeers(seg, createRootPeers(z), e)
 	case *types.Transaction:
 		_, err = encodeTransaction(seg, createRootTransaction(z), e)
-	case *node.Mempool:
+	case *message.Mempool:
 		_, err = encodeMempool(seg, createRootMempool(z), e)
-	case *node.Inventory:
+	case *types.Inventory:
 		_, err = encodeInventory(seg, createRootInventory(z), e)
-	case *node.Request:
+	case *message.Request:
 		_, err = encodeRequest(seg, createRootRequest(z), e)
-	case *node.Batch:
+	case *message.Batch:
 		_, err = encodeBatch(seg, createRootBatch(z), e)
+	case *message.Status:
+		_, err = encodeStatus(seg, createRootStatus(z), e)
+	case *message.GetHeaders:
+		_, err = encodeGetHeaders(seg, createRootGetHeaders(z), e)
+	case *message.Path:
+		_, err = encodePath(seg, createRootPath(z), e)
+	case *message.GetInv:
+		_, err = encodeGetInv(seg, createRootGetInv(z), e)
+	case *message.GetTx:
+		_, err = encodeGetTx(seg, createRootGetTx(z), e)
+	case *message.NotFound:
+		_, err = encodeNotFound(seg, createRootNotFound(z), e)
+	case *message.Announce:
+		_, err = encodeAnnounce(seg, createRootAnnounce(z), e)
+	case *message.Compact:
+		_, err = encodeCompact(seg, createRootCompact(z), e)
+	case *message.GetBlockTxs:
+		_, err = encodeGetBlockTxs(seg, createRootGetBlockTxs(z), e)
+	case *message.BlockTxs:
+		_, err = encodeBlockTxs(seg, createRootBlockTxs(z), e)
 	default:
 		return errors.Errorf("unknown message type (%T)", e)
 	}
 	if err != nil {
 		return err
 	}
-	err = capnp.NewEncoder(w).Encode(msg)
+	es.w = w
+	err = es.enc.Encode(msg)
+	es.w = nil
 	if err != nil {
 		return errors.Wrap(err, "could not encode message")
 	}
+	seg, err = msg.Segment(0)
+	if err == nil && cap(seg.Data()) <= maxArena {
+		es.arena = seg.Data()[:0]
+	}
 	return nil
 }
 
 // Decode will decode the binary data of the given reader into the original entity.
+// The size of the message is checked against the configured limits, including
+// the limit for its type, before we read its body, and list lengths are checked
+// before we allocate any entities.
+// Decoders and their buffers are reused, so the decoded entities never refer to
+// the message data directly.
 func (p Proto) Decode(r io.Reader) (interface{}, error) {
-	msg, err := capnp.NewDecoder(r).Decode()
+	ds := decoders.Get().(*decoderState)
+	defer decoders.Put(ds)
+	ds.r = r
+	defer func() { ds.r = nil }()
+	size, err := ds.frame(&p.cfg)
+	if err != nil {
+		return nil, err
+	}
+	which, err := ds.peek()
+	if err != nil {
+		return nil, err
+	}
+	err = check(ErrSize, which.String(), p.cfg.typeSizes[which], size)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := ds.dec.Decode()
 	if err != nil {
 		return nil, errors.Wrap(err, "could not decode message")
 	}
@@ -97,17 +153,37 @@ func (p Proto) Decode(r io.Reader) (interface{}, error) {
 	case Z_Which_discover:
 		return decodeDiscover(readRootDiscover(z))
 	case Z_Which_peers:
-		return decodePeers(readRootPeers(z))
+		return decodePeers(readRootPeers(z), &p.cfg)
 	case Z_Which_transaction:
-		return decodeTransaction(readRootTransaction(z))
+		return decodeTransaction(readRootTransaction(z), &p.cfg)
 	case Z_Which_mempool:
-		return decodeMempool(readRootMempool(z))
+		return decodeMempool(readRootMempool(z), &p.cfg)
 	case Z_Which_inventory:
-		return decodeInventory(readRootInventory(z))
+		return decodeInventory(readRootInventory(z), &p.cfg)
 	case Z_Which_request:
-		return decodeRequest(readRootRequest(z))
+		return decodeRequest(readRootRequest(z), &p.cfg)
 	case Z_Which_batch:
-		return decodeBatch(readRootBatch(z))
+		return decodeBatch(readRootBatch(z), &p.cfg)
+	case Z_Which_status:
+		return decodeStatus(readRootStatus(z))
+	case Z_Which_getHeaders:
+		return decodeGetHeaders(readRootGetHeaders(z), &p.cfg)
+	case Z_Which_path:
+		return decodePath(readRootPath(z), &p.cfg)
+	case Z_Which_getInv:
+		return decodeGetInv(readRootGetInv(z))
+	case Z_Which_getTx:
+		return decodeGetTx(readRootGetTx(z))
+	case Z_Which_notFound:
+		return decodeNotFound(readRootNotFound(z), &p.cfg)
+	case Z_Which_announce:
+		return decodeAnnounce(readRootAnnounce(z), &p.cfg)
+	case Z_Which_compact:
+		return decodeCompact(readRootCompact(z), &p.cfg)
+	case Z_Which_getBlockTxs:
+		return decodeGetBlockTxs(readRootGetBlockTxs(z), &p.cfg)
+	case Z_Which_blockTxs:
+		return decodeBlockTxs(readRootBlockTxs(z), &p.cfg)
 	default:
 		return nil, errors.Errorf("unknown message code (%v)", z.Which())
 	}
@@ -21,7 +21,7 @@ import (
 	"github.com/pkg/errors"
 	capnp "zombiezen.com/go/capnproto2"
 
-	"github.com/alvalor/alvalor-go/node"
+	"github.com/alvalor/alvalor-go/node/handlers/message"
 	"github.com/alvalor/alvalor-go/types"
 )
 
@@ -35,7 +35,7 @@ func readRootRequest(z Z) initRequest {
 	return z.Request
 }
 
-func encodeRequest(seg *capnp.Segment, create initRequest, e *node.Request) (Request, error) {
+func encodeRequest(seg *capnp.Segment, create initRequest, e *message.Request) (Request, error) {
 	request, err := create()
 	if err != nil {
 		return Request{}, errors.Wrap(err, "could not create request")
@@ -53,7 +53,7 @@ func encodeRequest(seg *capnp.Segment, create initRequest, e *node.Request) (Req
 	return request, nil
 }
 
-func decodeRequest(read initRequest) (*node.Request, error) {
+func decodeRequest(read initRequest, cfg *Config) (*message.Request, error) {
 	request, err := read()
 	if err != nil {
 		return nil, errors.Wrap(err, "could not read request")
@@ -62,7 +62,11 @@ func decodeRequest(read initRequest) (*node.Request, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "could not read hash list")
 	}
-	e := &node.Request{
+	err = check(ErrListLength, "hashes", cfg.maxHashes, uint64(hashes.Len()))
+	if err != nil {
+		return nil, err
+	}
+	e := &message.Request{
 		Hashes: make([]types.Hash, hashes.Len()),
 	}
 	for i := 0; i < hashes.Len(); i++ {
@@ -23,13 +23,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
-	"github.com/alvalor/alvalor-go/node"
+	"github.com/alvalor/alvalor-go/node/handlers/message"
 	"github.com/alvalor/alvalor-go/types"
 )
 
 func TestRequest(t *testing.T) {
 	proto := &Proto{}
-	request := &node.Request{
+	request := &message.Request{
 		Hashes: []types.Hash{
 			{11, 12, 13},
 			{21, 22, 23},
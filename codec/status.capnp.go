@@ -0,0 +1,125 @@
+// Code generated by capnpc-go. DO NOT EDIT.
+
+package codec
+
+import (
+	capnp "zombiezen.com/go/capnproto2"
+	text "zombiezen.com/go/capnproto2/encoding/text"
+	schemas "zombiezen.com/go/capnproto2/schemas"
+)
+
+type Status struct{ capnp.Struct }
+
+// Status_TypeID is the unique identifier for the type Status.
+const Status_TypeID = 0x8d2186dd0520c10c
+
+func NewStatus(s *capnp.Segment) (Status, error) {
+	st, err := capnp.NewStruct(s, capnp.ObjectSize{DataSize: 16, PointerCount: 2})
+	return Status{st}, err
+}
+
+func NewRootStatus(s *capnp.Segment) (Status, error) {
+	st, err := capnp.NewRootStruct(s, capnp.ObjectSize{DataSize: 16, PointerCount: 2})
+	return Status{st}, err
+}
+
+func ReadRootStatus(msg *capnp.Message) (Status, error) {
+	root, err := msg.RootPtr()
+	return Status{root.Struct()}, err
+}
+
+func (s Status) String() string {
+	str, _ := text.Marshal(0x8d2186dd0520c10c, s.Struct)
+	return str
+}
+
+func (s Status) Distance() uint64 {
+	return s.Struct.Uint64(0)
+}
+
+func (s Status) SetDistance(v uint64) {
+	s.Struct.SetUint64(0, v)
+}
+
+func (s Status) Hash() ([]byte, error) {
+	p, err := s.Struct.Ptr(0)
+	return []byte(p.Data()), err
+}
+
+func (s Status) HasHash() bool {
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s Status) SetHash(v []byte) error {
+	return s.Struct.SetData(0, v)
+}
+
+func (s Status) Height() uint64 {
+	return s.Struct.Uint64(8)
+}
+
+func (s Status) SetHeight(v uint64) {
+	s.Struct.SetUint64(8, v)
+}
+
+func (s Status) Genesis() ([]byte, error) {
+	p, err := s.Struct.Ptr(1)
+	return []byte(p.Data()), err
+}
+
+func (s Status) HasGenesis() bool {
+	p, err := s.Struct.Ptr(1)
+	return p.IsValid() || err != nil
+}
+
+func (s Status) SetGenesis(v []byte) error {
+	return s.Struct.SetData(1, v)
+}
+
+// Status_List is a list of Status.
+type Status_List struct{ capnp.List }
+
+// NewStatus creates a new list of Status.
+func NewStatus_List(s *capnp.Segment, sz int32) (Status_List, error) {
+	l, err := capnp.NewCompositeList(s, capnp.ObjectSize{DataSize: 16, PointerCount: 2}, sz)
+	return Status_List{l}, err
+}
+
+func (s Status_List) At(i int) Status { return Status{s.List.Struct(i)} }
+
+func (s Status_List) Set(i int, v Status) error { return s.List.SetStruct(i, v.Struct) }
+
+func (s Status_List) String() string {
+	str, _ := text.MarshalList(0x8d2186dd0520c10c, s.List)
+	return str
+}
+
+// Status_Promise is a wrapper for a Status promised by a client call.
+type Status_Promise struct{ *capnp.Pipeline }
+
+func (p Status_Promise) Struct() (Status, error) {
+	s, err := p.Pipeline.Struct()
+	return Status{s}, err
+}
+
+const schema_9eaf2c24ccbbffae = "x\xdaT\x8a1J3Q\x14\x85\xcf\xb9/\xf3?~" +
+	"0\x98\x8bc\x19P\xb0\x12q\x01i\x0c\x96Vs\xb1" +
+	"\xb0\x94G2d\x06\xcc0\xf0\xde\x80\x85A+\xb1q" +
+	"\x0b\x16.@\x17`e\xef\x16\xac]\xc6\xc8X)\x9c" +
+	"\xaf8\x1f\xdf\xe4b.\x9a]\x026\xca\xfe\xf5[\xef" +
+	"{\xd9\xe7\xfd\xfe#lL\xe9_\xfa\xb7\x8f\x83\xa3\xd7" +
+	"'d\xe2\x01\xdd}\xd6\xa9\xff\xd9\x17\xb03\xa5\xefc" +
+	"\x0a\xa9\x8b\xc7\x0b\x86\xb6ig\xe7)\xf8\xd4\xc5\x82," +
+	"(6q#`D@\xc3\x99\x96\xde\x96\x8e\xd6\x0a\xc9" +
+	"\x9c\x83\\\x1f\xea\xda\xdb\x95\xa3]\x0bU\x98S\x00\xed" +
+	"f\xdayK\x8ev'T\xc7\x9c\x0e\xd0\xcd\xa9n\xbc" +
+	"\xdd8\xda\x83\xb0_\xd61\x85fQ\x02((\xfc\x8f" +
+	"\x01nW!V\xc3\x1fc\x00J9\xa9\xcazU\xa5" +
+	"_\xd1\xed\xaal\xcaX\xc7\xbf\xdd\x9c\xdf\x03\x00\xb2\x07" +
+	"2\x17"
+
+func init() {
+	schemas.Register(schema_9eaf2c24ccbbffae,
+		0x8d2186dd0520c10c)
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package codec
+
+import (
+	"github.com/pkg/errors"
+	capnp "zombiezen.com/go/capnproto2"
+
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+)
+
+type initStatus func() (Status, error)
+
+func createRootStatus(z Z) initStatus {
+	return z.NewStatus
+}
+
+func readRootStatus(z Z) initStatus {
+	return z.Status
+}
+
+func encodeStatus(seg *capnp.Segment, create initStatus, e *message.Status) (Status, error) {
+	status, err := create()
+	if err != nil {
+		return Status{}, errors.Wrap(err, "could not create status")
+	}
+	err = status.SetHash(e.Hash[:])
+	if err != nil {
+		return Status{}, errors.Wrap(err, "could not set hash")
+	}
+	err = status.SetGenesis(e.Genesis[:])
+	if err != nil {
+		return Status{}, errors.Wrap(err, "could not set genesis")
+	}
+	status.SetHeight(e.Height)
+	status.SetDistance(e.Distance)
+	return status, nil
+}
+
+func decodeStatus(read initStatus) (*message.Status, error) {
+	status, err := read()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read status")
+	}
+	hash, err := status.Hash()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read hash")
+	}
+	genesis, err := status.Genesis()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read genesis")
+	}
+	e := &message.Status{
+		Height:   status.Height(),
+		Distance: status.Distance(),
+	}
+	copy(e.Hash[:], hash)
+	copy(e.Genesis[:], genesis)
+	return e, nil
+}
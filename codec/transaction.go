@@ -100,7 +100,7 @@ func encodeTransaction(seg *capnp.Segment, init initTransaction, e *types.Transa
 	return transaction, nil
 }
 
-func decodeTransaction(read initTransaction) (*types.Transaction, error) {
+func decodeTransaction(read initTransaction, cfg *Config) (*types.Transaction, error) {
 	transaction, err := read()
 	if err != nil {
 		return nil, errors.Wrap(err, "could not read transaction")
@@ -109,22 +109,38 @@ func decodeTransaction(read initTransaction) (*types.Transaction, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "could not read transfer list")
 	}
+	err = check(ErrListLength, "transfers", cfg.maxTransfers, uint64(transfers.Len()))
+	if err != nil {
+		return nil, err
+	}
 	fees, err := transaction.Fees()
 	if err != nil {
 		return nil, errors.Wrap(err, "could not read fee list")
 	}
+	err = check(ErrListLength, "fees", cfg.maxFees, uint64(fees.Len()))
+	if err != nil {
+		return nil, err
+	}
 	data, err := transaction.Data()
 	if err != nil {
 		return nil, errors.Wrap(err, "could not read data")
 	}
+	err = check(ErrDataSize, "data", cfg.maxData, uint64(len(data)))
+	if err != nil {
+		return nil, err
+	}
 	signatures, err := transaction.Signatures()
 	if err != nil {
 		return nil, errors.Wrap(err, "could not read signature list")
 	}
+	err = check(ErrListLength, "signatures", cfg.maxSignatures, uint64(signatures.Len()))
+	if err != nil {
+		return nil, err
+	}
 	e := &types.Transaction{
 		Transfers:  make([]*types.Transfer, 0, transfers.Len()),
 		Fees:       make([]*types.Fee, 0, fees.Len()),
-		Data:       data,
+		Data:       clone(data),
 		Nonce:      transaction.Nonce(),
 		Signatures: make([][]byte, 0, signatures.Len()),
 	}
@@ -149,7 +165,11 @@ func decodeTransaction(read initTransaction) (*types.Transaction, error) {
 		if err != nil {
 			return nil, errors.Wrap(err, "could not get signature")
 		}
-		e.Signatures = append(e.Signatures, signature)
+		err = check(ErrDataSize, "signature", cfg.maxSignature, uint64(len(signature)))
+		if err != nil {
+			return nil, err
+		}
+		e.Signatures = append(e.Signatures, clone(signature))
 	}
 	return e, nil
 }
@@ -71,8 +71,8 @@ func decodeTransfer(read initTransfer) (*types.Transfer, error) {
 	}
 	amount := transfer.Amount()
 	e := &types.Transfer{
-		From:   from,
-		To:     to,
+		From:   clone(from),
+		To:     clone(to),
 		Amount: amount,
 	}
 	return e, nil
@@ -22,10 +22,20 @@ const (
 	Z_Which_inventory   Z_Which = 6
 	Z_Which_request     Z_Which = 7
 	Z_Which_batch       Z_Which = 8
+	Z_Which_status      Z_Which = 9
+	Z_Which_getHeaders  Z_Which = 10
+	Z_Which_path        Z_Which = 11
+	Z_Which_getInv      Z_Which = 12
+	Z_Which_getTx       Z_Which = 13
+	Z_Which_notFound    Z_Which = 14
+	Z_Which_announce    Z_Which = 15
+	Z_Which_compact     Z_Which = 16
+	Z_Which_getBlockTxs Z_Which = 17
+	Z_Which_blockTxs    Z_Which = 18
 )
 
 func (w Z_Which) String() string {
-	const s = "pingpongdiscoverpeerstransactionmempoolinventoryrequestbatch"
+	const s = "pingpongdiscoverpeerstransactionmempoolinventoryrequestbatchstatusgetHeaderspathgetInvgetTxnotFoundannouncecompactgetBlockTxsblockTxs"
 	switch w {
 	case Z_Which_ping:
 		return s[0:4]
@@ -45,6 +55,26 @@ func (w Z_Which) String() string {
 		return s[48:55]
 	case Z_Which_batch:
 		return s[55:60]
+	case Z_Which_status:
+		return s[60:66]
+	case Z_Which_getHeaders:
+		return s[66:76]
+	case Z_Which_path:
+		return s[76:80]
+	case Z_Which_getInv:
+		return s[80:86]
+	case Z_Which_getTx:
+		return s[86:91]
+	case Z_Which_notFound:
+		return s[91:99]
+	case Z_Which_announce:
+		return s[99:107]
+	case Z_Which_compact:
+		return s[107:114]
+	case Z_Which_getBlockTxs:
+		return s[114:125]
+	case Z_Which_blockTxs:
+		return s[125:133]
 
 	}
 	return "Z_Which(" + strconv.FormatUint(uint64(w), 10) + ")"
@@ -373,6 +403,336 @@ func (s Z) NewBatch() (Batch, error) {
 	return ss, err
 }
 
+func (s Z) Status() (Status, error) {
+	if s.Struct.Uint16(0) != 9 {
+		panic("Which() != status")
+	}
+	p, err := s.Struct.Ptr(0)
+	return Status{Struct: p.Struct()}, err
+}
+
+func (s Z) HasStatus() bool {
+	if s.Struct.Uint16(0) != 9 {
+		return false
+	}
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s Z) SetStatus(v Status) error {
+	s.Struct.SetUint16(0, 9)
+	return s.Struct.SetPtr(0, v.Struct.ToPtr())
+}
+
+// NewStatus sets the status field to a newly
+// allocated Status struct, preferring placement in s's segment.
+func (s Z) NewStatus() (Status, error) {
+	s.Struct.SetUint16(0, 9)
+	ss, err := NewStatus(s.Struct.Segment())
+	if err != nil {
+		return Status{}, err
+	}
+	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
+	return ss, err
+}
+
+func (s Z) GetHeaders() (GetHeaders, error) {
+	if s.Struct.Uint16(0) != 10 {
+		panic("Which() != getHeaders")
+	}
+	p, err := s.Struct.Ptr(0)
+	return GetHeaders{Struct: p.Struct()}, err
+}
+
+func (s Z) HasGetHeaders() bool {
+	if s.Struct.Uint16(0) != 10 {
+		return false
+	}
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s Z) SetGetHeaders(v GetHeaders) error {
+	s.Struct.SetUint16(0, 10)
+	return s.Struct.SetPtr(0, v.Struct.ToPtr())
+}
+
+// NewGetHeaders sets the getHeaders field to a newly
+// allocated GetHeaders struct, preferring placement in s's segment.
+func (s Z) NewGetHeaders() (GetHeaders, error) {
+	s.Struct.SetUint16(0, 10)
+	ss, err := NewGetHeaders(s.Struct.Segment())
+	if err != nil {
+		return GetHeaders{}, err
+	}
+	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
+	return ss, err
+}
+
+func (s Z) Path() (Path, error) {
+	if s.Struct.Uint16(0) != 11 {
+		panic("Which() != path")
+	}
+	p, err := s.Struct.Ptr(0)
+	return Path{Struct: p.Struct()}, err
+}
+
+func (s Z) HasPath() bool {
+	if s.Struct.Uint16(0) != 11 {
+		return false
+	}
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s Z) SetPath(v Path) error {
+	s.Struct.SetUint16(0, 11)
+	return s.Struct.SetPtr(0, v.Struct.ToPtr())
+}
+
+// NewPath sets the path field to a newly
+// allocated Path struct, preferring placement in s's segment.
+func (s Z) NewPath() (Path, error) {
+	s.Struct.SetUint16(0, 11)
+	ss, err := NewPath(s.Struct.Segment())
+	if err != nil {
+		return Path{}, err
+	}
+	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
+	return ss, err
+}
+
+func (s Z) GetInv() (GetInv, error) {
+	if s.Struct.Uint16(0) != 12 {
+		panic("Which() != getInv")
+	}
+	p, err := s.Struct.Ptr(0)
+	return GetInv{Struct: p.Struct()}, err
+}
+
+func (s Z) HasGetInv() bool {
+	if s.Struct.Uint16(0) != 12 {
+		return false
+	}
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s Z) SetGetInv(v GetInv) error {
+	s.Struct.SetUint16(0, 12)
+	return s.Struct.SetPtr(0, v.Struct.ToPtr())
+}
+
+// NewGetInv sets the getInv field to a newly
+// allocated GetInv struct, preferring placement in s's segment.
+func (s Z) NewGetInv() (GetInv, error) {
+	s.Struct.SetUint16(0, 12)
+	ss, err := NewGetInv(s.Struct.Segment())
+	if err != nil {
+		return GetInv{}, err
+	}
+	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
+	return ss, err
+}
+
+func (s Z) GetTx() (GetTx, error) {
+	if s.Struct.Uint16(0) != 13 {
+		panic("Which() != getTx")
+	}
+	p, err := s.Struct.Ptr(0)
+	return GetTx{Struct: p.Struct()}, err
+}
+
+func (s Z) HasGetTx() bool {
+	if s.Struct.Uint16(0) != 13 {
+		return false
+	}
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s Z) SetGetTx(v GetTx) error {
+	s.Struct.SetUint16(0, 13)
+	return s.Struct.SetPtr(0, v.Struct.ToPtr())
+}
+
+// NewGetTx sets the getTx field to a newly
+// allocated GetTx struct, preferring placement in s's segment.
+func (s Z) NewGetTx() (GetTx, error) {
+	s.Struct.SetUint16(0, 13)
+	ss, err := NewGetTx(s.Struct.Segment())
+	if err != nil {
+		return GetTx{}, err
+	}
+	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
+	return ss, err
+}
+
+func (s Z) NotFound() (NotFound, error) {
+	if s.Struct.Uint16(0) != 14 {
+		panic("Which() != notFound")
+	}
+	p, err := s.Struct.Ptr(0)
+	return NotFound{Struct: p.Struct()}, err
+}
+
+func (s Z) HasNotFound() bool {
+	if s.Struct.Uint16(0) != 14 {
+		return false
+	}
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s Z) SetNotFound(v NotFound) error {
+	s.Struct.SetUint16(0, 14)
+	return s.Struct.SetPtr(0, v.Struct.ToPtr())
+}
+
+// NewNotFound sets the notFound field to a newly
+// allocated NotFound struct, preferring placement in s's segment.
+func (s Z) NewNotFound() (NotFound, error) {
+	s.Struct.SetUint16(0, 14)
+	ss, err := NewNotFound(s.Struct.Segment())
+	if err != nil {
+		return NotFound{}, err
+	}
+	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
+	return ss, err
+}
+
+func (s Z) Announce() (Announce, error) {
+	if s.Struct.Uint16(0) != 15 {
+		panic("Which() != announce")
+	}
+	p, err := s.Struct.Ptr(0)
+	return Announce{Struct: p.Struct()}, err
+}
+
+func (s Z) HasAnnounce() bool {
+	if s.Struct.Uint16(0) != 15 {
+		return false
+	}
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s Z) SetAnnounce(v Announce) error {
+	s.Struct.SetUint16(0, 15)
+	return s.Struct.SetPtr(0, v.Struct.ToPtr())
+}
+
+// NewAnnounce sets the announce field to a newly
+// allocated Announce struct, preferring placement in s's segment.
+func (s Z) NewAnnounce() (Announce, error) {
+	s.Struct.SetUint16(0, 15)
+	ss, err := NewAnnounce(s.Struct.Segment())
+	if err != nil {
+		return Announce{}, err
+	}
+	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
+	return ss, err
+}
+
+func (s Z) Compact() (Compact, error) {
+	if s.Struct.Uint16(0) != 16 {
+		panic("Which() != compact")
+	}
+	p, err := s.Struct.Ptr(0)
+	return Compact{Struct: p.Struct()}, err
+}
+
+func (s Z) HasCompact() bool {
+	if s.Struct.Uint16(0) != 16 {
+		return false
+	}
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s Z) SetCompact(v Compact) error {
+	s.Struct.SetUint16(0, 16)
+	return s.Struct.SetPtr(0, v.Struct.ToPtr())
+}
+
+// NewCompact sets the compact field to a newly
+// allocated Compact struct, preferring placement in s's segment.
+func (s Z) NewCompact() (Compact, error) {
+	s.Struct.SetUint16(0, 16)
+	ss, err := NewCompact(s.Struct.Segment())
+	if err != nil {
+		return Compact{}, err
+	}
+	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
+	return ss, err
+}
+
+func (s Z) GetBlockTxs() (GetBlockTxs, error) {
+	if s.Struct.Uint16(0) != 17 {
+		panic("Which() != getBlockTxs")
+	}
+	p, err := s.Struct.Ptr(0)
+	return GetBlockTxs{Struct: p.Struct()}, err
+}
+
+func (s Z) HasGetBlockTxs() bool {
+	if s.Struct.Uint16(0) != 17 {
+		return false
+	}
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s Z) SetGetBlockTxs(v GetBlockTxs) error {
+	s.Struct.SetUint16(0, 17)
+	return s.Struct.SetPtr(0, v.Struct.ToPtr())
+}
+
+// NewGetBlockTxs sets the getBlockTxs field to a newly
+// allocated GetBlockTxs struct, preferring placement in s's segment.
+func (s Z) NewGetBlockTxs() (GetBlockTxs, error) {
+	s.Struct.SetUint16(0, 17)
+	ss, err := NewGetBlockTxs(s.Struct.Segment())
+	if err != nil {
+		return GetBlockTxs{}, err
+	}
+	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
+	return ss, err
+}
+
+func (s Z) BlockTxs() (BlockTxs, error) {
+	if s.Struct.Uint16(0) != 18 {
+		panic("Which() != blockTxs")
+	}
+	p, err := s.Struct.Ptr(0)
+	return BlockTxs{Struct: p.Struct()}, err
+}
+
+func (s Z) HasBlockTxs() bool {
+	if s.Struct.Uint16(0) != 18 {
+		return false
+	}
+	p, err := s.Struct.Ptr(0)
+	return p.IsValid() || err != nil
+}
+
+func (s Z) SetBlockTxs(v BlockTxs) error {
+	s.Struct.SetUint16(0, 18)
+	return s.Struct.SetPtr(0, v.Struct.ToPtr())
+}
+
+// NewBlockTxs sets the blockTxs field to a newly
+// allocated BlockTxs struct, preferring placement in s's segment.
+func (s Z) NewBlockTxs() (BlockTxs, error) {
+	s.Struct.SetUint16(0, 18)
+	ss, err := NewBlockTxs(s.Struct.Segment())
+	if err != nil {
+		return BlockTxs{}, err
+	}
+	err = s.Struct.SetPtr(0, ss.Struct.ToPtr())
+	return ss, err
+}
+
 // Z_List is a list of Z.
 type Z_List struct{ capnp.List }
 
@@ -435,34 +795,101 @@ func (p Z_Promise) Batch() Batch_Promise {
 	return Batch_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
 }
 
-const schema_904d4f3f728c7f04 = "x\xda4\xce?h\x13\x7f\x1c\xc6\xf1\xe7\xb9\xfb\xe6\x1f" +
-	"\xbf\xe4\xd7o\xb8+\xa8\xa8q\x10D\xc1\x7f)\x8a\xe8" +
-	"\xd0\"\x08E\xd0x\x89.f\x904\x1e\x1ah\xee\xce" +
-	"\xcbY\xd4\xa5\x9b\x7f\x8a\x05\xc5\xd1A\x07\x11\x84:\x08" +
-	"\xea\xa0\xb8\xe9\xe0(\xba\xe8\xa0S!C\x14\xc5\x8a\xb1" +
-	"Z?\xf2-\xc9\xfa~\xf1\xc0\xb3\xeb9'\xac\xdd\xa9" +
-	"J\x0e\xf0\xa6Siy\xb6\xf1\xdd\xd8\x96=\xed\xc7\xf0" +
-	"\xb2\xa4\xa8\xd9\xeb\xf1x\xe5\xc8\x0d\x1cb&\x078\xf3" +
-	"\xaa\xea\xdcT\x19`l^\x09!(\xc9\xa5\x1d\xcdF" +
-	"\x14D\xfb\xc1\x93\xc7Ho\xb3\xad\xf2\"\x8a@\xf1\xf3" +
-	"6\xc0\xeb\xda\xf4\x96,\x16\xf8W\\\x9a\xfa\xcd\xd4\x9e" +
-	"M\xafo\xb1`\xad\x88K\x0b(\xfe8\x0cxK6" +
-	"\xab\xb4X\xb0\xff\x88K\x1b(\xae\x94\x01\xafo\xb3\xa6" +
-	"LV\xbf\xc5\xa5\x02\x1cr\x0a\xa8\xd2f-ozj" +
-	"Y\\\xa6\x00'\xc7\x83@M\x19\xd0\x06\xd2\xbf\xc4e" +
-	"\x1ap\x0a\xac\x02\xb5\xbc\x815\x062}q\x99\x01\x9c" +
-	"\xd1\xd5\x856\xb0\xde@\xf6\xa7\xb8\xcc\x02\xceZ\x96\x81" +
-	"\x9ak`\x13-\x8eD\xad\xe0\x0c\xb5,\x96>|\xff" +
-	"8\xf9\xf2\x1e@jp$\x0aW\xb3l\xbf[\x91\xf2" +
-	"\x93\xc5A\x96\xd3\xadN3\x9c\xf1c\x00\xd4r\xe1\xe9" +
-	"\xfd\xbd\xdd\x89\xde\xdc@K\x91\xef\xc7\x1djYW\xad" +
-	"\xbf\xda\xb0<\xf7b\xb8J\xe2F\xd0i4\x13dZ" +
-	"a@-\xa7\xec\xado\xd2\xef\xef\xf4\x06>\xdb\xf6\xdb" +
-	"Q\x18NS\xcb\xe8\xc2\xce\x13\x0f\xff\xdf\xf7u\xb8l" +
-	"\x053~\x90\x841x\x91Z\xae~\xfa\xef\xf6\xa3\xe3" +
-	"\x93\xd7\x86\xbb\xd8?w\xde\xef$\xd4r\xeb\xc0\xeb\xf1" +
-	"+\xc5\xb7\xdd\xe1\x93\xa9F\xd2<K-_\x16\xea\xf5" +
-	"\x07\xde\xd1\xcb\x83\xfe/\x00\x00\xff\xff<C\x89\xb1"
+func (p Z_Promise) Status() Status_Promise {
+	return Status_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
+}
+
+func (p Z_Promise) GetHeaders() GetHeaders_Promise {
+	return GetHeaders_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
+}
+
+func (p Z_Promise) Path() Path_Promise {
+	return Path_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
+}
+
+func (p Z_Promise) GetInv() GetInv_Promise {
+	return GetInv_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
+}
+
+func (p Z_Promise) GetTx() GetTx_Promise {
+	return GetTx_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
+}
+
+func (p Z_Promise) NotFound() NotFound_Promise {
+	return NotFound_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
+}
+
+func (p Z_Promise) Announce() Announce_Promise {
+	return Announce_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
+}
+
+func (p Z_Promise) Compact() Compact_Promise {
+	return Compact_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
+}
+
+func (p Z_Promise) GetBlockTxs() GetBlockTxs_Promise {
+	return GetBlockTxs_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
+}
+
+func (p Z_Promise) BlockTxs() BlockTxs_Promise {
+	return BlockTxs_Promise{Pipeline: p.Pipeline.GetPipeline(0)}
+}
+
+const schema_904d4f3f728c7f04 = "x\xdal\xd2]lTE\x14\xc0\xf1s\xce\xecv(" +
+	"\xddm\xbb\x9ciB\xd5\x02>\x18\xa1\x11)E\x09T" +
+	"\x92\xd6\x86\x9aR\x8b\xb4\x9d51T\xc4\xed\xf6f\xdd" +
+	"\xc8\xde\xbb\xec\xde6k\x8d\xa1%\xa6*\x91\xa4\x0d\xc6" +
+	"\x88\xc6\x00\x91\x10\x89\xf8@\xd2\x1ac\xf4\xa1\xa1M\xc0" +
+	"'\x15H\xc0\xf8\x11\x8dMjR+\xf2\xa1-~\x8c" +
+	"\x99l\xe9\xc3\xd5\xe7\xdf?3g\xce\xbduyl\xa2" +
+	"Xx/@g(\\b>Yui\xd3\xfd\x0fg" +
+	"\xc6\xa0s\x19\xa2\x09\x1dx=\xd7\xb8k\xe7\x08\xb4\xa0" +
+	"d\x80XUW\xacZ\xc6\xaa\xe5\xc6\xea\x0d!\x00>" +
+	"\x1c\x96f\xe0\xc1d\"\xebf\x1b\x00ww v " +
+	"u\xb6\x8bP\xc4\x98\x10\x02p\x98j9LR\x87H" +
+	"\xa0^I\x84Q\xfc\xc7(\xb4TE\xb5\\ER+" +
+	"Kk-\xd1\xdfF!\x01\xf0}\xd4\xc6\xebH\xea\xb5" +
+	"\x96\xb6Y\x12\x7f\x19\x85\x02\x80\xb7R=o%\xa9\xb7" +
+	"Xj\xb7\x14\xfa\xd3(\xb4\xc3\xec\xa0\x1e\xdeIR\xb7" +
+	"[z\xd6R\xf8\xb6Q\x18\x06\xe0=\xd4\xcc{H\xea" +
+	"\xa7-e-\x95,\x18\x85%\x00\x9c\xa1.\xdeOR" +
+	"g-\xbdlI\xce\x1b\x85\x12\x80\x87\xa8\x99\x87H\xea" +
+	"AKoXZ\xf6\x87Q\xb8\x0c\x80G\xa9\x9eGI" +
+	"\xea\x11K'-\x95\xfen\x14\x96\x02\xf0\x09j\xe0\x13" +
+	"$\xf5qKc\x96\x96\xdf2\x0a\x97\x03\xf0Y\xda\xcd" +
+	"\xe3$\xf5\x98\xa5\xf3\x96\xcan\x1a\x85e\x00<E\xb5" +
+	"<EROZ\xbab)r\xc3(\x8c\x00\xf0ej" +
+	"\xe0\xcb$\xf5%K3\x96\xa2\xd7\x8d\xc2(\x00OS" +
+	"=O\x93\xd4?Y\x9a\xb7T\xfe\x9bQX\x0e\xc0\xb7" +
+	"\xa8\x8d\x17H\xeayK\x11A\x18\xad\xb8f\x14V\x00" +
+	"p\xa9h\xe3\xa8\x90:\"\x04\xea5\x96*\x7f5\x0a" +
+	"+\x01\xb8F4s\x8d\x90\xfa\x1eKu\x96bsF" +
+	"a\x0c\x80\xd7\x8b\x1e\xde(\xa4\xae\xb3\xb4\xdd\xd2\x8a_" +
+	"\x8c\xc2\x15\x00\xfc\xa8h\xe3\x16!\xf5vKO\x09\xc2" +
+	"\x8al\xdaMu a\xa5\x99^\xfd\xf5\x8d\xefZ'" +
+	"O\x02@\x13\xc6Pv\x10b%`E\xd6\xbbS\x98" +
+	"\xf5\xc7w\x99\xfa\xf1\xe9@az\xd3\xf9\xa4\xd7\xef\xe4" +
+	"\x00\xa0\x18\x16>:\xb5y\xa6i\xf6P \\\x9du" +
+	"\x9c\\\xbe\x98\xdc\xd5\xd5=Us\xfb\xd0g\xc1\xb3\xfc" +
+	"\\\xc2\xcd'\x92>\xc8\xb4\xe7\x16\xd3\xbdb\xdd\x97%" +
+	"W\x8f\xcd\x06\xd2\x03\x19'\x93\xf5\xbc}\xc5\xa8\xea\xcc" +
+	"\x86'?,\xdfr-x^\xda\xedw\\\xdf\xcb\x01" +
+	"\xbeP\x0c_\xfd\xbe\xec\x9d\xb3\xf1\xd6\xd7\x82\xa7\xe5\x9c" +
+	"\xfd}N\xde/FG\x1e\xb9\xd0\xf8J\xec\xe2L\xf0" +
+	"\x01=\x09?\xf9\\1\x99;\xd3\xdd}\xba\xf3\x89\xe1" +
+	"@\xd2\x98\xf7\x13~\xdf\xe2##\x13k\xc2\xdf\x0e\xdf" +
+	"{88T\xca\xf1[\x9dD\xaf\x03\xe2\xce:\xce]" +
+	"<\xf2\xf1\xc1\xcfO\xbf\xfb\x9f\xe5'\xfc\xc5\xfb\x9e\x19" +
+	"\x9axhpd\xd3\x9b\xc1\xfbR\x8e\xbf\xc3\xed/6" +
+	"\xdd\x0f\x9c\x0f\xb5\x9c\xba\xfb\x83\xe0\xd8)\xc7\x8f\x17\x8a" +
+	"\xc9\xe8[\x93\xb3\xef7\x14.\x04Gr=\xff1\xaf" +
+	"\xcf\xed]\xfa\x86\xa9\xf4K\xce\x8f+\xc7\xdf\x0e\x86\x09" +
+	"\xd7\xf5\xfa\xdc\xa4\xb3\x14\x1e]\xf5\xe2\xb1\xc1\x9b\x8fO" +
+	"\x05\x17\x9a\xf42\xd9Drq\xa1\xd7\xe7\xae~:\xf0" +
+	"\xc5\xcf\x13\xff\xb3\x89\xe6}^\xf2y\x90\xf1\xc2\xe2*" +
+	"\xbe\xd96p\xf4\xbd\x83e\x0b\xc1\xb4\xc7v\xf1B~" +
+	"\xe9\xe2\xaf\xe2\xc3W~8\xb79\xf8_4\xe1\xbf\x03" +
+	"\x00Y\xe0.\xeb"
 
 func init() {
 	schemas.Register(schema_904d4f3f728c7f04,
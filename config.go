@@ -35,4 +35,5 @@ type Config struct {
 	IP        net.IP
 	Port      uint16
 	Bootstrap []string
+	DryRun    bool
 }
@@ -49,6 +49,9 @@ func (b *Badger) Has(key []byte) (bool, error) {
 	var ok bool
 	err := b.kv.View(func(tx *badger.Txn) error {
 		_, err := tx.Get(key)
+		if err == badger.ErrKeyNotFound {
+			return nil
+		}
 		if err != nil {
 			return errors.Wrap(err, "could not get item for key")
 		}
@@ -87,3 +90,26 @@ func (b *Badger) Del(key []byte) error {
 	})
 	return err
 }
+
+// Iterate will call the given function for each key-value pair with the given
+// prefix in the database.
+func (b *Badger) Iterate(prefix []byte, fn func(key []byte, val []byte) error) error {
+	err := b.kv.View(func(tx *badger.Txn) error {
+		it := tx.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			item := it.Item()
+			key := item.KeyCopy(nil)
+			val, err := item.ValueCopy(nil)
+			if err != nil {
+				return errors.Wrap(err, "could not get value from item")
+			}
+			err = fn(key, val)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return err
+}
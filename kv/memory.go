@@ -17,7 +17,10 @@
 
 package kv
 
-import "errors"
+import (
+	"bytes"
+	"errors"
+)
 
 // Memory is a wrapper around an in-memory key-value store.
 type Memory struct {
@@ -29,9 +32,9 @@ func NewMemory() *Memory {
 	return &Memory{kv: make(map[string][]byte)}
 }
 
-// Put will store the given value under the given key.
+// Put will store a copy of the given value under the given key.
 func (m *Memory) Put(key []byte, val []byte) error {
-	m.kv[string(key)] = val
+	m.kv[string(key)] = append([]byte(nil), val...)
 	return nil
 }
 
@@ -55,3 +58,18 @@ func (m *Memory) Del(key []byte) error {
 	delete(m.kv, string(key))
 	return nil
 }
+
+// Iterate will call the given function for each key-value pair with the given
+// prefix.
+func (m *Memory) Iterate(prefix []byte, fn func(key []byte, val []byte) error) error {
+	for key, val := range m.kv {
+		if !bytes.HasPrefix([]byte(key), prefix) {
+			continue
+		}
+		err := fn([]byte(key), val)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -29,10 +29,10 @@ import (
 	"github.com/dgraph-io/badger"
 	"github.com/rs/zerolog"
 	"github.com/spf13/pflag"
+	"golang.org/x/crypto/ed25519"
 
 	"github.com/alvalor/alvalor-go/blockchain"
 	"github.com/alvalor/alvalor-go/codec"
-	"github.com/alvalor/alvalor-go/finder"
 	"github.com/alvalor/alvalor-go/kv"
 	"github.com/alvalor/alvalor-go/network"
 	"github.com/alvalor/alvalor-go/node"
@@ -43,7 +43,7 @@ import (
 func main() {
 
 	// set up a channel to catch system signals to cleanly shut down
-	sig := make(chan os.Signal)
+	sig := make(chan os.Signal, 1)
 	signal.Notify(sig, os.Interrupt)
 
 	// initialize default configuration
@@ -51,6 +51,7 @@ func main() {
 
 	// apply the command line parameters to configuration
 	pflag.Uint16Var(&cfg.Port, "port", 21517, "listen port for incoming connections")
+	pflag.BoolVar(&cfg.DryRun, "migrate-dry-run", false, "only log the database migrations and exit")
 	pflag.Parse()
 
 	// seed the random generator
@@ -73,6 +74,33 @@ func main() {
 	// use our efficient capnproto codec for network communication
 	codec := codec.NewProto()
 
+	// make sure the database directory exists
+	dbDir := filepath.Join(dir, "database")
+	err = os.MkdirAll(dbDir, os.ModePerm)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create database directory")
+	}
+
+	// initialize the key-value database
+	opts := badger.DefaultOptions
+	opts.Dir = dbDir
+	opts.ValueDir = dbDir
+	db, err := badger.Open(opts)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not open database")
+	}
+
+	// create the wrapper around badger & upgrade the database schema
+	kv := kv.NewBadger(db)
+	err = store.Migrate(log, kv, cfg.DryRun, store.Reencode(codec, "b", "t"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not migrate database")
+	}
+	if cfg.DryRun {
+		_ = db.Close()
+		return
+	}
+
 	// create channel to pipe messages from network layer to node layer
 	sub := make(chan interface{}, 128)
 
@@ -93,36 +121,24 @@ func main() {
 		net.Add(address)
 	}
 
-	// make sure the database directory exists
-	dbDir := filepath.Join(dir, "database")
-	err = os.MkdirAll(dbDir, os.ModePerm)
-	if err != nil {
-		log.Fatal().Err(err).Msg("could not create database directory")
-	}
-
-	// initialize the key-value database
-	opts := badger.DefaultOptions
-	opts.Dir = dbDir
-	opts.ValueDir = dbDir
-	db, err := badger.Open(opts)
-	if err != nil {
-		log.Fatal().Err(err).Msg("could not open database")
-	}
-
-	// create the wrapper around badger & initialize entity stores
-	kv := kv.NewBadger(db)
-	blocks := store.New(kv, codec, "b")
-	txs := store.New(kv, codec, "t")
+	// initialize entity stores with our storage encoding
+	enc := store.NewEncoding()
+	blocks := store.New(kv, enc, "b")
+	txs := store.New(kv, enc, "t")
 	chain, err := blockchain.New(kv, kv, blocks, txs)
 	if err != nil {
 		log.Fatal().Err(err).Msg("could not initialize blockchain")
 	}
 
-	// initialize the path finder
-	find := finder.New(chain.Header().Hash())
+	// initialize the genesis header all our headers descend from
+	genesis := &types.Header{Diff: 1}
+	genesis.Hash = genesis.GetHash()
 
-	// initialize the node subscriber
-	n := node.New(log, net, chain, find, codec, sub)
+	// initialize the node on top of the network and the blockchain
+	n, err := node.New(log, net, chain, kv, enc, genesis, sub)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not initialize node")
+	}
 	nodeSub := make(chan interface{}, 128)
 	n.Subscribe(nodeSub)
 
@@ -142,11 +158,15 @@ Loop:
 			n.Stats()
 		case <-gen.C:
 			tx := generateTransaction()
-			n.Submit(tx)
+			err := n.Submit(tx)
+			if err != nil {
+				log.Error().Err(err).Msg("could not submit transaction")
+			}
 		}
 	}
 
-	// shut down the p2p network node
+	// shut down the node before the p2p network node it depends on
+	n.Stop()
 	net.Stop()
 }
 
@@ -158,17 +178,20 @@ func generateTransaction() *types.Transaction {
 	numData := rand.Int() % 4 * 1024
 
 	// create the transfers
+	keys := make(map[string]ed25519.PrivateKey)
 	transfers := make([]*types.Transfer, 0, numTransfers)
 	for i := 0; i < numTransfers; i++ {
-		transfer := generateTransfer()
+		transfer, priv := generateTransfer()
 		transfers = append(transfers, transfer)
+		keys[string(transfer.From)] = priv
 	}
 
 	// create the fees
 	fees := make([]*types.Fee, 0, numFees)
 	for i := 0; i < numFees; i++ {
-		fee := generateFee()
+		fee, priv := generateFee()
 		fees = append(fees, fee)
+		keys[string(fee.From)] = priv
 	}
 
 	// create the data block
@@ -182,12 +205,18 @@ func generateTransaction() *types.Transaction {
 		Data:      data,
 	}
 
+	// sign the transaction by each of its senders
+	tx.Hash = tx.GetHash()
+	for _, sender := range tx.Senders() {
+		sig := ed25519.Sign(keys[string(sender)], tx.Hash[:])
+		tx.Signatures = append(tx.Signatures, sig)
+	}
+
 	return tx
 }
 
-func generateTransfer() *types.Transfer {
-	from := make([]byte, 32)
-	_, _ = rand.Read(from)
+func generateTransfer() (*types.Transfer, ed25519.PrivateKey) {
+	from, priv, _ := ed25519.GenerateKey(nil)
 	to := make([]byte, 32)
 	_, _ = rand.Read(to)
 	amount := rand.Uint64()
@@ -195,15 +224,14 @@ func generateTransfer() *types.Transfer {
 		From:   from,
 		To:     to,
 		Amount: amount,
-	}
+	}, priv
 }
 
-func generateFee() *types.Fee {
-	from := make([]byte, 32)
-	_, _ = rand.Read(from)
-	amount := rand.Uint64()
+func generateFee() (*types.Fee, ed25519.PrivateKey) {
+	from, priv, _ := ed25519.GenerateKey(nil)
+	amount := rand.Uint64() % 1000000
 	return &types.Fee{
 		From:   from,
 		Amount: amount,
-	}
+	}, priv
 }
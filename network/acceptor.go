@@ -32,9 +32,10 @@ func handleAccepting(log zerolog.Logger, wg *sync.WaitGroup, cfg *Config, pendin
 
 	// configuration
 	var (
-		network = cfg.network
-		nonce   = cfg.nonce
-		address = conn.RemoteAddr().String()
+		network      = cfg.network
+		nonce        = cfg.nonce
+		compressions = cfg.compressions
+		address      = conn.RemoteAddr().String()
 	)
 
 	// configure logger
@@ -52,7 +53,7 @@ func handleAccepting(log zerolog.Logger, wg *sync.WaitGroup, cfg *Config, pendin
 	defer pending.Release(address)
 
 	// execute the handshake on the incoming connection
-	ack := append(network, nonce...)
+	ack := append(append(network, nonce...), compressionMask(compressions))
 	syn := make([]byte, len(ack))
 	_, err = conn.Read(syn)
 	if err != nil {
@@ -68,7 +69,7 @@ func handleAccepting(log zerolog.Logger, wg *sync.WaitGroup, cfg *Config, pendin
 		book.Block(address)
 		return
 	}
-	nonceIn := syn[len(network):]
+	nonceIn := syn[len(network) : len(network)+len(nonce)]
 	if bytes.Equal(nonceIn, nonce) {
 		log.Error().Hex("nonce", nonce).Msg("identical nonce")
 		conn.Close()
@@ -84,7 +85,8 @@ func handleAccepting(log zerolog.Logger, wg *sync.WaitGroup, cfg *Config, pendin
 	}
 
 	// submit the connection for a new peer creation
-	err = peers.Add(conn, nonceIn)
+	compression := negotiateCompression(compressions, syn[len(syn)-1])
+	err = peers.Add(conn, nonceIn, compression)
 	if err != nil {
 		log.Error().Err(err).Msg("could not add peer")
 		conn.Close()
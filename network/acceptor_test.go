@@ -73,7 +73,7 @@ func (suite *AcceptorSuite) TestAcceptorSuccess() {
 	pending.On("Release", mock.Anything).Return(nil)
 
 	peers := &PeerManagerMock{}
-	peers.On("Add", mock.Anything, mock.Anything).Return(nil)
+	peers.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	rep := &ReputationManagerMock{}
 	rep.On("Failure", mock.Anything)
@@ -93,7 +93,60 @@ func (suite *AcceptorSuite) TestAcceptorSuccess() {
 
 	pending.AssertCalled(t, "Claim", address)
 	pending.AssertCalled(t, "Release", address)
-	peers.AssertCalled(t, "Add", conn, nonce)
+	peers.AssertCalled(t, "Add", conn, nonce, CompressionNone)
+	rep.AssertCalled(t, "Success", address)
+	events.AssertCalled(t, "Connected", address)
+
+	conn.AssertNotCalled(t, "Close")
+	rep.AssertNotCalled(t, "Failure", mock.Anything)
+	book.AssertNotCalled(t, "Block", mock.Anything)
+}
+
+func (suite *AcceptorSuite) TestAcceptorCompression() {
+
+	// arrange
+	address := "192.0.2.100:1337"
+	nonce := uuid.Must(uuid.NewV4()).Bytes()
+	syn := append(append(suite.cfg.network, nonce...), compressionMask([]Compression{CompressionFlate}))
+
+	addr := &AddrMock{}
+	addr.On("String").Return(address)
+
+	conn := &ConnMock{}
+	conn.On("RemoteAddr").Return(addr)
+	conn.On("Read", mock.Anything).Run(func(args mock.Arguments) {
+		copy(args.Get(0).([]byte), syn)
+	}).Return(0, nil)
+	conn.On("Write", mock.Anything).Return(0, nil)
+	conn.On("Close").Return(nil)
+
+	pending := &PendingManagerMock{}
+	pending.On("Claim", mock.Anything).Return(nil)
+	pending.On("Release", mock.Anything).Return(nil)
+
+	peers := &PeerManagerMock{}
+	peers.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	rep := &ReputationManagerMock{}
+	rep.On("Failure", mock.Anything)
+	rep.On("Success", mock.Anything)
+
+	book := &AddressManagerMock{}
+	book.On("Block", mock.Anything)
+
+	events := &EventManagerMock{}
+	events.On("Connected", mock.Anything).Return(nil)
+
+	// act
+	suite.cfg.compressions = []Compression{CompressionLZ4, CompressionFlate}
+	handleAccepting(suite.log, &suite.wg, &suite.cfg, pending, peers, rep, book, events, conn)
+
+	// assert
+	t := suite.T()
+
+	pending.AssertCalled(t, "Claim", address)
+	pending.AssertCalled(t, "Release", address)
+	peers.AssertCalled(t, "Add", conn, nonce, CompressionFlate)
 	rep.AssertCalled(t, "Success", address)
 	events.AssertCalled(t, "Connected", address)
 
@@ -125,7 +178,7 @@ func (suite *AcceptorSuite) TestAcceptorClaimFails() {
 	pending.On("Release", mock.Anything).Return(nil)
 
 	peers := &PeerManagerMock{}
-	peers.On("Add", mock.Anything, mock.Anything).Return(nil)
+	peers.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	rep := &ReputationManagerMock{}
 	rep.On("Failure", mock.Anything)
@@ -147,7 +200,7 @@ func (suite *AcceptorSuite) TestAcceptorClaimFails() {
 	conn.AssertCalled(t, "Close")
 
 	pending.AssertNotCalled(t, "Release", mock.Anything)
-	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
 	rep.AssertNotCalled(t, "Success", mock.Anything)
 	rep.AssertNotCalled(t, "Failure", mock.Anything)
 	book.AssertNotCalled(t, "Block", mock.Anything)
@@ -177,7 +230,7 @@ func (suite *AcceptorSuite) TestAcceptorReadFails() {
 	pending.On("Release", mock.Anything).Return(nil)
 
 	peers := &PeerManagerMock{}
-	peers.On("Add", mock.Anything, mock.Anything).Return(nil)
+	peers.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	rep := &ReputationManagerMock{}
 	rep.On("Failure", mock.Anything)
@@ -200,7 +253,7 @@ func (suite *AcceptorSuite) TestAcceptorReadFails() {
 	rep.AssertCalled(t, "Failure", address)
 	conn.AssertCalled(t, "Close")
 
-	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
 	rep.AssertNotCalled(t, "Success", mock.Anything)
 	book.AssertNotCalled(t, "Block", mock.Anything)
 	events.AssertNotCalled(t, "Connected", mock.Anything)
@@ -229,7 +282,7 @@ func (suite *AcceptorSuite) TestAcceptorNetworkMismatch() {
 	pending.On("Release", mock.Anything).Return(nil)
 
 	peers := &PeerManagerMock{}
-	peers.On("Add", mock.Anything, mock.Anything).Return(nil)
+	peers.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	rep := &ReputationManagerMock{}
 	rep.On("Failure", mock.Anything)
@@ -252,7 +305,7 @@ func (suite *AcceptorSuite) TestAcceptorNetworkMismatch() {
 	book.AssertCalled(t, "Block", address)
 	conn.AssertCalled(t, "Close")
 
-	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
 	rep.AssertNotCalled(t, "Success", mock.Anything)
 	rep.AssertNotCalled(t, "Failure", mock.Anything)
 	events.AssertNotCalled(t, "Connected", mock.Anything)
@@ -280,7 +333,7 @@ func (suite *AcceptorSuite) TestAcceptorNonceIdentical() {
 	pending.On("Release", mock.Anything).Return(nil)
 
 	peers := &PeerManagerMock{}
-	peers.On("Add", mock.Anything, mock.Anything).Return(nil)
+	peers.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	rep := &ReputationManagerMock{}
 	rep.On("Failure", mock.Anything)
@@ -303,7 +356,7 @@ func (suite *AcceptorSuite) TestAcceptorNonceIdentical() {
 	book.AssertCalled(t, "Block", address)
 	conn.AssertCalled(t, "Close")
 
-	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
 	rep.AssertNotCalled(t, "Success", mock.Anything)
 	rep.AssertNotCalled(t, "Failure", mock.Anything)
 	events.AssertNotCalled(t, "Connected", mock.Anything)
@@ -332,7 +385,7 @@ func (suite *AcceptorSuite) TestAcceptorWriteFails() {
 	pending.On("Release", mock.Anything).Return(nil)
 
 	peers := &PeerManagerMock{}
-	peers.On("Add", mock.Anything, mock.Anything).Return(nil)
+	peers.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	rep := &ReputationManagerMock{}
 	rep.On("Failure", mock.Anything)
@@ -355,7 +408,7 @@ func (suite *AcceptorSuite) TestAcceptorWriteFails() {
 	rep.AssertCalled(t, "Failure", address)
 	conn.AssertCalled(t, "Close")
 
-	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
 	rep.AssertNotCalled(t, "Success", mock.Anything)
 	book.AssertNotCalled(t, "Block", mock.Anything)
 	events.AssertNotCalled(t, "Connected", mock.Anything)
@@ -384,7 +437,7 @@ func (suite *AcceptorSuite) TestAcceptorAddPeerFails() {
 	pending.On("Release", mock.Anything).Return(nil)
 
 	peers := &PeerManagerMock{}
-	peers.On("Add", mock.Anything, mock.Anything).Return(errors.New("could not add peer"))
+	peers.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("could not add peer"))
 
 	rep := &ReputationManagerMock{}
 	rep.On("Failure", mock.Anything)
@@ -404,7 +457,7 @@ func (suite *AcceptorSuite) TestAcceptorAddPeerFails() {
 
 	pending.AssertCalled(t, "Claim", address)
 	pending.AssertCalled(t, "Release", address)
-	peers.AssertCalled(t, "Add", conn, nonce)
+	peers.AssertCalled(t, "Add", conn, nonce, CompressionNone)
 	conn.AssertCalled(t, "Close")
 
 	rep.AssertNotCalled(t, "Success", mock.Anything)
@@ -0,0 +1,158 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"sync"
+
+	"github.com/pierrec/lz4"
+	"github.com/pkg/errors"
+)
+
+// Compression represents an algorithm used to compress messages on the wire.
+type Compression uint8
+
+// Enumeration of the compression algorithms we support. Which algorithm is used
+// on a connection is negotiated during the handshake, and each message is only
+// compressed if it is large enough for compression to be worth it.
+const (
+	CompressionNone Compression = iota
+	CompressionLZ4
+	CompressionFlate
+)
+
+// compressionMask returns the bitmask announcing the given compression
+// algorithms during the handshake.
+func compressionMask(compressions []Compression) byte {
+	var mask byte
+	for _, compression := range compressions {
+		mask |= 1 << compression
+	}
+	return mask
+}
+
+// negotiateCompression picks the first of our preferred compression algorithms
+// that is supported by the peer, according to the mask it announced.
+func negotiateCompression(compressions []Compression, mask byte) Compression {
+	for _, compression := range compressions {
+		if compression == CompressionNone {
+			continue
+		}
+		if mask&(1<<compression) != 0 {
+			return compression
+		}
+	}
+	return CompressionNone
+}
+
+// compress compresses the payload with the given compression algorithm.
+func compress(compression Compression, payload []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	var w io.WriteCloser
+	switch compression {
+	case CompressionLZ4:
+		w = lz4.NewWriter(buf)
+	case CompressionFlate:
+		fw, err := flate.NewWriter(buf, flate.BestSpeed)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create flate writer")
+		}
+		w = fw
+	default:
+		return nil, errors.Errorf("unknown compression (%v)", compression)
+	}
+	_, err := w.Write(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not write payload")
+	}
+	err = w.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not close compressor")
+	}
+	return buf.Bytes(), nil
+}
+
+// decompress decompresses the payload with the given compression algorithm,
+// making sure that it expands to exactly the given size. The output buffer only
+// grows with the data that is actually inflated, so that announcing a large
+// size without sending the data doesn't make us allocate it.
+func decompress(compression Compression, payload []byte, size uint32) ([]byte, error) {
+	var r io.Reader
+	switch compression {
+	case CompressionLZ4:
+		r = lz4.NewReader(bytes.NewReader(payload))
+	case CompressionFlate:
+		fr := flate.NewReader(bytes.NewReader(payload))
+		defer fr.Close()
+		r = fr
+	default:
+		return nil, errors.Errorf("unknown compression (%v)", compression)
+	}
+	buf := &bytes.Buffer{}
+	n, err := buf.ReadFrom(io.LimitReader(r, int64(size)+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read payload")
+	}
+	if n != int64(size) {
+		return nil, errors.Errorf("payload does not match announced size (%v != %v)", n, size)
+	}
+	return buf.Bytes(), nil
+}
+
+// compressionStats keeps track of how many bytes we saved by compressing the
+// messages sent to and received from our peers.
+type compressionStats struct {
+	sync.Mutex
+	rawOut  uint64
+	wireOut uint64
+	rawIn   uint64
+	wireIn  uint64
+}
+
+func (cs *compressionStats) Sent(raw int, wire int) {
+	cs.Lock()
+	defer cs.Unlock()
+	cs.rawOut += uint64(raw)
+	cs.wireOut += uint64(wire)
+}
+
+func (cs *compressionStats) Received(raw int, wire int) {
+	cs.Lock()
+	defer cs.Unlock()
+	cs.rawIn += uint64(raw)
+	cs.wireIn += uint64(wire)
+}
+
+// Ratios returns the ratio of bytes on the wire to uncompressed bytes for
+// outgoing and incoming messages.
+func (cs *compressionStats) Ratios() (float32, float32) {
+	cs.Lock()
+	defer cs.Unlock()
+	out := float32(1)
+	if cs.rawOut > 0 {
+		out = float32(cs.wireOut) / float32(cs.rawOut)
+	}
+	in := float32(1)
+	if cs.rawIn > 0 {
+		in = float32(cs.wireIn) / float32(cs.rawIn)
+	}
+	return out, in
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressionNegotiate(t *testing.T) {
+	mask := compressionMask([]Compression{CompressionFlate})
+	assert.Equal(t, CompressionFlate, negotiateCompression([]Compression{CompressionLZ4, CompressionFlate}, mask))
+	assert.Equal(t, CompressionNone, negotiateCompression([]Compression{CompressionLZ4}, mask))
+	assert.Equal(t, CompressionNone, negotiateCompression(nil, mask))
+
+	mask = compressionMask([]Compression{CompressionFlate, CompressionLZ4})
+	assert.Equal(t, CompressionLZ4, negotiateCompression([]Compression{CompressionLZ4, CompressionFlate}, mask))
+	assert.Equal(t, CompressionFlate, negotiateCompression([]Compression{CompressionFlate, CompressionLZ4}, mask))
+}
+
+func TestCompressionRoundtrip(t *testing.T) {
+	payload := bytes.Repeat([]byte{1, 2, 3, 4, 5, 6, 7, 8}, 1024)
+	for _, compression := range []Compression{CompressionLZ4, CompressionFlate} {
+		compressed, err := compress(compression, payload)
+		if assert.Nil(t, err) {
+			assert.True(t, len(compressed) < len(payload))
+			decompressed, err := decompress(compression, compressed, uint32(len(payload)))
+			assert.Nil(t, err)
+			assert.Equal(t, payload, decompressed)
+			_, err = decompress(compression, compressed, uint32(len(payload)-1))
+			assert.NotNil(t, err)
+			_, err = decompress(compression, compressed, uint32(len(payload)+1))
+			assert.NotNil(t, err)
+		}
+	}
+
+	_, err := compress(Compression(255), payload)
+	assert.NotNil(t, err)
+	_, err = decompress(Compression(255), payload, uint32(len(payload)))
+	assert.NotNil(t, err)
+}
+
+func TestCompressionRatios(t *testing.T) {
+	stats := &compressionStats{}
+	out, in := stats.Ratios()
+	assert.Equal(t, float32(1), out)
+	assert.Equal(t, float32(1), in)
+
+	stats.Sent(100, 50)
+	stats.Received(100, 25)
+	out, in = stats.Ratios()
+	assert.Equal(t, float32(0.5), out)
+	assert.Equal(t, float32(0.25), in)
+}
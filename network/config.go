@@ -22,16 +22,18 @@ import "time"
 // Config represents the configuration parameters available to configure a node
 // on the peer-to-peer network.
 type Config struct {
-	network    []byte
-	nonce      []byte
-	listen     bool
-	address    string
-	minPeers   uint
-	maxPeers   uint
-	maxPending uint
-	interval   time.Duration
-	codec      Codec
-	bufferSize uint
+	network      []byte
+	nonce        []byte
+	listen       bool
+	address      string
+	minPeers     uint
+	maxPeers     uint
+	maxPending   uint
+	interval     time.Duration
+	codec        Codec
+	bufferSize   uint
+	compressions []Compression
+	threshold    uint
 }
 
 // SetNetwork allows us to configure a custom network ID.
@@ -75,3 +77,19 @@ func SetMaxPending(maxPending uint) func(*Config) {
 		cfg.maxPending = maxPending
 	}
 }
+
+// SetCompressions allows us to configure the compression algorithms we support,
+// in order of preference.
+func SetCompressions(compressions ...Compression) func(*Config) {
+	return func(cfg *Config) {
+		cfg.compressions = compressions
+	}
+}
+
+// SetThreshold allows us to configure the minimum size of a message in bytes
+// for it to be compressed.
+func SetThreshold(threshold uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.threshold = threshold
+	}
+}
@@ -29,8 +29,9 @@ func handleConnecting(log zerolog.Logger, wg *sync.WaitGroup, cfg *Config, pendi
 
 	// extract the variables from the config we are interested in
 	var (
-		network = cfg.network
-		nonce   = cfg.nonce
+		network      = cfg.network
+		nonce        = cfg.nonce
+		compressions = cfg.compressions
 	)
 
 	// configure the component logger and set start/stop messages
@@ -55,7 +56,7 @@ func handleConnecting(log zerolog.Logger, wg *sync.WaitGroup, cfg *Config, pendi
 	}
 
 	// execute the network handshake
-	syn := append(network, nonce...)
+	syn := append(append(network, nonce...), compressionMask(compressions))
 	ack := make([]byte, len(syn))
 	_, err = conn.Write(syn)
 	if err != nil {
@@ -78,7 +79,7 @@ func handleConnecting(log zerolog.Logger, wg *sync.WaitGroup, cfg *Config, pendi
 		book.Block(address)
 		return
 	}
-	nonceIn := ack[len(network):]
+	nonceIn := ack[len(network) : len(network)+len(nonce)]
 	if bytes.Equal(nonceIn, nonce) {
 		log.Error().Hex("nonce", nonce).Msg("identical nonce")
 		conn.Close()
@@ -93,7 +94,8 @@ func handleConnecting(log zerolog.Logger, wg *sync.WaitGroup, cfg *Config, pendi
 	}
 
 	// create the peer for the valid connection
-	err = peers.Add(conn, nonceIn)
+	compression := negotiateCompression(compressions, ack[len(ack)-1])
+	err = peers.Add(conn, nonceIn, compression)
 	if err != nil {
 		log.Error().Err(err).Msg("could not add peer")
 		conn.Close()
@@ -70,7 +70,7 @@ func (suite *ConnectorSuite) TestConnectorSuccess() {
 
 	peers := &PeerManagerMock{}
 	peers.On("Known", mock.Anything).Return(false)
-	peers.On("Add", mock.Anything, mock.Anything).Return(nil)
+	peers.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	pending := &PendingManagerMock{}
 	pending.On("Claim", mock.Anything).Return(nil)
@@ -93,7 +93,7 @@ func (suite *ConnectorSuite) TestConnectorSuccess() {
 
 	pending.AssertCalled(t, "Claim", address)
 	pending.AssertCalled(t, "Release", address)
-	peers.AssertCalled(t, "Add", conn, nonce)
+	peers.AssertCalled(t, "Add", conn, nonce, CompressionNone)
 	rep.AssertCalled(t, "Success", address)
 	events.AssertCalled(t, "Connected", address)
 
@@ -122,7 +122,7 @@ func (suite *ConnectorSuite) TestConnectorClaimFails() {
 
 	peers := &PeerManagerMock{}
 	peers.On("Known", mock.Anything).Return(false)
-	peers.On("Add", mock.Anything, mock.Anything).Return(nil)
+	peers.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	pending := &PendingManagerMock{}
 	pending.On("Claim", mock.Anything).Return(errors.New("could not claim slot"))
@@ -146,7 +146,7 @@ func (suite *ConnectorSuite) TestConnectorClaimFails() {
 	pending.AssertCalled(t, "Claim", address)
 
 	pending.AssertNotCalled(t, "Release", mock.Anything)
-	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
 	rep.AssertNotCalled(t, "Success", mock.Anything)
 	conn.AssertNotCalled(t, "Close")
 	rep.AssertNotCalled(t, "Failure", mock.Anything)
@@ -174,7 +174,7 @@ func (suite *ConnectorSuite) TestConnectorDialFails() {
 
 	peers := &PeerManagerMock{}
 	peers.On("Known", mock.Anything).Return(false)
-	peers.On("Add", mock.Anything, mock.Anything).Return(nil)
+	peers.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	pending := &PendingManagerMock{}
 	pending.On("Claim", mock.Anything).Return(nil)
@@ -199,7 +199,7 @@ func (suite *ConnectorSuite) TestConnectorDialFails() {
 	pending.AssertCalled(t, "Release", address)
 	rep.AssertCalled(t, "Failure", address)
 
-	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
 	rep.AssertNotCalled(t, "Success", mock.Anything)
 	conn.AssertNotCalled(t, "Close")
 	book.AssertNotCalled(t, "Block", mock.Anything)
@@ -226,7 +226,7 @@ func (suite *ConnectorSuite) TestConnectorWriteFails() {
 
 	peers := &PeerManagerMock{}
 	peers.On("Known", mock.Anything).Return(false)
-	peers.On("Add", mock.Anything, mock.Anything).Return(nil)
+	peers.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	pending := &PendingManagerMock{}
 	pending.On("Claim", mock.Anything).Return(nil)
@@ -252,7 +252,7 @@ func (suite *ConnectorSuite) TestConnectorWriteFails() {
 	conn.AssertCalled(t, "Close")
 	rep.AssertCalled(t, "Failure", address)
 
-	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
 	rep.AssertNotCalled(t, "Success", mock.Anything)
 	book.AssertNotCalled(t, "Block", mock.Anything)
 	events.AssertNotCalled(t, "Connected", mock.Anything)
@@ -278,7 +278,7 @@ func (suite *ConnectorSuite) TestConnectorReadFails() {
 
 	peers := &PeerManagerMock{}
 	peers.On("Known", mock.Anything).Return(false)
-	peers.On("Add", mock.Anything, mock.Anything).Return(nil)
+	peers.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	pending := &PendingManagerMock{}
 	pending.On("Claim", mock.Anything).Return(nil)
@@ -304,7 +304,7 @@ func (suite *ConnectorSuite) TestConnectorReadFails() {
 	conn.AssertCalled(t, "Close")
 	rep.AssertCalled(t, "Failure", address)
 
-	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
 	rep.AssertNotCalled(t, "Success", mock.Anything)
 	book.AssertNotCalled(t, "Block", mock.Anything)
 	events.AssertNotCalled(t, "Connected", mock.Anything)
@@ -330,7 +330,7 @@ func (suite *ConnectorSuite) TestConnectorNetworkMismatch() {
 
 	peers := &PeerManagerMock{}
 	peers.On("Known", mock.Anything).Return(false)
-	peers.On("Add", mock.Anything, mock.Anything).Return(nil)
+	peers.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	pending := &PendingManagerMock{}
 	pending.On("Claim", mock.Anything).Return(nil)
@@ -356,7 +356,7 @@ func (suite *ConnectorSuite) TestConnectorNetworkMismatch() {
 	conn.AssertCalled(t, "Close")
 	book.AssertCalled(t, "Block", address)
 
-	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
 	rep.AssertNotCalled(t, "Success", mock.Anything)
 	rep.AssertNotCalled(t, "Failure", mock.Anything)
 	events.AssertNotCalled(t, "Connected", mock.Anything)
@@ -381,7 +381,7 @@ func (suite *ConnectorSuite) TestConnectorNonceIdentical() {
 
 	peers := &PeerManagerMock{}
 	peers.On("Known", mock.Anything).Return(false)
-	peers.On("Add", mock.Anything, mock.Anything).Return(nil)
+	peers.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	pending := &PendingManagerMock{}
 	pending.On("Claim", mock.Anything).Return(nil)
@@ -407,7 +407,7 @@ func (suite *ConnectorSuite) TestConnectorNonceIdentical() {
 	conn.AssertCalled(t, "Close")
 	book.AssertCalled(t, "Block", address)
 
-	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
 	rep.AssertNotCalled(t, "Success", mock.Anything)
 	rep.AssertNotCalled(t, "Failure", mock.Anything)
 	events.AssertNotCalled(t, "Connected", mock.Anything)
@@ -433,7 +433,7 @@ func (suite *ConnectorSuite) TestConnectorNonceKnown() {
 
 	peers := &PeerManagerMock{}
 	peers.On("Known", mock.Anything).Return(true)
-	peers.On("Add", mock.Anything, mock.Anything).Return(nil)
+	peers.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	pending := &PendingManagerMock{}
 	pending.On("Claim", mock.Anything).Return(nil)
@@ -459,7 +459,7 @@ func (suite *ConnectorSuite) TestConnectorNonceKnown() {
 	conn.AssertCalled(t, "Close")
 	book.AssertCalled(t, "Block", address)
 
-	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	peers.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
 	rep.AssertNotCalled(t, "Success", mock.Anything)
 	rep.AssertNotCalled(t, "Failure", mock.Anything)
 	events.AssertNotCalled(t, "Connected", mock.Anything)
@@ -485,7 +485,7 @@ func (suite *ConnectorSuite) TestConnectorAddPeerFails() {
 
 	peers := &PeerManagerMock{}
 	peers.On("Known", mock.Anything).Return(false)
-	peers.On("Add", mock.Anything, mock.Anything).Return(errors.New("could not add peer"))
+	peers.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("could not add peer"))
 
 	pending := &PendingManagerMock{}
 	pending.On("Claim", mock.Anything).Return(nil)
@@ -508,7 +508,7 @@ func (suite *ConnectorSuite) TestConnectorAddPeerFails() {
 
 	pending.AssertCalled(t, "Claim", address)
 	pending.AssertCalled(t, "Release", address)
-	peers.AssertCalled(t, "Add", conn, nonce)
+	peers.AssertCalled(t, "Add", conn, nonce, CompressionNone)
 	conn.AssertCalled(t, "Close")
 
 	rep.AssertNotCalled(t, "Success", mock.Anything)
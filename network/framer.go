@@ -0,0 +1,197 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// maxFrameSize is the maximum size of a message we accept on the wire, both in
+// compressed and uncompressed form. It matches the default message size limit
+// of the codec, so that a peer can't make us allocate more than we would ever
+// decode.
+const maxFrameSize = 4 << 20
+
+// frameWriter buffers everything written to it until it is flushed, at which
+// point the buffered message is written to the connection as a single frame.
+// If the message is large enough, it is compressed with the negotiated
+// compression algorithm.
+type frameWriter struct {
+	w           io.Writer
+	compression Compression
+	threshold   uint
+	stats       *compressionStats
+	buf         bytes.Buffer
+	frame       []byte
+}
+
+func newFrameWriter(w io.Writer, compression Compression, threshold uint, stats *compressionStats) *frameWriter {
+	return &frameWriter{
+		w:           w,
+		compression: compression,
+		threshold:   threshold,
+		stats:       stats,
+	}
+}
+
+func (fw *frameWriter) Write(p []byte) (int, error) {
+	return fw.buf.Write(p)
+}
+
+func (fw *frameWriter) Flush() error {
+	defer fw.buf.Reset()
+
+	// compress the payload, unless it's too small for it to be worth it
+	raw := fw.buf.Bytes()
+	compression := fw.compression
+	payload := raw
+	if compression != CompressionNone && uint(len(raw)) >= fw.threshold {
+		compressed, err := compress(compression, raw)
+		if err != nil {
+			return errors.Wrap(err, "could not compress frame")
+		}
+		payload = compressed
+	}
+
+	// fall back to no compression if we didn't gain anything
+	if len(payload) >= len(raw) {
+		compression = CompressionNone
+		payload = raw
+	}
+
+	// write the frame header and payload with a single write, reusing the
+	// frame buffer between messages
+	fw.frame = append(fw.frame[:0], byte(compression), 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(fw.frame[1:5], uint32(len(raw)))
+	binary.LittleEndian.PutUint32(fw.frame[5:9], uint32(len(payload)))
+	fw.frame = append(fw.frame, payload...)
+	_, err := fw.w.Write(fw.frame)
+	if err != nil {
+		return errors.Wrap(err, "could not write frame")
+	}
+
+	if fw.stats != nil {
+		fw.stats.Sent(len(raw), len(payload))
+	}
+
+	return nil
+}
+
+// frameError is returned when a peer sends a frame that breaks the framing
+// protocol. The rest of the stream can no longer be interpreted, so it marks
+// the error as a violation, which makes the receiver drop the peer.
+type frameError struct {
+	err error
+}
+
+// Error returns the description of the invalid frame.
+func (e *frameError) Error() string {
+	return e.err.Error()
+}
+
+// Violation marks the error as a protocol violation by the sender.
+func (e *frameError) Violation() bool {
+	return true
+}
+
+// frameReader reads frames from the connection and decompresses them if they
+// were compressed by the sender. Once it read an invalid frame, it keeps
+// returning the same error, as the stream is out of sync.
+type frameReader struct {
+	r       io.Reader
+	stats   *compressionStats
+	buf     *bytes.Reader
+	header  []byte
+	payload []byte
+	err     error
+}
+
+func newFrameReader(r io.Reader, stats *compressionStats) *frameReader {
+	return &frameReader{
+		r:      r,
+		stats:  stats,
+		buf:    bytes.NewReader(nil),
+		header: make([]byte, 9),
+	}
+}
+
+func (fr *frameReader) Read(p []byte) (int, error) {
+	if fr.err != nil {
+		return 0, fr.err
+	}
+	for fr.buf.Len() == 0 {
+		err := fr.next()
+		if err != nil {
+			return 0, err
+		}
+	}
+	return fr.buf.Read(p)
+}
+
+func (fr *frameReader) next() error {
+
+	// read the frame header and check the announced sizes
+	header := fr.header
+	_, err := io.ReadFull(fr.r, header)
+	if err != nil {
+		return err
+	}
+	compression := Compression(header[0])
+	raw := binary.LittleEndian.Uint32(header[1:5])
+	wire := binary.LittleEndian.Uint32(header[5:9])
+	if raw > maxFrameSize || wire > maxFrameSize {
+		return fr.fail(errors.Errorf("frame too big (%v/%v)", raw, wire))
+	}
+
+	// read the payload into our reusable buffer and decompress it if needed
+	if uint32(cap(fr.payload)) < wire {
+		fr.payload = make([]byte, wire)
+	}
+	payload := fr.payload[:wire]
+	_, err = io.ReadFull(fr.r, payload)
+	if err != nil {
+		return err
+	}
+	if compression != CompressionNone {
+		payload, err = decompress(compression, payload, raw)
+		if err != nil {
+			return fr.fail(errors.Wrap(err, "could not decompress frame"))
+		}
+	}
+	if uint32(len(payload)) != raw {
+		return fr.fail(errors.Errorf("invalid frame size (%v != %v)", len(payload), raw))
+	}
+
+	if fr.stats != nil {
+		fr.stats.Received(int(raw), int(wire))
+	}
+
+	fr.buf.Reset(payload)
+	return nil
+}
+
+// fail records an invalid frame, so that we don't read any further from the
+// stream.
+func (fr *frameReader) fail(err error) error {
+	fr.err = &frameError{err: err}
+	return fr.err
+}
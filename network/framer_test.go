@@ -0,0 +1,116 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFramer(t *testing.T) {
+	small := []byte{1, 2, 3, 4}
+	large := bytes.Repeat([]byte{1, 2, 3, 4, 5, 6, 7, 8}, 1024)
+	for _, compression := range []Compression{CompressionNone, CompressionLZ4, CompressionFlate} {
+		conn := &bytes.Buffer{}
+		stats := &compressionStats{}
+		fw := newFrameWriter(conn, compression, 128, stats)
+
+		_, err := fw.Write(small)
+		assert.Nil(t, err)
+		err = fw.Flush()
+		assert.Nil(t, err)
+		_, err = fw.Write(large[:4096])
+		assert.Nil(t, err)
+		_, err = fw.Write(large[4096:])
+		assert.Nil(t, err)
+		err = fw.Flush()
+		assert.Nil(t, err)
+
+		if compression == CompressionNone {
+			assert.Equal(t, len(small)+len(large)+18, conn.Len())
+		} else {
+			assert.True(t, conn.Len() < len(large))
+		}
+
+		fr := newFrameReader(conn, stats)
+		data := make([]byte, len(small)+len(large))
+		_, err = io.ReadFull(fr, data)
+		assert.Nil(t, err)
+		assert.Equal(t, small, data[:len(small)])
+		assert.Equal(t, large, data[len(small):])
+
+		_, err = fr.Read(data)
+		assert.Equal(t, io.EOF, err)
+
+		out, in := stats.Ratios()
+		assert.Equal(t, out, in)
+	}
+}
+
+func TestFramerOversized(t *testing.T) {
+	conn := bytes.NewBuffer([]byte{0, 255, 255, 255, 255, 255, 255, 255, 255})
+	fr := newFrameReader(conn, nil)
+	_, err := fr.Read(make([]byte, 16))
+	if assert.IsType(t, &frameError{}, err) {
+		assert.True(t, err.(violation).Violation())
+	}
+}
+
+func TestFramerOversizedRaw(t *testing.T) {
+	conn := bytes.NewBuffer([]byte{byte(CompressionFlate), 0, 0, 0, 255, 16, 0, 0, 0})
+	fr := newFrameReader(conn, nil)
+	_, err := fr.Read(make([]byte, 16))
+	assert.IsType(t, &frameError{}, err)
+}
+
+func TestFramerMismatch(t *testing.T) {
+	conn := bytes.NewBuffer([]byte{byte(CompressionNone), 8, 0, 0, 0, 4, 0, 0, 0, 1, 2, 3, 4})
+	conn.Write([]byte{0, 4, 0, 0, 0, 4, 0, 0, 0, 1, 2, 3, 4})
+	fr := newFrameReader(conn, nil)
+	_, err := fr.Read(make([]byte, 16))
+	assert.IsType(t, &frameError{}, err)
+
+	// the stream is out of sync, so we don't read the next frame
+	_, err = fr.Read(make([]byte, 16))
+	assert.IsType(t, &frameError{}, err)
+	assert.Equal(t, 13, conn.Len())
+}
+
+func BenchmarkFramer(b *testing.B) {
+	conn := &bytes.Buffer{}
+	fw := newFrameWriter(conn, CompressionNone, 1024, nil)
+	fr := newFrameReader(conn, nil)
+	msg := make([]byte, 512)
+	data := make([]byte, len(msg))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = fw.Write(msg)
+		err := fw.Flush()
+		if err != nil {
+			b.Fatal(err)
+		}
+		_, err = io.ReadFull(fr, data)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -197,8 +197,8 @@ type PeerManagerMock struct {
 	mock.Mock
 }
 
-func (pm *PeerManagerMock) Add(conn net.Conn, nonce []byte) error {
-	args := pm.Called(conn, nonce)
+func (pm *PeerManagerMock) Add(conn net.Conn, nonce []byte, compression Compression) error {
+	args := pm.Called(conn, nonce, compression)
 	return args.Error(0)
 }
 
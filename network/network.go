@@ -41,8 +41,11 @@ var (
 // Network defines the exposed API of the Alvalor network package.
 type Network interface {
 	Add(address string)
+	Subscribe(channel chan<- interface{}, filters ...func(interface{}) bool)
 	Send(address string, msg interface{}) error
 	Broadcast(msg interface{}, exclude ...string) error
+	Drop(address string) error
+	Penalize(address string)
 	Stop()
 	Stats()
 }
@@ -58,6 +61,7 @@ type simpleNetwork struct {
 	pending     pendingManager
 	peers       peerManager
 	rep         reputationManager
+	compression *compressionStats
 	stream      chan interface{}
 	subscribers []subscriber
 	events      eventManager
@@ -86,16 +90,18 @@ func New(log zerolog.Logger, codec Codec, options ...func(*Config)) Network {
 
 	// initialize the default configuration and apply custom options
 	cfg := &Config{
-		network:    Odin,
-		listen:     false,
-		address:    "0.0.0.0:31337",
-		minPeers:   3,
-		maxPeers:   10,
-		maxPending: 16,
-		nonce:      uuid.Must(uuid.NewV4()).Bytes(),
-		interval:   time.Second,
-		codec:      codec,
-		bufferSize: 16,
+		network:      Odin,
+		listen:       false,
+		address:      "0.0.0.0:31337",
+		minPeers:     3,
+		maxPeers:     10,
+		maxPending:   16,
+		nonce:        uuid.Must(uuid.NewV4()).Bytes(),
+		interval:     time.Second,
+		codec:        codec,
+		bufferSize:   16,
+		compressions: []Compression{CompressionLZ4, CompressionFlate},
+		threshold:    1024,
 	}
 	for _, option := range options {
 		option(cfg)
@@ -110,8 +116,12 @@ func New(log zerolog.Logger, codec Codec, options ...func(*Config)) Network {
 	pending := newSimplePendingManager(cfg.maxPending)
 	net.pending = pending
 
+	// initialize the statistics on the compression of messages
+	compression := &compressionStats{}
+	net.compression = compression
+
 	// initialize the peer manager that handles connected peers
-	peers := newSimplePeerManager(net, cfg.minPeers, cfg.maxPeers)
+	peers := newSimplePeerManager(net, cfg.minPeers, cfg.maxPeers, cfg.threshold, compression)
 	net.peers = peers
 
 	// initialize the reputation manager that handles reputation of peers
@@ -258,9 +268,27 @@ func (net *simpleNetwork) Send(address string, msg interface{}) error {
 	return net.peers.Send(address, msg)
 }
 
+// Drop disconnects the peer with the given address and lowers its reputation,
+// so that misbehaving peers can be removed by the layers above the network.
+func (net *simpleNetwork) Drop(address string) error {
+	net.rep.Failure(address)
+	err := net.peers.Drop(address)
+	if err != nil {
+		return errors.Wrap(err, "could not drop peer")
+	}
+	return nil
+}
+
+// Penalize lowers the reputation of the peer with the given address without
+// disconnecting it, so that misbehaving peers are less likely to be dialed again.
+func (net *simpleNetwork) Penalize(address string) {
+	net.rep.Failure(address)
+}
+
 // Stats will log information of the network layer.
 func (net *simpleNetwork) Stats() {
 	numPeers := net.peers.Count()
 	numPending := net.pending.Count()
-	net.log.Info().Uint("num_peers", numPeers).Uint("num_pending", numPending).Msg("stats")
+	ratioOut, ratioIn := net.compression.Ratios()
+	net.log.Info().Uint("num_peers", numPeers).Uint("num_pending", numPending).Float32("compression_out", ratioOut).Float32("compression_in", ratioIn).Msg("stats")
 }
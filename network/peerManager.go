@@ -22,12 +22,11 @@ import (
 	"net"
 	"sync"
 
-	"github.com/pierrec/lz4"
 	"github.com/pkg/errors"
 )
 
 type peerManager interface {
-	Add(conn net.Conn, nonce []byte) error
+	Add(conn net.Conn, nonce []byte, compression Compression) error
 	Send(address string, msg interface{}) error
 	Drop(address string) error
 	Count() uint
@@ -37,24 +36,28 @@ type peerManager interface {
 
 type simplePeerManager struct {
 	sync.Mutex
-	handlers handlerManager
-	min      uint
-	max      uint
-	buffer   uint
-	reg      map[string]*peer
+	handlers  handlerManager
+	min       uint
+	max       uint
+	buffer    uint
+	threshold uint
+	stats     *compressionStats
+	reg       map[string]*peer
 }
 
-func newSimplePeerManager(handlers handlerManager, min uint, max uint) *simplePeerManager {
+func newSimplePeerManager(handlers handlerManager, min uint, max uint, threshold uint, stats *compressionStats) *simplePeerManager {
 	return &simplePeerManager{
-		handlers: handlers,
-		min:      min,
-		max:      max,
-		buffer:   2048,
-		reg:      make(map[string]*peer),
+		handlers:  handlers,
+		min:       min,
+		max:       max,
+		buffer:    2048,
+		threshold: threshold,
+		stats:     stats,
+		reg:       make(map[string]*peer),
 	}
 }
 
-func (pm *simplePeerManager) Add(conn net.Conn, nonce []byte) error {
+func (pm *simplePeerManager) Add(conn net.Conn, nonce []byte, compression Compression) error {
 	pm.Lock()
 	defer pm.Unlock()
 
@@ -78,9 +81,9 @@ func (pm *simplePeerManager) Add(conn net.Conn, nonce []byte) error {
 		nonce:  nonce,
 	}
 
-	// initialize the readers and writers
-	r := lz4.NewReader(conn)
-	w := lz4.NewWriter(conn)
+	// initialize the readers and writers, which frame and compress messages
+	r := newFrameReader(conn, pm.stats)
+	w := newFrameWriter(conn, compression, pm.threshold, pm.stats)
 
 	// launch the message processing routines
 	pm.handlers.Sender(address, p.output, w)
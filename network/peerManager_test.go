@@ -29,10 +29,14 @@ func TestNewPeerManager(t *testing.T) {
 	handlers := &HandlerManagerMock{}
 	min := uint(1)
 	max := uint(2)
-	peers := newSimplePeerManager(handlers, min, max)
+	threshold := uint(3)
+	stats := &compressionStats{}
+	peers := newSimplePeerManager(handlers, min, max, threshold, stats)
 	assert.Equal(t, handlers, peers.handlers)
 	assert.Equal(t, min, peers.min)
 	assert.Equal(t, max, peers.max)
+	assert.Equal(t, threshold, peers.threshold)
+	assert.Equal(t, stats, peers.stats)
 	assert.NotZero(t, peers.buffer)
 	assert.NotNil(t, peers.reg)
 }
@@ -54,18 +58,18 @@ func TestPeerManagerAdd(t *testing.T) {
 	}
 
 	peers.max = 0
-	err := peers.Add(conn, nonce)
+	err := peers.Add(conn, nonce, CompressionLZ4)
 	assert.NotNil(t, err)
 	assert.Empty(t, peers.reg)
 
 	peers.max = 2
 	peers.reg[address] = &peer{}
-	err = peers.Add(conn, nonce)
+	err = peers.Add(conn, nonce, CompressionLZ4)
 	assert.NotNil(t, err)
 	assert.Len(t, peers.reg, 1)
 
 	delete(peers.reg, address)
-	err = peers.Add(conn, nonce)
+	err = peers.Add(conn, nonce, CompressionLZ4)
 	assert.Nil(t, err)
 	if assert.Contains(t, peers.reg, address) {
 		p := peers.reg[address]
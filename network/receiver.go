@@ -25,6 +25,12 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// violation is implemented by decoding errors that are caused by a peer
+// breaking the protocol limits, for example by sending oversized messages.
+type violation interface {
+	Violation() bool
+}
+
 func handleReceiving(log zerolog.Logger, wg *sync.WaitGroup, cfg *Config, rep reputationManager, peers peerManager, address string, r io.Reader, input chan<- interface{}) {
 	defer wg.Done()
 
@@ -45,6 +51,12 @@ func handleReceiving(log zerolog.Logger, wg *sync.WaitGroup, cfg *Config, rep re
 			log.Debug().Msg("network connection closed")
 			break
 		}
+		v, ok := errors.Cause(err).(violation)
+		if ok && v.Violation() {
+			log.Error().Err(err).Msg("peer violated message limits")
+			rep.Failure(address)
+			break
+		}
 		if err != nil {
 			log.Error().Err(err).Msg("could not read message")
 			rep.Failure(address)
@@ -164,3 +164,51 @@ func (suite *ReceiverSuite) TestReceiverError() {
 	rep.AssertCalled(t, "Failure", address)
 	peers.AssertCalled(t, "Drop", address)
 }
+
+type violationError struct{}
+
+func (violationError) Error() string {
+	return "message limit exceeded"
+}
+
+func (violationError) Violation() bool {
+	return true
+}
+
+func (suite *ReceiverSuite) TestReceiverViolation() {
+
+	// arrange
+	address := "192.0.2.100:1337"
+	input := make(chan interface{}, 16)
+	r := &bytes.Buffer{}
+
+	message := "message"
+
+	rep := &ReputationManagerMock{}
+	rep.On("Failure", mock.Anything)
+
+	codec := &CodecMock{}
+	codec.On("Decode", r).Return(nil, violationError{}).Once()
+	codec.On("Decode", r).Return(message, nil).Once()
+	codec.On("Decode", r).Return(nil, io.EOF)
+
+	peers := &PeerManagerMock{}
+	peers.On("Drop", mock.Anything).Return(nil)
+
+	// act
+	suite.cfg.codec = codec
+	go handleReceiving(suite.log, &suite.wg, &suite.cfg, rep, peers, address, r, input)
+	var msgs []interface{}
+	for msg := range input {
+		msgs = append(msgs, msg)
+	}
+	suite.wg.Wait()
+
+	// assert
+	t := suite.T()
+
+	assert.Empty(t, msgs)
+
+	rep.AssertCalled(t, "Failure", address)
+	peers.AssertCalled(t, "Drop", address)
+}
@@ -26,6 +26,12 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// flusher is implemented by writers that buffer a message until it is
+// complete, such as our frame writer.
+type flusher interface {
+	Flush() error
+}
+
 func handleSending(log zerolog.Logger, wg *sync.WaitGroup, cfg *Config, rep reputationManager, events eventManager, address string, output <-chan interface{}, w io.Writer) {
 	defer wg.Done()
 
@@ -43,6 +49,8 @@ func handleSending(log zerolog.Logger, wg *sync.WaitGroup, cfg *Config, rep repu
 	// we keep reading messages from the output channel and writing them to the network connection
 	var msg interface{}
 	var ok bool
+	heartbeat := time.NewTimer(interval)
+	defer heartbeat.Stop()
 Loop:
 	for {
 
@@ -52,12 +60,24 @@ Loop:
 			if !ok {
 				break Loop
 			}
-		case <-time.After(interval):
+		case <-heartbeat.C:
 			msg = &Ping{}
 		}
 
+		// reuse the heartbeat timer instead of creating a new one per message
+		heartbeat.Stop()
+		select {
+		case <-heartbeat.C:
+		default:
+		}
+		heartbeat.Reset(interval)
+
 		// send the message, break the loop on closed connection, register other failures
 		err := codec.Encode(w, msg)
+		f, ok := w.(flusher)
+		if err == nil && ok {
+			err = f.Flush()
+		}
 		if errors.Cause(err) == io.EOF || isClosedErr(err) {
 			log.Debug().Msg("network connection closed")
 			break
@@ -0,0 +1,253 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+
+	"github.com/alvalor/alvalor-go/node/repos/headers"
+	"github.com/alvalor/alvalor-go/node/repos/inventories"
+	"github.com/alvalor/alvalor-go/node/state/peers"
+	"github.com/alvalor/alvalor-go/node/state/subscribers"
+	"github.com/alvalor/alvalor-go/node/sync/compact"
+	"github.com/alvalor/alvalor-go/node/sync/orchestration"
+	"github.com/alvalor/alvalor-go/node/sync/progress"
+	"github.com/alvalor/alvalor-go/node/sync/reorg"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// peerTracker adapts the peer state to the handlers, which don't care whether
+// a peer disconnected before we could update its state.
+type peerTracker struct {
+	state *peers.State
+}
+
+func (pt peerTracker) Active(address string) {
+	pt.state.Active(address)
+}
+
+func (pt peerTracker) Inactive(address string) {
+	_ = pt.state.Inactive(address)
+}
+
+func (pt peerTracker) Received(address string, hash types.Hash) {
+	_ = pt.state.Received(address, hash)
+}
+
+func (pt peerTracker) Missing(address string, hash types.Hash) {
+	_ = pt.state.Missing(address, hash)
+}
+
+// signaler passes received inventories on to the block collection; inventories
+// of blocks we are not collecting are simply kept.
+type signaler struct {
+	collector *orchestration.Manager
+}
+
+func (s signaler) Signal(hash types.Hash) error {
+	err := s.collector.Inventory(hash)
+	if errors.Cause(err) == orchestration.ErrNotExist {
+		return nil
+	}
+	return err
+}
+
+// connector passes assembled blocks on to the reorg engine, which is created
+// after the block assembly, as it depends on it through the block collection.
+type connector struct {
+	node *Node
+}
+
+func (c connector) Connect(block *types.Block) error {
+	return c.node.engine.Connect(block)
+}
+
+// follower passes the changes of the best path in the headers repository to
+// the reorg engine, as the branch from the fork point to the best header. If
+// the engine is not on a path with that fork point, for example right after a
+// restart, it gets the whole best path instead.
+type follower struct {
+	headers *headers.Persistent
+	engine  *reorg.Engine
+}
+
+func (f follower) Follow(change *headers.Reorg) error {
+
+	// if the fork point left the best path already, the engine follows the
+	// change that replaced it instead
+	branch, err := f.headers.Since(change.Ancestor)
+	if errors.Cause(err) == headers.ErrPath {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not get changed branch")
+	}
+	err = f.engine.Follow(branch)
+	if errors.Cause(err) != reorg.ErrFork {
+		return err
+	}
+
+	// otherwise, follow the whole best path, which starts at the root
+	path, _ := f.headers.Path()
+	best := make([]types.Hash, 0, len(path))
+	for i := len(path) - 1; i >= 0; i-- {
+		best = append(best, path[i])
+	}
+	return f.engine.Follow(best)
+}
+
+// invalidator marks the headers of invalid blocks in the headers repository
+// and passes the branch that replaces them on the best path back to the reorg
+// engine.
+type invalidator struct {
+	headers *headers.Persistent
+}
+
+func (i invalidator) Invalidate(hash types.Hash) ([]types.Hash, error) {
+	change, err := i.headers.Invalidate(hash)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not invalidate header")
+	}
+	if change == nil {
+		return nil, nil
+	}
+	return i.headers.Since(change.Ancestor)
+}
+
+// entityEvents publishes the entities accepted by the entity handler and
+// passes transactions on to the block collection.
+type entityEvents struct {
+	log       zerolog.Logger
+	events    *subscribers.Manager
+	collector *orchestration.Manager
+}
+
+func (ee entityEvents) Header(hash types.Hash) {
+	err := ee.events.Header(hash)
+	if err != nil {
+		ee.log.Warn().Err(err).Hex("hash", hash[:]).Msg("could not publish header event")
+	}
+}
+
+func (ee entityEvents) Transaction(hash types.Hash) {
+	err := ee.events.Transaction(hash)
+	if err != nil {
+		ee.log.Warn().Err(err).Hex("hash", hash[:]).Msg("could not publish transaction event")
+	}
+	err = ee.collector.Transaction(hash)
+	if err != nil && errors.Cause(err) != orchestration.ErrNotExist {
+		ee.log.Error().Err(err).Hex("hash", hash[:]).Msg("could not pass transaction to block collection")
+	}
+}
+
+// chainEvents publishes the changes of the blockchain and the progress of the
+// synchronization, and propagates newly connected tips to our peers as compact
+// blocks.
+type chainEvents struct {
+	events      *subscribers.Manager
+	headers     *headers.Persistent
+	inventories *inventories.Repo
+	compact     *compact.Manager
+	progress    *progress.Tracker
+}
+
+func (ce chainEvents) Block(hash types.Hash) error {
+	err := ce.events.Block(hash)
+	if err != nil {
+		return errors.Wrap(err, "could not publish block event")
+	}
+
+	// let subscribers know how far we are behind our best header
+	height, err := ce.headers.Height(hash)
+	if err != nil {
+		return errors.Wrap(err, "could not get height of block")
+	}
+	ce.progress.Connected(height)
+	tip, err := ce.publish(height)
+	if err != nil {
+		return err
+	}
+
+	// only propagate the tip, so we don't flood peers while catching up
+	if tip != hash {
+		return nil
+	}
+	header, err := ce.headers.Get(hash)
+	if err != nil {
+		return errors.Wrap(err, "could not get header to propagate")
+	}
+	inv, err := ce.inventories.Get(hash)
+	if errors.Cause(err) == inventories.ErrNotExist {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not get inventory to propagate")
+	}
+	err = ce.compact.Propagate(header, inv)
+	if err != nil {
+		return errors.Wrap(err, "could not propagate block")
+	}
+
+	return nil
+}
+
+func (ce chainEvents) Disconnected(hash types.Hash) error {
+	err := ce.events.Disconnected(hash)
+	if err != nil {
+		return errors.Wrap(err, "could not publish disconnected event")
+	}
+
+	// our blockchain now ends at the parent of the disconnected block
+	height, err := ce.headers.Height(hash)
+	if err != nil {
+		return errors.Wrap(err, "could not get height of block")
+	}
+	ce.progress.Disconnected(height)
+	if height > 0 {
+		height--
+	}
+	_, err = ce.publish(height)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// publish lets subscribers know how far the given height of our blockchain is
+// behind our best header, which it returns.
+func (ce chainEvents) publish(height uint64) (types.Hash, error) {
+	path, _ := ce.headers.Recent(1)
+	if len(path) == 0 {
+		return types.ZeroHash, nil
+	}
+	best, err := ce.headers.Height(path[0])
+	if err != nil {
+		return types.ZeroHash, errors.Wrap(err, "could not get height of best header")
+	}
+	err = ce.events.Progress(height, best)
+	if err != nil {
+		return types.ZeroHash, errors.Wrap(err, "could not publish progress event")
+	}
+	return path[0], nil
+}
+
+func (ce chainEvents) Reorg(old types.Hash, new types.Hash, ancestor types.Hash, depth uint) error {
+	return ce.events.Reorg(old, new, ancestor, depth)
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import "time"
+
+// Config represents the parameters of a node.
+type Config struct {
+	interval        time.Duration
+	trickle         time.Duration
+	announce        time.Duration
+	requestTimeout  time.Duration
+	downloadTimeout time.Duration
+	compactTimeout  time.Duration
+	maxPartials     uint
+	partialQuota    uint
+	maxDownloads    uint
+	maxDepth        uint
+	maxOrphans      uint
+	orphanQuota     uint
+	orphanExpiry    time.Duration
+	cacheSize       uint
+	eventBuffer     uint
+	eventTimeout    time.Duration
+	workers         uint
+	entityQueue     uint
+	entityTotal     uint
+	reward          uint64
+	allocation      map[string]uint64
+}
+
+// DefaultConfig returns the default parameters of a node.
+func DefaultConfig() Config {
+	return Config{
+		interval:        time.Second,
+		trickle:         100 * time.Millisecond,
+		announce:        time.Minute,
+		requestTimeout:  30 * time.Second,
+		downloadTimeout: 10 * time.Second,
+		compactTimeout:  5 * time.Second,
+		maxPartials:     64,
+		partialQuota:    4,
+		maxDownloads:    64,
+		maxDepth:        100,
+		maxOrphans:      1024,
+		orphanQuota:     64,
+		orphanExpiry:    10 * time.Minute,
+		cacheSize:       8192,
+		eventBuffer:     1024,
+		eventTimeout:    10 * time.Millisecond,
+		workers:         8,
+		entityQueue:     4096,
+		entityTotal:     65536,
+		reward:          1000000,
+		allocation:      make(map[string]uint64),
+	}
+}
+
+// SetInterval allows us to configure the interval at which we check for
+// stalled header requests and expired downloads.
+func SetInterval(interval time.Duration) func(*Config) {
+	return func(cfg *Config) {
+		cfg.interval = interval
+	}
+}
+
+// SetTrickle allows us to configure the interval at which we send queued
+// transaction announcements to our peers.
+func SetTrickle(trickle time.Duration) func(*Config) {
+	return func(cfg *Config) {
+		cfg.trickle = trickle
+	}
+}
+
+// SetAnnounce allows us to configure the interval at which we send the bloom
+// filter of our memory pool to our peers.
+func SetAnnounce(announce time.Duration) func(*Config) {
+	return func(cfg *Config) {
+		cfg.announce = announce
+	}
+}
+
+// SetRequestTimeout allows us to configure how long a peer has to answer our
+// header requests before we drop it.
+func SetRequestTimeout(requestTimeout time.Duration) func(*Config) {
+	return func(cfg *Config) {
+		cfg.requestTimeout = requestTimeout
+	}
+}
+
+// SetDownloadTimeout allows us to configure how long a peer has to deliver
+// an entity before we retry the download with another peer.
+func SetDownloadTimeout(downloadTimeout time.Duration) func(*Config) {
+	return func(cfg *Config) {
+		cfg.downloadTimeout = downloadTimeout
+	}
+}
+
+// SetCompactTimeout allows us to configure how long a peer has to deliver the
+// missing transactions of a compact block before we download the full block.
+func SetCompactTimeout(compactTimeout time.Duration) func(*Config) {
+	return func(cfg *Config) {
+		cfg.compactTimeout = compactTimeout
+	}
+}
+
+// SetPartials allows us to configure the maximum number of compact blocks we
+// wait for missing transactions on and the maximum number from a single peer.
+func SetPartials(maxPartials uint, partialQuota uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxPartials = maxPartials
+		cfg.partialQuota = partialQuota
+	}
+}
+
+// SetMaxDownloads allows us to configure the maximum number of pending
+// downloads per peer.
+func SetMaxDownloads(maxDownloads uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxDownloads = maxDownloads
+	}
+}
+
+// SetMaxDepth allows us to configure the maximum number of blocks we
+// disconnect when switching to a new best path.
+func SetMaxDepth(maxDepth uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxDepth = maxDepth
+	}
+}
+
+// SetOrphans allows us to configure the maximum number of orphan headers, the
+// maximum number of orphans per peer and how long we keep them.
+func SetOrphans(maxOrphans uint, orphanQuota uint, orphanExpiry time.Duration) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxOrphans = maxOrphans
+		cfg.orphanQuota = orphanQuota
+		cfg.orphanExpiry = orphanExpiry
+	}
+}
+
+// SetCacheSize allows us to configure the number of headers we keep in memory.
+func SetCacheSize(cacheSize uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.cacheSize = cacheSize
+	}
+}
+
+// SetEvents allows us to configure the buffer size for events to subscribers
+// and how long we wait for a stalling subscriber.
+func SetEvents(eventBuffer uint, eventTimeout time.Duration) func(*Config) {
+	return func(cfg *Config) {
+		cfg.eventBuffer = eventBuffer
+		cfg.eventTimeout = eventTimeout
+	}
+}
+
+// SetWorkers allows us to configure the number of concurrent workers for each
+// stage of processing.
+func SetWorkers(workers uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.workers = workers
+	}
+}
+
+// SetEntityQueues allows us to configure how many entities we queue for each
+// peer and over all peers. A single message can carry up to a page of headers,
+// a batch of transactions or a mempool push, so the queue of each peer should
+// be able to hold the largest of them.
+func SetEntityQueues(entityQueue uint, entityTotal uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.entityQueue = entityQueue
+		cfg.entityTotal = entityTotal
+	}
+}
+
+// SetReward allows us to configure the amount credited to the miner of each
+// block on top of the fees.
+func SetReward(reward uint64) func(*Config) {
+	return func(cfg *Config) {
+		cfg.reward = reward
+	}
+}
+
+// SetAllocation allows us to configure the balance of an account as allocated
+// by the genesis block.
+func SetAllocation(account []byte, amount uint64) func(*Config) {
+	return func(cfg *Config) {
+		cfg.allocation[string(account)] = amount
+	}
+}
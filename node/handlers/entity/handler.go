@@ -20,8 +20,11 @@ package entity
 import (
 	"sync"
 
-	"github.com/alvalor/alvalor-go/types"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
+
+	"github.com/alvalor/alvalor-go/node/handlers/workers"
+	"github.com/alvalor/alvalor-go/types"
 )
 
 // Handler is the handler for entities. We use a struct rather than a
@@ -33,16 +36,69 @@ type Handler struct {
 	events       Events
 	headers      Headers
 	transactions Transactions
-	peers        Peers
+	orphans      Orphans
+	requests     Requests
+	validator    Validator
+	relay        Relay
+	pool         Pool
 }
 
-// Process is the entity handler's function for processing a new entity.
-func (handler *Handler) Process(wg *sync.WaitGroup, entity types.Entity) {
-	wg.Add(1)
+// NewHandler creates a new handler for entities received from the network.
+func NewHandler(log zerolog.Logger, net Network, paths Paths, events Events, headers Headers, transactions Transactions, orphans Orphans, requests Requests, validator Validator, relay Relay, pool Pool) *Handler {
+	return &Handler{
+		log:          log,
+		net:          net,
+		paths:        paths,
+		events:       events,
+		headers:      headers,
+		transactions: transactions,
+		orphans:      orphans,
+		requests:     requests,
+		validator:    validator,
+		relay:        relay,
+		pool:         pool,
+	}
+}
+
+// Process is the entity handler's function for processing a new entity, as
+// received from the peer with the given address. The entity is queued with the
+// worker pool, which processes the entities of each peer in order; the pool
+// should block when full, so that the messages carrying many entities slow
+// down instead of getting the peer dropped.
+func (handler *Handler) Process(wg *sync.WaitGroup, address string, entity types.Entity) {
+	var task func()
 	switch e := entity.(type) {
 	case *types.Header:
-		go handler.processHeader(wg, e)
+		task = func() { handler.processHeader(wg, address, e) }
 	case *types.Transaction:
-		go handler.processTransaction(wg, e)
+		task = func() { handler.processTransaction(wg, address, e) }
+	default:
+		return
+	}
+	wg.Add(1)
+	handler.dispatch(wg, address, task)
+}
+
+// dispatch submits the task to the worker pool and drops the peer if its own
+// queue is full. If the pool as a whole is busy, the task is discarded without
+// blaming the peer. If no worker pool is configured, the task runs on its own
+// goroutine.
+func (handler *Handler) dispatch(wg *sync.WaitGroup, address string, task func()) {
+	if handler.pool == nil {
+		go task()
+		return
+	}
+	err := handler.pool.Submit(address, task)
+	if err == nil {
+		return
+	}
+	wg.Done()
+	handler.log.Error().Err(err).Str("address", address).Msg("could not queue entity")
+	if errors.Cause(err) != workers.ErrFull {
+		return
+	}
+	err = handler.net.Drop(address)
+	if err != nil {
+		handler.log.Error().Err(err).Str("address", address).Msg("could not drop flooding peer")
 	}
 }
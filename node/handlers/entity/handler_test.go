@@ -16,3 +16,127 @@
 // along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
 
 package entity
+
+import (
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alvalor/alvalor-go/node/handlers/workers"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+func TestHandlerDispatchSuccess(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	entity := &types.Transaction{Nonce: 1}
+
+	// initialize mocks
+	pool := &PoolMock{}
+	transactions := &TransactionsMock{}
+	net := &NetworkMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:          zerolog.New(ioutil.Discard),
+		pool:         pool,
+		transactions: transactions,
+		net:          net,
+	}
+
+	// program mocks
+	pool.On("Submit", mock.Anything, mock.Anything).Return(nil)
+	transactions.On("Has", mock.Anything).Return(true)
+	net.On("Drop", mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, entity)
+	wg.Wait()
+
+	// check conditions
+	pool.AssertCalled(t, "Submit", address, mock.Anything)
+
+	transactions.AssertNumberOfCalls(t, "Has", 1)
+
+	net.AssertNotCalled(t, "Drop", mock.Anything)
+}
+
+func TestHandlerDispatchFull(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	entity := &types.Transaction{Nonce: 1}
+
+	// initialize mocks
+	pool := &PoolMock{}
+	transactions := &TransactionsMock{}
+	net := &NetworkMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:          zerolog.New(ioutil.Discard),
+		pool:         pool,
+		transactions: transactions,
+		net:          net,
+	}
+
+	// program mocks
+	pool.On("Submit", mock.Anything, mock.Anything).Return(errors.Wrap(workers.ErrFull, "could not submit"))
+	net.On("Drop", mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, entity)
+	wg.Wait()
+
+	// check conditions
+	transactions.AssertNotCalled(t, "Has", mock.Anything)
+
+	net.AssertCalled(t, "Drop", address)
+}
+
+func TestHandlerDispatchBusy(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	entity := &types.Transaction{Nonce: 1}
+
+	// initialize mocks
+	pool := &PoolMock{}
+	transactions := &TransactionsMock{}
+	net := &NetworkMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:          zerolog.New(ioutil.Discard),
+		pool:         pool,
+		transactions: transactions,
+		net:          net,
+	}
+
+	// program mocks
+	pool.On("Submit", mock.Anything, mock.Anything).Return(errors.Wrap(workers.ErrBusy, "could not submit"))
+	net.On("Drop", mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, entity)
+	wg.Wait()
+
+	// check conditions
+	transactions.AssertNotCalled(t, "Has", mock.Anything)
+
+	net.AssertNotCalled(t, "Drop", mock.Anything)
+}
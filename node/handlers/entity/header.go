@@ -20,20 +20,23 @@ package entity
 import (
 	"sync"
 
-	"github.com/alvalor/alvalor-go/node/state/peers"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+	"github.com/alvalor/alvalor-go/node/repos/headers"
+	"github.com/alvalor/alvalor-go/node/validation"
 	"github.com/alvalor/alvalor-go/types"
 )
 
-func (handler *Handler) processHeader(wg *sync.WaitGroup, header *types.Header) {
+func (handler *Handler) processHeader(wg *sync.WaitGroup, address string, header *types.Header) {
 	defer wg.Done()
 
-	// precompute header hash
-	header.Hash = header.GetHash()
-
 	// configure logger
 	with := handler.log.With()
 	with.Str("component", "entity")
 	with.Str("entity_type", "header")
+	with.Str("address", address)
 	with.Hex("hash", header.Hash[:])
 	log := with.Logger()
 
@@ -48,31 +51,56 @@ func (handler *Handler) processHeader(wg *sync.WaitGroup, header *types.Header)
 		return
 	}
 
-	// check the validity of the header
-	// TODO
+	// check the validity of the header; if we don't know the parent yet, we
+	// keep it as orphan, otherwise we drop the peer who sent an invalid header
+	err := handler.validator.Validate(header)
+	if errors.Cause(err) == validation.ErrParent {
+		handler.orphan(log, address, header)
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("invalid header")
+		handler.drop(log, address)
+		return
+	}
 
 	// add the header to the pathfinder
-	err := handler.headers.Add(header)
+	reorg, err := handler.headers.Add(header)
+	if errors.Cause(err) == headers.ErrOrphan {
+		handler.orphan(log, address, header)
+		return
+	}
+	if errors.Cause(err) == headers.ErrInvalid {
+		log.Error().Err(err).Msg("header extends invalid block")
+		handler.drop(log, address)
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("could not add header")
 		return
 	}
 
-	// we let subscribers know that we received a new header
+	// process the orphans that were waiting for this header right away, so
+	// that we never wait for space in the worker pool we are running on
+	for _, orphan := range handler.orphans.Take(header.Hash) {
+		wg.Add(1)
+		handler.processHeader(wg, orphan.Address, orphan.Header)
+	}
+
+	// we let subscribers know that we received a new header; we don't relay
+	// it, as peers learn about our new tips from the compact blocks we
+	// propagate once they are connected
 	handler.events.Header(header.Hash)
 
-	// we should propagate it to peers who are unaware of the header
-	// TODO: change broadcast to have target addresses and not exclusion
-	addresses := handler.peers.Addresses(peers.HasEntity(false, header.Hash))
-	err = handler.net.Broadcast(header, addresses...)
-	if err != nil {
-		log.Error().Err(err).Msg("could not propagate entity")
+	// if the best path did not change, we are done
+	if reorg == nil {
+		log.Debug().Msg("header processed")
 		return
 	}
 
-	// switch the downloader to the new best path
-	path, _ := handler.headers.Path()
-	err = handler.paths.Follow(path)
+	// switch to the new best path, which lets subscribers know about the
+	// reorganization once the blocks are connected
+	err = handler.paths.Follow(reorg)
 	if err != nil {
 		log.Error().Err(err).Msg("could not follow changed path")
 		return
@@ -80,3 +108,53 @@ func (handler *Handler) processHeader(wg *sync.WaitGroup, header *types.Header)
 
 	log.Debug().Msg("header processed")
 }
+
+// orphan keeps a header with unknown parent in the orphan pool and requests
+// the missing headers from the peer who sent it.
+func (handler *Handler) orphan(log zerolog.Logger, address string, header *types.Header) {
+
+	// add the header to the orphan pool, which is bounded by peer
+	err := handler.orphans.Add(address, header)
+	if err != nil {
+		log.Debug().Err(err).Msg("could not add orphan")
+		return
+	}
+
+	// headers we created ourselves have nobody to request the parent from
+	if address == "" {
+		log.Debug().Msg("orphan header added")
+		return
+	}
+
+	// request the headers up to the missing parent
+	locators, _ := handler.headers.Locators()
+	request := &message.GetHeaders{
+		Locators: locators,
+		Stop:     header.Parent,
+		Max:      message.MaxHeaders,
+	}
+	err = handler.requests.Start(address, request.Locators, request.Max)
+	if err != nil {
+		log.Debug().Err(err).Msg("could not start parent request")
+		return
+	}
+	err = handler.net.Send(address, request)
+	if err != nil {
+		log.Error().Err(err).Msg("could not send parent request")
+		_, _ = handler.requests.Finish(address)
+		return
+	}
+
+	log.Debug().Msg("orphan header added")
+}
+
+// drop disconnects a peer who sent us an invalid entity.
+func (handler *Handler) drop(log zerolog.Logger, address string) {
+	if address == "" {
+		return
+	}
+	err := handler.net.Drop(address)
+	if err != nil {
+		log.Error().Err(err).Msg("could not drop peer")
+	}
+}
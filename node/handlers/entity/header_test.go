@@ -23,6 +23,9 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+	"github.com/alvalor/alvalor-go/node/repos/headers"
+	"github.com/alvalor/alvalor-go/node/validation"
 	"github.com/alvalor/alvalor-go/types"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/mock"
@@ -32,47 +35,42 @@ func TestHeaderKnown(t *testing.T) {
 
 	// initialize parameters
 	address1 := "192.0.2.1"
-	address2 := "192.0.2.2"
-	address3 := "192.0.2.3"
-	hash1 := types.Hash{0x1}
-	hash2 := types.Hash{0x2}
-	hash3 := types.Hash{0x3}
 
 	// initialize entities
 	wg := &sync.WaitGroup{}
 	entity := &types.Header{Nonce: 1}
-	hash := entity.GetHash()
-	addresses := []string{address1, address2, address3}
-	path := []types.Hash{hash1, hash2, hash3}
+	entity.Hash = entity.GetHash()
+	hash := entity.Hash
 
 	// initialize mocks
 	headers := &HeadersMock{}
 	events := &EventsMock{}
-	peers := &PeersMock{}
 	net := &NetworkMock{}
 	paths := &PathsMock{}
+	orphans := &OrphansMock{}
+	validator := &ValidatorMock{}
 
 	// program mocks
+	validator.On("Validate", mock.Anything).Return(nil)
 	headers.On("Has", mock.Anything).Return(true)
-	headers.On("Add", mock.Anything).Return(nil)
+	headers.On("Add", mock.Anything).Return(nil, nil)
 	events.On("Header", mock.Anything)
-	peers.On("Addresses", mock.Anything).Return(addresses)
-	net.On("Broadcast", mock.Anything, mock.Anything).Return(nil)
-	headers.On("Path").Return(path, 0)
 	paths.On("Follow", mock.Anything).Return(nil)
+	orphans.On("Take", mock.Anything).Return(nil)
 
 	// initialize handler
 	handler := &Handler{
-		log:     zerolog.New(ioutil.Discard),
-		headers: headers,
-		events:  events,
-		peers:   peers,
-		net:     net,
-		paths:   paths,
+		log:       zerolog.New(ioutil.Discard),
+		headers:   headers,
+		events:    events,
+		net:       net,
+		paths:     paths,
+		orphans:   orphans,
+		validator: validator,
 	}
 
 	// execute process
-	handler.Process(wg, entity)
+	handler.Process(wg, address1, entity)
 	wg.Wait()
 
 	// check conditions
@@ -84,11 +82,7 @@ func TestHeaderKnown(t *testing.T) {
 
 	events.AssertNumberOfCalls(t, "Header", 0)
 
-	peers.AssertNumberOfCalls(t, "Addresses", 0)
-
-	net.AssertNumberOfCalls(t, "Broadcast", 0)
-
-	headers.AssertNumberOfCalls(t, "Path", 0)
+	headers.AssertNumberOfCalls(t, "Locators", 0)
 
 	paths.AssertNumberOfCalls(t, "Follow", 0)
 }
@@ -97,47 +91,42 @@ func TestHeaderAddFails(t *testing.T) {
 
 	// initialize parameters
 	address1 := "192.0.2.1"
-	address2 := "192.0.2.2"
-	address3 := "192.0.2.3"
-	hash1 := types.Hash{0x1}
-	hash2 := types.Hash{0x2}
-	hash3 := types.Hash{0x3}
 
 	// initialize entities
 	wg := &sync.WaitGroup{}
 	entity := &types.Header{Nonce: 1}
-	hash := entity.GetHash()
-	addresses := []string{address1, address2, address3}
-	path := []types.Hash{hash1, hash2, hash3}
+	entity.Hash = entity.GetHash()
+	hash := entity.Hash
 
 	// initialize mocks
 	headers := &HeadersMock{}
 	events := &EventsMock{}
-	peers := &PeersMock{}
 	net := &NetworkMock{}
 	paths := &PathsMock{}
+	orphans := &OrphansMock{}
+	validator := &ValidatorMock{}
 
 	// program mocks
+	validator.On("Validate", mock.Anything).Return(nil)
 	headers.On("Has", mock.Anything).Return(false)
-	headers.On("Add", mock.Anything).Return(errors.New(""))
+	headers.On("Add", mock.Anything).Return(nil, errors.New(""))
 	events.On("Header", mock.Anything)
-	peers.On("Addresses", mock.Anything).Return(addresses)
-	net.On("Broadcast", mock.Anything, mock.Anything).Return(nil)
-	headers.On("Path").Return(path, 0)
 	paths.On("Follow", mock.Anything).Return(nil)
+	orphans.On("Take", mock.Anything).Return(nil)
 
 	// initialize handler
 	handler := &Handler{
-		log:     zerolog.New(ioutil.Discard),
-		headers: headers,
-		events:  events,
-		peers:   peers,
-		net:     net,
-		paths:   paths,
+		log:       zerolog.New(ioutil.Discard),
+		headers:   headers,
+		events:    events,
+		net:       net,
+		paths:     paths,
+		orphans:   orphans,
+		validator: validator,
 	}
 
 	// execute process
-	handler.Process(wg, entity)
+	handler.Process(wg, address1, entity)
 	wg.Wait()
 
 	// check conditions
@@ -151,60 +140,54 @@ func TestHeaderAddFails(t *testing.T) {
 
 	events.AssertNumberOfCalls(t, "Header", 0)
 
-	peers.AssertNumberOfCalls(t, "Addresses", 0)
-
-	net.AssertNumberOfCalls(t, "Broadcast", 0)
-
-	headers.AssertNumberOfCalls(t, "Path", 0)
+	headers.AssertNumberOfCalls(t, "Locators", 0)
 
 	paths.AssertNumberOfCalls(t, "Follow", 0)
 }
 
-func TestHeaderBroadcastFails(t *testing.T) {
+func TestHeaderFollowFails(t *testing.T) {
 
 	// initialize parameters
 	address1 := "192.0.2.1"
-	address2 := "192.0.2.2"
-	address3 := "192.0.2.3"
 	hash1 := types.Hash{0x1}
 	hash2 := types.Hash{0x2}
-	hash3 := types.Hash{0x3}
 
 	// initialize entities
 	wg := &sync.WaitGroup{}
 	entity := &types.Header{Nonce: 1}
-	hash := entity.GetHash()
-	addresses := []string{address1, address2, address3}
-	path := []types.Hash{hash1, hash2, hash3}
+	entity.Hash = entity.GetHash()
+	hash := entity.Hash
+	reorg := &headers.Reorg{Old: hash2, New: hash1, Ancestor: hash2}
 
 	// initialize mocks
 	headers := &HeadersMock{}
 	events := &EventsMock{}
-	peers := &PeersMock{}
 	net := &NetworkMock{}
 	paths := &PathsMock{}
+	orphans := &OrphansMock{}
+	validator := &ValidatorMock{}
 
 	// program mocks
+	validator.On("Validate", mock.Anything).Return(nil)
 	headers.On("Has", mock.Anything).Return(false)
-	headers.On("Add", mock.Anything).Return(nil)
+	headers.On("Add", mock.Anything).Return(reorg, nil)
 	events.On("Header", mock.Anything)
-	peers.On("Addresses", mock.Anything).Return(addresses)
-	net.On("Broadcast", mock.Anything, mock.Anything).Return(errors.New(""))
-	headers.On("Path").Return(path, 0)
-	paths.On("Follow", mock.Anything).Return(nil)
+	paths.On("Follow", mock.Anything).Return(errors.New(""))
+	orphans.On("Take", mock.Anything).Return(nil)
 
 	// initialize handler
 	handler := &Handler{
-		log:     zerolog.New(ioutil.Discard),
-		headers: headers,
-		events:  events,
-		peers:   peers,
-		net:     net,
-		paths:   paths,
+		log:       zerolog.New(ioutil.Discard),
+		headers:   headers,
+		events:    events,
+		net:       net,
+		paths:     paths,
+		orphans:   orphans,
+		validator: validator,
 	}
 
 	// execute process
-	handler.Process(wg, entity)
+	handler.Process(wg, address1, entity)
 	wg.Wait()
 
 	// check conditions
@@ -220,64 +203,54 @@ func TestHeaderBroadcastFails(t *testing.T) {
 		events.AssertCalled(t, "Header", hash)
 	}
 
-	if peers.AssertNumberOfCalls(t, "Addresses", 1) {
-		peers.AssertCalled(t, "Addresses", mock.Anything)
-	}
-
-	if net.AssertNumberOfCalls(t, "Broadcast", 1) {
-		net.AssertCalled(t, "Broadcast", entity, addresses)
+	if paths.AssertNumberOfCalls(t, "Follow", 1) {
+		paths.AssertCalled(t, "Follow", reorg)
 	}
-
-	headers.AssertNumberOfCalls(t, "Path", 0)
-
-	paths.AssertNumberOfCalls(t, "Follow", 0)
 }
 
-func TestHeaderFollowFails(t *testing.T) {
+func TestHeaderSuccess(t *testing.T) {
 
 	// initialize parameters
 	address1 := "192.0.2.1"
-	address2 := "192.0.2.2"
-	address3 := "192.0.2.3"
 	hash1 := types.Hash{0x1}
 	hash2 := types.Hash{0x2}
-	hash3 := types.Hash{0x3}
 
 	// initialize entities
 	wg := &sync.WaitGroup{}
 	entity := &types.Header{Nonce: 1}
-	hash := entity.GetHash()
-	addresses := []string{address1, address2, address3}
-	path := []types.Hash{hash1, hash2, hash3}
+	entity.Hash = entity.GetHash()
+	hash := entity.Hash
+	reorg := &headers.Reorg{Old: hash2, New: hash1, Ancestor: hash2}
 
 	// initialize mocks
 	headers := &HeadersMock{}
 	events := &EventsMock{}
-	peers := &PeersMock{}
 	net := &NetworkMock{}
 	paths := &PathsMock{}
+	orphans := &OrphansMock{}
+	validator := &ValidatorMock{}
 
 	// program mocks
+	validator.On("Validate", mock.Anything).Return(nil)
 	headers.On("Has", mock.Anything).Return(false)
-	headers.On("Add", mock.Anything).Return(nil)
+	headers.On("Add", mock.Anything).Return(reorg, nil)
 	events.On("Header", mock.Anything)
-	peers.On("Addresses", mock.Anything).Return(addresses)
-	net.On("Broadcast", mock.Anything, mock.Anything).Return(nil)
-	headers.On("Path").Return(path, 0)
-	paths.On("Follow", mock.Anything).Return(errors.New(""))
+	paths.On("Follow", mock.Anything).Return(nil)
+	orphans.On("Take", mock.Anything).Return(nil)
 
 	// initialize handler
 	handler := &Handler{
-		log:     zerolog.New(ioutil.Discard),
-		headers: headers,
-		events:  events,
-		peers:   peers,
-		net:     net,
-		paths:   paths,
+		log:       zerolog.New(ioutil.Discard),
+		headers:   headers,
+		events:    events,
+		net:       net,
+		paths:     paths,
+		orphans:   orphans,
+		validator: validator,
 	}
 
 	// execute process
-	handler.Process(wg, entity)
+	handler.Process(wg, address1, entity)
 	wg.Wait()
 
 	// check conditions
@@ -293,96 +266,248 @@ func TestHeaderFollowFails(t *testing.T) {
 		events.AssertCalled(t, "Header", hash)
 	}
 
-	if peers.AssertNumberOfCalls(t, "Addresses", 1) {
-		peers.AssertCalled(t, "Addresses", mock.Anything)
+	if paths.AssertNumberOfCalls(t, "Follow", 1) {
+		paths.AssertCalled(t, "Follow", reorg)
+	}
+}
+
+func TestHeaderOrphan(t *testing.T) {
+
+	// initialize parameters
+	address1 := "192.0.2.1"
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	entity := &types.Header{Parent: hash2, Nonce: 1}
+	orphan := headers.ErrOrphan
+	locators := []types.Hash{hash1}
+	request := &message.GetHeaders{Locators: locators, Stop: hash2, Max: message.MaxHeaders}
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	events := &EventsMock{}
+	net := &NetworkMock{}
+	orphans := &OrphansMock{}
+	validator := &ValidatorMock{}
+	requests := &RequestsMock{}
+
+	// program mocks
+	validator.On("Validate", mock.Anything).Return(nil)
+	headers.On("Has", mock.Anything).Return(false)
+	headers.On("Add", mock.Anything).Return(nil, orphan)
+	headers.On("Locators").Return(locators, 0)
+	orphans.On("Add", mock.Anything, mock.Anything).Return(nil)
+	requests.On("Start", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// initialize handler
+	handler := &Handler{
+		log:       zerolog.New(ioutil.Discard),
+		headers:   headers,
+		events:    events,
+		net:       net,
+		orphans:   orphans,
+		validator: validator,
+		requests:  requests,
 	}
 
-	if net.AssertNumberOfCalls(t, "Broadcast", 1) {
-		net.AssertCalled(t, "Broadcast", entity, addresses)
+	// execute process
+	handler.Process(wg, address1, entity)
+	wg.Wait()
+
+	// check conditions
+	if orphans.AssertNumberOfCalls(t, "Add", 1) {
+		orphans.AssertCalled(t, "Add", address1, entity)
 	}
 
-	if headers.AssertNumberOfCalls(t, "Path", 1) {
-		headers.AssertCalled(t, "Path")
+	if requests.AssertNumberOfCalls(t, "Start", 1) {
+		requests.AssertCalled(t, "Start", address1, request.Locators, request.Max)
 	}
 
-	if paths.AssertNumberOfCalls(t, "Follow", 1) {
-		paths.AssertCalled(t, "Follow", path)
+	if net.AssertNumberOfCalls(t, "Send", 1) {
+		net.AssertCalled(t, "Send", address1, request)
 	}
+
+	events.AssertNumberOfCalls(t, "Header", 0)
 }
 
-func TestHeaderSuccess(t *testing.T) {
+func TestHeaderOrphanQuota(t *testing.T) {
 
 	// initialize parameters
 	address1 := "192.0.2.1"
-	address2 := "192.0.2.2"
-	address3 := "192.0.2.3"
-	hash1 := types.Hash{0x1}
 	hash2 := types.Hash{0x2}
-	hash3 := types.Hash{0x3}
 
 	// initialize entities
 	wg := &sync.WaitGroup{}
-	entity := &types.Header{Nonce: 1}
-	hash := entity.GetHash()
-	addresses := []string{address1, address2, address3}
-	path := []types.Hash{hash1, hash2, hash3}
+	entity := &types.Header{Parent: hash2, Nonce: 1}
+	orphan := headers.ErrOrphan
 
 	// initialize mocks
 	headers := &HeadersMock{}
-	events := &EventsMock{}
-	peers := &PeersMock{}
 	net := &NetworkMock{}
-	paths := &PathsMock{}
+	orphans := &OrphansMock{}
+	validator := &ValidatorMock{}
+	requests := &RequestsMock{}
 
 	// program mocks
+	validator.On("Validate", mock.Anything).Return(nil)
 	headers.On("Has", mock.Anything).Return(false)
-	headers.On("Add", mock.Anything).Return(nil)
-	events.On("Header", mock.Anything)
-	peers.On("Addresses", mock.Anything).Return(addresses)
-	net.On("Broadcast", mock.Anything, mock.Anything).Return(nil)
-	headers.On("Path").Return(path, 0)
-	paths.On("Follow", mock.Anything).Return(nil)
+	headers.On("Add", mock.Anything).Return(nil, orphan)
+	orphans.On("Add", mock.Anything, mock.Anything).Return(errors.New(""))
 
 	// initialize handler
 	handler := &Handler{
-		log:     zerolog.New(ioutil.Discard),
-		headers: headers,
-		events:  events,
-		peers:   peers,
-		net:     net,
-		paths:   paths,
+		log:       zerolog.New(ioutil.Discard),
+		headers:   headers,
+		net:       net,
+		orphans:   orphans,
+		validator: validator,
+		requests:  requests,
 	}
 
 	// execute process
-	handler.Process(wg, entity)
+	handler.Process(wg, address1, entity)
 	wg.Wait()
 
 	// check conditions
-	if headers.AssertNumberOfCalls(t, "Has", 1) {
-		headers.AssertCalled(t, "Has", hash)
+	orphans.AssertNumberOfCalls(t, "Add", 1)
+
+	requests.AssertNumberOfCalls(t, "Start", 0)
+
+	net.AssertNumberOfCalls(t, "Send", 0)
+}
+
+func TestHeaderInvalid(t *testing.T) {
+
+	// initialize parameters
+	address1 := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	entity := &types.Header{Nonce: 1}
+	invalid := validation.ErrWork
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	net := &NetworkMock{}
+	orphans := &OrphansMock{}
+	validator := &ValidatorMock{}
+
+	// program mocks
+	headers.On("Has", mock.Anything).Return(false)
+	validator.On("Validate", mock.Anything).Return(invalid)
+	net.On("Drop", mock.Anything).Return(nil)
+
+	// initialize handler
+	handler := &Handler{
+		log:       zerolog.New(ioutil.Discard),
+		headers:   headers,
+		net:       net,
+		orphans:   orphans,
+		validator: validator,
 	}
 
-	if headers.AssertNumberOfCalls(t, "Add", 1) {
-		headers.AssertCalled(t, "Add", entity)
+	// execute process
+	handler.Process(wg, address1, entity)
+	wg.Wait()
+
+	// check conditions
+	if validator.AssertNumberOfCalls(t, "Validate", 1) {
+		validator.AssertCalled(t, "Validate", entity)
 	}
 
-	if events.AssertNumberOfCalls(t, "Header", 1) {
-		events.AssertCalled(t, "Header", hash)
+	headers.AssertNumberOfCalls(t, "Add", 0)
+
+	if net.AssertNumberOfCalls(t, "Drop", 1) {
+		net.AssertCalled(t, "Drop", address1)
 	}
+}
+
+func TestHeaderInvalidOrphan(t *testing.T) {
+
+	// initialize parameters
+	address1 := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	entity := &types.Header{Nonce: 1}
+	unknown := validation.ErrParent
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	net := &NetworkMock{}
+	orphans := &OrphansMock{}
+	validator := &ValidatorMock{}
+
+	// program mocks
+	headers.On("Has", mock.Anything).Return(false)
+	validator.On("Validate", mock.Anything).Return(unknown)
+	orphans.On("Add", mock.Anything, mock.Anything).Return(errors.New(""))
 
-	if peers.AssertNumberOfCalls(t, "Addresses", 1) {
-		peers.AssertCalled(t, "Addresses", mock.Anything)
+	// initialize handler
+	handler := &Handler{
+		log:       zerolog.New(ioutil.Discard),
+		headers:   headers,
+		net:       net,
+		orphans:   orphans,
+		validator: validator,
 	}
 
-	if net.AssertNumberOfCalls(t, "Broadcast", 1) {
-		net.AssertCalled(t, "Broadcast", entity, addresses)
+	// execute process
+	handler.Process(wg, address1, entity)
+	wg.Wait()
+
+	// check conditions
+	headers.AssertNumberOfCalls(t, "Add", 0)
+
+	if orphans.AssertNumberOfCalls(t, "Add", 1) {
+		orphans.AssertCalled(t, "Add", address1, entity)
 	}
 
-	if headers.AssertNumberOfCalls(t, "Path", 1) {
-		headers.AssertCalled(t, "Path")
+	net.AssertNumberOfCalls(t, "Drop", 0)
+}
+
+func TestHeaderInvalidParent(t *testing.T) {
+
+	// initialize parameters
+	address1 := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	entity := &types.Header{Nonce: 1}
+	invalid := headers.ErrInvalid
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	net := &NetworkMock{}
+	orphans := &OrphansMock{}
+	validator := &ValidatorMock{}
+
+	// program mocks
+	headers.On("Has", mock.Anything).Return(false)
+	headers.On("Add", mock.Anything).Return(nil, invalid)
+	validator.On("Validate", mock.Anything).Return(nil)
+	net.On("Drop", mock.Anything).Return(nil)
+
+	// initialize handler
+	handler := &Handler{
+		log:       zerolog.New(ioutil.Discard),
+		headers:   headers,
+		net:       net,
+		orphans:   orphans,
+		validator: validator,
 	}
 
-	if paths.AssertNumberOfCalls(t, "Follow", 1) {
-		paths.AssertCalled(t, "Follow", path)
+	// execute process
+	handler.Process(wg, address1, entity)
+	wg.Wait()
+
+	// check conditions
+	orphans.AssertNumberOfCalls(t, "Add", 0)
+
+	if net.AssertNumberOfCalls(t, "Drop", 1) {
+		net.AssertCalled(t, "Drop", address1)
 	}
 }
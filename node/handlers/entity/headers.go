@@ -17,11 +17,14 @@
 
 package entity
 
-import "github.com/alvalor/alvalor-go/types"
+import (
+	"github.com/alvalor/alvalor-go/node/repos/headers"
+	"github.com/alvalor/alvalor-go/types"
+)
 
 // Headers represents the store for all headers.
 type Headers interface {
-	Add(header *types.Header) error
+	Add(header *types.Header) (*headers.Reorg, error)
 	Has(hash types.Hash) bool
-	Path() ([]types.Hash, uint64)
+	Locators() ([]types.Hash, uint64)
 }
@@ -18,6 +18,7 @@
 package entity
 
 import (
+	"github.com/alvalor/alvalor-go/node/repos/headers"
 	"github.com/alvalor/alvalor-go/types"
 	"github.com/stretchr/testify/mock"
 )
@@ -28,9 +29,13 @@ type HeadersMock struct {
 }
 
 // Add simulates adding a header.
-func (hm *HeadersMock) Add(header *types.Header) error {
+func (hm *HeadersMock) Add(header *types.Header) (*headers.Reorg, error) {
 	args := hm.Called(header)
-	return args.Error(0)
+	var reorg *headers.Reorg
+	if args.Get(0) != nil {
+		reorg = args.Get(0).(*headers.Reorg)
+	}
+	return reorg, args.Error(1)
 }
 
 // Has checks whether a header with the given hash exists.
@@ -39,12 +44,12 @@ func (hm *HeadersMock) Has(hash types.Hash) bool {
 	return args.Bool(0)
 }
 
-// Path returns current best path with distance.
-func (hm *HeadersMock) Path() ([]types.Hash, uint64) {
+// Locators returns the locators of the best path with its distance.
+func (hm *HeadersMock) Locators() ([]types.Hash, uint64) {
 	args := hm.Called()
-	var path []types.Hash
+	var locators []types.Hash
 	if args.Get(0) != nil {
-		path = args.Get(0).([]types.Hash)
+		locators = args.Get(0).([]types.Hash)
 	}
-	return path, uint64(args.Int(1))
+	return locators, uint64(args.Int(1))
 }
@@ -19,5 +19,6 @@ package entity
 
 // Network defines what we need from the network module.
 type Network interface {
-	Broadcast(msg interface{}, addresses ...string) error
+	Send(address string, msg interface{}) error
+	Drop(address string) error
 }
@@ -29,3 +29,15 @@ func (nm *NetworkMock) Broadcast(msg interface{}, addresses ...string) error {
 	args := nm.Called(msg, addresses)
 	return args.Error(0)
 }
+
+// Send mocks the send functionality.
+func (nm *NetworkMock) Send(address string, msg interface{}) error {
+	args := nm.Called(address, msg)
+	return args.Error(0)
+}
+
+// Drop mocks the drop functionality.
+func (nm *NetworkMock) Drop(address string) error {
+	args := nm.Called(address)
+	return args.Error(0)
+}
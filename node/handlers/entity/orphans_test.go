@@ -0,0 +1,45 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package entity
+
+import (
+	"github.com/alvalor/alvalor-go/node/state/orphans"
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/stretchr/testify/mock"
+)
+
+// OrphansMock mocks the orphan pool interface.
+type OrphansMock struct {
+	mock.Mock
+}
+
+// Add mocks adding an orphan to the pool.
+func (om *OrphansMock) Add(address string, header *types.Header) error {
+	args := om.Called(address, header)
+	return args.Error(0)
+}
+
+// Take mocks taking the children of a header from the pool.
+func (om *OrphansMock) Take(parent types.Hash) []orphans.Orphan {
+	args := om.Called(parent)
+	var taken []orphans.Orphan
+	if args.Get(0) != nil {
+		taken = args.Get(0).([]orphans.Orphan)
+	}
+	return taken
+}
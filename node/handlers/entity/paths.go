@@ -17,10 +17,11 @@
 
 package entity
 
-import "github.com/alvalor/alvalor-go/types"
+import "github.com/alvalor/alvalor-go/node/repos/headers"
 
 // Paths is responsible for tracking the paths in our tree of headers and
-// downloading the entities required for the best one.
+// downloading the entities required for the best one. It is told about every
+// change of the best path, so that it only has to look at the new branch.
 type Paths interface {
-	Follow(path []types.Hash) error
+	Follow(reorg *headers.Reorg) error
 }
@@ -18,7 +18,7 @@
 package entity
 
 import (
-	"github.com/alvalor/alvalor-go/types"
+	"github.com/alvalor/alvalor-go/node/repos/headers"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -28,7 +28,7 @@ type PathsMock struct {
 }
 
 // Follow mocks the follow function of the pathfinder.
-func (pm *PathsMock) Follow(path []types.Hash) error {
-	args := pm.Called(path)
+func (pm *PathsMock) Follow(reorg *headers.Reorg) error {
+	args := pm.Called(reorg)
 	return args.Error(0)
 }
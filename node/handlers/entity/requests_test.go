@@ -0,0 +1,45 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package entity
+
+import (
+	"github.com/alvalor/alvalor-go/node/state/requests"
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/stretchr/testify/mock"
+)
+
+// RequestsMock mocks the header request state interface.
+type RequestsMock struct {
+	mock.Mock
+}
+
+// Start mocks the start function of the header request state interface.
+func (rm *RequestsMock) Start(address string, locators []types.Hash, max uint32) error {
+	args := rm.Called(address, locators, max)
+	return args.Error(0)
+}
+
+// Finish mocks the finish function of the header request state interface.
+func (rm *RequestsMock) Finish(address string) (*requests.Request, error) {
+	args := rm.Called(address)
+	var req *requests.Request
+	if args.Get(0) != nil {
+		req = args.Get(0).(*requests.Request)
+	}
+	return req, args.Error(1)
+}
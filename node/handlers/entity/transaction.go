@@ -20,11 +20,10 @@ package entity
 import (
 	"sync"
 
-	"github.com/alvalor/alvalor-go/node/state/peers"
 	"github.com/alvalor/alvalor-go/types"
 )
 
-func (handler *Handler) processTransaction(wg *sync.WaitGroup, tx *types.Transaction) {
+func (handler *Handler) processTransaction(wg *sync.WaitGroup, address string, tx *types.Transaction) {
 	defer wg.Done()
 
 	// precompute transaction hash
@@ -34,6 +33,7 @@ func (handler *Handler) processTransaction(wg *sync.WaitGroup, tx *types.Transac
 	with := handler.log.With()
 	with.Str("component", "entity")
 	with.Str("entity_type", "transaction")
+	with.Str("address", address)
 	with.Hex("hash", tx.Hash[:])
 	log := with.Logger()
 
@@ -48,10 +48,8 @@ func (handler *Handler) processTransaction(wg *sync.WaitGroup, tx *types.Transac
 		return
 	}
 
-	// check the validity of the transaction
-	// TODO
-
-	// add the transaction to the transaction pool
+	// add the transaction to the transaction pool, which checks the sender
+	// signatures before accepting it
 	err := handler.transactions.Add(tx)
 	if err != nil {
 		log.Error().Err(err).Msg("could not add transaction")
@@ -60,13 +58,8 @@ func (handler *Handler) processTransaction(wg *sync.WaitGroup, tx *types.Transac
 
 	handler.events.Transaction(tx.Hash)
 
-	// create lookup to know who to exclude from broadcast
-	addresses := handler.peers.Addresses(peers.HasEntity(false, tx.Hash))
-	err = handler.net.Broadcast(tx, addresses...)
-	if err != nil {
-		log.Error().Err(err).Msg("could not propagate entity")
-		return
-	}
+	// queue the transaction hash for announcement to our peers
+	handler.relay.Announce(tx.Hash)
 
 	log.Debug().Msg("transaction processed")
 }
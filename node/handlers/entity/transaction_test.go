@@ -31,40 +31,34 @@ import (
 func TestTransactionKnown(t *testing.T) {
 
 	// initialize parameters
-	address1 := "192.0.2.1"
-	address2 := "192.0.2.2"
-	address3 := "192.0.2.3"
+	address := "192.0.2.1"
 
 	// initialize entities
 	wg := &sync.WaitGroup{}
 	entity := &types.Transaction{Nonce: 1}
 	hash := entity.GetHash()
-	addresses := []string{address1, address2, address3}
 
 	// initialize mocks
 	transactions := &TransactionsMock{}
 	events := &EventsMock{}
-	peers := &PeersMock{}
-	net := &NetworkMock{}
+	relay := &RelayMock{}
 
 	// program mocks
 	transactions.On("Has", mock.Anything).Return(true)
 	transactions.On("Add", mock.Anything).Return(nil)
 	events.On("Transaction", mock.Anything)
-	peers.On("Addresses", mock.Anything).Return(addresses)
-	net.On("Broadcast", mock.Anything, mock.Anything).Return(nil)
+	relay.On("Announce", mock.Anything)
 
 	// initialize handler
 	handler := &Handler{
 		log:          zerolog.New(ioutil.Discard),
 		transactions: transactions,
 		events:       events,
-		peers:        peers,
-		net:          net,
+		relay:        relay,
 	}
 
 	// execute process
-	handler.Process(wg, entity)
+	handler.Process(wg, address, entity)
 	wg.Wait()
 
 	// check conditions
@@ -76,48 +70,40 @@ func TestTransactionKnown(t *testing.T) {
 
 	events.AssertNumberOfCalls(t, "Transaction", 0)
 
-	peers.AssertNumberOfCalls(t, "Addresses", 0)
-
-	net.AssertNumberOfCalls(t, "Broadcast", 0)
+	relay.AssertNumberOfCalls(t, "Announce", 0)
 }
 
 func TestTransactionAddFails(t *testing.T) {
 
 	// initialize parameters
-	address1 := "192.0.2.1"
-	address2 := "192.0.2.2"
-	address3 := "192.0.2.3"
+	address := "192.0.2.1"
 
 	// initialize entities
 	wg := &sync.WaitGroup{}
 	entity := &types.Transaction{Nonce: 1}
 	hash := entity.GetHash()
-	addresses := []string{address1, address2, address3}
 
 	// initialize mocks
 	transactions := &TransactionsMock{}
 	events := &EventsMock{}
-	peers := &PeersMock{}
-	net := &NetworkMock{}
+	relay := &RelayMock{}
 
 	// program mocks
 	transactions.On("Has", mock.Anything).Return(false)
 	transactions.On("Add", mock.Anything).Return(errors.New(""))
 	events.On("Transaction", mock.Anything)
-	peers.On("Addresses", mock.Anything).Return(addresses)
-	net.On("Broadcast", mock.Anything, mock.Anything).Return(nil)
+	relay.On("Announce", mock.Anything)
 
 	// initialize handler
 	handler := &Handler{
 		log:          zerolog.New(ioutil.Discard),
 		transactions: transactions,
 		events:       events,
-		peers:        peers,
-		net:          net,
+		relay:        relay,
 	}
 
 	// execute process
-	handler.Process(wg, entity)
+	handler.Process(wg, address, entity)
 	wg.Wait()
 
 	// check conditions
@@ -131,109 +117,40 @@ func TestTransactionAddFails(t *testing.T) {
 
 	events.AssertNumberOfCalls(t, "Transaction", 0)
 
-	peers.AssertNumberOfCalls(t, "Addresses", 0)
-
-	net.AssertNumberOfCalls(t, "Broadcast", 0)
-}
-
-func TestTransactionBroadcastFails(t *testing.T) {
-
-	// initialize parameters
-	address1 := "192.0.2.1"
-	address2 := "192.0.2.2"
-	address3 := "192.0.2.3"
-
-	// initialize entities
-	wg := &sync.WaitGroup{}
-	entity := &types.Transaction{Nonce: 1}
-	hash := entity.GetHash()
-	addresses := []string{address1, address2, address3}
-
-	// initialize mocks
-	transactions := &TransactionsMock{}
-	events := &EventsMock{}
-	peers := &PeersMock{}
-	net := &NetworkMock{}
-
-	// program mocks
-	transactions.On("Has", mock.Anything).Return(false)
-	transactions.On("Add", mock.Anything).Return(nil)
-	events.On("Transaction", mock.Anything)
-	peers.On("Addresses", mock.Anything).Return(addresses)
-	net.On("Broadcast", mock.Anything, mock.Anything).Return(errors.New(""))
-
-	// initialize handler
-	handler := &Handler{
-		log:          zerolog.New(ioutil.Discard),
-		transactions: transactions,
-		events:       events,
-		peers:        peers,
-		net:          net,
-	}
-
-	// execute process
-	handler.Process(wg, entity)
-	wg.Wait()
-
-	// check conditions
-	if transactions.AssertNumberOfCalls(t, "Has", 1) {
-		transactions.AssertCalled(t, "Has", hash)
-	}
-
-	if transactions.AssertNumberOfCalls(t, "Add", 1) {
-		transactions.AssertCalled(t, "Add", entity)
-	}
-
-	if events.AssertNumberOfCalls(t, "Transaction", 1) {
-		events.AssertCalled(t, "Transaction", hash)
-	}
-
-	if peers.AssertNumberOfCalls(t, "Addresses", 1) {
-		peers.AssertCalled(t, "Addresses", mock.Anything)
-	}
-
-	if net.AssertNumberOfCalls(t, "Broadcast", 1) {
-		net.AssertCalled(t, "Broadcast", entity, addresses)
-	}
+	relay.AssertNumberOfCalls(t, "Announce", 0)
 }
 
 func TestTransactionSuccess(t *testing.T) {
 
 	// initialize parameters
-	address1 := "192.0.2.1"
-	address2 := "192.0.2.2"
-	address3 := "192.0.2.3"
+	address := "192.0.2.1"
 
 	// initialize entities
 	wg := &sync.WaitGroup{}
 	entity := &types.Transaction{Nonce: 1}
 	hash := entity.GetHash()
-	addresses := []string{address1, address2, address3}
 
 	// initialize mocks
 	transactions := &TransactionsMock{}
 	events := &EventsMock{}
-	peers := &PeersMock{}
-	net := &NetworkMock{}
+	relay := &RelayMock{}
 
 	// program mocks
 	transactions.On("Has", mock.Anything).Return(false)
 	transactions.On("Add", mock.Anything).Return(nil)
 	events.On("Transaction", mock.Anything)
-	peers.On("Addresses", mock.Anything).Return(addresses)
-	net.On("Broadcast", mock.Anything, mock.Anything).Return(nil)
+	relay.On("Announce", mock.Anything)
 
 	// initialize handler
 	handler := &Handler{
 		log:          zerolog.New(ioutil.Discard),
 		transactions: transactions,
 		events:       events,
-		peers:        peers,
-		net:          net,
+		relay:        relay,
 	}
 
 	// execute process
-	handler.Process(wg, entity)
+	handler.Process(wg, address, entity)
 	wg.Wait()
 
 	// check conditions
@@ -249,11 +166,7 @@ func TestTransactionSuccess(t *testing.T) {
 		events.AssertCalled(t, "Transaction", hash)
 	}
 
-	if peers.AssertNumberOfCalls(t, "Addresses", 1) {
-		peers.AssertCalled(t, "Addresses", mock.Anything)
-	}
-
-	if net.AssertNumberOfCalls(t, "Broadcast", 1) {
-		net.AssertCalled(t, "Broadcast", entity, addresses)
+	if relay.AssertNumberOfCalls(t, "Announce", 1) {
+		relay.AssertCalled(t, "Announce", hash)
 	}
 }
@@ -40,14 +40,37 @@ func (handler *Handler) processConnected(wg *sync.WaitGroup, connected network.C
 
 	handler.peers.Active(connected.Address)
 
-	// send our current best distance
-	_, distance := handler.headers.Path()
+	// send the tip of our best path, with the genesis it starts from, which is
+	// always the last of our locators
+	locators, distance := handler.headers.Locators()
+	if len(locators) == 0 {
+		log.Error().Msg("could not get best path")
+		return
+	}
+	height, err := handler.headers.Height(locators[0])
+	if err != nil {
+		log.Error().Err(err).Msg("could not get height of best header")
+		return
+	}
 	status := &message.Status{
+		Hash:     locators[0],
+		Height:   height,
 		Distance: distance,
+		Genesis:  locators[len(locators)-1],
 	}
-	err := handler.net.Send(connected.Address, status)
+	err = handler.net.Send(connected.Address, status)
 	if err != nil {
 		log.Error().Err(err).Msg("could not send status message")
 		return
 	}
+
+	// let the peer know which transactions we already have
+	mempool := &message.Mempool{
+		Bloom: handler.reconciler.Filter(),
+	}
+	err = handler.net.Send(connected.Address, mempool)
+	if err != nil {
+		log.Error().Err(err).Msg("could not send mempool message")
+		return
+	}
 }
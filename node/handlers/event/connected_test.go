@@ -25,8 +25,10 @@ import (
 
 	"github.com/alvalor/alvalor-go/network"
 	"github.com/alvalor/alvalor-go/node/handlers/message"
+	"github.com/alvalor/alvalor-go/types"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/mock"
+	"github.com/willf/bloom"
 )
 
 func TestProcessConnectedSuccess(t *testing.T) {
@@ -34,30 +36,39 @@ func TestProcessConnectedSuccess(t *testing.T) {
 	// initialize parameters
 	address := "192.0.2.1"
 	distance := 1337
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
 
 	// initialize entities
 	wg := &sync.WaitGroup{}
 	event := network.Connected{Address: address}
-	status := &message.Status{Distance: uint64(distance)}
+	locators := []types.Hash{hash1, hash2}
+	status := &message.Status{Hash: hash1, Height: 1, Distance: uint64(distance), Genesis: hash2}
+	filter := bloom.NewWithEstimates(10, 0.01)
+	mempool := &message.Mempool{Bloom: filter}
 
 	// initialize mocks
 	net := &NetworkMock{}
 	headers := &HeadersMock{}
 	peers := &PeersMock{}
 	message := &MessageMock{}
+	reconciler := &ReconcilerMock{}
 
 	// initialize handler
 	handler := &Handler{
-		log:     zerolog.New(ioutil.Discard),
-		net:     net,
-		headers: headers,
-		peers:   peers,
-		message: message,
+		log:        zerolog.New(ioutil.Discard),
+		net:        net,
+		headers:    headers,
+		peers:      peers,
+		message:    message,
+		reconciler: reconciler,
 	}
 
 	// program mocks
 	peers.On("Active", mock.Anything)
-	headers.On("Path").Return(nil, distance)
+	headers.On("Locators").Return(locators, distance)
+	headers.On("Height", hash1).Return(1, nil)
+	reconciler.On("Filter").Return(filter)
 	net.On("Send", mock.Anything, mock.Anything).Return(nil)
 
 	// execute process
@@ -69,12 +80,13 @@ func TestProcessConnectedSuccess(t *testing.T) {
 		peers.AssertCalled(t, "Active", address)
 	}
 
-	if headers.AssertNumberOfCalls(t, "Path", 1) {
-		headers.AssertCalled(t, "Path")
+	if headers.AssertNumberOfCalls(t, "Locators", 1) {
+		headers.AssertCalled(t, "Locators")
 	}
 
-	if net.AssertNumberOfCalls(t, "Send", 1) {
+	if net.AssertNumberOfCalls(t, "Send", 2) {
 		net.AssertCalled(t, "Send", address, status)
+		net.AssertCalled(t, "Send", address, mempool)
 	}
 }
 
@@ -83,30 +95,36 @@ func TestProcessConnectedSendFails(t *testing.T) {
 	// initialize parameters
 	address := "192.0.2.1"
 	distance := 1337
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
 
 	// initialize entities
 	wg := &sync.WaitGroup{}
 	event := network.Connected{Address: address}
-	status := &message.Status{Distance: uint64(distance)}
+	locators := []types.Hash{hash1, hash2}
+	status := &message.Status{Hash: hash1, Height: 1, Distance: uint64(distance), Genesis: hash2}
 
 	// initialize mocks
 	net := &NetworkMock{}
 	headers := &HeadersMock{}
 	peers := &PeersMock{}
 	message := &MessageMock{}
+	reconciler := &ReconcilerMock{}
 
 	// initialize handler
 	handler := &Handler{
-		log:     zerolog.New(ioutil.Discard),
-		net:     net,
-		headers: headers,
-		peers:   peers,
-		message: message,
+		log:        zerolog.New(ioutil.Discard),
+		net:        net,
+		headers:    headers,
+		peers:      peers,
+		message:    message,
+		reconciler: reconciler,
 	}
 
 	// program mocks
 	peers.On("Active", mock.Anything)
-	headers.On("Path").Return(nil, distance)
+	headers.On("Locators").Return(locators, distance)
+	headers.On("Height", hash1).Return(1, nil)
 	net.On("Send", mock.Anything, mock.Anything).Return(errors.New(""))
 
 	// execute process
@@ -118,11 +136,13 @@ func TestProcessConnectedSendFails(t *testing.T) {
 		peers.AssertCalled(t, "Active", address)
 	}
 
-	if headers.AssertNumberOfCalls(t, "Path", 1) {
-		headers.AssertCalled(t, "Path")
+	if headers.AssertNumberOfCalls(t, "Locators", 1) {
+		headers.AssertCalled(t, "Locators")
 	}
 
 	if net.AssertNumberOfCalls(t, "Send", 1) {
 		net.AssertCalled(t, "Send", address, status)
 	}
+
+	reconciler.AssertNotCalled(t, "Filter")
 }
@@ -38,4 +38,13 @@ func (handler *Handler) processDisconnected(wg *sync.WaitGroup, disconnected net
 	defer log.Debug().Msg("routine stopped")
 
 	handler.peers.Inactive(disconnected.Address)
+
+	// forget about pending header requests, so we can request again later
+	_, _ = handler.requests.Finish(disconnected.Address)
+
+	// reset the rate limits, so a reconnecting peer starts with fresh buckets
+	handler.limiter.Forget(disconnected.Address)
+
+	// stop counting the peer towards the best known distance
+	handler.tracker.Forget(disconnected.Address)
 }
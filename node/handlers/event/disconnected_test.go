@@ -40,19 +40,28 @@ func TestProcessDisconnectedSuccess(t *testing.T) {
 	net := &NetworkMock{}
 	headers := &HeadersMock{}
 	peers := &PeersMock{}
+	requests := &RequestsMock{}
 	message := &MessageMock{}
+	limiter := &LimiterMock{}
+	tracker := &TrackerMock{}
 
 	// initialize handler
 	handler := &Handler{
-		log:     zerolog.New(ioutil.Discard),
-		net:     net,
-		headers: headers,
-		peers:   peers,
-		message: message,
+		log:      zerolog.New(ioutil.Discard),
+		net:      net,
+		headers:  headers,
+		peers:    peers,
+		requests: requests,
+		message:  message,
+		limiter:  limiter,
+		tracker:  tracker,
 	}
 
 	// program mocks
 	peers.On("Inactive", mock.Anything)
+	requests.On("Finish", mock.Anything).Return(nil, nil)
+	limiter.On("Forget", mock.Anything)
+	tracker.On("Forget", mock.Anything)
 
 	// execute process
 	handler.Process(wg, event)
@@ -62,4 +71,16 @@ func TestProcessDisconnectedSuccess(t *testing.T) {
 	if peers.AssertNumberOfCalls(t, "Inactive", 1) {
 		peers.AssertCalled(t, "Inactive", address)
 	}
+
+	if requests.AssertNumberOfCalls(t, "Finish", 1) {
+		requests.AssertCalled(t, "Finish", address)
+	}
+
+	if limiter.AssertNumberOfCalls(t, "Forget", 1) {
+		limiter.AssertCalled(t, "Forget", address)
+	}
+
+	if tracker.AssertNumberOfCalls(t, "Forget", 1) {
+		tracker.AssertCalled(t, "Forget", address)
+	}
 }
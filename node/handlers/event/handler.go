@@ -26,22 +26,66 @@ import (
 
 // Handler represents the handler for events received from the network layer.
 type Handler struct {
-	log     zerolog.Logger
-	net     Network
-	headers Headers
-	peers   Peers
-	message Message
+	log        zerolog.Logger
+	net        Network
+	headers    Headers
+	peers      Peers
+	requests   Requests
+	message    Message
+	reconciler Reconciler
+	limiter    Limiter
+	tracker    Tracker
+	pool       Pool
 }
 
-// Process makes the event handler process an event.
+// NewHandler creates a new handler for events received from the network layer.
+func NewHandler(log zerolog.Logger, net Network, headers Headers, peers Peers, requests Requests, message Message, reconciler Reconciler, limiter Limiter, tracker Tracker, pool Pool) *Handler {
+	return &Handler{
+		log:        log,
+		net:        net,
+		headers:    headers,
+		peers:      peers,
+		requests:   requests,
+		message:    message,
+		reconciler: reconciler,
+		limiter:    limiter,
+		tracker:    tracker,
+		pool:       pool,
+	}
+}
+
+// Process makes the event handler process an event. The event is queued with
+// the worker pool, which processes the events of each peer in order.
 func (handler *Handler) Process(wg *sync.WaitGroup, event interface{}) {
-	wg.Add(1)
+	var address string
+	var task func()
 	switch e := event.(type) {
 	case network.Connected:
-		go handler.processConnected(wg, e)
+		address = e.Address
+		task = func() { handler.processConnected(wg, e) }
 	case network.Disconnected:
-		go handler.processDisconnected(wg, e)
+		address = e.Address
+		task = func() { handler.processDisconnected(wg, e) }
 	case network.Received:
-		go handler.processReceived(wg, e)
+		address = e.Address
+		task = func() { handler.processReceived(wg, e) }
+	default:
+		return
+	}
+	wg.Add(1)
+	handler.dispatch(wg, address, task)
+}
+
+// dispatch submits the task to the worker pool. If no worker pool is
+// configured, the task runs on its own goroutine.
+func (handler *Handler) dispatch(wg *sync.WaitGroup, address string, task func()) {
+	if handler.pool == nil {
+		go task()
+		return
+	}
+	err := handler.pool.Submit(address, task)
+	if err != nil {
+		wg.Done()
+		handler.log.Error().Err(err).Str("address", address).Msg("could not queue event")
 	}
 }
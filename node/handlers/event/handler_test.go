@@ -16,3 +16,96 @@
 // along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
 
 package event
+
+import (
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alvalor/alvalor-go/network"
+	"github.com/alvalor/alvalor-go/node/handlers/workers"
+)
+
+func TestHandlerDispatchSuccess(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	event := network.Disconnected{Address: address}
+
+	// initialize mocks
+	pool := &PoolMock{}
+	peers := &PeersMock{}
+	requests := &RequestsMock{}
+	limiter := &LimiterMock{}
+	tracker := &TrackerMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:      zerolog.New(ioutil.Discard),
+		pool:     pool,
+		peers:    peers,
+		requests: requests,
+		limiter:  limiter,
+		tracker:  tracker,
+	}
+
+	// program mocks
+	pool.On("Submit", mock.Anything, mock.Anything).Return(nil)
+	peers.On("Inactive", mock.Anything)
+	requests.On("Finish", mock.Anything).Return(nil, nil)
+	limiter.On("Forget", mock.Anything)
+	tracker.On("Forget", mock.Anything)
+
+	// execute process
+	handler.Process(wg, event)
+	wg.Wait()
+
+	// check conditions
+	pool.AssertCalled(t, "Submit", address, mock.Anything)
+
+	peers.AssertCalled(t, "Inactive", address)
+}
+
+func TestHandlerDispatchStopped(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	event := network.Disconnected{Address: address}
+
+	// initialize mocks
+	pool := &PoolMock{}
+	peers := &PeersMock{}
+	requests := &RequestsMock{}
+	limiter := &LimiterMock{}
+	tracker := &TrackerMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:      zerolog.New(ioutil.Discard),
+		pool:     pool,
+		peers:    peers,
+		requests: requests,
+		limiter:  limiter,
+		tracker:  tracker,
+	}
+
+	// program mocks
+	pool.On("Submit", mock.Anything, mock.Anything).Return(errors.Wrap(workers.ErrStopped, "could not submit"))
+
+	// execute process
+	handler.Process(wg, event)
+	wg.Wait()
+
+	// check conditions
+	peers.AssertNotCalled(t, "Inactive", mock.Anything)
+}
@@ -22,5 +22,6 @@ import "github.com/alvalor/alvalor-go/types"
 // Headers represents the header repository interface, as needed by the event
 // handler package.
 type Headers interface {
-	Path() ([]types.Hash, uint64)
+	Locators() ([]types.Hash, uint64)
+	Height(hash types.Hash) (uint64, error)
 }
@@ -27,12 +27,18 @@ type HeadersMock struct {
 	mock.Mock
 }
 
-// Path returns current best path with distance.
-func (hm *HeadersMock) Path() ([]types.Hash, uint64) {
+// Locators returns the locators of the best path with its distance.
+func (hm *HeadersMock) Locators() ([]types.Hash, uint64) {
 	args := hm.Called()
-	var path []types.Hash
+	var locators []types.Hash
 	if args.Get(0) != nil {
-		path = args.Get(0).([]types.Hash)
+		locators = args.Get(0).([]types.Hash)
 	}
-	return path, uint64(args.Int(1))
+	return locators, uint64(args.Int(1))
+}
+
+// Height returns the height of the header with the given hash.
+func (hm *HeadersMock) Height(hash types.Hash) (uint64, error) {
+	args := hm.Called(hash)
+	return uint64(args.Int(0)), args.Error(1)
 }
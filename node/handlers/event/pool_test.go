@@ -0,0 +1,36 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package event
+
+import "github.com/stretchr/testify/mock"
+
+// PoolMock mocks the worker pool interface.
+type PoolMock struct {
+	mock.Mock
+}
+
+// Submit mocks the submit function of the worker pool interface. Accepted
+// tasks are run right away.
+func (pm *PoolMock) Submit(address string, task func()) error {
+	args := pm.Called(address, task)
+	err := args.Error(0)
+	if err == nil {
+		go task()
+	}
+	return err
+}
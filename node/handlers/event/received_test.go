@@ -56,7 +56,7 @@ func TestProcessReceivedSuccess(t *testing.T) {
 	}
 
 	// program mocks
-	message.On("Process", mock.Anything, mock.Anything, mock.Anything)
+	message.On("Process", mock.Anything, mock.Anything)
 
 	// execute process
 	handler.Process(wg, event)
@@ -64,6 +64,6 @@ func TestProcessReceivedSuccess(t *testing.T) {
 
 	// assert conditions
 	if message.AssertNumberOfCalls(t, "Process", 1) {
-		message.AssertCalled(t, "Process", wg, address, msg)
+		message.AssertCalled(t, "Process", address, msg)
 	}
 }
@@ -0,0 +1,38 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package event
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/willf/bloom"
+)
+
+// ReconcilerMock mocks the mempool reconciler interface.
+type ReconcilerMock struct {
+	mock.Mock
+}
+
+// Filter mocks the filter function of the mempool reconciler interface.
+func (rm *ReconcilerMock) Filter() *bloom.BloomFilter {
+	args := rm.Called()
+	var filter *bloom.BloomFilter
+	if args.Get(0) != nil {
+		filter = args.Get(0).(*bloom.BloomFilter)
+	}
+	return filter
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package limits
+
+// Limit represents a token bucket with a sustained rate in messages per second
+// and a burst of messages that can be sent at once.
+type Limit struct {
+	Rate  float64
+	Burst uint
+}
+
+// Config represents the rate limits for each message type.
+type Config struct {
+	limits map[string]Limit
+}
+
+// DefaultConfig returns the default rate limits. They only cover the messages
+// that make us do work on behalf of the peer; the names match the message types
+// used by the message handler.
+func DefaultConfig() Config {
+	return Config{
+		limits: map[string]Limit{
+			"get_headers":   {Rate: 1, Burst: 8},
+			"get_inv":       {Rate: 32, Burst: 256},
+			"get_tx":        {Rate: 256, Burst: 1024},
+			"request":       {Rate: 16, Burst: 64},
+			"mempool":       {Rate: 0.1, Burst: 2},
+			"get_block_txs": {Rate: 8, Burst: 32},
+			"announce":      {Rate: 4, Burst: 16},
+			"compact":       {Rate: 1, Burst: 8},
+			"block_txs":     {Rate: 1, Burst: 8},
+		},
+	}
+}
+
+// SetLimit allows us to configure the rate limit for a message type. A rate of
+// zero removes the limit for the message type.
+func SetLimit(kind string, rate float64, burst uint) func(*Config) {
+	return func(cfg *Config) {
+		if rate == 0 {
+			delete(cfg.limits, kind)
+			return
+		}
+		cfg.limits[kind] = Limit{Rate: rate, Burst: burst}
+	}
+}
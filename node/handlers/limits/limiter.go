@@ -0,0 +1,154 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package limits
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Metrics contains the counters of allowed and throttled messages for a peer,
+// with the throttled messages also broken down by message type.
+type Metrics struct {
+	Allowed   uint
+	Throttled uint
+	Kinds     map[string]uint
+}
+
+// Limiter keeps a token bucket per peer and message type. Each message takes
+// one token; the buckets refill at the configured rate up to their burst.
+type Limiter struct {
+	sync.Mutex
+	cfg     Config
+	now     func() time.Time
+	buckets map[string]map[string]*bucket
+	metrics map[string]*Metrics
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter creates a new rate limiter.
+func NewLimiter(options ...func(*Config)) *Limiter {
+	cfg := DefaultConfig()
+	for _, option := range options {
+		option(&cfg)
+	}
+	return &Limiter{
+		cfg:     cfg,
+		now:     time.Now,
+		buckets: make(map[string]map[string]*bucket),
+		metrics: make(map[string]*Metrics),
+	}
+}
+
+// Take takes a token for a message of the given type from the given peer. It
+// returns an error if the peer exceeded its rate limit for the message type.
+func (l *Limiter) Take(address string, kind string) error {
+	l.Lock()
+	defer l.Unlock()
+
+	// messages without configured limit are always allowed
+	limit, ok := l.cfg.limits[kind]
+	if !ok {
+		return nil
+	}
+
+	// get the metrics for the peer
+	metrics, ok := l.metrics[address]
+	if !ok {
+		metrics = &Metrics{Kinds: make(map[string]uint)}
+		l.metrics[address] = metrics
+	}
+
+	// get the bucket for the peer and message type, starting out full
+	now := l.now()
+	buckets, ok := l.buckets[address]
+	if !ok {
+		buckets = make(map[string]*bucket)
+		l.buckets[address] = buckets
+	}
+	b, ok := buckets[kind]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		buckets[kind] = b
+	}
+
+	// refill the bucket for the time that passed since the last message
+	elapsed := now.Sub(b.last).Seconds()
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+	b.last = now
+
+	// take a token, if there is one left
+	if b.tokens < 1 {
+		metrics.Throttled++
+		metrics.Kinds[kind]++
+		return errors.Wrapf(ErrLimited, "could not take token (%s, %s)", address, kind)
+	}
+	b.tokens--
+	metrics.Allowed++
+
+	return nil
+}
+
+// Forget removes the buckets and metrics of the given peer.
+func (l *Limiter) Forget(address string) {
+	l.Lock()
+	defer l.Unlock()
+
+	delete(l.buckets, address)
+	delete(l.metrics, address)
+}
+
+// Metrics returns the metrics of all peers that sent us rate limited messages.
+func (l *Limiter) Metrics() map[string]Metrics {
+	l.Lock()
+	defer l.Unlock()
+
+	metrics := make(map[string]Metrics, len(l.metrics))
+	for address, m := range l.metrics {
+		kinds := make(map[string]uint, len(m.Kinds))
+		for kind, count := range m.Kinds {
+			kinds[kind] = count
+		}
+		metrics[address] = Metrics{Allowed: m.Allowed, Throttled: m.Throttled, Kinds: kinds}
+	}
+
+	return metrics
+}
+
+// Throttled returns the addresses of the peers that exceeded a rate limit at
+// least once.
+func (l *Limiter) Throttled() []string {
+	l.Lock()
+	defer l.Unlock()
+
+	var addresses []string
+	for address, m := range l.metrics {
+		if m.Throttled > 0 {
+			addresses = append(addresses, address)
+		}
+	}
+
+	return addresses
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package limits
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiterBurst(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	now := time.Unix(1000, 0)
+
+	// initialize limiter
+	limiter := NewLimiter(SetLimit("get_inv", 1, 3))
+	limiter.now = func() time.Time { return now }
+
+	// take the whole burst and one more
+	for i := 0; i < 3; i++ {
+		err := limiter.Take(address, "get_inv")
+		assert.Nil(t, err)
+	}
+	err := limiter.Take(address, "get_inv")
+
+	// check conditions
+	assert.Equal(t, ErrLimited, errors.Cause(err))
+	assert.Equal(t, Metrics{Allowed: 3, Throttled: 1, Kinds: map[string]uint{"get_inv": 1}}, limiter.Metrics()[address])
+	assert.Equal(t, []string{address}, limiter.Throttled())
+}
+
+func TestLimiterRefill(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	now := time.Unix(1000, 0)
+
+	// initialize limiter
+	limiter := NewLimiter(SetLimit("get_inv", 2, 2))
+	limiter.now = func() time.Time { return now }
+
+	// empty the bucket and let it refill partially
+	_ = limiter.Take(address, "get_inv")
+	_ = limiter.Take(address, "get_inv")
+	now = now.Add(500 * time.Millisecond)
+	err1 := limiter.Take(address, "get_inv")
+	err2 := limiter.Take(address, "get_inv")
+
+	// let it refill for longer than needed to be full
+	now = now.Add(time.Hour)
+	err3 := limiter.Take(address, "get_inv")
+	err4 := limiter.Take(address, "get_inv")
+	err5 := limiter.Take(address, "get_inv")
+
+	// check conditions
+	assert.Nil(t, err1)
+	assert.Equal(t, ErrLimited, errors.Cause(err2))
+	assert.Nil(t, err3)
+	assert.Nil(t, err4)
+	assert.Equal(t, ErrLimited, errors.Cause(err5))
+}
+
+func TestLimiterSeparateBuckets(t *testing.T) {
+
+	// initialize parameters
+	address1 := "192.0.2.1"
+	address2 := "192.0.2.2"
+
+	// initialize limiter
+	limiter := NewLimiter(SetLimit("get_inv", 1, 1), SetLimit("get_tx", 1, 1))
+
+	// take one token from each bucket
+	err1 := limiter.Take(address1, "get_inv")
+	err2 := limiter.Take(address1, "get_tx")
+	err3 := limiter.Take(address2, "get_inv")
+	err4 := limiter.Take(address1, "get_inv")
+
+	// check conditions
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Nil(t, err3)
+	assert.Equal(t, ErrLimited, errors.Cause(err4))
+}
+
+func TestLimiterUnlimited(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize limiter
+	limiter := NewLimiter(SetLimit("get_inv", 0, 0))
+
+	// take many tokens for messages without limits
+	for i := 0; i < 10000; i++ {
+		err := limiter.Take(address, "get_inv")
+		assert.Nil(t, err)
+		err = limiter.Take(address, "status")
+		assert.Nil(t, err)
+	}
+
+	// check conditions
+	assert.Empty(t, limiter.Metrics())
+}
+
+func TestLimiterForget(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize limiter
+	limiter := NewLimiter(SetLimit("get_inv", 1, 1))
+
+	// exceed the limit and forget the peer
+	_ = limiter.Take(address, "get_inv")
+	_ = limiter.Take(address, "get_inv")
+	limiter.Forget(address)
+	err := limiter.Take(address, "get_inv")
+
+	// check conditions
+	assert.Nil(t, err)
+	assert.Empty(t, limiter.Throttled())
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package message
+
+import (
+	"sync"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// The Announce is a message sent by peers to let us know about transactions
+// they recently received. We remember that they have them and download the
+// ones we don't know yet; hashes that are already being downloaded from
+// another peer are skipped by the download manager.
+func (handler *Handler) processAnnounce(wg *sync.WaitGroup, address string, announce *Announce) {
+	defer wg.Done()
+
+	// configure logger
+	with := handler.log.With()
+	with.Str("component", "message")
+	with.Str("message_type", "announce")
+	with.Str("address", address)
+	with.Int("num_hashes", len(announce.Hashes))
+	log := with.Logger()
+
+	// wrap routine in start and stop messages
+	log.Debug().Msg("routine started")
+	defer log.Debug().Msg("routine stopped")
+
+	// peers never announce more hashes at once than we do
+	if len(announce.Hashes) > MaxBatchTxs {
+		log.Error().Int("max", MaxBatchTxs).Msg("too many hashes in announce")
+		handler.drop(log, address)
+		return
+	}
+
+	// mark the transactions as known by the peer and collect unknown ones
+	var unknown []types.Hash
+	for _, hash := range announce.Hashes {
+		handler.peers.Received(address, hash)
+		ok := handler.transactions.Has(hash)
+		if ok {
+			continue
+		}
+		unknown = append(unknown, hash)
+	}
+
+	// request the unknown transactions
+	if len(unknown) > 0 {
+		err := handler.downloads.StartTxs(unknown)
+		if err != nil {
+			log.Error().Err(err).Msg("could not start transaction downloads")
+			return
+		}
+	}
+
+	log.Debug().Int("num_unknown", len(unknown)).Msg("processed announce message")
+}
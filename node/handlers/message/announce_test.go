@@ -0,0 +1,190 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package message
+
+import (
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+func TestProcessAnnounceSuccess(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+	hash3 := types.Hash{0x3}
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &Announce{Hashes: []types.Hash{hash1, hash2, hash3}}
+
+	// initialize mocks
+	peers := &PeersMock{}
+	transactions := &TransactionsMock{}
+	downloads := &DownloadsMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:          zerolog.New(ioutil.Discard),
+		peers:        peers,
+		transactions: transactions,
+		downloads:    downloads,
+	}
+
+	// program mocks
+	peers.On("Received", mock.Anything, mock.Anything)
+	transactions.On("Has", hash1).Return(false)
+	transactions.On("Has", hash2).Return(true)
+	transactions.On("Has", hash3).Return(false)
+	downloads.On("StartTxs", mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	if peers.AssertNumberOfCalls(t, "Received", 3) {
+		peers.AssertCalled(t, "Received", address, hash1)
+		peers.AssertCalled(t, "Received", address, hash2)
+		peers.AssertCalled(t, "Received", address, hash3)
+	}
+
+	if downloads.AssertNumberOfCalls(t, "StartTxs", 1) {
+		downloads.AssertCalled(t, "StartTxs", []types.Hash{hash1, hash3})
+	}
+}
+
+func TestProcessAnnounceAllKnown(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	hash := types.Hash{0x1}
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &Announce{Hashes: []types.Hash{hash}}
+
+	// initialize mocks
+	peers := &PeersMock{}
+	transactions := &TransactionsMock{}
+	downloads := &DownloadsMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:          zerolog.New(ioutil.Discard),
+		peers:        peers,
+		transactions: transactions,
+		downloads:    downloads,
+	}
+
+	// program mocks
+	peers.On("Received", mock.Anything, mock.Anything)
+	transactions.On("Has", mock.Anything).Return(true)
+	downloads.On("StartTxs", mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	peers.AssertCalled(t, "Received", address, hash)
+
+	downloads.AssertNumberOfCalls(t, "StartTxs", 0)
+}
+
+func TestProcessAnnounceStartFails(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	hash := types.Hash{0x1}
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &Announce{Hashes: []types.Hash{hash}}
+
+	// initialize mocks
+	peers := &PeersMock{}
+	transactions := &TransactionsMock{}
+	downloads := &DownloadsMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:          zerolog.New(ioutil.Discard),
+		peers:        peers,
+		transactions: transactions,
+		downloads:    downloads,
+	}
+
+	// program mocks
+	peers.On("Received", mock.Anything, mock.Anything)
+	transactions.On("Has", mock.Anything).Return(false)
+	downloads.On("StartTxs", mock.Anything).Return(errors.New("could not start"))
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	downloads.AssertCalled(t, "StartTxs", []types.Hash{hash})
+}
+
+func TestProcessAnnounceTooMany(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &Announce{Hashes: make([]types.Hash, MaxBatchTxs+1)}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	peers := &PeersMock{}
+	transactions := &TransactionsMock{}
+	downloads := &DownloadsMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:          zerolog.New(ioutil.Discard),
+		net:          net,
+		peers:        peers,
+		transactions: transactions,
+		downloads:    downloads,
+	}
+
+	// program mocks
+	net.On("Drop", mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	net.AssertCalled(t, "Drop", address)
+	peers.AssertNotCalled(t, "Received", mock.Anything, mock.Anything)
+	transactions.AssertNotCalled(t, "Has", mock.Anything)
+	downloads.AssertNotCalled(t, "StartTxs", mock.Anything)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package message
+
+import "sync"
+
+// The Batch message is a message containing a batch of transactions, usually
+// in response to our Request. A batch can contain only part of the requested
+// transactions; the remaining downloads stay pending until they are delivered,
+// not found or time out.
+func (handler *Handler) processBatch(wg *sync.WaitGroup, address string, batch *Batch) {
+	defer wg.Done()
+
+	// configure logger
+	with := handler.log.With()
+	with.Str("component", "message")
+	with.Str("message_type", "batch")
+	with.Str("address", address)
+	with.Int("num_txs", len(batch.Transactions))
+	log := with.Logger()
+
+	// wrap routine in start and stop messages
+	log.Debug().Msg("routine started")
+	defer log.Debug().Msg("routine stopped")
+
+	for _, tx := range batch.Transactions {
+
+		// precompute the transaction hash
+		tx.Hash = tx.GetHash()
+
+		// mark any pending download of this transaction as delivered
+		handler.downloads.Received(address, tx.Hash)
+
+		// mark the transaction as received for the respective peer
+		handler.peers.Received(address, tx.Hash)
+
+		// handle the transaction entity
+		handler.entity.Process(wg, address, tx)
+	}
+
+	log.Debug().Msg("processed batch message")
+}
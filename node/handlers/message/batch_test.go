@@ -0,0 +1,77 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package message
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProcessBatch(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities, without hashes, as we don't trust the peer's
+	wg := &sync.WaitGroup{}
+	tx1 := &types.Transaction{Nonce: 1}
+	tx2 := &types.Transaction{Nonce: 2}
+	hash1 := tx1.GetHash()
+	hash2 := tx2.GetHash()
+	msg := &Batch{Transactions: []*types.Transaction{tx1, tx2}}
+
+	// initialize mocks
+	downloads := &DownloadsMock{}
+	peers := &PeersMock{}
+	entity := &EntityMock{}
+
+	// initialize handler
+	handler := &Handler{
+		downloads: downloads,
+		peers:     peers,
+		entity:    entity,
+	}
+
+	// program mocks
+	downloads.On("Received", mock.Anything, mock.Anything)
+	peers.On("Received", mock.Anything, mock.Anything)
+	entity.On("Process", mock.Anything, mock.Anything)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	if downloads.AssertNumberOfCalls(t, "Received", 2) {
+		downloads.AssertCalled(t, "Received", address, hash1)
+		downloads.AssertCalled(t, "Received", address, hash2)
+	}
+
+	if peers.AssertNumberOfCalls(t, "Received", 2) {
+		peers.AssertCalled(t, "Received", address, hash1)
+		peers.AssertCalled(t, "Received", address, hash2)
+	}
+
+	if entity.AssertNumberOfCalls(t, "Process", 2) {
+		entity.AssertCalled(t, "Process", address, tx1)
+		entity.AssertCalled(t, "Process", address, tx2)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package message
+
+import "sync"
+
+// The BlockTxs is a message containing the transactions we were missing to
+// reconstruct a compact block, in response to our GetBlockTxs.
+func (handler *Handler) processBlockTxs(wg *sync.WaitGroup, address string, blockTxs *BlockTxs) {
+	defer wg.Done()
+
+	// configure logger
+	with := handler.log.With()
+	with.Str("component", "message")
+	with.Str("message_type", "block_txs")
+	with.Str("address", address)
+	with.Hex("hash", blockTxs.Hash[:])
+	with.Int("num_txs", len(blockTxs.Transactions))
+	log := with.Logger()
+
+	// wrap routine in start and stop messages
+	log.Debug().Msg("routine started")
+	defer log.Debug().Msg("routine stopped")
+
+	// complete the reconstruction of the block
+	err := handler.compacts.Fill(address, blockTxs)
+	if err != nil {
+		log.Error().Err(err).Msg("could not fill compact block")
+		return
+	}
+
+	// mark the transactions as received for the respective peer
+	for _, tx := range blockTxs.Transactions {
+		handler.peers.Received(address, tx.Hash)
+	}
+
+	log.Debug().Msg("processed block_txs message")
+}
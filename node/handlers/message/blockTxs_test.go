@@ -0,0 +1,98 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package message
+
+import (
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+func TestProcessBlockTxsSuccess(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	tx := &types.Transaction{Hash: types.Hash{0x2}}
+	msg := &BlockTxs{Hash: types.Hash{0x1}, Transactions: []*types.Transaction{tx}}
+
+	// initialize mocks
+	peers := &PeersMock{}
+	compacts := &CompactsMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:      zerolog.New(ioutil.Discard),
+		peers:    peers,
+		compacts: compacts,
+	}
+
+	// program mocks
+	peers.On("Received", mock.Anything, mock.Anything)
+	compacts.On("Fill", mock.Anything, mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	compacts.AssertCalled(t, "Fill", address, msg)
+
+	peers.AssertCalled(t, "Received", address, tx.Hash)
+}
+
+func TestProcessBlockTxsFillFails(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	tx := &types.Transaction{Hash: types.Hash{0x2}}
+	msg := &BlockTxs{Hash: types.Hash{0x1}, Transactions: []*types.Transaction{tx}}
+
+	// initialize mocks
+	peers := &PeersMock{}
+	compacts := &CompactsMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:      zerolog.New(ioutil.Discard),
+		peers:    peers,
+		compacts: compacts,
+	}
+
+	// program mocks
+	peers.On("Received", mock.Anything, mock.Anything)
+	compacts.On("Fill", mock.Anything, mock.Anything).Return(errors.New("could not fill"))
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	peers.AssertNotCalled(t, "Received", mock.Anything, mock.Anything)
+}
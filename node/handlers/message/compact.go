@@ -0,0 +1,69 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package message
+
+import "sync"
+
+// The Compact is a message sent by peers to propagate a new block. We handle
+// the header like any other and, once it is accepted, try to reconstruct the
+// block from the transactions in our memory pool.
+func (handler *Handler) processCompact(wg *sync.WaitGroup, address string, compact *Compact) {
+	defer wg.Done()
+
+	// precompute the block hash
+	header := compact.Header
+	header.Hash = header.GetHash()
+
+	// configure logger
+	with := handler.log.With()
+	with.Str("component", "message")
+	with.Str("message_type", "compact")
+	with.Str("address", address)
+	with.Hex("hash", header.Hash[:])
+	with.Int("num_ids", len(compact.ShortIDs))
+	log := with.Logger()
+
+	// wrap routine in start and stop messages
+	log.Debug().Msg("routine started")
+	defer log.Debug().Msg("routine stopped")
+
+	// mark the block as known by the peer
+	handler.peers.Received(address, header.Hash)
+
+	// handle the header entity and wait until it is processed, so that we
+	// don't do any work for blocks with invalid headers
+	done := &sync.WaitGroup{}
+	handler.entity.Process(done, address, header)
+	done.Wait()
+
+	// only reconstruct the blocks of headers we accepted
+	ok := handler.headers.Has(header.Hash)
+	if !ok {
+		log.Debug().Msg("header not accepted")
+		return
+	}
+
+	// reconstruct the block from our memory pool
+	err := handler.compacts.Reconstruct(address, compact)
+	if err != nil {
+		log.Error().Err(err).Msg("could not reconstruct compact block")
+		return
+	}
+
+	log.Debug().Msg("processed compact message")
+}
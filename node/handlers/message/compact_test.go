@@ -0,0 +1,153 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package message
+
+import (
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+func TestProcessCompactSuccess(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	header := &types.Header{Nonce: 1}
+	hash := header.GetHash()
+	msg := &Compact{Header: header, ShortIDs: []uint64{1, 2, 3}}
+
+	// initialize mocks
+	peers := &PeersMock{}
+	entity := &EntityMock{}
+	headers := &HeadersMock{}
+	compacts := &CompactsMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:      zerolog.New(ioutil.Discard),
+		peers:    peers,
+		entity:   entity,
+		headers:  headers,
+		compacts: compacts,
+	}
+
+	// program mocks
+	peers.On("Received", mock.Anything, mock.Anything)
+	entity.On("Process", mock.Anything, mock.Anything)
+	headers.On("Has", mock.Anything).Return(true)
+	compacts.On("Reconstruct", mock.Anything, mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	peers.AssertCalled(t, "Received", address, hash)
+
+	entity.AssertCalled(t, "Process", address, header)
+
+	compacts.AssertCalled(t, "Reconstruct", address, msg)
+}
+
+func TestProcessCompactReconstructFails(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &Compact{Header: &types.Header{Nonce: 1}}
+
+	// initialize mocks
+	peers := &PeersMock{}
+	entity := &EntityMock{}
+	headers := &HeadersMock{}
+	compacts := &CompactsMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:      zerolog.New(ioutil.Discard),
+		peers:    peers,
+		entity:   entity,
+		headers:  headers,
+		compacts: compacts,
+	}
+
+	// program mocks
+	peers.On("Received", mock.Anything, mock.Anything)
+	entity.On("Process", mock.Anything, mock.Anything)
+	headers.On("Has", mock.Anything).Return(true)
+	compacts.On("Reconstruct", mock.Anything, mock.Anything).Return(errors.New("could not reconstruct"))
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	compacts.AssertNumberOfCalls(t, "Reconstruct", 1)
+}
+
+func TestProcessCompactHeaderRejected(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	header := &types.Header{Nonce: 1}
+	hash := header.GetHash()
+	msg := &Compact{Header: header, ShortIDs: []uint64{1, 2, 3}}
+
+	// initialize mocks
+	peers := &PeersMock{}
+	entity := &EntityMock{}
+	headers := &HeadersMock{}
+	compacts := &CompactsMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:      zerolog.New(ioutil.Discard),
+		peers:    peers,
+		entity:   entity,
+		headers:  headers,
+		compacts: compacts,
+	}
+
+	// program mocks
+	peers.On("Received", mock.Anything, mock.Anything)
+	entity.On("Process", mock.Anything, mock.Anything)
+	headers.On("Has", mock.Anything).Return(false)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	entity.AssertCalled(t, "Process", address, header)
+	headers.AssertCalled(t, "Has", hash)
+	compacts.AssertNotCalled(t, "Reconstruct", mock.Anything, mock.Anything)
+}
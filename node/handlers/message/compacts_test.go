@@ -0,0 +1,48 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package message
+
+import (
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/stretchr/testify/mock"
+)
+
+// CompactsMock mocks the compact block manager interface.
+type CompactsMock struct {
+	mock.Mock
+}
+
+// Reconstruct mocks the reconstruct function of the compact block manager
+// interface.
+func (cm *CompactsMock) Reconstruct(address string, compact *Compact) error {
+	args := cm.Called(address, compact)
+	return args.Error(0)
+}
+
+// Fill mocks the fill function of the compact block manager interface.
+func (cm *CompactsMock) Fill(address string, blockTxs *BlockTxs) error {
+	args := cm.Called(address, blockTxs)
+	return args.Error(0)
+}
+
+// NotFound mocks the not found function of the compact block manager
+// interface.
+func (cm *CompactsMock) NotFound(address string, hash types.Hash) error {
+	args := cm.Called(address, hash)
+	return args.Error(0)
+}
@@ -22,5 +22,7 @@ import "github.com/alvalor/alvalor-go/types"
 // Downloads represents the downloads helper interface, as needed by the message
 // handler.
 type Downloads interface {
-	Cancel(hash types.Hash)
+	StartTxs(hashes []types.Hash) error
+	Received(address string, hash types.Hash)
+	NotFound(address string, hash types.Hash) error
 }
@@ -27,7 +27,20 @@ type DownloadsMock struct {
 	mock.Mock
 }
 
-// Cancel mocks the cancel function of the download helper interface.
-func (dm *DownloadsMock) Cancel(hash types.Hash) {
-	dm.Called(hash)
+// StartTxs mocks the start transactions function of the download helper
+// interface.
+func (dm *DownloadsMock) StartTxs(hashes []types.Hash) error {
+	args := dm.Called(hashes)
+	return args.Error(0)
+}
+
+// Received mocks the received function of the download helper interface.
+func (dm *DownloadsMock) Received(address string, hash types.Hash) {
+	dm.Called(address, hash)
+}
+
+// NotFound mocks the not found function of the download helper interface.
+func (dm *DownloadsMock) NotFound(address string, hash types.Hash) error {
+	args := dm.Called(address, hash)
+	return args.Error(0)
 }
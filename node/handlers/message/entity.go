@@ -25,5 +25,5 @@ import (
 
 // Entity represents a handler for network messages.
 type Entity interface {
-	Process(wg *sync.WaitGroup, entity types.Entity)
+	Process(wg *sync.WaitGroup, address string, entity types.Entity)
 }
@@ -29,7 +29,9 @@ type EntityMock struct {
 	mock.Mock
 }
 
-// Process mocks the process function of the message handler interface.
-func (em *EntityMock) Process(wg *sync.WaitGroup, entity types.Entity) {
-	em.Called(wg, entity)
+// Process mocks the process function of the message handler interface. The
+// wait group is not recorded, as it is modified concurrently by the handler
+// routines while the mock formats its arguments.
+func (em *EntityMock) Process(wg *sync.WaitGroup, address string, entity types.Entity) {
+	em.Called(address, entity)
 }
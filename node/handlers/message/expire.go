@@ -0,0 +1,53 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package message
+
+import (
+	"sync"
+	"time"
+)
+
+// Expire drops all peers that did not answer our header request within the
+// given timeout, so that we can continue synchronizing with other peers.
+func (handler *Handler) Expire(wg *sync.WaitGroup, timeout time.Duration) {
+	wg.Add(1)
+	go handler.processExpire(wg, timeout)
+}
+
+func (handler *Handler) processExpire(wg *sync.WaitGroup, timeout time.Duration) {
+	defer wg.Done()
+
+	// configure logger
+	with := handler.log.With()
+	with.Str("component", "message")
+	with.Dur("timeout", timeout)
+	log := with.Logger()
+
+	// wrap routine in start and stop messages
+	log.Debug().Msg("routine started")
+	defer log.Debug().Msg("routine stopped")
+
+	// drop every peer whose header request stalled
+	addresses := handler.requests.Stalled(timeout)
+	for _, address := range addresses {
+		log.Info().Str("address", address).Msg("header request stalled")
+		handler.drop(log, address)
+	}
+
+	log.Debug().Int("num_stalled", len(addresses)).Msg("processed expired requests")
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package message
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExpireStalled(t *testing.T) {
+
+	// initialize parameters
+	address1 := "192.0.2.1"
+	address2 := "192.0.2.2"
+	timeout := 30 * time.Second
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	requests := &RequestsMock{}
+
+	// initialize handler
+	handler := &Handler{
+		net:      net,
+		requests: requests,
+	}
+
+	// program mocks
+	requests.On("Stalled", mock.Anything).Return([]string{address1, address2})
+	net.On("Drop", mock.Anything).Return(nil)
+
+	// execute expire
+	handler.Expire(wg, timeout)
+	wg.Wait()
+
+	// check conditions
+	if requests.AssertNumberOfCalls(t, "Stalled", 1) {
+		requests.AssertCalled(t, "Stalled", timeout)
+	}
+
+	if net.AssertNumberOfCalls(t, "Drop", 2) {
+		net.AssertCalled(t, "Drop", address1)
+		net.AssertCalled(t, "Drop", address2)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package message
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+
+	"github.com/alvalor/alvalor-go/node/repos/inventories"
+	"github.com/alvalor/alvalor-go/node/repos/transactions"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// The GetBlockTxs is a message sent by peers who could not reconstruct a
+// compact block we sent them. We send them the transactions at the requested
+// indexes; if we can't, we let them know so they can download the full block.
+func (handler *Handler) processGetBlockTxs(wg *sync.WaitGroup, address string, getBlockTxs *GetBlockTxs) {
+	defer wg.Done()
+
+	// configure logger
+	with := handler.log.With()
+	with.Str("component", "message")
+	with.Str("message_type", "get_block_txs")
+	with.Str("address", address)
+	with.Hex("hash", getBlockTxs.Hash[:])
+	with.Int("num_indexes", len(getBlockTxs.Indexes))
+	log := with.Logger()
+
+	// wrap routine in start and stop messages
+	log.Debug().Msg("routine started")
+	defer log.Debug().Msg("routine stopped")
+
+	// collect the requested transactions from the block inventory
+	inv, err := handler.inventories.Get(getBlockTxs.Hash)
+	if errors.Cause(err) == inventories.ErrNotExist {
+		handler.notFound(log, address, getBlockTxs.Hash)
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("could not get inventory")
+		return
+	}
+	var txs []*types.Transaction
+	for _, index := range getBlockTxs.Indexes {
+		if int(index) >= len(inv.Hashes) {
+			log.Error().Uint32("index", index).Msg("invalid transaction index")
+			return
+		}
+		tx, err := handler.transactions.Get(inv.Hashes[index])
+		if errors.Cause(err) == transactions.ErrNotExist {
+			handler.notFound(log, address, getBlockTxs.Hash)
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("could not get transaction")
+			return
+		}
+		txs = append(txs, tx)
+	}
+
+	// send the transactions to the peer
+	err = handler.net.Send(address, &BlockTxs{Hash: getBlockTxs.Hash, Transactions: txs})
+	if err != nil {
+		log.Error().Err(err).Msg("could not send block transactions")
+		return
+	}
+
+	log.Debug().Msg("processed get_block_txs message")
+}
+
+// notFound lets the peer know that we can't serve the entity with the given
+// hash.
+func (handler *Handler) notFound(log zerolog.Logger, address string, hash types.Hash) {
+	err := handler.net.Send(address, &NotFound{Hashes: []types.Hash{hash}})
+	if err != nil {
+		log.Error().Err(err).Msg("could not send not found")
+		return
+	}
+	log.Debug().Msg("block transactions not found")
+}
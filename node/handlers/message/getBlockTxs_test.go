@@ -0,0 +1,191 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package message
+
+import (
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alvalor/alvalor-go/node/repos/inventories"
+	"github.com/alvalor/alvalor-go/node/repos/transactions"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+func TestProcessGetBlockTxsSuccess(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	hash := types.Hash{0x1}
+	txHash1 := types.Hash{0x2}
+	txHash2 := types.Hash{0x3}
+	txHash3 := types.Hash{0x4}
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &GetBlockTxs{Hash: hash, Indexes: []uint32{0, 2}}
+	inv := &types.Inventory{Hash: hash, Hashes: []types.Hash{txHash1, txHash2, txHash3}}
+	tx1 := &types.Transaction{Hash: txHash1}
+	tx3 := &types.Transaction{Hash: txHash3}
+	blockTxs := &BlockTxs{Hash: hash, Transactions: []*types.Transaction{tx1, tx3}}
+
+	// initialize mocks
+	inventories := &InventoriesMock{}
+	transactions := &TransactionsMock{}
+	net := &NetworkMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:          zerolog.New(ioutil.Discard),
+		inventories:  inventories,
+		transactions: transactions,
+		net:          net,
+	}
+
+	// program mocks
+	inventories.On("Get", hash).Return(inv, nil)
+	transactions.On("Get", txHash1).Return(tx1, nil)
+	transactions.On("Get", txHash3).Return(tx3, nil)
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	if net.AssertNumberOfCalls(t, "Send", 1) {
+		net.AssertCalled(t, "Send", address, blockTxs)
+	}
+}
+
+func TestProcessGetBlockTxsInventoryMissing(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	hash := types.Hash{0x1}
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &GetBlockTxs{Hash: hash, Indexes: []uint32{0}}
+	notFound := &NotFound{Hashes: []types.Hash{hash}}
+	missing := inventories.ErrNotExist
+
+	// initialize mocks
+	inventories := &InventoriesMock{}
+	net := &NetworkMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:         zerolog.New(ioutil.Discard),
+		inventories: inventories,
+		net:         net,
+	}
+
+	// program mocks
+	inventories.On("Get", mock.Anything).Return(nil, errors.Wrap(missing, "could not find"))
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	if net.AssertNumberOfCalls(t, "Send", 1) {
+		net.AssertCalled(t, "Send", address, notFound)
+	}
+}
+
+func TestProcessGetBlockTxsTransactionMissing(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	hash := types.Hash{0x1}
+	txHash := types.Hash{0x2}
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &GetBlockTxs{Hash: hash, Indexes: []uint32{0}}
+	inv := &types.Inventory{Hash: hash, Hashes: []types.Hash{txHash}}
+	notFound := &NotFound{Hashes: []types.Hash{hash}}
+	missing := transactions.ErrNotExist
+
+	// initialize mocks
+	inventories := &InventoriesMock{}
+	transactions := &TransactionsMock{}
+	net := &NetworkMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:          zerolog.New(ioutil.Discard),
+		inventories:  inventories,
+		transactions: transactions,
+		net:          net,
+	}
+
+	// program mocks
+	inventories.On("Get", mock.Anything).Return(inv, nil)
+	transactions.On("Get", mock.Anything).Return(nil, errors.Wrap(missing, "could not find"))
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	if net.AssertNumberOfCalls(t, "Send", 1) {
+		net.AssertCalled(t, "Send", address, notFound)
+	}
+}
+
+func TestProcessGetBlockTxsInvalidIndex(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	hash := types.Hash{0x1}
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &GetBlockTxs{Hash: hash, Indexes: []uint32{1}}
+	inv := &types.Inventory{Hash: hash, Hashes: []types.Hash{{0x2}}}
+
+	// initialize mocks
+	inventories := &InventoriesMock{}
+	net := &NetworkMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:         zerolog.New(ioutil.Discard),
+		inventories: inventories,
+		net:         net,
+	}
+
+	// program mocks
+	inventories.On("Get", mock.Anything).Return(inv, nil)
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	net.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
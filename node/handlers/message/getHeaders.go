@@ -0,0 +1,72 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package message
+
+import (
+	"sync"
+)
+
+// The GetHeaders message is a request for block headers. It contains a number
+// of locator hashes that allows the receiving peer to search a common block
+// header hash on his best path. The receiving peer will then send a Path
+// message with the missing headers, in chronological order from oldest to
+// newest, limited to the requested maximum and ending at the stop hash. The
+// requesting peer continues with a new request if the page was full. We
+// always reply, even without headers, so the requester knows we are done.
+func (handler *Handler) processGetHeaders(wg *sync.WaitGroup, address string, request *GetHeaders) {
+	defer wg.Done()
+
+	// configure logger
+	with := handler.log.With()
+	with.Str("component", "message")
+	with.Str("message_type", "get_headers")
+	with.Str("address", address)
+	with.Int("num_locators", len(request.Locators))
+	with.Uint32("max", request.Max)
+	log := with.Logger()
+
+	// wrap routine in start and stop messages
+	log.Debug().Msg("routine started")
+	defer log.Debug().Msg("routine stopped")
+
+	// cap the number of headers we send in one message
+	max := request.Max
+	if max == 0 || max > MaxHeaders {
+		max = MaxHeaders
+	}
+
+	// collect the headers on our best path that follow the highest locator we
+	// know, starting with the oldest, until we reach the maximum or the stop hash
+	hdrs, err := handler.headers.Following(request.Locators, request.Stop, uint(max))
+	if err != nil {
+		log.Error().Err(err).Msg("could not retrieve headers")
+		return
+	}
+
+	// send the partial path to our best distance to the other node
+	p := &Path{
+		Headers: hdrs,
+	}
+	err = handler.net.Send(address, p)
+	if err != nil {
+		log.Error().Err(err).Msg("could not send path")
+		return
+	}
+
+	log.Debug().Int("num_headers", len(hdrs)).Msg("processed get headers message")
+}
@@ -26,21 +26,17 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
-func TestProcessSyncSuccess(t *testing.T) {
+func TestProcessGetHeadersSuccess(t *testing.T) {
 
 	// initialize parameters
 	address := "192.0.2.1"
 	hash1 := types.Hash{0x1}
 	hash2 := types.Hash{0x2}
 	hash3 := types.Hash{0x3}
-	hash4 := types.Hash{0x4}
 
 	// initialize entities
 	wg := &sync.WaitGroup{}
-	msg := &Sync{Locators: []types.Hash{hash1, hash2}}
-	path := []types.Hash{hash4, hash3, hash2, hash1}
-	header1 := &types.Header{Nonce: 1}
-	header2 := &types.Header{Nonce: 2}
+	msg := &GetHeaders{Locators: []types.Hash{hash1, hash2}, Stop: hash3}
 	header3 := &types.Header{Nonce: 3}
 	header4 := &types.Header{Nonce: 4}
 	pathMsg := &Path{Headers: []*types.Header{header3, header4}}
@@ -56,11 +52,7 @@ func TestProcessSyncSuccess(t *testing.T) {
 	}
 
 	// program mocks
-	headers.On("Path").Return(path, 0)
-	headers.On("Get", hash1).Return(header1, nil)
-	headers.On("Get", hash2).Return(header2, nil)
-	headers.On("Get", hash3).Return(header3, nil)
-	headers.On("Get", hash4).Return(header4, nil)
+	headers.On("Following", mock.Anything, mock.Anything, mock.Anything).Return([]*types.Header{header3, header4}, nil)
 	net.On("Send", mock.Anything, mock.Anything).Return(nil)
 
 	// execute process
@@ -68,11 +60,8 @@ func TestProcessSyncSuccess(t *testing.T) {
 	wg.Wait()
 
 	// check conditions
-	headers.AssertNumberOfCalls(t, "Path", 1)
-
-	if headers.AssertNumberOfCalls(t, "Get", 2) {
-		headers.AssertCalled(t, "Get", hash3)
-		headers.AssertCalled(t, "Get", hash4)
+	if headers.AssertNumberOfCalls(t, "Following", 1) {
+		headers.AssertCalled(t, "Following", msg.Locators, hash3, uint(MaxHeaders))
 	}
 
 	if net.AssertNumberOfCalls(t, "Send", 1) {
@@ -80,23 +69,17 @@ func TestProcessSyncSuccess(t *testing.T) {
 	}
 }
 
-func TestProcessSyncNoPath(t *testing.T) {
+func TestProcessGetHeadersNoPath(t *testing.T) {
 
 	// initialize parameters
 	address := "192.0.2.1"
 	hash1 := types.Hash{0x1}
 	hash2 := types.Hash{0x2}
-	hash3 := types.Hash{0x3}
-	hash4 := types.Hash{0x4}
 
 	// initialize entities
 	wg := &sync.WaitGroup{}
-	msg := &Sync{Locators: []types.Hash{hash1, hash2}}
-	path := []types.Hash{hash2, hash1}
-	header1 := &types.Header{Nonce: 1}
-	header2 := &types.Header{Nonce: 2}
-	header3 := &types.Header{Nonce: 3}
-	header4 := &types.Header{Nonce: 4}
+	msg := &GetHeaders{Locators: []types.Hash{hash1, hash2}}
+	pathMsg := &Path{}
 
 	// initialize mocks
 	headers := &HeadersMock{}
@@ -109,11 +92,7 @@ func TestProcessSyncNoPath(t *testing.T) {
 	}
 
 	// program mocks
-	headers.On("Path").Return(path, 0)
-	headers.On("Get", hash1).Return(header1, nil)
-	headers.On("Get", hash2).Return(header2, nil)
-	headers.On("Get", hash3).Return(header3, nil)
-	headers.On("Get", hash4).Return(header4, nil)
+	headers.On("Following", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 	net.On("Send", mock.Anything, mock.Anything).Return(nil)
 
 	// execute process
@@ -121,30 +100,23 @@ func TestProcessSyncNoPath(t *testing.T) {
 	wg.Wait()
 
 	// check conditions
-	headers.AssertNumberOfCalls(t, "Path", 1)
-
-	headers.AssertNumberOfCalls(t, "Get", 0)
+	headers.AssertNumberOfCalls(t, "Following", 1)
 
-	net.AssertNumberOfCalls(t, "Send", 0)
+	if net.AssertNumberOfCalls(t, "Send", 1) {
+		net.AssertCalled(t, "Send", address, pathMsg)
+	}
 }
 
-func TestProcessSyncGetFails(t *testing.T) {
+func TestProcessGetHeadersFollowingFails(t *testing.T) {
 
 	// initialize parameters
 	address := "192.0.2.1"
 	hash1 := types.Hash{0x1}
 	hash2 := types.Hash{0x2}
-	hash3 := types.Hash{0x3}
-	hash4 := types.Hash{0x4}
 
 	// initialize entities
 	wg := &sync.WaitGroup{}
-	msg := &Sync{Locators: []types.Hash{hash1, hash2}}
-	path := []types.Hash{hash4, hash3, hash2, hash1}
-	header1 := &types.Header{Nonce: 1}
-	header2 := &types.Header{Nonce: 2}
-	header3 := &types.Header{Nonce: 3}
-	header4 := &types.Header{Nonce: 4}
+	msg := &GetHeaders{Locators: []types.Hash{hash1, hash2}}
 
 	// initialize mocks
 	headers := &HeadersMock{}
@@ -157,11 +129,7 @@ func TestProcessSyncGetFails(t *testing.T) {
 	}
 
 	// program mocks
-	headers.On("Path").Return(path, 0)
-	headers.On("Get", hash1).Return(header1, nil)
-	headers.On("Get", hash2).Return(header2, nil)
-	headers.On("Get", hash3).Return(header3, errors.New(""))
-	headers.On("Get", hash4).Return(header4, errors.New(""))
+	headers.On("Following", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New(""))
 	net.On("Send", mock.Anything, mock.Anything).Return(nil)
 
 	// execute process
@@ -169,30 +137,21 @@ func TestProcessSyncGetFails(t *testing.T) {
 	wg.Wait()
 
 	// check conditions
-	headers.AssertNumberOfCalls(t, "Path", 1)
-
-	if headers.AssertNumberOfCalls(t, "Get", 1) {
-		headers.AssertCalled(t, "Get", hash3)
-	}
+	headers.AssertNumberOfCalls(t, "Following", 1)
 
 	net.AssertNumberOfCalls(t, "Send", 0)
 }
 
-func TestProcessSyncSendFails(t *testing.T) {
+func TestProcessGetHeadersSendFails(t *testing.T) {
 
 	// initialize parameters
 	address := "192.0.2.1"
 	hash1 := types.Hash{0x1}
 	hash2 := types.Hash{0x2}
-	hash3 := types.Hash{0x3}
-	hash4 := types.Hash{0x4}
 
 	// initialize entities
 	wg := &sync.WaitGroup{}
-	msg := &Sync{Locators: []types.Hash{hash1, hash2}}
-	path := []types.Hash{hash4, hash3, hash2, hash1}
-	header1 := &types.Header{Nonce: 1}
-	header2 := &types.Header{Nonce: 2}
+	msg := &GetHeaders{Locators: []types.Hash{hash1, hash2}}
 	header3 := &types.Header{Nonce: 3}
 	header4 := &types.Header{Nonce: 4}
 	pathMsg := &Path{Headers: []*types.Header{header3, header4}}
@@ -208,11 +167,7 @@ func TestProcessSyncSendFails(t *testing.T) {
 	}
 
 	// program mocks
-	headers.On("Path").Return(path, 0)
-	headers.On("Get", hash1).Return(header1, nil)
-	headers.On("Get", hash2).Return(header2, nil)
-	headers.On("Get", hash3).Return(header3, nil)
-	headers.On("Get", hash4).Return(header4, nil)
+	headers.On("Following", mock.Anything, mock.Anything, mock.Anything).Return([]*types.Header{header3, header4}, nil)
 	net.On("Send", mock.Anything, mock.Anything).Return(errors.New(""))
 
 	// execute process
@@ -220,14 +175,46 @@ func TestProcessSyncSendFails(t *testing.T) {
 	wg.Wait()
 
 	// check conditions
-	headers.AssertNumberOfCalls(t, "Path", 1)
-
-	if headers.AssertNumberOfCalls(t, "Get", 2) {
-		headers.AssertCalled(t, "Get", hash3)
-		headers.AssertCalled(t, "Get", hash4)
-	}
+	headers.AssertNumberOfCalls(t, "Following", 1)
 
 	if net.AssertNumberOfCalls(t, "Send", 1) {
 		net.AssertCalled(t, "Send", address, pathMsg)
 	}
 }
+
+func TestProcessGetHeadersMax(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	hash1 := types.Hash{0x1}
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	small := &GetHeaders{Locators: []types.Hash{hash1}, Max: 2}
+	large := &GetHeaders{Locators: []types.Hash{hash1}, Max: MaxHeaders + 1}
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	net := &NetworkMock{}
+
+	// initialize handler
+	handler := &Handler{
+		headers: headers,
+		net:     net,
+	}
+
+	// program mocks
+	headers.On("Following", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, small)
+	handler.Process(wg, address, large)
+	wg.Wait()
+
+	// check conditions
+	if headers.AssertNumberOfCalls(t, "Following", 2) {
+		headers.AssertCalled(t, "Following", small.Locators, types.Hash{}, uint(2))
+		headers.AssertCalled(t, "Following", large.Locators, types.Hash{}, uint(MaxHeaders))
+	}
+}
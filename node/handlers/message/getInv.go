@@ -19,11 +19,17 @@ package message
 
 import (
 	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/alvalor/alvalor-go/node/repos/inventories"
+	"github.com/alvalor/alvalor-go/types"
 )
 
 // The GetInv is a message sent by peers who want to download the given
 // block inventory from us. If we have it, we send it to them.
-// TODO: reply with not available if we don't have it
+// Otherwise, we let them know that we don't have it, so they can try to
+// download it from someone else.
 func (handler *Handler) processGetInv(wg *sync.WaitGroup, address string, getInv *GetInv) {
 	defer wg.Done()
 
@@ -41,6 +47,17 @@ func (handler *Handler) processGetInv(wg *sync.WaitGroup, address string, getInv
 
 	// try to get the inventory
 	inv, err := handler.inventories.Get(getInv.Hash)
+
+	// if we don't have it, let the peer know so it can ask someone else
+	if errors.Cause(err) == inventories.ErrNotExist {
+		err = handler.net.Send(address, &NotFound{Hashes: []types.Hash{getInv.Hash}})
+		if err != nil {
+			log.Error().Err(err).Msg("could not send not found")
+			return
+		}
+		log.Debug().Msg("inventory not found")
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("could not get inventory")
 		return
@@ -18,12 +18,13 @@
 package message
 
 import (
-	"errors"
 	"io/ioutil"
 	"sync"
 	"testing"
 
+	"github.com/alvalor/alvalor-go/node/repos/inventories"
 	"github.com/alvalor/alvalor-go/types"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/mock"
 )
@@ -145,3 +146,40 @@ func TestProcessGetInvSendFails(t *testing.T) {
 		net.AssertCalled(t, "Send", address, inv)
 	}
 }
+
+func TestProcessGetInvNotFound(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	hash := types.Hash{0x1}
+	missing := inventories.ErrNotExist
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &GetInv{Hash: hash}
+	notFound := &NotFound{Hashes: []types.Hash{hash}}
+
+	// initialize mocks
+	inventories := &InventoriesMock{}
+	net := &NetworkMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:         zerolog.New(ioutil.Discard),
+		inventories: inventories,
+		net:         net,
+	}
+
+	// program mocks
+	inventories.On("Get", mock.Anything).Return(nil, errors.Wrap(missing, "could not find"))
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	if net.AssertNumberOfCalls(t, "Send", 1) {
+		net.AssertCalled(t, "Send", address, notFound)
+	}
+}
@@ -17,11 +17,19 @@
 
 package message
 
-import "sync"
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/alvalor/alvalor-go/node/repos/transactions"
+	"github.com/alvalor/alvalor-go/types"
+)
 
 // The GetTx is a message sent by peers who want to download the given
 // transaction from us. If we have it, we send it to them.
-// TODO: reply with not available if we don't have it
+// Otherwise, we let them know that we don't have it, so they can try to
+// download it from someone else.
 func (handler *Handler) processGetTx(wg *sync.WaitGroup, address string, getTx *GetTx) {
 	defer wg.Done()
 
@@ -39,6 +47,17 @@ func (handler *Handler) processGetTx(wg *sync.WaitGroup, address string, getTx *
 
 	// try to get the inventory
 	tx, err := handler.transactions.Get(getTx.Hash)
+
+	// if we don't have it, let the peer know so it can ask someone else
+	if errors.Cause(err) == transactions.ErrNotExist {
+		err = handler.net.Send(address, &NotFound{Hashes: []types.Hash{getTx.Hash}})
+		if err != nil {
+			log.Error().Err(err).Msg("could not send not found")
+			return
+		}
+		log.Debug().Msg("transaction not found")
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("could not get transaction")
 		return
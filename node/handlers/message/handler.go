@@ -20,10 +20,30 @@ package message
 import (
 	"sync"
 
-	"github.com/alvalor/alvalor-go/types"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
+
+	"github.com/alvalor/alvalor-go/node/handlers/workers"
+	"github.com/alvalor/alvalor-go/types"
 )
 
+// MaxHeaders is the maximum number of headers we request and send in a single
+// Path message.
+const MaxHeaders = 2000
+
+// MaxBatchTxs is the maximum number of transactions we request and send in a
+// single Batch message.
+const MaxBatchTxs = 1024
+
+// MaxBatchSize is the maximum approximate size in bytes of the transactions we
+// send in a single Batch message.
+const MaxBatchSize = 3 << 20
+
+// MaxBloomHashes is the maximum number of hash functions we accept for the
+// bloom filter of a Mempool message, as each one is applied to every pending
+// transaction we test against the filter.
+const MaxBloomHashes = 32
+
 // Handler represents the handler for messages from the network stack.
 type Handler struct {
 	log          zerolog.Logger
@@ -34,26 +54,135 @@ type Handler struct {
 	inventories  Inventories
 	transactions Transactions
 	peers        Peers
+	requests     Requests
 	entity       Entity
+	reconciler   Reconciler
+	compacts     Compacts
+	pool         Pool
+	limiter      Limiter
+	tracker      Tracker
+}
+
+// NewHandler creates a new handler for messages from the network stack.
+func NewHandler(log zerolog.Logger, net Network, paths Paths, downloads Downloads, headers Headers, inventories Inventories, transactions Transactions, peers Peers, requests Requests, entity Entity, reconciler Reconciler, compacts Compacts, pool Pool, limiter Limiter, tracker Tracker) *Handler {
+	return &Handler{
+		log:          log,
+		net:          net,
+		paths:        paths,
+		downloads:    downloads,
+		headers:      headers,
+		inventories:  inventories,
+		transactions: transactions,
+		peers:        peers,
+		requests:     requests,
+		entity:       entity,
+		reconciler:   reconciler,
+		compacts:     compacts,
+		pool:         pool,
+		limiter:      limiter,
+		tracker:      tracker,
+	}
 }
 
-// Process processes a message from the network.
+// Process processes a message from the network. Messages exceeding the rate
+// limits of the peer are discarded. Others are queued with the worker pool,
+// which processes the messages of each peer in order; if the peer floods us
+// with messages and its queue is full, we drop the peer.
 func (handler *Handler) Process(wg *sync.WaitGroup, address string, message interface{}) {
-	wg.Add(1)
+	var kind string
+	var task func()
 	switch msg := message.(type) {
 	case *Status:
-		go handler.processStatus(wg, address, msg)
-	case *Sync:
-		go handler.processSync(wg, address, msg)
+		kind = "status"
+		task = func() { handler.processStatus(wg, address, msg) }
+	case *GetHeaders:
+		kind = "get_headers"
+		task = func() { handler.processGetHeaders(wg, address, msg) }
 	case *Path:
-		go handler.processPath(wg, address, msg)
+		kind = "path"
+		task = func() { handler.processPath(wg, address, msg) }
 	case *GetInv:
-		go handler.processGetInv(wg, address, msg)
+		kind = "get_inv"
+		task = func() { handler.processGetInv(wg, address, msg) }
 	case *GetTx:
-		go handler.processGetTx(wg, address, msg)
+		kind = "get_tx"
+		task = func() { handler.processGetTx(wg, address, msg) }
+	case *NotFound:
+		kind = "not_found"
+		task = func() { handler.processNotFound(wg, address, msg) }
+	case *Request:
+		kind = "request"
+		task = func() { handler.processRequest(wg, address, msg) }
+	case *Batch:
+		kind = "batch"
+		task = func() { handler.processBatch(wg, address, msg) }
+	case *Mempool:
+		kind = "mempool"
+		task = func() { handler.processMempool(wg, address, msg) }
+	case *Announce:
+		kind = "announce"
+		task = func() { handler.processAnnounce(wg, address, msg) }
+	case *Compact:
+		kind = "compact"
+		task = func() { handler.processCompact(wg, address, msg) }
+	case *GetBlockTxs:
+		kind = "get_block_txs"
+		task = func() { handler.processGetBlockTxs(wg, address, msg) }
+	case *BlockTxs:
+		kind = "block_txs"
+		task = func() { handler.processBlockTxs(wg, address, msg) }
 	case *types.Inventory:
-		go handler.processInventory(wg, address, msg)
+		kind = "inventory"
+		task = func() { handler.processInventory(wg, address, msg) }
 	case *types.Transaction:
-		go handler.processTransaction(wg, address, msg)
+		kind = "transaction"
+		task = func() { handler.processTransaction(wg, address, msg) }
+	default:
+		return
+	}
+	if !handler.allow(address, kind) {
+		return
+	}
+	wg.Add(1)
+	handler.dispatch(wg, address, task)
+}
+
+// dispatch submits the task to the worker pool and drops the peer if its own
+// queue is full. If the pool as a whole is busy, the task is discarded without
+// blaming the peer. If no worker pool is configured, the task runs on its own
+// goroutine.
+func (handler *Handler) dispatch(wg *sync.WaitGroup, address string, task func()) {
+	if handler.pool == nil {
+		go task()
+		return
+	}
+	err := handler.pool.Submit(address, task)
+	if err == nil {
+		return
+	}
+	wg.Done()
+	handler.log.Error().Err(err).Str("address", address).Msg("could not queue message")
+	if errors.Cause(err) != workers.ErrFull {
+		return
+	}
+	err = handler.net.Drop(address)
+	if err != nil {
+		handler.log.Error().Err(err).Str("address", address).Msg("could not drop flooding peer")
+	}
+}
+
+// allow checks the message against the rate limits of the peer. If the peer
+// exceeded its limit, we lower its reputation and discard the message. If no
+// rate limiter is configured, all messages are allowed.
+func (handler *Handler) allow(address string, kind string) bool {
+	if handler.limiter == nil {
+		return true
+	}
+	err := handler.limiter.Take(address, kind)
+	if err == nil {
+		return true
 	}
+	handler.log.Warn().Err(err).Str("address", address).Str("message_type", kind).Msg("discarding rate limited message")
+	handler.net.Penalize(address)
+	return false
 }
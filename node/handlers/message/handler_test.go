@@ -16,3 +16,227 @@
 // along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
 
 package message
+
+import (
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alvalor/alvalor-go/node/handlers/limits"
+	"github.com/alvalor/alvalor-go/node/handlers/workers"
+)
+
+func TestHandlerDispatchSuccess(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &NotFound{}
+
+	// initialize mocks
+	pool := &PoolMock{}
+	net := &NetworkMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:  zerolog.New(ioutil.Discard),
+		pool: pool,
+		net:  net,
+	}
+
+	// program mocks
+	pool.On("Submit", mock.Anything, mock.Anything).Return(nil)
+	net.On("Drop", mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	pool.AssertCalled(t, "Submit", address, mock.Anything)
+
+	net.AssertNotCalled(t, "Drop", mock.Anything)
+}
+
+func TestHandlerDispatchFull(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &NotFound{}
+
+	// initialize mocks
+	pool := &PoolMock{}
+	net := &NetworkMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:  zerolog.New(ioutil.Discard),
+		pool: pool,
+		net:  net,
+	}
+
+	// program mocks
+	pool.On("Submit", mock.Anything, mock.Anything).Return(errors.Wrap(workers.ErrFull, "could not submit"))
+	net.On("Drop", mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	net.AssertCalled(t, "Drop", address)
+}
+
+func TestHandlerDispatchBusy(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &NotFound{}
+
+	// initialize mocks
+	pool := &PoolMock{}
+	net := &NetworkMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:  zerolog.New(ioutil.Discard),
+		pool: pool,
+		net:  net,
+	}
+
+	// program mocks
+	pool.On("Submit", mock.Anything, mock.Anything).Return(errors.Wrap(workers.ErrBusy, "could not submit"))
+	net.On("Drop", mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	net.AssertNotCalled(t, "Drop", mock.Anything)
+}
+
+func TestHandlerDispatchStopped(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &NotFound{}
+
+	// initialize mocks
+	pool := &PoolMock{}
+	net := &NetworkMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:  zerolog.New(ioutil.Discard),
+		pool: pool,
+		net:  net,
+	}
+
+	// program mocks
+	pool.On("Submit", mock.Anything, mock.Anything).Return(errors.Wrap(workers.ErrStopped, "could not submit"))
+	net.On("Drop", mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	net.AssertNotCalled(t, "Drop", mock.Anything)
+}
+
+func TestHandlerLimitAllowed(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &GetInv{}
+
+	// initialize mocks
+	pool := &PoolMock{}
+	net := &NetworkMock{}
+	limiter := &LimiterMock{}
+	inventories := &InventoriesMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:         zerolog.New(ioutil.Discard),
+		pool:        pool,
+		net:         net,
+		limiter:     limiter,
+		inventories: inventories,
+	}
+
+	// program mocks
+	limiter.On("Take", mock.Anything, mock.Anything).Return(nil)
+	pool.On("Submit", mock.Anything, mock.Anything).Return(nil)
+	net.On("Penalize", mock.Anything)
+	inventories.On("Get", mock.Anything).Return(nil, errors.New(""))
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	limiter.AssertCalled(t, "Take", address, "get_inv")
+
+	pool.AssertCalled(t, "Submit", address, mock.Anything)
+
+	net.AssertNotCalled(t, "Penalize", mock.Anything)
+}
+
+func TestHandlerLimitExceeded(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &GetHeaders{}
+
+	// initialize mocks
+	pool := &PoolMock{}
+	net := &NetworkMock{}
+	limiter := &LimiterMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:     zerolog.New(ioutil.Discard),
+		pool:    pool,
+		net:     net,
+		limiter: limiter,
+	}
+
+	// program mocks
+	limiter.On("Take", mock.Anything, mock.Anything).Return(errors.Wrap(limits.ErrLimited, "could not take"))
+	pool.On("Submit", mock.Anything, mock.Anything).Return(nil)
+	net.On("Penalize", mock.Anything)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	limiter.AssertCalled(t, "Take", address, "get_headers")
+
+	pool.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything)
+
+	net.AssertCalled(t, "Penalize", address)
+}
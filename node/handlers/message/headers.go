@@ -22,6 +22,7 @@ import "github.com/alvalor/alvalor-go/types"
 // Headers represents the header repository interface, as needed by the message
 // handler
 type Headers interface {
-	Get(hash types.Hash) (*types.Header, error)
-	Path() ([]types.Hash, uint64)
+	Has(hash types.Hash) bool
+	Locators() ([]types.Hash, uint64)
+	Following(locators []types.Hash, stop types.Hash, max uint) ([]*types.Header, error)
 }
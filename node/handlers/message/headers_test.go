@@ -27,22 +27,28 @@ type HeadersMock struct {
 	mock.Mock
 }
 
-// Get mocks the get function of the header repository interface.
-func (hm *HeadersMock) Get(hash types.Hash) (*types.Header, error) {
+// Has mocks the has function of the header repository interface.
+func (hm *HeadersMock) Has(hash types.Hash) bool {
 	args := hm.Called(hash)
-	var header *types.Header
+	return args.Bool(0)
+}
+
+// Locators mocks the locators function of the header repository interface.
+func (hm *HeadersMock) Locators() ([]types.Hash, uint64) {
+	args := hm.Called()
+	var locators []types.Hash
 	if args.Get(0) != nil {
-		header = args.Get(0).(*types.Header)
+		locators = args.Get(0).([]types.Hash)
 	}
-	return header, args.Error(1)
+	return locators, uint64(args.Int(1))
 }
 
-// Path mocks the path function of the header repository interface.
-func (hm *HeadersMock) Path() ([]types.Hash, uint64) {
-	args := hm.Called()
-	var path []types.Hash
+// Following mocks the following function of the header repository interface.
+func (hm *HeadersMock) Following(locators []types.Hash, stop types.Hash, max uint) ([]*types.Header, error) {
+	args := hm.Called(locators, stop, max)
+	var headers []*types.Header
 	if args.Get(0) != nil {
-		path = args.Get(0).([]types.Hash)
+		headers = args.Get(0).([]*types.Header)
 	}
-	return path, uint64(args.Int(1))
+	return headers, args.Error(1)
 }
@@ -41,8 +41,8 @@ func (handler *Handler) processInventory(wg *sync.WaitGroup, address string, inv
 	log.Debug().Msg("routine started")
 	defer log.Debug().Msg("routine stopped")
 
-	// cancel any pending download retries for this inventory
-	handler.downloads.Cancel(inv.Hash)
+	// mark any pending download of this inventory as delivered
+	handler.downloads.Received(address, inv.Hash)
 
 	// mark the inventory as received for the respective peer
 	handler.peers.Received(address, inv.Hash)
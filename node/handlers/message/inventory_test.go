@@ -54,7 +54,7 @@ func TestProcessInventorySuccess(t *testing.T) {
 	}
 
 	// program mocks
-	downloads.On("Cancel", mock.Anything)
+	downloads.On("Received", mock.Anything, mock.Anything)
 	peers.On("Received", mock.Anything, mock.Anything)
 	inventories.On("Add", mock.Anything).Return(nil)
 	paths.On("Signal", mock.Anything).Return(nil)
@@ -64,8 +64,8 @@ func TestProcessInventorySuccess(t *testing.T) {
 	wg.Wait()
 
 	// check conditions
-	if downloads.AssertNumberOfCalls(t, "Cancel", 1) {
-		downloads.AssertCalled(t, "Cancel", hash)
+	if downloads.AssertNumberOfCalls(t, "Received", 1) {
+		downloads.AssertCalled(t, "Received", address, hash)
 	}
 
 	if peers.AssertNumberOfCalls(t, "Received", 1) {
@@ -107,7 +107,7 @@ func TestProcessInventoryAddFails(t *testing.T) {
 	}
 
 	// program mocks
-	downloads.On("Cancel", mock.Anything)
+	downloads.On("Received", mock.Anything, mock.Anything)
 	peers.On("Received", mock.Anything, mock.Anything)
 	inventories.On("Add", mock.Anything).Return(errors.New(""))
 	paths.On("Signal", mock.Anything).Return(nil)
@@ -117,8 +117,8 @@ func TestProcessInventoryAddFails(t *testing.T) {
 	wg.Wait()
 
 	// check conditions
-	if downloads.AssertNumberOfCalls(t, "Cancel", 1) {
-		downloads.AssertCalled(t, "Cancel", hash)
+	if downloads.AssertNumberOfCalls(t, "Received", 1) {
+		downloads.AssertCalled(t, "Received", address, hash)
 	}
 
 	if peers.AssertNumberOfCalls(t, "Received", 1) {
@@ -158,7 +158,7 @@ func TestProcessInventorySignalFails(t *testing.T) {
 	}
 
 	// program mocks
-	downloads.On("Cancel", mock.Anything)
+	downloads.On("Received", mock.Anything, mock.Anything)
 	peers.On("Received", mock.Anything, mock.Anything)
 	inventories.On("Add", mock.Anything).Return(nil)
 	paths.On("Signal", mock.Anything).Return(errors.New(""))
@@ -168,8 +168,8 @@ func TestProcessInventorySignalFails(t *testing.T) {
 	wg.Wait()
 
 	// check conditions
-	if downloads.AssertNumberOfCalls(t, "Cancel", 1) {
-		downloads.AssertCalled(t, "Cancel", hash)
+	if downloads.AssertNumberOfCalls(t, "Received", 1) {
+		downloads.AssertCalled(t, "Received", address, hash)
 	}
 
 	if peers.AssertNumberOfCalls(t, "Received", 1) {
@@ -0,0 +1,62 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package message
+
+import "sync"
+
+// The Mempool is a message sent by peers to let us know which transactions
+// they already have in their memory pool. We send them the pending
+// transactions of our own that don't match their filter.
+func (handler *Handler) processMempool(wg *sync.WaitGroup, address string, mempool *Mempool) {
+	defer wg.Done()
+
+	// configure logger
+	with := handler.log.With()
+	with.Str("component", "message")
+	with.Str("message_type", "mempool")
+	with.Str("address", address)
+	log := with.Logger()
+
+	// wrap routine in start and stop messages
+	log.Debug().Msg("routine started")
+	defer log.Debug().Msg("routine stopped")
+
+	// ignore messages without a filter
+	if mempool.Bloom == nil {
+		log.Debug().Msg("ignoring mempool message without filter")
+		return
+	}
+
+	// drop peers sending filters that would make us divide by zero or spend
+	// an unreasonable amount of time hashing our pending transactions
+	if mempool.Bloom.Cap() == 0 || mempool.Bloom.K() == 0 || mempool.Bloom.K() > MaxBloomHashes {
+		log.Error().Uint("m", mempool.Bloom.Cap()).Uint("k", mempool.Bloom.K()).Msg("invalid mempool filter")
+		handler.drop(log, address)
+		return
+	}
+
+	// send the transactions the peer is missing in size-limited batches
+	txs := handler.reconciler.Missing(mempool.Bloom)
+	err := handler.sendBatches(address, txs)
+	if err != nil {
+		log.Error().Err(err).Msg("could not send batches")
+		return
+	}
+
+	log.Debug().Int("num_txs", len(txs)).Msg("processed mempool message")
+}
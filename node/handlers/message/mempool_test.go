@@ -0,0 +1,207 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package message
+
+import (
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/willf/bloom"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+func TestProcessMempoolSuccess(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	filter := bloom.NewWithEstimates(10, 0.01)
+	msg := &Mempool{Bloom: filter}
+	tx1 := &types.Transaction{Hash: types.Hash{0x1}}
+	tx2 := &types.Transaction{Hash: types.Hash{0x2}}
+	batch := &Batch{Transactions: []*types.Transaction{tx1, tx2}}
+
+	// initialize mocks
+	reconciler := &ReconcilerMock{}
+	net := &NetworkMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:        zerolog.New(ioutil.Discard),
+		reconciler: reconciler,
+		net:        net,
+	}
+
+	// program mocks
+	reconciler.On("Missing", filter).Return([]*types.Transaction{tx1, tx2})
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	reconciler.AssertCalled(t, "Missing", filter)
+
+	if net.AssertNumberOfCalls(t, "Send", 1) {
+		net.AssertCalled(t, "Send", address, batch)
+	}
+}
+
+func TestProcessMempoolNothingMissing(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &Mempool{Bloom: bloom.NewWithEstimates(10, 0.01)}
+
+	// initialize mocks
+	reconciler := &ReconcilerMock{}
+	net := &NetworkMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:        zerolog.New(ioutil.Discard),
+		reconciler: reconciler,
+		net:        net,
+	}
+
+	// program mocks
+	reconciler.On("Missing", mock.Anything).Return(nil)
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	net.AssertNumberOfCalls(t, "Send", 0)
+}
+
+func TestProcessMempoolNoFilter(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &Mempool{}
+
+	// initialize mocks
+	reconciler := &ReconcilerMock{}
+	net := &NetworkMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:        zerolog.New(ioutil.Discard),
+		reconciler: reconciler,
+		net:        net,
+	}
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	reconciler.AssertNotCalled(t, "Missing", mock.Anything)
+	net.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
+
+func TestProcessMempoolInvalidFilter(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	filters := []*bloom.BloomFilter{
+		bloom.From(nil, 3),
+		bloom.From(make([]uint64, 16), 0),
+		bloom.From(make([]uint64, 16), MaxBloomHashes+1),
+	}
+
+	for _, filter := range filters {
+
+		wg := &sync.WaitGroup{}
+		msg := &Mempool{Bloom: filter}
+
+		// initialize mocks
+		reconciler := &ReconcilerMock{}
+		net := &NetworkMock{}
+
+		// initialize handler
+		handler := &Handler{
+			log:        zerolog.New(ioutil.Discard),
+			reconciler: reconciler,
+			net:        net,
+		}
+
+		// program mocks
+		net.On("Drop", mock.Anything).Return(nil)
+
+		// execute process
+		handler.Process(wg, address, msg)
+		wg.Wait()
+
+		// check conditions
+		reconciler.AssertNotCalled(t, "Missing", mock.Anything)
+		net.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+		net.AssertCalled(t, "Drop", address)
+	}
+}
+
+func TestProcessMempoolSendFails(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &Mempool{Bloom: bloom.NewWithEstimates(10, 0.01)}
+	tx := &types.Transaction{Hash: types.Hash{0x1}}
+
+	// initialize mocks
+	reconciler := &ReconcilerMock{}
+	net := &NetworkMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:        zerolog.New(ioutil.Discard),
+		reconciler: reconciler,
+		net:        net,
+	}
+
+	// program mocks
+	reconciler.On("Missing", mock.Anything).Return([]*types.Transaction{tx})
+	net.On("Send", mock.Anything, mock.Anything).Return(errors.New("could not send"))
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	net.AssertNumberOfCalls(t, "Send", 1)
+}
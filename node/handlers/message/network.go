@@ -21,4 +21,6 @@ package message
 // handler.
 type Network interface {
 	Send(address string, msg interface{}) error
+	Drop(address string) error
+	Penalize(address string)
 }
@@ -29,3 +29,14 @@ func (nm *NetworkMock) Send(address string, msg interface{}) error {
 	args := nm.Called(address, msg)
 	return args.Error(0)
 }
+
+// Drop mocks the drop functionality.
+func (nm *NetworkMock) Drop(address string) error {
+	args := nm.Called(address)
+	return args.Error(0)
+}
+
+// Penalize mocks the penalize functionality.
+func (nm *NetworkMock) Penalize(address string) {
+	nm.Called(address)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package message
+
+import "sync"
+
+// The NotFound is a message sent by peers in response to our download
+// requests for entities they don't have. We remember that they don't have
+// them and immediately reroute the downloads to other peers.
+func (handler *Handler) processNotFound(wg *sync.WaitGroup, address string, notFound *NotFound) {
+	defer wg.Done()
+
+	// configure logger
+	with := handler.log.With()
+	with.Str("component", "message")
+	with.Str("message_type", "not_found")
+	with.Str("address", address)
+	with.Int("num_hashes", len(notFound.Hashes))
+	log := with.Logger()
+
+	// wrap routine in start and stop messages
+	log.Debug().Msg("routine started")
+	defer log.Debug().Msg("routine stopped")
+
+	for _, hash := range notFound.Hashes {
+
+		// mark the entity as missing for the respective peer
+		handler.peers.Missing(address, hash)
+
+		// fall back to a full download if this was a compact block
+		err := handler.compacts.NotFound(address, hash)
+		if err == nil {
+			continue
+		}
+
+		// reroute the pending download to another peer
+		err = handler.downloads.NotFound(address, hash)
+		if err != nil {
+			log.Error().Err(err).Hex("hash", hash[:]).Msg("could not reroute download")
+		}
+	}
+
+	log.Debug().Msg("processed not_found message")
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package message
+
+import (
+	"errors"
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProcessNotFound(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &NotFound{Hashes: []types.Hash{hash1, hash2}}
+
+	// initialize mocks
+	peers := &PeersMock{}
+	downloads := &DownloadsMock{}
+	compacts := &CompactsMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:       zerolog.New(ioutil.Discard),
+		peers:     peers,
+		downloads: downloads,
+		compacts:  compacts,
+	}
+
+	// program mocks
+	peers.On("Missing", mock.Anything, mock.Anything)
+	compacts.On("NotFound", mock.Anything, mock.Anything).Return(errors.New(""))
+	downloads.On("NotFound", mock.Anything, hash1).Return(errors.New(""))
+	downloads.On("NotFound", mock.Anything, hash2).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	if peers.AssertNumberOfCalls(t, "Missing", 2) {
+		peers.AssertCalled(t, "Missing", address, hash1)
+		peers.AssertCalled(t, "Missing", address, hash2)
+	}
+
+	if downloads.AssertNumberOfCalls(t, "NotFound", 2) {
+		downloads.AssertCalled(t, "NotFound", address, hash1)
+		downloads.AssertCalled(t, "NotFound", address, hash2)
+	}
+}
+
+func TestProcessNotFoundCompact(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	hash := types.Hash{0x1}
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &NotFound{Hashes: []types.Hash{hash}}
+
+	// initialize mocks
+	peers := &PeersMock{}
+	downloads := &DownloadsMock{}
+	compacts := &CompactsMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:       zerolog.New(ioutil.Discard),
+		peers:     peers,
+		downloads: downloads,
+		compacts:  compacts,
+	}
+
+	// program mocks
+	peers.On("Missing", mock.Anything, mock.Anything)
+	compacts.On("NotFound", mock.Anything, mock.Anything).Return(nil)
+	downloads.On("NotFound", mock.Anything, mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	peers.AssertCalled(t, "Missing", address, hash)
+
+	compacts.AssertCalled(t, "NotFound", address, hash)
+
+	downloads.AssertNotCalled(t, "NotFound", mock.Anything, mock.Anything)
+}
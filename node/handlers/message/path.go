@@ -19,12 +19,17 @@ package message
 
 import (
 	"sync"
+
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/rs/zerolog"
 )
 
-// The Path message is a reply to the Sync message, which contains the missing
-// block headers on the best path, as identified by the locator hashes. They
-// should be ordered by chronological order, from oldest to newest, in order
-// to allow the most efficient construction of the best path.
+// The Path message is a reply to the GetHeaders message, which contains the
+// missing block headers on the best path, as identified by the locator hashes.
+// They should be ordered by chronological order, from oldest to newest, in
+// order to allow the most efficient construction of the best path. A peer
+// sending us headers we did not ask for, more headers than we asked for, or
+// headers that do not connect to our locators and to each other, is dropped.
 func (handler *Handler) processPath(wg *sync.WaitGroup, address string, path *Path) {
 	defer wg.Done()
 
@@ -40,9 +45,88 @@ func (handler *Handler) processPath(wg *sync.WaitGroup, address string, path *Pa
 	log.Debug().Msg("routine started")
 	defer log.Debug().Msg("routine stopped")
 
+	// check that we actually requested headers from this peer
+	request, err := handler.requests.Finish(address)
+	if err != nil {
+		log.Error().Err(err).Msg("unsolicited path message")
+		handler.drop(log, address)
+		return
+	}
+
+	// check that we did not receive more headers than requested
+	if uint32(len(path.Headers)) > request.Max {
+		log.Error().Uint32("max", request.Max).Msg("too many headers in path")
+		handler.drop(log, address)
+		return
+	}
+
+	// if there are no headers, we are caught up with this peer
+	if len(path.Headers) == 0 {
+		log.Debug().Msg("caught up with peer")
+		return
+	}
+
+	// check that the headers connect to one of our locators and to each other
+	lookup := make(map[types.Hash]struct{})
+	for _, locator := range request.Locators {
+		lookup[locator] = struct{}{}
+	}
+	_, ok := lookup[path.Headers[0].Parent]
+	if !ok {
+		log.Error().Msg("path not connected to locators")
+		handler.drop(log, address)
+		return
+	}
+	for i := 1; i < len(path.Headers); i++ {
+		if path.Headers[i].Parent != path.Headers[i-1].Hash {
+			log.Error().Int("index", i).Msg("path not connected")
+			handler.drop(log, address)
+			return
+		}
+	}
+
 	for _, header := range path.Headers {
-		handler.entity.Process(wg, header)
+		handler.entity.Process(wg, address, header)
+	}
+
+	// if the page was not full, we are caught up with this peer
+	if uint32(len(path.Headers)) < request.Max {
+		log.Debug().Msg("processed path message")
+		return
+	}
+
+	// otherwise, continue from the last header we received; we keep sending the
+	// original locators, so that the peer can still find a shared header if it
+	// switched to another path in the meantime, but replace the header we
+	// continued from last time, so that the locators don't grow with each page
+	last := path.Headers[len(path.Headers)-1]
+	locators := request.Locators
+	if len(locators) > 1 && locators[0] == path.Headers[0].Parent {
+		locators = locators[1:]
+	}
+	next := &GetHeaders{
+		Locators: append([]types.Hash{last.Hash}, locators...),
+		Max:      request.Max,
+	}
+	err = handler.requests.Start(address, next.Locators, next.Max)
+	if err != nil {
+		log.Error().Err(err).Msg("could not start header request")
+		return
+	}
+	err = handler.net.Send(address, next)
+	if err != nil {
+		log.Error().Err(err).Msg("could not send header request")
+		_, _ = handler.requests.Finish(address)
+		return
 	}
 
 	log.Debug().Msg("processed path message")
 }
+
+// drop disconnects a misbehaving peer.
+func (handler *Handler) drop(log zerolog.Logger, address string) {
+	err := handler.net.Drop(address)
+	if err != nil {
+		log.Error().Err(err).Msg("could not drop peer")
+	}
+}
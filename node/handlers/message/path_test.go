@@ -18,9 +18,11 @@
 package message
 
 import (
+	"errors"
 	"sync"
 	"testing"
 
+	"github.com/alvalor/alvalor-go/node/state/requests"
 	"github.com/alvalor/alvalor-go/types"
 	"github.com/stretchr/testify/mock"
 )
@@ -29,22 +31,34 @@ func TestProcessPathSuccess(t *testing.T) {
 
 	// initialize parameters
 	address := "192.0.2.1"
+	hash0 := types.Hash{0x0, 0x1}
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
 
 	// initialize entities
 	wg := &sync.WaitGroup{}
-	header1 := &types.Header{Nonce: 1}
-	header2 := &types.Header{Nonce: 2}
+	header1 := &types.Header{Hash: hash1, Parent: hash0, Nonce: 1}
+	header2 := &types.Header{Hash: hash2, Parent: hash1, Nonce: 2}
 	msg := &Path{Headers: []*types.Header{header1, header2}}
+	request := &requests.Request{Locators: []types.Hash{hash0}, Max: 3}
 
 	// initialize mocks
+	net := &NetworkMock{}
+	requests := &RequestsMock{}
 	entity := &EntityMock{}
 
 	// initialize handler
 	handler := &Handler{
-		entity: entity,
+		net:      net,
+		requests: requests,
+		entity:   entity,
 	}
 
 	// program mocks
+	requests.On("Finish", mock.Anything).Return(request, nil)
+	requests.On("Start", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+	net.On("Drop", mock.Anything).Return(nil)
 	entity.On("Process", mock.Anything, mock.Anything)
 
 	// execute process
@@ -52,8 +66,240 @@ func TestProcessPathSuccess(t *testing.T) {
 	wg.Wait()
 
 	// check conditions
+	if requests.AssertNumberOfCalls(t, "Finish", 1) {
+		requests.AssertCalled(t, "Finish", address)
+	}
+
 	if entity.AssertNumberOfCalls(t, "Process", 2) {
-		entity.AssertCalled(t, "Process", wg, header1)
-		entity.AssertCalled(t, "Process", wg, header2)
+		entity.AssertCalled(t, "Process", address, header1)
+		entity.AssertCalled(t, "Process", address, header2)
+	}
+
+	requests.AssertNumberOfCalls(t, "Start", 0)
+
+	net.AssertNumberOfCalls(t, "Send", 0)
+
+	net.AssertNumberOfCalls(t, "Drop", 0)
+}
+
+func TestProcessPathContinue(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	hash0 := types.Hash{0x0, 0x1}
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+	root := types.Hash{0x0, 0x0, 0x1}
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	header1 := &types.Header{Hash: hash1, Parent: hash0, Nonce: 1}
+	header2 := &types.Header{Hash: hash2, Parent: hash1, Nonce: 2}
+	msg := &Path{Headers: []*types.Header{header1, header2}}
+	request := &requests.Request{Locators: []types.Hash{hash0, root}, Max: 2}
+	next := &GetHeaders{Locators: []types.Hash{hash2, root}, Max: 2}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	requests := &RequestsMock{}
+	entity := &EntityMock{}
+
+	// initialize handler
+	handler := &Handler{
+		net:      net,
+		requests: requests,
+		entity:   entity,
+	}
+
+	// program mocks
+	requests.On("Finish", mock.Anything).Return(request, nil)
+	requests.On("Start", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+	entity.On("Process", mock.Anything, mock.Anything)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	entity.AssertNumberOfCalls(t, "Process", 2)
+
+	if requests.AssertNumberOfCalls(t, "Start", 1) {
+		requests.AssertCalled(t, "Start", address, next.Locators, next.Max)
+	}
+
+	if net.AssertNumberOfCalls(t, "Send", 1) {
+		net.AssertCalled(t, "Send", address, next)
+	}
+}
+
+func TestProcessPathUnsolicited(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	header1 := &types.Header{Nonce: 1}
+	msg := &Path{Headers: []*types.Header{header1}}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	requests := &RequestsMock{}
+	entity := &EntityMock{}
+
+	// initialize handler
+	handler := &Handler{
+		net:      net,
+		requests: requests,
+		entity:   entity,
+	}
+
+	// program mocks
+	requests.On("Finish", mock.Anything).Return(nil, errors.New(""))
+	net.On("Drop", mock.Anything).Return(nil)
+	entity.On("Process", mock.Anything, mock.Anything)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	entity.AssertNumberOfCalls(t, "Process", 0)
+
+	if net.AssertNumberOfCalls(t, "Drop", 1) {
+		net.AssertCalled(t, "Drop", address)
+	}
+}
+
+func TestProcessPathTooMany(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	hash0 := types.Hash{0x0, 0x1}
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	header1 := &types.Header{Hash: hash1, Parent: hash0, Nonce: 1}
+	header2 := &types.Header{Hash: hash2, Parent: hash1, Nonce: 2}
+	msg := &Path{Headers: []*types.Header{header1, header2}}
+	request := &requests.Request{Locators: []types.Hash{hash0}, Max: 1}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	requests := &RequestsMock{}
+	entity := &EntityMock{}
+
+	// initialize handler
+	handler := &Handler{
+		net:      net,
+		requests: requests,
+		entity:   entity,
+	}
+
+	// program mocks
+	requests.On("Finish", mock.Anything).Return(request, nil)
+	net.On("Drop", mock.Anything).Return(nil)
+	entity.On("Process", mock.Anything, mock.Anything)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	entity.AssertNumberOfCalls(t, "Process", 0)
+
+	if net.AssertNumberOfCalls(t, "Drop", 1) {
+		net.AssertCalled(t, "Drop", address)
+	}
+}
+
+func TestProcessPathDisconnected(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	hash0 := types.Hash{0x0, 0x1}
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+	hash3 := types.Hash{0x3}
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	header1 := &types.Header{Hash: hash1, Parent: hash0, Nonce: 1}
+	header2 := &types.Header{Hash: hash2, Parent: hash3, Nonce: 2}
+	msg := &Path{Headers: []*types.Header{header1, header2}}
+	request := &requests.Request{Locators: []types.Hash{hash0}, Max: 10}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	requests := &RequestsMock{}
+	entity := &EntityMock{}
+
+	// initialize handler
+	handler := &Handler{
+		net:      net,
+		requests: requests,
+		entity:   entity,
+	}
+
+	// program mocks
+	requests.On("Finish", mock.Anything).Return(request, nil)
+	net.On("Drop", mock.Anything).Return(nil)
+	entity.On("Process", mock.Anything, mock.Anything)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	entity.AssertNumberOfCalls(t, "Process", 0)
+
+	if net.AssertNumberOfCalls(t, "Drop", 1) {
+		net.AssertCalled(t, "Drop", address)
+	}
+}
+
+func TestProcessPathUnknownFork(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	hash0 := types.Hash{0x0, 0x1}
+	hash1 := types.Hash{0x1}
+	hash3 := types.Hash{0x3}
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	header1 := &types.Header{Hash: hash1, Parent: hash3, Nonce: 1}
+	msg := &Path{Headers: []*types.Header{header1}}
+	request := &requests.Request{Locators: []types.Hash{hash0}, Max: 10}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	requests := &RequestsMock{}
+	entity := &EntityMock{}
+
+	// initialize handler
+	handler := &Handler{
+		net:      net,
+		requests: requests,
+		entity:   entity,
+	}
+
+	// program mocks
+	requests.On("Finish", mock.Anything).Return(request, nil)
+	net.On("Drop", mock.Anything).Return(nil)
+	entity.On("Process", mock.Anything, mock.Anything)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	entity.AssertNumberOfCalls(t, "Process", 0)
+
+	if net.AssertNumberOfCalls(t, "Drop", 1) {
+		net.AssertCalled(t, "Drop", address)
 	}
 }
@@ -22,4 +22,5 @@ import "github.com/alvalor/alvalor-go/types"
 // Peers represents the peer state interface, as needed by the message handler.
 type Peers interface {
 	Received(address string, hash types.Hash)
+	Missing(address string, hash types.Hash)
 }
@@ -31,3 +31,8 @@ type PeersMock struct {
 func (pm *PeersMock) Received(address string, hash types.Hash) {
 	pm.Called(address, hash)
 }
+
+// Missing mocks the missing function of the peer state interface.
+func (pm *PeersMock) Missing(address string, hash types.Hash) {
+	pm.Called(address, hash)
+}
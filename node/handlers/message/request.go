@@ -0,0 +1,117 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package message
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/alvalor/alvalor-go/node/repos/transactions"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// The Request is a message sent by peers who want to download a number of
+// transactions from us. We send the ones we have in batches that respect the
+// size limits and let them know about the ones we don't have.
+func (handler *Handler) processRequest(wg *sync.WaitGroup, address string, request *Request) {
+	defer wg.Done()
+
+	// configure logger
+	with := handler.log.With()
+	with.Str("component", "message")
+	with.Str("message_type", "request")
+	with.Str("address", address)
+	with.Int("num_hashes", len(request.Hashes))
+	log := with.Logger()
+
+	// wrap routine in start and stop messages
+	log.Debug().Msg("routine started")
+	defer log.Debug().Msg("routine stopped")
+
+	// collect the transactions we have and the ones we don't have
+	var txs []*types.Transaction
+	var missing []types.Hash
+	for _, hash := range request.Hashes {
+		tx, err := handler.transactions.Get(hash)
+		if errors.Cause(err) == transactions.ErrNotExist {
+			missing = append(missing, hash)
+			continue
+		}
+		if err != nil {
+			log.Error().Err(err).Hex("hash", hash[:]).Msg("could not get transaction")
+			return
+		}
+		txs = append(txs, tx)
+	}
+
+	// send the transactions in batches that don't exceed the limits
+	err := handler.sendBatches(address, txs)
+	if err != nil {
+		log.Error().Err(err).Msg("could not send batches")
+		return
+	}
+
+	// let the peer know which transactions we don't have
+	if len(missing) > 0 {
+		err = handler.net.Send(address, &NotFound{Hashes: missing})
+		if err != nil {
+			log.Error().Err(err).Msg("could not send not found")
+			return
+		}
+	}
+
+	log.Debug().Int("num_missing", len(missing)).Msg("processed request message")
+}
+
+// sendBatches sends the given transactions to the peer with the given address
+// in batches that don't exceed the count and size limits.
+func (handler *Handler) sendBatches(address string, txs []*types.Transaction) error {
+	for len(txs) > 0 {
+		n := 0
+		size := 0
+		for n < len(txs) && n < MaxBatchTxs {
+			size += approximate(txs[n])
+			if n > 0 && size > MaxBatchSize {
+				break
+			}
+			n++
+		}
+		err := handler.net.Send(address, &Batch{Transactions: txs[:n]})
+		if err != nil {
+			return errors.Wrap(err, "could not send batch")
+		}
+		txs = txs[n:]
+	}
+	return nil
+}
+
+// approximate returns the approximate encoded size of a transaction in bytes.
+func approximate(tx *types.Transaction) int {
+	size := len(tx.Hash) + len(tx.Data) + 64
+	for _, transfer := range tx.Transfers {
+		size += len(transfer.From) + len(transfer.To) + 16
+	}
+	for _, fee := range tx.Fees {
+		size += len(fee.From) + 16
+	}
+	for _, signature := range tx.Signatures {
+		size += len(signature) + 8
+	}
+	return size
+}
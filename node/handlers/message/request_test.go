@@ -0,0 +1,151 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package message
+
+import (
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/alvalor/alvalor-go/node/repos/transactions"
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProcessRequestSuccess(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+	hash3 := types.Hash{0x3}
+	missing := transactions.ErrNotExist
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &Request{Hashes: []types.Hash{hash1, hash2, hash3}}
+	tx1 := &types.Transaction{Hash: hash1, Data: make([]byte, MaxBatchSize/2)}
+	tx2 := &types.Transaction{Hash: hash2, Data: make([]byte, MaxBatchSize/2)}
+	batch1 := &Batch{Transactions: []*types.Transaction{tx1}}
+	batch2 := &Batch{Transactions: []*types.Transaction{tx2}}
+	notFound := &NotFound{Hashes: []types.Hash{hash3}}
+
+	// initialize mocks
+	transactions := &TransactionsMock{}
+	net := &NetworkMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:          zerolog.New(ioutil.Discard),
+		transactions: transactions,
+		net:          net,
+	}
+
+	// program mocks
+	transactions.On("Get", hash1).Return(tx1, nil)
+	transactions.On("Get", hash2).Return(tx2, nil)
+	transactions.On("Get", hash3).Return(nil, errors.Wrap(missing, "could not find"))
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	transactions.AssertNumberOfCalls(t, "Get", 3)
+
+	if net.AssertNumberOfCalls(t, "Send", 3) {
+		net.AssertCalled(t, "Send", address, batch1)
+		net.AssertCalled(t, "Send", address, batch2)
+		net.AssertCalled(t, "Send", address, notFound)
+	}
+}
+
+func TestProcessRequestMaxTxs(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &Request{}
+	for i := 0; i < MaxBatchTxs+1; i++ {
+		msg.Hashes = append(msg.Hashes, types.Hash{byte(i >> 8), byte(i)})
+	}
+
+	// initialize mocks
+	transactions := &TransactionsMock{}
+	net := &NetworkMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:          zerolog.New(ioutil.Discard),
+		transactions: transactions,
+		net:          net,
+	}
+
+	// program mocks
+	transactions.On("Get", mock.Anything).Return(&types.Transaction{}, nil)
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	if net.AssertNumberOfCalls(t, "Send", 2) {
+		assert.Len(t, net.Calls[0].Arguments.Get(1).(*Batch).Transactions, MaxBatchTxs)
+		assert.Len(t, net.Calls[1].Arguments.Get(1).(*Batch).Transactions, 1)
+	}
+}
+
+func TestProcessRequestGetFails(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	hash := types.Hash{0x1}
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &Request{Hashes: []types.Hash{hash}}
+
+	// initialize mocks
+	transactions := &TransactionsMock{}
+	net := &NetworkMock{}
+
+	// initialize handler
+	handler := &Handler{
+		log:          zerolog.New(ioutil.Discard),
+		transactions: transactions,
+		net:          net,
+	}
+
+	// program mocks
+	transactions.On("Get", mock.Anything).Return(nil, errors.New(""))
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	net.AssertNumberOfCalls(t, "Send", 0)
+}
@@ -19,8 +19,6 @@ package message
 
 import (
 	"sync"
-
-	"github.com/alvalor/alvalor-go/types"
 )
 
 func (handler *Handler) processStatus(wg *sync.WaitGroup, address string, status *Status) {
@@ -31,6 +29,8 @@ func (handler *Handler) processStatus(wg *sync.WaitGroup, address string, status
 	with.Str("component", "message")
 	with.Str("message_type", "status")
 	with.Str("address", address)
+	with.Hex("hash", status.Hash[:])
+	with.Uint64("height", status.Height)
 	with.Uint64("distance", status.Distance)
 	log := with.Logger()
 
@@ -38,42 +38,49 @@ func (handler *Handler) processStatus(wg *sync.WaitGroup, address string, status
 	log.Debug().Msg("routine started")
 	defer log.Debug().Msg("routine stopped")
 
-	// The Status message is a handshake sent by both peers on a new connection.
-	// It contains the distance of their best path and helps each peer to
-	// determine whether they should request missing headers from the other. If a
-	// peer is behind, it should send a Sync message with a number of locator
-	// hashes of block headers, to request the missing headers from the peer who
-	// is ahead.
+	// The Status message is a handshake sent by both peers on a new connection,
+	// and again whenever the best path of a peer changes. It contains the tip and
+	// distance of their best path and helps each peer to determine whether they
+	// should request missing headers from the other. If a peer is behind, it
+	// should send a GetHeaders message with a number of locator hashes of block
+	// headers, to request the missing headers from the peer who is ahead. As
+	// every peer who is ahead receives its own request, headers are downloaded
+	// from multiple peers in parallel.
+
+	// peers on a different genesis will never share a path with us; our
+	// locators always end with the root of our best path
+	locators, distance := handler.headers.Locators()
+	if len(locators) > 0 && status.Genesis != locators[len(locators)-1] {
+		log.Warn().Hex("genesis", status.Genesis[:]).Msg("peer on different genesis")
+		handler.drop(log, address)
+		return
+	}
+
+	// remember how far the peer is, so we know how far we are behind
+	handler.tracker.Peer(address, status.Distance)
 
 	// if we are on a better path, we can ignore the status message
-	path, distance := handler.headers.Path()
 	if distance >= status.Distance {
 		log.Debug().Msg("not behind peer")
 		return
 	}
 
-	// collect headers from the top of our longest path backwards
-	// use increasing distance after first 8, finish with root (genesis)
-	// TODO: move out and do separate testing for this algorithm
-	var locators []types.Hash
-	index := 0
-	step := 1
-	for index < len(path)-1 {
-		locators = append(locators, path[index])
-		if len(locators) >= 8 {
-			step *= 2
-		}
-		index += step
-	}
-	locators = append(locators, path[len(path)-1])
-
-	// send synchronization message
-	sync := &Sync{
+	// mark the header request as pending, unless we already wait for one
+	request := &GetHeaders{
 		Locators: locators,
+		Max:      MaxHeaders,
 	}
-	err := handler.net.Send(address, sync)
+	err := handler.requests.Start(address, request.Locators, request.Max)
+	if err != nil {
+		log.Debug().Err(err).Msg("could not start header request")
+		return
+	}
+
+	// send header request message
+	err = handler.net.Send(address, request)
 	if err != nil {
-		log.Error().Err(err).Msg("could not send sync message")
+		log.Error().Err(err).Msg("could not send header request")
+		_, _ = handler.requests.Finish(address)
 		return
 	}
 
@@ -37,22 +37,29 @@ func TestProcessStatusSuccess(t *testing.T) {
 
 	// initialize entities
 	wg := &sync.WaitGroup{}
-	msg := &Status{Distance: uint64(distance2)}
-	path := []types.Hash{hash1, hash2}
-	sync := &Sync{Locators: []types.Hash{hash1, hash2}}
+	msg := &Status{Distance: uint64(distance2), Genesis: hash2}
+	locators := []types.Hash{hash1, hash2}
+	request := &GetHeaders{Locators: locators, Max: MaxHeaders}
 
 	// initialize mocks
 	headers := &HeadersMock{}
 	net := &NetworkMock{}
+	requests := &RequestsMock{}
+	tracker := &TrackerMock{}
 
 	// initialize handler
 	handler := &Handler{
-		headers: headers,
-		net:     net,
+		headers:  headers,
+		net:      net,
+		requests: requests,
+		tracker:  tracker,
 	}
 
 	// program mocks
-	headers.On("Path").Return(path, distance1)
+	headers.On("Locators").Return(locators, distance1)
+	tracker.On("Peer", mock.Anything, mock.Anything)
+	requests.On("Start", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	requests.On("Finish", mock.Anything).Return(nil, nil)
 	net.On("Send", mock.Anything, mock.Anything).Return(nil)
 
 	// execute process
@@ -60,11 +67,21 @@ func TestProcessStatusSuccess(t *testing.T) {
 	wg.Wait()
 
 	// check conditions
-	headers.AssertNumberOfCalls(t, "Path", 1)
+	headers.AssertNumberOfCalls(t, "Locators", 1)
+
+	if tracker.AssertNumberOfCalls(t, "Peer", 1) {
+		tracker.AssertCalled(t, "Peer", address, uint64(distance2))
+	}
+
+	if requests.AssertNumberOfCalls(t, "Start", 1) {
+		requests.AssertCalled(t, "Start", address, request.Locators, request.Max)
+	}
 
 	if net.AssertNumberOfCalls(t, "Send", 1) {
-		net.AssertCalled(t, "Send", address, sync)
+		net.AssertCalled(t, "Send", address, request)
 	}
+
+	requests.AssertNumberOfCalls(t, "Finish", 0)
 }
 
 func TestProcessStatusBehind(t *testing.T) {
@@ -78,21 +95,28 @@ func TestProcessStatusBehind(t *testing.T) {
 
 	// initialize entities
 	wg := &sync.WaitGroup{}
-	msg := &Status{Distance: uint64(distance2)}
-	path := []types.Hash{hash1, hash2}
+	msg := &Status{Distance: uint64(distance2), Genesis: hash2}
+	locators := []types.Hash{hash1, hash2}
 
 	// initialize mocks
 	headers := &HeadersMock{}
 	net := &NetworkMock{}
+	requests := &RequestsMock{}
+	tracker := &TrackerMock{}
 
 	// initialize handler
 	handler := &Handler{
-		headers: headers,
-		net:     net,
+		headers:  headers,
+		net:      net,
+		requests: requests,
+		tracker:  tracker,
 	}
 
 	// program mocks
-	headers.On("Path").Return(path, distance1)
+	headers.On("Locators").Return(locators, distance1)
+	tracker.On("Peer", mock.Anything, mock.Anything)
+	requests.On("Start", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	requests.On("Finish", mock.Anything).Return(nil, nil)
 	net.On("Send", mock.Anything, mock.Anything).Return(nil)
 
 	// execute process
@@ -100,7 +124,7 @@ func TestProcessStatusBehind(t *testing.T) {
 	wg.Wait()
 
 	// check conditions
-	headers.AssertNumberOfCalls(t, "Path", 1)
+	headers.AssertNumberOfCalls(t, "Locators", 1)
 
 	net.AssertNumberOfCalls(t, "Send", 0)
 }
@@ -116,22 +140,29 @@ func TestProcessStatusSendFails(t *testing.T) {
 
 	// initialize entities
 	wg := &sync.WaitGroup{}
-	msg := &Status{Distance: uint64(distance2)}
-	path := []types.Hash{hash1, hash2}
-	sync := &Sync{Locators: []types.Hash{hash1, hash2}}
+	msg := &Status{Distance: uint64(distance2), Genesis: hash2}
+	locators := []types.Hash{hash1, hash2}
+	request := &GetHeaders{Locators: locators, Max: MaxHeaders}
 
 	// initialize mocks
 	headers := &HeadersMock{}
 	net := &NetworkMock{}
+	requests := &RequestsMock{}
+	tracker := &TrackerMock{}
 
 	// initialize handler
 	handler := &Handler{
-		headers: headers,
-		net:     net,
+		headers:  headers,
+		net:      net,
+		requests: requests,
+		tracker:  tracker,
 	}
 
 	// program mocks
-	headers.On("Path").Return(path, distance1)
+	headers.On("Locators").Return(locators, distance1)
+	tracker.On("Peer", mock.Anything, mock.Anything)
+	requests.On("Start", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	requests.On("Finish", mock.Anything).Return(nil, nil)
 	net.On("Send", mock.Anything, mock.Anything).Return(errors.New(""))
 
 	// execute process
@@ -139,9 +170,106 @@ func TestProcessStatusSendFails(t *testing.T) {
 	wg.Wait()
 
 	// check conditions
-	headers.AssertNumberOfCalls(t, "Path", 1)
+	headers.AssertNumberOfCalls(t, "Locators", 1)
 
 	if net.AssertNumberOfCalls(t, "Send", 1) {
-		net.AssertCalled(t, "Send", address, sync)
+		net.AssertCalled(t, "Send", address, request)
+	}
+
+	if requests.AssertNumberOfCalls(t, "Finish", 1) {
+		requests.AssertCalled(t, "Finish", address)
+	}
+}
+
+func TestProcessStatusPending(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	distance1 := 10
+	distance2 := 20
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &Status{Distance: uint64(distance2), Genesis: hash2}
+	locators := []types.Hash{hash1, hash2}
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	net := &NetworkMock{}
+	requests := &RequestsMock{}
+	tracker := &TrackerMock{}
+
+	// initialize handler
+	handler := &Handler{
+		headers:  headers,
+		net:      net,
+		requests: requests,
+		tracker:  tracker,
+	}
+
+	// program mocks
+	headers.On("Locators").Return(locators, distance1)
+	tracker.On("Peer", mock.Anything, mock.Anything)
+	requests.On("Start", mock.Anything, mock.Anything, mock.Anything).Return(errors.New(""))
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	requests.AssertNumberOfCalls(t, "Start", 1)
+
+	net.AssertNumberOfCalls(t, "Send", 0)
+}
+
+func TestProcessStatusGenesis(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	distance1 := 10
+	distance2 := 20
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+	hash3 := types.Hash{0x3}
+
+	// initialize entities
+	wg := &sync.WaitGroup{}
+	msg := &Status{Distance: uint64(distance2), Genesis: hash3}
+	locators := []types.Hash{hash1, hash2}
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	net := &NetworkMock{}
+	requests := &RequestsMock{}
+	tracker := &TrackerMock{}
+
+	// initialize handler
+	handler := &Handler{
+		headers:  headers,
+		net:      net,
+		requests: requests,
+		tracker:  tracker,
 	}
+
+	// program mocks
+	headers.On("Locators").Return(locators, distance1)
+	net.On("Drop", mock.Anything).Return(nil)
+
+	// execute process
+	handler.Process(wg, address, msg)
+	wg.Wait()
+
+	// check conditions
+	if net.AssertNumberOfCalls(t, "Drop", 1) {
+		net.AssertCalled(t, "Drop", address)
+	}
+
+	net.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+
+	tracker.AssertNotCalled(t, "Peer", mock.Anything, mock.Anything)
+
+	requests.AssertNotCalled(t, "Start", mock.Anything, mock.Anything, mock.Anything)
 }
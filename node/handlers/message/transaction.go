@@ -39,14 +39,14 @@ func (handler *Handler) processTransaction(wg *sync.WaitGroup, address string, t
 	log.Debug().Msg("routine started")
 	defer log.Debug().Msg("routine stopped")
 
-	// cancel any pending download retries for this transaction
-	handler.downloads.Cancel(tx.Hash)
+	// mark any pending download of this transaction as delivered
+	handler.downloads.Received(address, tx.Hash)
 
 	// mark the inventory download as completed for the respective peer
 	handler.peers.Received(address, tx.Hash)
 
 	// handle the transaction entity
-	handler.entity.Process(wg, tx)
+	handler.entity.Process(wg, address, tx)
 
 	log.Debug().Msg("processed transaction message")
 }
@@ -48,7 +48,7 @@ func TestProcessTransactionSuccess(t *testing.T) {
 	}
 
 	// program mocks
-	downloads.On("Cancel", mock.Anything)
+	downloads.On("Received", mock.Anything, mock.Anything)
 	peers.On("Received", mock.Anything, mock.Anything)
 	entity.On("Process", mock.Anything, mock.Anything)
 
@@ -57,8 +57,8 @@ func TestProcessTransactionSuccess(t *testing.T) {
 	wg.Wait()
 
 	// check conditions
-	if downloads.AssertNumberOfCalls(t, "Cancel", 1) {
-		downloads.AssertCalled(t, "Cancel", hash)
+	if downloads.AssertNumberOfCalls(t, "Received", 1) {
+		downloads.AssertCalled(t, "Received", address, hash)
 	}
 
 	if peers.AssertNumberOfCalls(t, "Received", 1) {
@@ -66,6 +66,6 @@ func TestProcessTransactionSuccess(t *testing.T) {
 	}
 
 	if entity.AssertNumberOfCalls(t, "Process", 1) {
-		entity.AssertCalled(t, "Process", wg, msg)
+		entity.AssertCalled(t, "Process", address, msg)
 	}
 }
@@ -22,5 +22,6 @@ import "github.com/alvalor/alvalor-go/types"
 // Transactions represents the transaction repository interface, as needed by
 // the message handler.
 type Transactions interface {
+	Has(hash types.Hash) bool
 	Get(hash types.Hash) (*types.Transaction, error)
 }
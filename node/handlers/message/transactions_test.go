@@ -27,7 +27,13 @@ type TransactionsMock struct {
 	mock.Mock
 }
 
-// Has mocks the get function of the transactions repository interface.
+// Has mocks the has function of the transactions repository interface.
+func (tm *TransactionsMock) Has(hash types.Hash) bool {
+	args := tm.Called(hash)
+	return args.Bool(0)
+}
+
+// Get mocks the get function of the transactions repository interface.
 func (tm *TransactionsMock) Get(hash types.Hash) (*types.Transaction, error) {
 	args := tm.Called(hash)
 	var tx *types.Transaction
@@ -17,19 +17,34 @@
 
 package message
 
-import "github.com/alvalor/alvalor-go/types"
+import (
+	"github.com/willf/bloom"
 
-// Status message shares our current best distance and locator hashes for our best path.
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// Status message shares the tip of our best path, with its height and
+// cumulative difficulty, as well as the genesis hash it starts from. It is sent
+// when a connection is established and again whenever our best path changes.
 type Status struct {
+	Hash     types.Hash
+	Height   uint64
 	Distance uint64
+	Genesis  types.Hash
 }
 
-// Sync message shares locator hashes from our current best path.
-type Sync struct {
+// GetHeaders message requests the headers following the first locator hash
+// that is on the best path of the receiving peer. The response is capped at
+// the given maximum number of headers and ends early at the stop hash, unless
+// it is the zero hash.
+type GetHeaders struct {
 	Locators []types.Hash
+	Stop     types.Hash
+	Max      uint32
 }
 
-// Path message shares a partial path from to our best header.
+// Path message shares a partial path towards our best header, in response to a
+// GetHeaders message.
 type Path struct {
 	Headers []*types.Header
 }
@@ -43,3 +58,56 @@ type GetInv struct {
 type GetTx struct {
 	Hash types.Hash
 }
+
+// Request is a download request for a batch of transactions.
+type Request struct {
+	Hashes []types.Hash
+}
+
+// Batch message shares a batch of transactions, in response to a Request
+// message. Transactions we don't have are omitted and listed in a separate
+// NotFound message.
+type Batch struct {
+	Transactions []*types.Transaction
+}
+
+// NotFound message tells a peer that we don't have the requested entities, in
+// response to a GetInv or GetTx message.
+type NotFound struct {
+	Hashes []types.Hash
+}
+
+// Mempool message shares a bloom filter of the hashes of our pending
+// transactions, so that the receiving peer can send us the ones we are missing.
+type Mempool struct {
+	Bloom *bloom.BloomFilter
+}
+
+// Announce message shares the hashes of transactions we recently received, so
+// that the receiving peer can request the ones it doesn't have yet.
+type Announce struct {
+	Hashes []types.Hash
+}
+
+// Compact message shares a new block as its header and the short IDs of its
+// transactions, which are salted with the nonce and truncated, so that peers
+// can reconstruct the block from their memory pool.
+type Compact struct {
+	Header   *types.Header
+	Nonce    uint64
+	ShortIDs []uint64
+}
+
+// GetBlockTxs is a download request for the transactions at the given indexes
+// of a block, in response to a Compact message we could not fully reconstruct.
+type GetBlockTxs struct {
+	Hash    types.Hash
+	Indexes []uint32
+}
+
+// BlockTxs message shares the transactions of a block, in response to a
+// GetBlockTxs message, in the order of the requested indexes.
+type BlockTxs struct {
+	Hash         types.Hash
+	Transactions []*types.Transaction
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package workers
+
+// Policy describes what happens when a task is submitted to a full queue.
+type Policy uint8
+
+// List of possible policies for full queues.
+const (
+	PolicyDrop Policy = iota
+	PolicyBlock
+)
+
+// Config represents the parameters of a worker pool.
+type Config struct {
+	workers  uint
+	maxQueue uint
+	maxTotal uint
+	policy   Policy
+}
+
+// DefaultConfig returns the default parameters of a worker pool.
+func DefaultConfig() Config {
+	return Config{
+		workers:  8,
+		maxQueue: 256,
+		maxTotal: 4096,
+		policy:   PolicyDrop,
+	}
+}
+
+// SetWorkers allows us to configure the number of tasks that are processed
+// concurrently.
+func SetWorkers(workers uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.workers = workers
+	}
+}
+
+// SetMaxQueue allows us to configure the maximum number of queued tasks per
+// peer, so that a single peer can't fill up the pool.
+func SetMaxQueue(maxQueue uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxQueue = maxQueue
+	}
+}
+
+// SetMaxTotal allows us to configure the maximum number of queued tasks over
+// all peers. Unlike a full queue of a single peer, reaching it is not the fault
+// of the peer that submits the next task.
+func SetMaxTotal(maxTotal uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxTotal = maxTotal
+	}
+}
+
+// SetPolicy allows us to configure whether tasks submitted to a full queue are
+// rejected or whether the submission blocks until there is space.
+func SetPolicy(policy Policy) func(*Config) {
+	return func(cfg *Config) {
+		cfg.policy = policy
+	}
+}
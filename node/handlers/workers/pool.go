@@ -0,0 +1,173 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package workers
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Pool is a bounded pool of workers that processes the tasks submitted for our
+// peers. Each peer has its own queue; the workers serve the queues in
+// round-robin order, so that a busy peer can't starve the others, and never
+// run two tasks of the same peer at once, so that the tasks of each peer are
+// processed in the order they were submitted.
+type Pool struct {
+	mutex   sync.Mutex
+	work    *sync.Cond
+	space   *sync.Cond
+	cfg     Config
+	queues  map[string][]func()
+	busy    map[string]bool
+	ring    []string
+	total   uint
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// NewPool creates a new worker pool and starts its workers.
+func NewPool(options ...func(*Config)) *Pool {
+	cfg := DefaultConfig()
+	for _, option := range options {
+		option(&cfg)
+	}
+	pool := &Pool{
+		cfg:    cfg,
+		queues: make(map[string][]func()),
+		busy:   make(map[string]bool),
+	}
+	pool.work = sync.NewCond(&pool.mutex)
+	pool.space = sync.NewCond(&pool.mutex)
+	for i := uint(0); i < cfg.workers; i++ {
+		pool.wg.Add(1)
+		go pool.run()
+	}
+	return pool
+}
+
+// Submit queues a task for the peer with the given address. If the queue of the
+// peer or the pool as a whole is full, the task is either rejected or the call
+// blocks until there is space, depending on the configured policy. Rejections
+// because of the queue of the peer fail with ErrFull, while rejections because
+// of the pool as a whole fail with ErrBusy.
+func (pool *Pool) Submit(address string, task func()) error {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	// wait for space in the queue, unless we should drop the task
+	for {
+		if pool.stopped {
+			return errors.Wrap(ErrStopped, "could not submit task")
+		}
+		full := uint(len(pool.queues[address])) >= pool.cfg.maxQueue
+		busy := pool.total >= pool.cfg.maxTotal
+		if !full && !busy {
+			break
+		}
+		if pool.cfg.policy == PolicyDrop && full {
+			return errors.Wrapf(ErrFull, "could not submit task (%s)", address)
+		}
+		if pool.cfg.policy == PolicyDrop {
+			return errors.Wrapf(ErrBusy, "could not submit task (%s)", address)
+		}
+		pool.space.Wait()
+	}
+
+	// queue the task and wake up a worker
+	if len(pool.queues[address]) == 0 {
+		pool.ring = append(pool.ring, address)
+	}
+	pool.queues[address] = append(pool.queues[address], task)
+	pool.total++
+	pool.work.Signal()
+
+	return nil
+}
+
+// Stop stops accepting new tasks and waits until the workers have processed
+// all queued tasks.
+func (pool *Pool) Stop() {
+	pool.mutex.Lock()
+	pool.stopped = true
+	pool.work.Broadcast()
+	pool.space.Broadcast()
+	pool.mutex.Unlock()
+	pool.wg.Wait()
+}
+
+// run is the loop of a single worker.
+func (pool *Pool) run() {
+	defer pool.wg.Done()
+	for {
+		address, task, ok := pool.next()
+		if !ok {
+			return
+		}
+		task()
+		pool.mutex.Lock()
+		delete(pool.busy, address)
+		if len(pool.queues[address]) > 0 {
+			pool.work.Signal()
+		}
+		pool.mutex.Unlock()
+	}
+}
+
+// next waits for the next task of a peer that is not busy and takes it from
+// its queue. It returns false once the pool is stopped and drained.
+func (pool *Pool) next() (string, func(), bool) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	for {
+
+		// find the first peer in the ring that is not busy
+		for i, address := range pool.ring {
+			if pool.busy[address] {
+				continue
+			}
+
+			// take its first task and move it to the end of the ring
+			queue := pool.queues[address]
+			task := queue[0]
+			queue = queue[1:]
+			pool.ring = append(pool.ring[:i], pool.ring[i+1:]...)
+			if len(queue) > 0 {
+				pool.queues[address] = queue
+				pool.ring = append(pool.ring, address)
+			} else {
+				delete(pool.queues, address)
+			}
+			pool.busy[address] = true
+			pool.total--
+			pool.space.Broadcast()
+
+			return address, task, true
+		}
+
+		// once stopped, exit when there is no more work and wake up the other
+		// workers so they can exit as well
+		if pool.stopped && pool.total == 0 {
+			pool.work.Broadcast()
+			return "", nil, false
+		}
+
+		pool.work.Wait()
+	}
+}
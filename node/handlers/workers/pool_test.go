@@ -0,0 +1,241 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package workers
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoolOrderPerPeer(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize pool
+	pool := NewPool(SetWorkers(4))
+
+	// submit tasks for the same peer
+	var mutex sync.Mutex
+	var order []int
+	for i := 0; i < 100; i++ {
+		i := i
+		err := pool.Submit(address, func() {
+			mutex.Lock()
+			order = append(order, i)
+			mutex.Unlock()
+		})
+		assert.Nil(t, err)
+	}
+	pool.Stop()
+
+	// check conditions
+	if assert.Len(t, order, 100) {
+		for i, n := range order {
+			assert.Equal(t, i, n)
+		}
+	}
+}
+
+func TestPoolMaxWorkers(t *testing.T) {
+
+	// initialize pool
+	pool := NewPool(SetWorkers(2))
+
+	// submit slow tasks for different peers
+	var mutex sync.Mutex
+	var running, max int
+	addresses := []string{"192.0.2.1", "192.0.2.2", "192.0.2.3", "192.0.2.4"}
+	for _, address := range addresses {
+		err := pool.Submit(address, func() {
+			mutex.Lock()
+			running++
+			if running > max {
+				max = running
+			}
+			mutex.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+		})
+		assert.Nil(t, err)
+	}
+	pool.Stop()
+
+	// check conditions
+	assert.Equal(t, 2, max)
+}
+
+func TestPoolFairness(t *testing.T) {
+
+	// initialize parameters
+	address1 := "192.0.2.1"
+	address2 := "192.0.2.2"
+
+	// initialize pool and block its only worker
+	pool := NewPool(SetWorkers(1))
+	block := make(chan struct{})
+	err := pool.Submit(address1, func() { <-block })
+	assert.Nil(t, err)
+	time.Sleep(10 * time.Millisecond)
+
+	// flood the pool with tasks from one peer, then submit one from another
+	var mutex sync.Mutex
+	var order []string
+	record := func(address string) func() {
+		return func() {
+			mutex.Lock()
+			order = append(order, address)
+			mutex.Unlock()
+		}
+	}
+	for i := 0; i < 10; i++ {
+		err = pool.Submit(address1, record(address1))
+		assert.Nil(t, err)
+	}
+	err = pool.Submit(address2, record(address2))
+	assert.Nil(t, err)
+	close(block)
+	pool.Stop()
+
+	// check conditions
+	if assert.Len(t, order, 11) {
+		assert.Equal(t, address1, order[0])
+		assert.Equal(t, address2, order[1])
+	}
+}
+
+func TestPoolDropPolicy(t *testing.T) {
+
+	// initialize parameters
+	address1 := "192.0.2.1"
+	address2 := "192.0.2.2"
+
+	// initialize pool and block its only worker
+	pool := NewPool(SetWorkers(1), SetMaxQueue(2), SetPolicy(PolicyDrop))
+	block := make(chan struct{})
+	err := pool.Submit(address2, func() { <-block })
+	assert.Nil(t, err)
+	time.Sleep(10 * time.Millisecond)
+
+	// fill up the queue of the peer
+	err1 := pool.Submit(address1, func() {})
+	err2 := pool.Submit(address1, func() {})
+	err3 := pool.Submit(address1, func() {})
+	err4 := pool.Submit(address2, func() {})
+	close(block)
+	pool.Stop()
+
+	// check conditions
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Equal(t, ErrFull, errors.Cause(err3))
+	assert.Nil(t, err4)
+}
+
+func TestPoolMaxTotal(t *testing.T) {
+
+	// initialize parameters
+	address1 := "192.0.2.1"
+	address2 := "192.0.2.2"
+
+	// initialize pool and block its only worker
+	pool := NewPool(SetWorkers(1), SetMaxTotal(1), SetPolicy(PolicyDrop))
+	block := make(chan struct{})
+	err := pool.Submit(address1, func() { <-block })
+	assert.Nil(t, err)
+	time.Sleep(10 * time.Millisecond)
+
+	// fill up the pool
+	err1 := pool.Submit(address1, func() {})
+	err2 := pool.Submit(address2, func() {})
+	close(block)
+	pool.Stop()
+
+	// check conditions
+	assert.Nil(t, err1)
+	assert.Equal(t, ErrBusy, errors.Cause(err2))
+}
+
+func TestPoolBlockPolicy(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize pool and block its only worker
+	pool := NewPool(SetWorkers(1), SetMaxQueue(1), SetPolicy(PolicyBlock))
+	block := make(chan struct{})
+	err := pool.Submit(address, func() { <-block })
+	assert.Nil(t, err)
+	time.Sleep(10 * time.Millisecond)
+	err = pool.Submit(address, func() {})
+	assert.Nil(t, err)
+
+	// submit another task, which has to wait for space
+	done := make(chan error)
+	go func() {
+		done <- pool.Submit(address, func() {})
+	}()
+	select {
+	case <-done:
+		t.Fatal("submit did not block on full queue")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(block)
+
+	// check conditions
+	select {
+	case err = <-done:
+		assert.Nil(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("submit did not unblock")
+	}
+	pool.Stop()
+}
+
+func TestPoolStop(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize pool
+	pool := NewPool(SetWorkers(2))
+
+	// submit tasks and stop the pool
+	var mutex sync.Mutex
+	count := 0
+	for i := 0; i < 10; i++ {
+		err := pool.Submit(address, func() {
+			mutex.Lock()
+			count++
+			mutex.Unlock()
+		})
+		assert.Nil(t, err)
+	}
+	pool.Stop()
+	err := pool.Submit(address, func() {})
+
+	// check conditions
+	assert.Equal(t, 10, count)
+	assert.Equal(t, ErrStopped, errors.Cause(err))
+}
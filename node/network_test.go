@@ -0,0 +1,48 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import "github.com/stretchr/testify/mock"
+
+// NetworkMock mocks the network interface.
+type NetworkMock struct {
+	mock.Mock
+}
+
+// Send mocks the send functionality.
+func (nm *NetworkMock) Send(address string, msg interface{}) error {
+	args := nm.Called(address, msg)
+	return args.Error(0)
+}
+
+// Broadcast mocks the broadcast functionality.
+func (nm *NetworkMock) Broadcast(msg interface{}, exclude ...string) error {
+	args := nm.Called(msg, exclude)
+	return args.Error(0)
+}
+
+// Drop mocks the drop functionality.
+func (nm *NetworkMock) Drop(address string) error {
+	args := nm.Called(address)
+	return args.Error(0)
+}
+
+// Penalize mocks the penalize functionality.
+func (nm *NetworkMock) Penalize(address string) {
+	nm.Called(address)
+}
@@ -19,6 +19,36 @@ package node
 
 import (
 	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+
+	"github.com/alvalor/alvalor-go/node/handlers/entity"
+	"github.com/alvalor/alvalor-go/node/handlers/event"
+	"github.com/alvalor/alvalor-go/node/handlers/limits"
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+	"github.com/alvalor/alvalor-go/node/handlers/workers"
+	"github.com/alvalor/alvalor-go/node/repos/headers"
+	"github.com/alvalor/alvalor-go/node/repos/inventories"
+	"github.com/alvalor/alvalor-go/node/repos/transactions"
+	"github.com/alvalor/alvalor-go/node/state/accounts"
+	"github.com/alvalor/alvalor-go/node/state/orphans"
+	"github.com/alvalor/alvalor-go/node/state/path"
+	"github.com/alvalor/alvalor-go/node/state/peers"
+	"github.com/alvalor/alvalor-go/node/state/requests"
+	"github.com/alvalor/alvalor-go/node/state/subscribers"
+	"github.com/alvalor/alvalor-go/node/sync/assembly"
+	"github.com/alvalor/alvalor-go/node/sync/compact"
+	"github.com/alvalor/alvalor-go/node/sync/download"
+	"github.com/alvalor/alvalor-go/node/sync/orchestration"
+	"github.com/alvalor/alvalor-go/node/sync/progress"
+	"github.com/alvalor/alvalor-go/node/sync/reconcile"
+	"github.com/alvalor/alvalor-go/node/sync/relay"
+	"github.com/alvalor/alvalor-go/node/sync/reorg"
+	"github.com/alvalor/alvalor-go/node/sync/status"
+	"github.com/alvalor/alvalor-go/node/validation"
+	"github.com/alvalor/alvalor-go/types"
 )
 
 // EventHandler represents a handler to process events. We could use a function, but
@@ -36,3 +66,263 @@ func Run(wg *sync.WaitGroup, events <-chan interface{}, handler EventHandler) {
 		handler.Process(wg, event)
 	}
 }
+
+// Node wires together the handlers, repositories, state and synchronization
+// components of a blockchain node. It processes the events of the network
+// layer on the input channel and runs the periodic maintenance of its
+// components until it is stopped.
+type Node struct {
+	log          zerolog.Logger
+	wg           *sync.WaitGroup
+	cfg          Config
+	input        <-chan interface{}
+	stop         chan struct{}
+	loops        sync.WaitGroup
+	headers      *headers.Persistent
+	inventories  *inventories.Repo
+	transactions *transactions.Repo
+	peers        *peers.State
+	requests     *requests.State
+	orphans      *orphans.Pool
+	events       *subscribers.Manager
+	download     *download.Manager
+	collector    *orchestration.Manager
+	engine       *reorg.Engine
+	compact      *compact.Manager
+	relay        *relay.Relay
+	reconciler   *reconcile.Reconciler
+	limiter      *limits.Limiter
+	progress     *progress.Tracker
+	broadcaster  *status.Broadcaster
+	accepted     entityEvents
+	eventPool    *workers.Pool
+	messagePool  *workers.Pool
+	entityPool   *workers.Pool
+	event        *event.Handler
+	message      *message.Handler
+}
+
+// New creates a new node on top of the given network and blockchain, with the
+// given header as root of the chain, and starts processing the network events
+// from the input channel.
+func New(log zerolog.Logger, net Network, chain Blockchain, kv KV, codec Codec, root *types.Header, input <-chan interface{}, options ...func(*Config)) (*Node, error) {
+
+	// initialize the default configuration and apply custom options
+	cfg := DefaultConfig()
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	// add the package information to the top package level logger
+	log = log.With().Str("package", "node").Logger()
+
+	n := &Node{
+		log:   log,
+		wg:    &sync.WaitGroup{},
+		cfg:   cfg,
+		input: input,
+		stop:  make(chan struct{}),
+	}
+
+	// initialize the repositories for headers, inventories and transactions
+	hdrs, err := headers.NewPersistent(kv, codec, root, cfg.cacheSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not initialize headers repository")
+	}
+	n.headers = hdrs
+	n.inventories = inventories.NewRepo()
+	n.transactions = transactions.NewRepo(codec, validation.NewTransaction())
+
+	// initialize the state of peers, header requests, orphans and subscribers
+	n.peers = peers.NewState()
+	n.requests = requests.NewState()
+	n.orphans = orphans.NewPool(cfg.maxOrphans, cfg.orphanQuota, cfg.orphanExpiry)
+	n.events = subscribers.NewManager(cfg.eventBuffer, cfg.eventTimeout)
+
+	// initialize the block synchronization, from the downloads to the engine
+	// that connects the assembled blocks to our best path
+	n.download = download.NewManager(net, n.peers, cfg.downloadTimeout, cfg.maxDownloads)
+	blocks := assembly.NewManager(n.headers, n.inventories, n.transactions, validation.NewTransaction(), connector{node: n})
+	n.collector = orchestration.NewManager(n.download, blocks, n.inventories, n.transactions)
+	n.compact = compact.NewManager(net, n.peers, n.transactions, n.inventories, n.download, signaler{collector: n.collector}, cfg.compactTimeout, cfg.maxPartials, cfg.partialQuota)
+	n.progress = progress.NewTracker(n.headers, n.download, n.events)
+	chainEvents := chainEvents{events: n.events, headers: n.headers, inventories: n.inventories, compact: n.compact, progress: n.progress}
+	state := accounts.NewState(cfg.reward)
+	for account, amount := range cfg.allocation {
+		err = state.Credit([]byte(account), amount)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not allocate genesis balance")
+		}
+	}
+	n.engine = reorg.NewEngine(&path.State{}, invalidator{headers: n.headers}, chain, state, n.transactions, n.collector, chainEvents, root.Hash, cfg.maxDepth)
+
+	// initialize the propagation of our best path and of transactions
+	n.broadcaster = status.NewBroadcaster(net, n.headers, root.Hash)
+	n.relay = relay.NewRelay(net, n.peers)
+	n.reconciler = reconcile.NewReconciler(net, n.transactions)
+
+	// initialize the handlers, with a worker pool for each stage; the event
+	// stage blocks when full, so that we don't lose network events, and so does
+	// the entity stage, so that messages carrying many entities slow down the
+	// message stage instead of getting honest peers dropped
+	n.limiter = limits.NewLimiter()
+	n.accepted = entityEvents{log: log, events: n.events, collector: n.collector}
+	n.eventPool = workers.NewPool(workers.SetWorkers(cfg.workers), workers.SetPolicy(workers.PolicyBlock))
+	n.messagePool = workers.NewPool(workers.SetWorkers(cfg.workers), workers.SetPolicy(workers.PolicyDrop))
+	n.entityPool = workers.NewPool(workers.SetWorkers(cfg.workers), workers.SetMaxQueue(cfg.entityQueue), workers.SetMaxTotal(cfg.entityTotal), workers.SetPolicy(workers.PolicyBlock))
+	tracker := peerTracker{state: n.peers}
+	validator := validation.NewHeader(n.headers)
+	ent := entity.NewHandler(log, net, follower{headers: n.headers, engine: n.engine}, n.accepted, n.headers, n.transactions, n.orphans, n.requests, validator, n.relay, n.entityPool)
+	n.message = message.NewHandler(log, net, signaler{collector: n.collector}, n.download, n.headers, n.inventories, n.transactions, tracker, n.requests, ent, n.reconciler, n.compact, n.messagePool, n.limiter, n.progress)
+	n.event = event.NewHandler(log, net, n.headers, tracker, n.requests, n.message, n.reconciler, n.limiter, n.progress, n.eventPool)
+
+	// start processing events and the periodic maintenance
+	n.loops.Add(2)
+	go n.process()
+	go n.maintain()
+
+	return n, nil
+}
+
+// Submit adds a transaction created locally to our memory pool and announces
+// it to our peers.
+func (n *Node) Submit(tx *types.Transaction) error {
+	tx.Hash = tx.GetHash()
+	err := n.transactions.Add(tx)
+	if err != nil {
+		return errors.Wrap(err, "could not add transaction")
+	}
+	n.accepted.Transaction(tx.Hash)
+	n.relay.Announce(tx.Hash)
+	return nil
+}
+
+// Subscribe adds a subscriber for the events of the node, which receives the
+// events matching any of the given filters, or all events if there are none.
+func (n *Node) Subscribe(sub chan<- interface{}, filters ...func(interface{}) bool) {
+	n.events.Subscribe(sub, filters...)
+}
+
+// SubscribeWith adds a subscriber for the events of the node with the given
+// buffer size and policy for when the subscriber falls behind.
+func (n *Node) SubscribeWith(sub chan<- interface{}, buffer uint, policy subscribers.Policy, filters ...func(interface{}) bool) {
+	n.events.SubscribeWith(sub, buffer, policy, filters...)
+}
+
+// Unsubscribe removes a subscriber for the events of the node.
+func (n *Node) Unsubscribe(sub chan<- interface{}) {
+	n.events.Unsubscribe(sub)
+}
+
+// Status returns the progress of the synchronization with our peers.
+func (n *Node) Status() progress.Status {
+	return n.progress.Status()
+}
+
+// Stats will log information of the node layer.
+func (n *Node) Stats() {
+	path, distance := n.headers.Recent(1)
+	height, _ := n.headers.Height(path[0])
+	status := n.progress.Status()
+	numPeers := n.peers.Count(peers.IsActive(true))
+	numTxs := n.transactions.Count()
+	sizeTxs := n.transactions.Size()
+	numOrphans := n.orphans.Count()
+	numRequests := n.requests.Count()
+	numThrottled := len(n.limiter.Throttled())
+	n.log.Info().Uint("num_peers", numPeers).Uint64("height", height).Uint64("distance", distance).Uint("num_txs", numTxs).Uint("size_txs", sizeTxs).Uint("num_orphans", numOrphans).Uint("num_requests", numRequests).Int("num_throttled", numThrottled).Msg("stats")
+	n.log.Info().Str("phase", status.Phase.String()).Uint64("target", status.Target).Uint64("height", status.Height).Uint64("best", status.Best).Uint("pending_invs", status.Invs).Uint("pending_txs", status.Txs).Float64("header_rate", status.HeaderRate).Float64("block_rate", status.BlockRate).Msg("sync")
+}
+
+// Stop stops processing network events and the periodic maintenance, then
+// lets each stage of handlers finish its queued work before shutting down the
+// next one, and waits for all remaining routines to finish.
+func (n *Node) Stop() {
+	close(n.stop)
+	n.loops.Wait()
+	n.eventPool.Stop()
+	n.messagePool.Stop()
+	n.entityPool.Stop()
+	n.wg.Wait()
+	n.events.Stop()
+}
+
+// process passes the network events to the event handler until we stop or the
+// input channel is closed.
+func (n *Node) process() {
+	defer n.loops.Done()
+
+	log := n.log.With().Str("component", "process").Logger()
+	log.Debug().Msg("routine started")
+	defer log.Debug().Msg("routine stopped")
+
+	for {
+		select {
+		case <-n.stop:
+			return
+		case e, ok := <-n.input:
+			if !ok {
+				return
+			}
+			n.event.Process(n.wg, e)
+		}
+	}
+}
+
+// maintain runs the periodic maintenance of the node components until we
+// stop.
+func (n *Node) maintain() {
+	defer n.loops.Done()
+
+	log := n.log.With().Str("component", "maintain").Logger()
+	log.Debug().Msg("routine started")
+	defer log.Debug().Msg("routine stopped")
+
+	interval := time.NewTicker(n.cfg.interval)
+	defer interval.Stop()
+	trickle := time.NewTicker(n.cfg.trickle)
+	defer trickle.Stop()
+	announce := time.NewTicker(n.cfg.announce)
+	defer announce.Stop()
+
+	for {
+		select {
+		case <-n.stop:
+			return
+		case <-interval.C:
+			n.message.Expire(n.wg, n.cfg.requestTimeout)
+			for _, hash := range n.transactions.Evicted() {
+				err := n.events.Evicted(hash)
+				if err != nil {
+					log.Warn().Err(err).Hex("hash", hash[:]).Msg("could not publish evicted event")
+				}
+			}
+			err := n.download.Check()
+			if err != nil {
+				log.Error().Err(err).Msg("could not check downloads")
+			}
+			err = n.progress.Update()
+			if err != nil {
+				log.Error().Err(err).Msg("could not update sync progress")
+			}
+			err = n.broadcaster.Check()
+			if err != nil {
+				log.Error().Err(err).Msg("could not broadcast status")
+			}
+			err = n.compact.Check()
+			if err != nil {
+				log.Error().Err(err).Msg("could not check compact blocks")
+			}
+		case <-trickle.C:
+			err := n.relay.Trickle()
+			if err != nil {
+				log.Error().Err(err).Msg("could not trickle announcements")
+			}
+		case <-announce.C:
+			err := n.reconciler.Announce()
+			if err != nil {
+				log.Error().Err(err).Msg("could not announce memory pool")
+			}
+		}
+	}
+}
@@ -18,11 +18,23 @@
 package node
 
 import (
+	"io/ioutil"
+	"runtime"
 	"sync"
 	"testing"
+	"time"
 
-	"github.com/alvalor/alvalor-go/network"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/alvalor/alvalor-go/kv"
+	"github.com/alvalor/alvalor-go/network"
+	"github.com/alvalor/alvalor-go/node/state/subscribers"
+	"github.com/alvalor/alvalor-go/node/sync/progress"
+	"github.com/alvalor/alvalor-go/store"
+	"github.com/alvalor/alvalor-go/types"
 )
 
 type HandlerMock struct {
@@ -65,3 +77,111 @@ func TestRun(t *testing.T) {
 		handler.AssertCalled(t, "Process", wg, e3)
 	}
 }
+
+func TestNodeSubmit(t *testing.T) {
+
+	// initialize entities
+	root := &types.Header{Diff: 1}
+	root.Hash = root.GetHash()
+	input := make(chan interface{})
+	from, priv, _ := ed25519.GenerateKey(nil)
+	tx := &types.Transaction{
+		Fees: []*types.Fee{{From: from, Amount: 1}},
+		Data: []byte{1, 2, 3},
+	}
+	hash := tx.GetHash()
+	tx.Signatures = [][]byte{ed25519.Sign(priv, hash[:])}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	chain := &BlockchainMock{}
+
+	// initialize node
+	n, err := New(zerolog.New(ioutil.Discard), net, chain, kv.NewMemory(), store.NewEncoding(), root, input)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	sub := make(chan interface{}, 1)
+	n.Subscribe(sub, subscribers.IsType(subscribers.TypeTransaction))
+
+	// execute submit
+	err = n.Submit(tx)
+
+	// check conditions
+	assert.Nil(t, err)
+	assert.Equal(t, tx.GetHash(), tx.Hash)
+	assert.True(t, n.transactions.Has(tx.Hash))
+
+	select {
+	case event := <-sub:
+		assert.Equal(t, &subscribers.Transaction{Hash: tx.Hash}, event)
+	case <-time.After(time.Second):
+		t.Error("transaction event not received")
+	}
+
+	n.Stop()
+
+	err = n.Submit(tx)
+	assert.NotNil(t, err)
+}
+
+func TestNodeStatus(t *testing.T) {
+
+	// initialize entities
+	root := &types.Header{Diff: 1}
+	root.Hash = root.GetHash()
+	input := make(chan interface{})
+
+	// initialize mocks
+	net := &NetworkMock{}
+	chain := &BlockchainMock{}
+
+	// initialize node
+	n, err := New(zerolog.New(ioutil.Discard), net, chain, kv.NewMemory(), store.NewEncoding(), root, input)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer n.Stop()
+
+	// execute status
+	status := n.Status()
+
+	// check conditions
+	assert.Equal(t, progress.PhaseIdle, status.Phase)
+	assert.Zero(t, status.Peers)
+}
+
+func TestNodeStop(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	root := &types.Header{Diff: 1}
+	root.Hash = root.GetHash()
+	input := make(chan interface{}, 1)
+
+	// initialize mocks
+	net := &NetworkMock{}
+	chain := &BlockchainMock{}
+
+	// program mocks
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// initialize node and pass it a network event
+	n, err := New(zerolog.New(ioutil.Discard), net, chain, kv.NewMemory(), store.NewEncoding(), root, input)
+	if !assert.Nil(t, err) {
+		return
+	}
+	input <- network.Connected{Address: address}
+
+	// execute stop, which has to wait for the event to be processed
+	for len(input) > 0 {
+		runtime.Gosched()
+	}
+	n.Stop()
+
+	// check conditions
+	net.AssertCalled(t, "Send", address, mock.Anything)
+}
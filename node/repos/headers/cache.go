@@ -0,0 +1,77 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package headers
+
+import (
+	"container/list"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// entry is a header together with its index data.
+type entry struct {
+	header   *types.Header
+	distance uint64
+	height   uint64
+}
+
+// cache is a bounded least-recently-used cache of header entries.
+type cache struct {
+	size    uint
+	order   *list.List
+	entries map[types.Hash]*list.Element
+}
+
+// newCache creates a new cache holding up to the given number of entries.
+func newCache(size uint) *cache {
+	return &cache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[types.Hash]*list.Element),
+	}
+}
+
+// get returns the entry for the given hash, if it is cached.
+func (c *cache) get(hash types.Hash) (*entry, bool) {
+	elem, ok := c.entries[hash]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*entry), true
+}
+
+// put adds an entry to the cache, evicting the least recently used entry if
+// the cache is full.
+func (c *cache) put(e *entry) {
+	if c.size == 0 {
+		return
+	}
+	elem, ok := c.entries[e.header.Hash]
+	if ok {
+		elem.Value = e
+		c.order.MoveToFront(elem)
+		return
+	}
+	if uint(c.order.Len()) >= c.size {
+		last := c.order.Back()
+		c.order.Remove(last)
+		delete(c.entries, last.Value.(*entry).header.Hash)
+	}
+	c.entries[e.header.Hash] = c.order.PushFront(e)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package headers
+
+import (
+	"testing"
+
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheEviction(t *testing.T) {
+
+	// create entities
+	entry1 := &entry{header: &types.Header{Hash: types.Hash{0x1}}}
+	entry2 := &entry{header: &types.Header{Hash: types.Hash{0x2}}}
+	entry3 := &entry{header: &types.Header{Hash: types.Hash{0x3}}}
+
+	// fill the cache and touch the first entry
+	c := newCache(2)
+	c.put(entry1)
+	c.put(entry2)
+	_, ok := c.get(entry1.header.Hash)
+	assert.True(t, ok)
+
+	// the least recently used entry should be evicted
+	c.put(entry3)
+	_, ok = c.get(entry2.header.Hash)
+	assert.False(t, ok)
+	e, ok := c.get(entry1.header.Hash)
+	if assert.True(t, ok) {
+		assert.Equal(t, entry1, e)
+	}
+	_, ok = c.get(entry3.header.Hash)
+	assert.True(t, ok)
+	assert.Len(t, c.entries, 2)
+}
+
+func TestCacheDisabled(t *testing.T) {
+	c := newCache(0)
+	c.put(&entry{header: &types.Header{Hash: types.Hash{0x1}}})
+	_, ok := c.get(types.Hash{0x1})
+	assert.False(t, ok)
+}
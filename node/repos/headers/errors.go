@@ -23,4 +23,7 @@ import "errors"
 var (
 	ErrExist    = errors.New("header already exists")
 	ErrNotExist = errors.New("header does not exist")
+	ErrOrphan   = errors.New("header parent does not exist")
+	ErrInvalid  = errors.New("header invalid")
+	ErrPath     = errors.New("header not on best path")
 )
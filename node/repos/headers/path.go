@@ -19,36 +19,94 @@ package headers
 
 import "github.com/alvalor/alvalor-go/types"
 
-// Path returns the best path of the graph by total difficulty.
+// Path returns the best path of the graph by total difficulty, from the best
+// header back to the root.
 func (hr *Repo) Path() ([]types.Hash, uint64) {
+	hr.Lock()
+	defer hr.Unlock()
 
-	// create a topological sort and get distance for each header
-	var hash types.Hash
-	var max uint64
-	var best types.Hash
-	sorted := make([]types.Hash, 0, len(hr.headers))
-	queue := []types.Hash{hr.root}
-	distances := make(map[types.Hash]uint64)
-	for len(queue) > 0 {
-		hash, queue = queue[0], queue[1:]
-		sorted = append(sorted, hash)
-		queue = append(queue, hr.children[hash]...)
-		header := hr.headers[hash]
-		distance := distances[header.Parent] + header.Diff
-		if distance > max {
-			max = distance
-			best = hash
-		}
-		distances[hash] = distance
+	return hr.recent(uint(len(hr.best)))
+}
+
+// Recent returns at most the given number of hashes from the top of the best
+// path, starting with the best header, together with its total difficulty.
+func (hr *Repo) Recent(n uint) ([]types.Hash, uint64) {
+	hr.Lock()
+	defer hr.Unlock()
+
+	return hr.recent(n)
+}
+
+// recent returns the top of the best path without locking the repository.
+func (hr *Repo) recent(n uint) ([]types.Hash, uint64) {
+	if n > uint(len(hr.best)) {
+		n = uint(len(hr.best))
+	}
+	path := make([]types.Hash, 0, n)
+	for i := len(hr.best) - 1; uint(len(path)) < n; i-- {
+		path = append(path, hr.best[i])
+	}
+	return path, hr.distances[hr.tip()]
+}
+
+// Locators returns a sparse list of hashes from the best path, to be used as
+// locators when requesting headers, together with its total difficulty. It
+// starts with the best header and always ends with the root.
+func (hr *Repo) Locators() ([]types.Hash, uint64) {
+	hr.Lock()
+	defer hr.Unlock()
+
+	heights := sparse(uint64(len(hr.best) - 1))
+	locators := make([]types.Hash, 0, len(heights))
+	for _, height := range heights {
+		locators = append(locators, hr.best[height])
 	}
+	return locators, hr.distances[hr.tip()]
+}
+
+// Following returns at most the given number of headers of the best path that
+// follow the highest of the given locators on it, from oldest to newest, and
+// ends early at the stop hash. If none of the locators is on the best path, we
+// return no headers, as the requester could not connect them to its own.
+func (hr *Repo) Following(locators []types.Hash, stop types.Hash, max uint) ([]*types.Header, error) {
+	hr.Lock()
+	defer hr.Unlock()
 
-	// iterate back to parents from best child
-	header := hr.headers[best]
-	path := []types.Hash{header.Hash}
-	for header.Parent != types.ZeroHash {
-		header = hr.headers[header.Parent]
-		path = append(path, header.Hash)
+	start := uint64(0)
+	found := false
+	for _, locator := range locators {
+		if hr.onPath(locator) && hr.heights[locator]+1 > start {
+			start = hr.heights[locator] + 1
+			found = true
+		}
+	}
+	if !found {
+		return nil, nil
 	}
+	var headers []*types.Header
+	for height := start; height < uint64(len(hr.best)) && uint(len(headers)) < max; height++ {
+		hash := hr.best[height]
+		headers = append(headers, hr.headers[hash])
+		if hash == stop {
+			break
+		}
+	}
+	return headers, nil
+}
 
-	return path, max
+// sparse returns the heights of the locators for a best path with the given
+// height, going back one header at a time for the first eight, then doubling
+// the step each time, and finishing with the root.
+func sparse(top uint64) []uint64 {
+	var heights []uint64
+	index := uint64(0)
+	step := uint64(1)
+	for index < top {
+		heights = append(heights, top-index)
+		if len(heights) >= 8 {
+			step *= 2
+		}
+		index += step
+	}
+	return append(heights, 0)
 }
@@ -180,20 +180,12 @@ func TestRepoPathRoot(t *testing.T) {
 	// loop through the test vectors
 	for name, vector := range vectors {
 
-		// initialize the repository with required maps
-		hr := &Repo{
-			root:     hash0,
-			headers:  make(map[types.Hash]*types.Header),
-			pending:  make(map[types.Hash][]*types.Header),
-			children: make(map[types.Hash][]types.Hash),
-		}
-
-		// add the first header as root
-		hr.headers[vector.headers[0].Hash] = vector.headers[0]
+		// initialize the repository with the first header as root
+		hr := NewRepo(vector.headers[0])
 
 		// add the rest of the headers
 		for i := 1; i < len(vector.headers); i++ {
-			_ = hr.Add(vector.headers[i])
+			_, _ = hr.Add(vector.headers[i])
 		}
 
 		// get the distance/path and compare
@@ -203,3 +195,122 @@ func TestRepoPathRoot(t *testing.T) {
 	}
 
 }
+
+func TestRepoRecent(t *testing.T) {
+
+	// create entities
+	header0 := &types.Header{Hash: types.Hash{0x0, 0x1}, Diff: 1}
+	header1 := &types.Header{Hash: types.Hash{0x1}, Parent: header0.Hash, Diff: 1}
+	header2 := &types.Header{Hash: types.Hash{0x2}, Parent: header1.Hash, Diff: 1}
+
+	// initialize the repository
+	hr := NewRepo(header0)
+	_, _ = hr.Add(header1)
+	_, _ = hr.Add(header2)
+
+	// check the bounded suffix of the best path
+	path, distance := hr.Recent(2)
+	assert.Equal(t, []types.Hash{header2.Hash, header1.Hash}, path)
+	assert.Equal(t, uint64(3), distance)
+
+	path, _ = hr.Recent(10)
+	assert.Equal(t, []types.Hash{header2.Hash, header1.Hash, header0.Hash}, path)
+}
+
+func TestRepoReorg(t *testing.T) {
+
+	// create entities
+	header0 := &types.Header{Hash: types.Hash{0x0, 0x1}, Diff: 1}
+	header1 := &types.Header{Hash: types.Hash{0x1}, Parent: header0.Hash, Diff: 10}
+	header11 := &types.Header{Hash: types.Hash{0x11}, Parent: header1.Hash, Diff: 10}
+	header12 := &types.Header{Hash: types.Hash{0x12}, Parent: header1.Hash, Diff: 5}
+	header121 := &types.Header{Hash: types.Hash{0x12, 0x1}, Parent: header12.Hash, Diff: 10}
+
+	// initialize the repository
+	hr := NewRepo(header0)
+
+	// extending the best path reports the old tip as ancestor
+	reorg, err := hr.Add(header1)
+	assert.Nil(t, err)
+	assert.Equal(t, &Reorg{Old: header0.Hash, New: header1.Hash, Ancestor: header0.Hash}, reorg)
+
+	reorg, err = hr.Add(header11)
+	assert.Nil(t, err)
+	assert.Equal(t, &Reorg{Old: header1.Hash, New: header11.Hash, Ancestor: header1.Hash}, reorg)
+
+	// a weaker side branch does not change the best path
+	reorg, err = hr.Add(header12)
+	assert.Nil(t, err)
+	assert.Nil(t, reorg)
+
+	// a stronger side branch switches the best path
+	reorg, err = hr.Add(header121)
+	assert.Nil(t, err)
+	assert.Equal(t, &Reorg{Old: header11.Hash, New: header121.Hash, Ancestor: header1.Hash}, reorg)
+
+	path, distance := hr.Path()
+	assert.Equal(t, []types.Hash{header121.Hash, header12.Hash, header1.Hash, header0.Hash}, path)
+	assert.Equal(t, uint64(26), distance)
+}
+
+func TestRepoLocators(t *testing.T) {
+
+	// create a best path of one hundred headers on top of the root
+	root := &types.Header{Hash: types.Hash{0xff}, Diff: 1}
+	hr := NewRepo(root)
+	parent := root.Hash
+	for i := 1; i <= 100; i++ {
+		header := &types.Header{Hash: types.Hash{byte(i)}, Parent: parent, Diff: 1}
+		_, _ = hr.Add(header)
+		parent = header.Hash
+	}
+
+	// check that the locators get sparse and end with the root
+	locators, distance := hr.Locators()
+	expected := []types.Hash{
+		{100}, {99}, {98}, {97}, {96}, {95}, {94}, {93},
+		{91}, {87}, {79}, {63}, {31}, {0xff},
+	}
+	assert.Equal(t, expected, locators)
+	assert.Equal(t, uint64(101), distance)
+}
+
+func TestRepoLocatorsRoot(t *testing.T) {
+	root := &types.Header{Hash: types.Hash{0xff}, Diff: 1}
+	hr := NewRepo(root)
+	locators, _ := hr.Locators()
+	assert.Equal(t, []types.Hash{root.Hash}, locators)
+}
+
+func TestRepoFollowing(t *testing.T) {
+
+	// create entities
+	header0 := &types.Header{Hash: types.Hash{0x0, 0x1}, Diff: 1}
+	header1 := &types.Header{Hash: types.Hash{0x1}, Parent: header0.Hash, Diff: 10}
+	header2 := &types.Header{Hash: types.Hash{0x2}, Parent: header1.Hash, Diff: 10}
+	header3 := &types.Header{Hash: types.Hash{0x3}, Parent: header2.Hash, Diff: 10}
+	header21 := &types.Header{Hash: types.Hash{0x21}, Parent: header1.Hash, Diff: 5}
+
+	// initialize the repository
+	hr := NewRepo(header0)
+	_, _ = hr.Add(header1)
+	_, _ = hr.Add(header2)
+	_, _ = hr.Add(header3)
+	_, _ = hr.Add(header21)
+
+	// start after the highest locator on the best path
+	headers, err := hr.Following([]types.Hash{header21.Hash, header0.Hash, header1.Hash}, types.ZeroHash, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []*types.Header{header2, header3}, headers)
+
+	// stop at the maximum and at the stop hash
+	headers, _ = hr.Following([]types.Hash{header0.Hash}, types.ZeroHash, 1)
+	assert.Equal(t, []*types.Header{header1}, headers)
+	headers, _ = hr.Following([]types.Hash{header0.Hash}, header2.Hash, 10)
+	assert.Equal(t, []*types.Header{header1, header2}, headers)
+
+	// return nothing if we share no locator
+	headers, err = hr.Following([]types.Hash{header21.Hash}, types.ZeroHash, 2)
+	assert.Nil(t, err)
+	assert.Empty(t, headers)
+}
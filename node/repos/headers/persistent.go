@@ -0,0 +1,711 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package headers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// Key prefixes used by the persistent repository.
+var (
+	prefixHeader   = []byte("h")
+	prefixIndex    = []byte("i")
+	prefixChildren = []byte("c")
+	prefixHeight   = []byte("n")
+	prefixInvalid  = []byte("x")
+	keyTip         = []byte("meta:tip")
+)
+
+// Persistent is a header repository backed by a key-value store. It persists
+// the headers with their parent/child links, cumulative difficulty and height,
+// as well as the best path indexed by height, so that a restarted node can
+// continue from its previous best tip. Headers are kept in a bounded cache,
+// while only the tip of the best path and its height are kept in memory.
+type Persistent struct {
+	sync.Mutex
+	kv     KV
+	codec  Codec
+	cache  *cache
+	best   types.Hash
+	height uint64
+}
+
+// NewPersistent creates a new persistent header repository. If the key-value
+// store is empty, it is initialized with the given root header; otherwise,
+// we continue from the stored tip, whose best path has to start at the root.
+func NewPersistent(kv KV, codec Codec, root *types.Header, size uint) (*Persistent, error) {
+	hr := &Persistent{
+		kv:    kv,
+		codec: codec,
+		cache: newCache(size),
+	}
+
+	// if we have no tip yet, initialize the store with the root
+	ok, err := kv.Has(keyTip)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not check tip")
+	}
+	if !ok {
+		err = hr.save(&entry{header: root, distance: root.Diff, height: 0})
+		if err != nil {
+			return nil, errors.Wrap(err, "could not save root")
+		}
+		err = hr.extend(0, []types.Hash{root.Hash})
+		if err != nil {
+			return nil, errors.Wrap(err, "could not save best path")
+		}
+		return hr, nil
+	}
+
+	// otherwise, load the tip of the best path and check its root
+	data, err := kv.Get(keyTip)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get tip")
+	}
+	var tip types.Hash
+	copy(tip[:], data)
+	e, err := hr.entry(tip)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get tip entry")
+	}
+	hr.best = tip
+	hr.height = e.height
+	first, err := hr.hash(0)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get root of best path")
+	}
+	if first != root.Hash {
+		return nil, errors.Errorf("best path has different root (%x)", first)
+	}
+
+	return hr, nil
+}
+
+// Add adds a new header to the repository. If the header changes the best
+// path, the change is returned as a reorg. Headers with unknown parent are
+// refused, so that they can be kept in a bounded orphan pool.
+func (hr *Persistent) Add(header *types.Header) (*Reorg, error) {
+	hr.Lock()
+	defer hr.Unlock()
+
+	// if we already know the header, fail
+	if hr.has(header.Hash) {
+		return nil, errors.Wrap(ErrExist, "header already known")
+	}
+
+	// add the header and update the best path
+	old := hr.tip()
+	err := hr.add(header)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not add header")
+	}
+
+	// if the tip did not change, there is nothing to report
+	tip := hr.tip()
+	if tip == old {
+		return nil, nil
+	}
+
+	// find the last header of the old path that is still on the best path
+	ancestor, err := hr.ancestor(old)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find ancestor")
+	}
+	reorg := &Reorg{
+		Old:      old,
+		New:      tip,
+		Ancestor: ancestor,
+	}
+
+	return reorg, nil
+}
+
+// Invalidate marks the header with the given hash and all of its descendants
+// as invalid, so they never become part of the best path again and children
+// are refused. If the header was on the best path, we switch to the valid
+// header with the most total difficulty and return the change as a reorg.
+func (hr *Persistent) Invalidate(hash types.Hash) (*Reorg, error) {
+	hr.Lock()
+	defer hr.Unlock()
+
+	// the root is what all headers descend from, so it can't be invalid
+	e, err := hr.entry(hash)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get header entry")
+	}
+	if e.height == 0 {
+		return nil, errors.New("can not invalidate root")
+	}
+
+	// mark the header and its descendants as invalid
+	queue := []types.Hash{hash}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		err = hr.kv.Put(key(prefixInvalid, next), []byte{})
+		if err != nil {
+			return nil, errors.Wrap(err, "could not put invalid mark")
+		}
+		children, err := hr.children(next)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get children")
+		}
+		queue = append(queue, children...)
+	}
+
+	// if the header was not on the best path, nothing else changes
+	ok, err := hr.onPath(hash, e.height)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not check header")
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	// cut the best path back to the parent, then follow the best valid header
+	old := hr.tip()
+	parent, err := hr.entry(e.header.Parent)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get parent entry")
+	}
+	err = hr.extend(parent.height, []types.Hash{parent.header.Hash})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not cut best path")
+	}
+	best, err := hr.search()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find best header")
+	}
+	if best.header.Hash != parent.header.Hash {
+		err = hr.follow(best)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not follow header")
+		}
+	}
+
+	// find the last header of the old path that is still on the best path
+	ancestor, err := hr.ancestor(old)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find ancestor")
+	}
+	reorg := &Reorg{
+		Old:      old,
+		New:      hr.tip(),
+		Ancestor: ancestor,
+	}
+
+	return reorg, nil
+}
+
+// add inserts a header into the repository and updates the best path.
+func (hr *Persistent) add(header *types.Header) error {
+
+	// if we don't know the parent, refuse the header
+	parent, err := hr.entry(header.Parent)
+	if errors.Cause(err) == ErrNotExist {
+		return errors.Wrap(ErrOrphan, "header parent unknown")
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not get parent entry")
+	}
+
+	// if the parent is invalid, so is the header
+	invalid, err := hr.invalid(header.Parent)
+	if err != nil {
+		return errors.Wrap(err, "could not check parent")
+	}
+	if invalid {
+		return errors.Wrap(ErrInvalid, "header parent invalid")
+	}
+
+	// link the header to its parent and save it with its index data
+	err = hr.link(header.Parent, header.Hash)
+	if err != nil {
+		return errors.Wrap(err, "could not link header")
+	}
+	e := &entry{
+		header:   header,
+		distance: parent.distance + header.Diff,
+		height:   parent.height + 1,
+	}
+	err = hr.save(e)
+	if err != nil {
+		return errors.Wrap(err, "could not save header")
+	}
+
+	// if the header has more total difficulty than our tip, switch to it
+	best, err := hr.entry(hr.tip())
+	if err != nil {
+		return errors.Wrap(err, "could not get tip entry")
+	}
+	if e.distance > best.distance {
+		err = hr.follow(e)
+		if err != nil {
+			return errors.Wrap(err, "could not follow header")
+		}
+	}
+
+	return nil
+}
+
+// follow switches the best path to end at the given entry, only replacing the
+// part after the fork with the current best path.
+func (hr *Persistent) follow(e *entry) error {
+	var branch []types.Hash
+	for {
+		ok, err := hr.onPath(e.header.Hash, e.height)
+		if err != nil {
+			return errors.Wrap(err, "could not check branch entry")
+		}
+		if ok {
+			break
+		}
+		branch = append(branch, e.header.Hash)
+		e, err = hr.entry(e.header.Parent)
+		if err != nil {
+			return errors.Wrap(err, "could not get branch entry")
+		}
+	}
+	for i, j := 0, len(branch)-1; i < j; i, j = i+1, j-1 {
+		branch[i], branch[j] = branch[j], branch[i]
+	}
+	return hr.extend(e.height+1, branch)
+}
+
+// extend replaces the best path from the given height onwards with the given
+// hashes and persists the change, including the new tip.
+func (hr *Persistent) extend(height uint64, hashes []types.Hash) error {
+	for i, hash := range hashes {
+		err := hr.kv.Put(heightKey(height+uint64(i)), hash[:])
+		if err != nil {
+			return errors.Wrap(err, "could not put best path")
+		}
+	}
+	top := height + uint64(len(hashes)) - 1
+	for h := top + 1; h <= hr.height; h++ {
+		err := hr.kv.Del(heightKey(h))
+		if err != nil {
+			return errors.Wrap(err, "could not delete best path")
+		}
+	}
+	tip := hashes[len(hashes)-1]
+	err := hr.kv.Put(keyTip, tip[:])
+	if err != nil {
+		return errors.Wrap(err, "could not put tip")
+	}
+	hr.best = tip
+	hr.height = top
+	return nil
+}
+
+// Has checks if the given hash is already known.
+func (hr *Persistent) Has(hash types.Hash) bool {
+	hr.Lock()
+	defer hr.Unlock()
+
+	return hr.has(hash)
+}
+
+// has checks if the given hash is already known without locking the
+// repository.
+func (hr *Persistent) has(hash types.Hash) bool {
+	_, ok := hr.cache.get(hash)
+	if ok {
+		return true
+	}
+	ok, err := hr.kv.Has(key(prefixHeader, hash))
+	return err == nil && ok
+}
+
+// Get returns the header with the given hash.
+func (hr *Persistent) Get(hash types.Hash) (*types.Header, error) {
+	hr.Lock()
+	defer hr.Unlock()
+
+	e, err := hr.entry(hash)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get header entry")
+	}
+	return e.header, nil
+}
+
+// Height returns the height of the header with the given hash.
+func (hr *Persistent) Height(hash types.Hash) (uint64, error) {
+	hr.Lock()
+	defer hr.Unlock()
+
+	e, err := hr.entry(hash)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not get header entry")
+	}
+	return e.height, nil
+}
+
+// Path returns the best path of the graph by total difficulty, from the best
+// header back to the root.
+func (hr *Persistent) Path() ([]types.Hash, uint64) {
+	hr.Lock()
+	defer hr.Unlock()
+
+	return hr.recent(uint(hr.height + 1))
+}
+
+// Recent returns at most the given number of hashes from the top of the best
+// path, starting with the best header, together with its total difficulty.
+func (hr *Persistent) Recent(n uint) ([]types.Hash, uint64) {
+	hr.Lock()
+	defer hr.Unlock()
+
+	return hr.recent(n)
+}
+
+// recent returns the top of the best path without locking the repository.
+func (hr *Persistent) recent(n uint) ([]types.Hash, uint64) {
+	if uint64(n) > hr.height+1 {
+		n = uint(hr.height + 1)
+	}
+	path := make([]types.Hash, 0, n)
+	for height := hr.height; uint(len(path)) < n; height-- {
+		hash, err := hr.hash(height)
+		if err != nil {
+			break
+		}
+		path = append(path, hash)
+	}
+	return path, hr.distance()
+}
+
+// Since returns the best path from the header with the given hash up to the
+// best header, from oldest to newest.
+func (hr *Persistent) Since(hash types.Hash) ([]types.Hash, error) {
+	hr.Lock()
+	defer hr.Unlock()
+
+	e, err := hr.entry(hash)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get header entry")
+	}
+	ok, err := hr.onPath(hash, e.height)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not check header")
+	}
+	if !ok {
+		return nil, errors.Wrap(ErrPath, "header not on best path")
+	}
+	path := make([]types.Hash, 0, hr.height-e.height+1)
+	for height := e.height; height <= hr.height; height++ {
+		hash, err := hr.hash(height)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get best path")
+		}
+		path = append(path, hash)
+	}
+	return path, nil
+}
+
+// Locators returns a sparse list of hashes from the best path, to be used as
+// locators when requesting headers, together with its total difficulty. It
+// starts with the best header and always ends with the root.
+func (hr *Persistent) Locators() ([]types.Hash, uint64) {
+	hr.Lock()
+	defer hr.Unlock()
+
+	heights := sparse(hr.height)
+	locators := make([]types.Hash, 0, len(heights))
+	for _, height := range heights {
+		hash, err := hr.hash(height)
+		if err != nil {
+			break
+		}
+		locators = append(locators, hash)
+	}
+	return locators, hr.distance()
+}
+
+// Following returns at most the given number of headers of the best path that
+// follow the highest of the given locators on it, from oldest to newest, and
+// ends early at the stop hash. If none of the locators is on the best path, we
+// return no headers, as the requester could not connect them to its own.
+func (hr *Persistent) Following(locators []types.Hash, stop types.Hash, max uint) ([]*types.Header, error) {
+	hr.Lock()
+	defer hr.Unlock()
+
+	start := uint64(0)
+	found := false
+	for _, locator := range locators {
+		e, err := hr.entry(locator)
+		if errors.Cause(err) == ErrNotExist {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get locator entry")
+		}
+		ok, err := hr.onPath(locator, e.height)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not check locator")
+		}
+		if ok && e.height+1 > start {
+			start = e.height + 1
+			found = true
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+	var headers []*types.Header
+	for height := start; height <= hr.height && uint(len(headers)) < max; height++ {
+		hash, err := hr.hash(height)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get best path")
+		}
+		e, err := hr.entry(hash)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get header entry")
+		}
+		headers = append(headers, e.header)
+		if e.header.Hash == stop {
+			break
+		}
+	}
+	return headers, nil
+}
+
+// entry returns the header with its index data, from the cache if possible.
+func (hr *Persistent) entry(hash types.Hash) (*entry, error) {
+	e, ok := hr.cache.get(hash)
+	if ok {
+		return e, nil
+	}
+	ok, err := hr.kv.Has(key(prefixHeader, hash))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not check header")
+	}
+	if !ok {
+		return nil, errors.Wrap(ErrNotExist, "header not found")
+	}
+	data, err := hr.kv.Get(key(prefixHeader, hash))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get header data")
+	}
+	entity, err := hr.codec.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode header")
+	}
+	header, ok := entity.(*types.Header)
+	if !ok {
+		return nil, errors.Errorf("invalid header entity (%T)", entity)
+	}
+	header.Hash = hash
+	index, err := hr.kv.Get(key(prefixIndex, hash))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get header index")
+	}
+	if len(index) != 16 {
+		return nil, errors.Errorf("invalid header index length (%d)", len(index))
+	}
+	e = &entry{
+		header:   header,
+		distance: binary.LittleEndian.Uint64(index[:8]),
+		height:   binary.LittleEndian.Uint64(index[8:]),
+	}
+	hr.cache.put(e)
+	return e, nil
+}
+
+// save persists the header with its index data. The header record is written
+// last, so that a header is only known once its index data is complete.
+func (hr *Persistent) save(e *entry) error {
+	index := make([]byte, 16)
+	binary.LittleEndian.PutUint64(index[:8], e.distance)
+	binary.LittleEndian.PutUint64(index[8:], e.height)
+	err := hr.kv.Put(key(prefixIndex, e.header.Hash), index)
+	if err != nil {
+		return errors.Wrap(err, "could not put header index")
+	}
+	buf := &bytes.Buffer{}
+	err = hr.codec.Encode(buf, e.header)
+	if err != nil {
+		return errors.Wrap(err, "could not encode header")
+	}
+	err = hr.kv.Put(key(prefixHeader, e.header.Hash), buf.Bytes())
+	if err != nil {
+		return errors.Wrap(err, "could not put header data")
+	}
+	hr.cache.put(e)
+	return nil
+}
+
+// link adds the child to the list of children of the parent.
+func (hr *Persistent) link(parent types.Hash, child types.Hash) error {
+	children, err := hr.children(parent)
+	if err != nil {
+		return errors.Wrap(err, "could not get children")
+	}
+	data := make([]byte, 0, (len(children)+1)*len(child))
+	for _, hash := range children {
+		data = append(data, hash[:]...)
+	}
+	data = append(data, child[:]...)
+	err = hr.kv.Put(key(prefixChildren, parent), data)
+	if err != nil {
+		return errors.Wrap(err, "could not put children")
+	}
+	return nil
+}
+
+// children returns the hashes of all known children of the given header.
+func (hr *Persistent) children(hash types.Hash) ([]types.Hash, error) {
+	ok, err := hr.kv.Has(key(prefixChildren, hash))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not check children")
+	}
+	if !ok {
+		return nil, nil
+	}
+	data, err := hr.kv.Get(key(prefixChildren, hash))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get children")
+	}
+	children := make([]types.Hash, len(data)/len(hash))
+	for i := range children {
+		copy(children[i][:], data[i*len(hash):])
+	}
+	return children, nil
+}
+
+// ancestor returns the last header before the given one that is on the best
+// path, or the header itself if it is on it.
+func (hr *Persistent) ancestor(hash types.Hash) (types.Hash, error) {
+	for {
+		e, err := hr.entry(hash)
+		if err != nil {
+			return hash, errors.Wrap(err, "could not get ancestor entry")
+		}
+		ok, err := hr.onPath(hash, e.height)
+		if err != nil {
+			return hash, errors.Wrap(err, "could not check ancestor")
+		}
+		if ok {
+			return hash, nil
+		}
+		hash = e.header.Parent
+	}
+}
+
+// search returns the valid header with the most total difficulty, preferring
+// the one on the best path on ties. It walks the whole tree of valid headers,
+// which is fine for the rare case of an invalid block on the best path.
+func (hr *Persistent) search() (*entry, error) {
+	best, err := hr.entry(hr.tip())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get tip entry")
+	}
+	root, err := hr.hash(0)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get root")
+	}
+	stack := []types.Hash{root}
+	for len(stack) > 0 {
+		hash := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		invalid, err := hr.invalid(hash)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not check header")
+		}
+		if invalid {
+			continue
+		}
+		e, err := hr.entry(hash)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get header entry")
+		}
+		if e.distance > best.distance {
+			best = e
+		}
+		children, err := hr.children(hash)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get children")
+		}
+		stack = append(stack, children...)
+	}
+	return best, nil
+}
+
+// invalid checks whether the header with the given hash was marked invalid.
+func (hr *Persistent) invalid(hash types.Hash) (bool, error) {
+	return hr.kv.Has(key(prefixInvalid, hash))
+}
+
+// onPath checks whether the header with the given hash and height is on the
+// best path.
+func (hr *Persistent) onPath(hash types.Hash, height uint64) (bool, error) {
+	if height > hr.height {
+		return false, nil
+	}
+	best, err := hr.hash(height)
+	if err != nil {
+		return false, errors.Wrap(err, "could not get best path")
+	}
+	return best == hash, nil
+}
+
+// hash returns the hash of the header at the given height of the best path.
+func (hr *Persistent) hash(height uint64) (types.Hash, error) {
+	var hash types.Hash
+	data, err := hr.kv.Get(heightKey(height))
+	if err != nil {
+		return hash, errors.Wrapf(err, "could not get best path entry (%d)", height)
+	}
+	copy(hash[:], data)
+	return hash, nil
+}
+
+// tip returns the hash of the last header on the best path.
+func (hr *Persistent) tip() types.Hash {
+	return hr.best
+}
+
+// distance returns the total difficulty of the best path.
+func (hr *Persistent) distance() uint64 {
+	e, err := hr.entry(hr.best)
+	if err != nil {
+		return 0
+	}
+	return e.distance
+}
+
+// key returns the key for the given hash with the given prefix.
+func key(prefix []byte, hash types.Hash) []byte {
+	k := make([]byte, 0, len(prefix)+len(hash))
+	k = append(k, prefix...)
+	return append(k, hash[:]...)
+}
+
+// heightKey returns the key of the best path entry at the given height.
+func heightKey(height uint64) []byte {
+	k := make([]byte, len(prefixHeight)+8)
+	copy(k, prefixHeight)
+	binary.BigEndian.PutUint64(k[len(prefixHeight):], height)
+	return k
+}
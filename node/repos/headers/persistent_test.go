@@ -0,0 +1,294 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package headers
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alvalor/alvalor-go/kv"
+	"github.com/alvalor/alvalor-go/store"
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistentNew(t *testing.T) {
+
+	// create entities
+	root := &types.Header{Hash: types.Hash{0x0, 0x1}, Diff: 1, Time: time.Unix(1, 0).UTC()}
+
+	// initialize an empty repository
+	db := kv.NewMemory()
+	hr, err := NewPersistent(db, store.NewEncoding(), root, 16)
+	require.Nil(t, err)
+
+	// check that the root is stored and is the best path
+	assert.True(t, hr.Has(root.Hash))
+	header, err := hr.Get(root.Hash)
+	if assert.Nil(t, err) {
+		assert.Equal(t, root, header)
+	}
+	path, distance := hr.Path()
+	assert.Equal(t, []types.Hash{root.Hash}, path)
+	assert.Equal(t, uint64(1), distance)
+
+	// check that a different root is refused on restart
+	other := &types.Header{Hash: types.Hash{0x0, 0x2}}
+	_, err = NewPersistent(db, store.NewEncoding(), other, 16)
+	assert.NotNil(t, err)
+}
+
+func TestPersistentAdd(t *testing.T) {
+
+	// create entities
+	header0 := &types.Header{Hash: types.Hash{0x0, 0x1}, Diff: 1}
+	header1 := &types.Header{Hash: types.Hash{0x1}, Parent: header0.Hash, Diff: 10}
+	header11 := &types.Header{Hash: types.Hash{0x11}, Parent: header1.Hash, Diff: 10}
+	header12 := &types.Header{Hash: types.Hash{0x12}, Parent: header1.Hash, Diff: 5}
+	header121 := &types.Header{Hash: types.Hash{0x12, 0x1}, Parent: header12.Hash, Diff: 10}
+
+	// initialize the repository without cache, so we always hit the store
+	hr, err := NewPersistent(kv.NewMemory(), store.NewEncoding(), header0, 0)
+	require.Nil(t, err)
+
+	// adding a header twice fails
+	reorg, err := hr.Add(header1)
+	assert.Nil(t, err)
+	assert.Equal(t, &Reorg{Old: header0.Hash, New: header1.Hash, Ancestor: header0.Hash}, reorg)
+	_, err = hr.Add(header1)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrExist, errors.Cause(err))
+	}
+
+	// headers with missing parents are refused
+	_, err = hr.Add(header121)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrOrphan, errors.Cause(err))
+	}
+	assert.False(t, hr.Has(header121.Hash))
+
+	reorg, err = hr.Add(header11)
+	assert.Nil(t, err)
+	assert.Equal(t, &Reorg{Old: header1.Hash, New: header11.Hash, Ancestor: header1.Hash}, reorg)
+
+	// connecting the orphan switches to the stronger branch
+	reorg, err = hr.Add(header12)
+	assert.Nil(t, err)
+	assert.Nil(t, reorg)
+	reorg, err = hr.Add(header121)
+	assert.Nil(t, err)
+	assert.Equal(t, &Reorg{Old: header11.Hash, New: header121.Hash, Ancestor: header1.Hash}, reorg)
+
+	path, distance := hr.Path()
+	assert.Equal(t, []types.Hash{header121.Hash, header12.Hash, header1.Hash, header0.Hash}, path)
+	assert.Equal(t, uint64(26), distance)
+
+	children, err := hr.children(header1.Hash)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []types.Hash{header11.Hash, header12.Hash}, children)
+
+	_, err = hr.Get(types.Hash{0xff})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrNotExist, errors.Cause(err))
+	}
+}
+
+func TestPersistentRestart(t *testing.T) {
+
+	// create entities
+	header0 := &types.Header{Hash: types.Hash{0x0, 0x1}, Diff: 1}
+	header1 := &types.Header{Hash: types.Hash{0x1}, Parent: header0.Hash, Diff: 10}
+	header2 := &types.Header{Hash: types.Hash{0x2}, Parent: header1.Hash, Diff: 10}
+	header21 := &types.Header{Hash: types.Hash{0x21}, Parent: header2.Hash, Diff: 1}
+	header3 := &types.Header{Hash: types.Hash{0x3}, Parent: header1.Hash, Diff: 30}
+
+	// fill the repository
+	db := kv.NewMemory()
+	hr, err := NewPersistent(db, store.NewEncoding(), header0, 16)
+	require.Nil(t, err)
+	_, _ = hr.Add(header1)
+	_, _ = hr.Add(header2)
+	_, _ = hr.Add(header21)
+	ok, err := db.Has(heightKey(3))
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	_, _ = hr.Add(header3)
+
+	// load it again from the same store and check the state
+	hr, err = NewPersistent(db, store.NewEncoding(), header0, 16)
+	require.Nil(t, err)
+	path, distance := hr.Path()
+	assert.Equal(t, []types.Hash{header3.Hash, header1.Hash, header0.Hash}, path)
+	assert.Equal(t, uint64(41), distance)
+	assert.True(t, hr.Has(header2.Hash))
+	header, err := hr.Get(header3.Hash)
+	if assert.Nil(t, err) {
+		assert.Equal(t, header3.Parent, header.Parent)
+		assert.Equal(t, header3.Diff, header.Diff)
+	}
+
+	// the stale best path entry above the tip was removed on reorg
+	ok, err = db.Has(heightKey(3))
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestPersistentLocators(t *testing.T) {
+
+	// create entities
+	header0 := &types.Header{Hash: types.Hash{0x0, 0x1}, Diff: 1}
+	header1 := &types.Header{Hash: types.Hash{0x1}, Parent: header0.Hash, Diff: 10}
+	header2 := &types.Header{Hash: types.Hash{0x2}, Parent: header1.Hash, Diff: 10}
+	header21 := &types.Header{Hash: types.Hash{0x21}, Parent: header1.Hash, Diff: 5}
+
+	// initialize the repository without cache, so we always hit the store
+	hr, err := NewPersistent(kv.NewMemory(), store.NewEncoding(), header0, 0)
+	require.Nil(t, err)
+	_, _ = hr.Add(header1)
+	_, _ = hr.Add(header2)
+	_, _ = hr.Add(header21)
+
+	// check the locators of the best path
+	locators, distance := hr.Locators()
+	assert.Equal(t, []types.Hash{header2.Hash, header1.Hash, header0.Hash}, locators)
+	assert.Equal(t, uint64(21), distance)
+
+	// check the headers following the locators we share
+	headers, err := hr.Following([]types.Hash{header21.Hash, types.Hash{0xff}, header0.Hash}, types.ZeroHash, 10)
+	assert.Nil(t, err)
+	if assert.Len(t, headers, 2) {
+		assert.Equal(t, header1.Hash, headers[0].Hash)
+		assert.Equal(t, header2.Hash, headers[1].Hash)
+	}
+
+	// return nothing if we share no locator
+	headers, err = hr.Following([]types.Hash{header21.Hash}, types.ZeroHash, 10)
+	assert.Nil(t, err)
+	assert.Empty(t, headers)
+}
+
+func TestPersistentSince(t *testing.T) {
+
+	// create entities
+	header0 := &types.Header{Hash: types.Hash{0x0, 0x1}, Diff: 1}
+	header1 := &types.Header{Hash: types.Hash{0x1}, Parent: header0.Hash, Diff: 10}
+	header2 := &types.Header{Hash: types.Hash{0x2}, Parent: header1.Hash, Diff: 10}
+	header21 := &types.Header{Hash: types.Hash{0x21}, Parent: header1.Hash, Diff: 5}
+
+	// initialize the repository without cache, so we always hit the store
+	hr, err := NewPersistent(kv.NewMemory(), store.NewEncoding(), header0, 0)
+	require.Nil(t, err)
+	_, _ = hr.Add(header1)
+	_, _ = hr.Add(header2)
+	_, _ = hr.Add(header21)
+
+	// check the best path from a header on it
+	path, err := hr.Since(header1.Hash)
+	assert.Nil(t, err)
+	assert.Equal(t, []types.Hash{header1.Hash, header2.Hash}, path)
+
+	// headers on other branches have no best path following them
+	_, err = hr.Since(header21.Hash)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrPath, errors.Cause(err))
+	}
+}
+
+func TestPersistentInvalidate(t *testing.T) {
+
+	// create entities
+	header0 := &types.Header{Hash: types.Hash{0x0, 0x1}, Diff: 1}
+	header1 := &types.Header{Hash: types.Hash{0x1}, Parent: header0.Hash, Diff: 10}
+	header2 := &types.Header{Hash: types.Hash{0x2}, Parent: header1.Hash, Diff: 10}
+	header3 := &types.Header{Hash: types.Hash{0x3}, Parent: header2.Hash, Diff: 10}
+	header21 := &types.Header{Hash: types.Hash{0x21}, Parent: header1.Hash, Diff: 5}
+	header22 := &types.Header{Hash: types.Hash{0x22}, Parent: header1.Hash, Diff: 8}
+	header4 := &types.Header{Hash: types.Hash{0x4}, Parent: header3.Hash, Diff: 10}
+
+	// initialize the repository without cache, so we always hit the store
+	hr, err := NewPersistent(kv.NewMemory(), store.NewEncoding(), header0, 0)
+	require.Nil(t, err)
+	_, _ = hr.Add(header1)
+	_, _ = hr.Add(header2)
+	_, _ = hr.Add(header3)
+	_, _ = hr.Add(header21)
+	_, _ = hr.Add(header22)
+
+	// invalidating a header on a side branch keeps the best path
+	reorg, err := hr.Invalidate(header21.Hash)
+	assert.Nil(t, err)
+	assert.Nil(t, reorg)
+
+	// invalidating a header on the best path switches to the best valid one
+	reorg, err = hr.Invalidate(header2.Hash)
+	assert.Nil(t, err)
+	assert.Equal(t, &Reorg{Old: header3.Hash, New: header22.Hash, Ancestor: header1.Hash}, reorg)
+	path, distance := hr.Path()
+	assert.Equal(t, []types.Hash{header22.Hash, header1.Hash, header0.Hash}, path)
+	assert.Equal(t, uint64(19), distance)
+
+	// descendants of invalid headers are refused
+	_, err = hr.Add(header4)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrInvalid, errors.Cause(err))
+	}
+
+	// the root can't be invalid
+	_, err = hr.Invalidate(header0.Hash)
+	assert.NotNil(t, err)
+}
+
+func TestPersistentConcurrency(t *testing.T) {
+
+	// initialize the repository with a small cache
+	root := &types.Header{Hash: types.Hash{0xff}}
+	hr, err := NewPersistent(kv.NewMemory(), store.NewEncoding(), root, 16)
+	require.Nil(t, err)
+
+	// add a separate branch from each goroutine while reading concurrently
+	wg := &sync.WaitGroup{}
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			parent := root.Hash
+			for i := 0; i < 32; i++ {
+				header := &types.Header{Hash: types.Hash{byte(g), byte(i)}, Parent: parent, Diff: 1}
+				_, err := hr.Add(header)
+				assert.Nil(t, err)
+				assert.True(t, hr.Has(header.Hash))
+				_, err = hr.Get(header.Hash)
+				assert.Nil(t, err)
+				_, err = hr.Height(header.Hash)
+				assert.Nil(t, err)
+				hr.Path()
+				hr.Recent(8)
+				parent = header.Hash
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	// check that the best path has the full length
+	path, distance := hr.Path()
+	assert.Len(t, path, 33)
+	assert.Equal(t, uint64(32), distance)
+}
@@ -18,77 +18,160 @@
 package headers
 
 import (
+	"sync"
+
 	"github.com/pkg/errors"
 
 	"github.com/alvalor/alvalor-go/types"
 )
 
 // Repo manages block headers and the best path through the tree of
-// headers by using a topological sort of the headers to identify the path with
-// the longest distance.
+// headers. It keeps track of the cumulative difficulty of each header and of
+// the best path incrementally, so that the best path is available without
+// traversing the whole tree.
 type Repo struct {
-	root     types.Hash
-	headers  map[types.Hash]*types.Header
-	children map[types.Hash][]types.Hash
-	pending  map[types.Hash][]*types.Header
+	sync.Mutex
+	root      types.Hash
+	headers   map[types.Hash]*types.Header
+	children  map[types.Hash][]types.Hash
+	distances map[types.Hash]uint64
+	heights   map[types.Hash]uint64
+	best      []types.Hash
+}
+
+// Reorg describes a change of the best path, from the old tip to the new tip,
+// with the last header both paths have in common. If the new path simply
+// extends the old one, the common ancestor is the old tip.
+type Reorg struct {
+	Old      types.Hash
+	New      types.Hash
+	Ancestor types.Hash
 }
 
-// NewRepo creates a new simple header store.
-// TODO: load the headers from disk on startup
+// NewRepo creates a new in-memory header store with the given root header.
 func NewRepo(root *types.Header) *Repo {
 	hr := &Repo{
-		root:     root.Hash,
-		headers:  make(map[types.Hash]*types.Header),
-		children: make(map[types.Hash][]types.Hash),
-		pending:  make(map[types.Hash][]*types.Header),
+		root:      root.Hash,
+		headers:   make(map[types.Hash]*types.Header),
+		children:  make(map[types.Hash][]types.Hash),
+		distances: make(map[types.Hash]uint64),
+		heights:   make(map[types.Hash]uint64),
+		best:      []types.Hash{root.Hash},
 	}
 	hr.headers[root.Hash] = root
+	hr.distances[root.Hash] = root.Diff
+	hr.heights[root.Hash] = 0
 	return hr
 }
 
-// Add adds a new header to the graph.
-func (hr *Repo) Add(header *types.Header) error {
+// Add adds a new header to the graph. If the header changes the best path, the
+// change is returned as a reorg. Headers with unknown parent are refused, so
+// that they can be kept in a bounded orphan pool until the parent is known.
+func (hr *Repo) Add(header *types.Header) (*Reorg, error) {
+	hr.Lock()
+	defer hr.Unlock()
 
 	// if we already know the header, fail
 	_, ok := hr.headers[header.Hash]
 	if ok {
-		return errors.Wrap(ErrExist, "header already known")
+		return nil, errors.Wrap(ErrExist, "header already known")
 	}
 
-	// if we don't know the parent, add to pending headers and skip rest
-	_, ok = hr.headers[header.Parent]
+	// if we don't know the parent, refuse the header
+	parent, ok := hr.headers[header.Parent]
 	if !ok {
-		hr.pending[header.Parent] = append(hr.pending[header.Parent], header)
-		return nil
+		return nil, errors.Wrap(ErrOrphan, "header parent unknown")
 	}
 
 	// if we have the parent, add it to its children and register header
 	hr.children[header.Parent] = append(hr.children[header.Parent], header.Hash)
 	hr.headers[header.Hash] = header
+	hr.distances[header.Hash] = hr.distances[parent.Hash] + header.Diff
+	hr.heights[header.Hash] = hr.heights[parent.Hash] + 1
 
-	// then check if any pending headers have this header as parent
-	children, ok := hr.pending[header.Hash]
-	if ok {
-		delete(hr.pending, header.Hash)
-		for _, child := range children {
-			_ = hr.Add(child)
-		}
+	// if the header has more total difficulty than our tip, switch to it
+	old := hr.tip()
+	if hr.distances[header.Hash] > hr.distances[old] {
+		hr.follow(header.Hash)
 	}
 
-	return nil
+	// if the tip did not change, there is nothing to report
+	tip := hr.tip()
+	if tip == old {
+		return nil, nil
+	}
+
+	// find the last header of the old path that is still on the best path
+	ancestor := old
+	for !hr.onPath(ancestor) {
+		ancestor = hr.headers[ancestor].Parent
+	}
+	reorg := &Reorg{
+		Old:      old,
+		New:      tip,
+		Ancestor: ancestor,
+	}
+
+	return reorg, nil
+}
+
+// follow switches the best path to end at the header with the given hash,
+// only replacing the part after the fork with the current best path.
+func (hr *Repo) follow(hash types.Hash) {
+	var branch []types.Hash
+	for !hr.onPath(hash) {
+		branch = append(branch, hash)
+		hash = hr.headers[hash].Parent
+	}
+	hr.best = hr.best[:hr.heights[hash]+1]
+	for i := len(branch) - 1; i >= 0; i-- {
+		hr.best = append(hr.best, branch[i])
+	}
+}
+
+// onPath checks whether the header with the given hash is on the best path.
+func (hr *Repo) onPath(hash types.Hash) bool {
+	height, ok := hr.heights[hash]
+	if !ok || height >= uint64(len(hr.best)) {
+		return false
+	}
+	return hr.best[height] == hash
+}
+
+// tip returns the hash of the last header on the best path.
+func (hr *Repo) tip() types.Hash {
+	return hr.best[len(hr.best)-1]
 }
 
 // Has checks if the given hash is already known.
 func (hr *Repo) Has(hash types.Hash) bool {
+	hr.Lock()
+	defer hr.Unlock()
+
 	_, ok := hr.headers[hash]
 	return ok
 }
 
 // Get returns the header with the given hash.
 func (hr *Repo) Get(hash types.Hash) (*types.Header, error) {
+	hr.Lock()
+	defer hr.Unlock()
+
 	header, ok := hr.headers[hash]
 	if !ok {
 		return nil, errors.Wrap(ErrNotExist, "header not found")
 	}
 	return header, nil
 }
+
+// Height returns the height of the header with the given hash.
+func (hr *Repo) Height(hash types.Hash) (uint64, error) {
+	hr.Lock()
+	defer hr.Unlock()
+
+	height, ok := hr.heights[hash]
+	if !ok {
+		return 0, errors.Wrap(ErrNotExist, "header not found")
+	}
+	return height, nil
+}
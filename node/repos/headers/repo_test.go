@@ -18,6 +18,7 @@
 package headers
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/alvalor/alvalor-go/types"
@@ -41,7 +42,7 @@ func TestRepoAddExisting(t *testing.T) {
 	hr.headers[header.Hash] = &types.Header{}
 
 	// try adding header already known and check outcome
-	err := hr.Add(header)
+	_, err := hr.Add(header)
 	if assert.NotNil(t, err) {
 		assert.Equal(t, ErrExist, errors.Cause(err))
 	}
@@ -50,12 +51,12 @@ func TestRepoAddExisting(t *testing.T) {
 	}
 }
 
-func TestRepoAddPending(t *testing.T) {
+func TestRepoAddOrphan(t *testing.T) {
 
 	// initialize the repository with required maps
 	hr := &Repo{
 		headers: make(map[types.Hash]*types.Header),
-		pending: make(map[types.Hash][]*types.Header),
+		best:    []types.Hash{{0x3}},
 	}
 
 	// create entities and set up state
@@ -65,23 +66,15 @@ func TestRepoAddPending(t *testing.T) {
 	header := &types.Header{Hash: hash1, Parent: hash2}
 
 	// try adding header with missing parent and check outcome
-	err := hr.Add(header)
-	assert.Nil(t, err)
-	assert.Empty(t, hr.headers)
-	if assert.Len(t, hr.pending, 1) {
-		assert.ElementsMatch(t, hr.pending[header.Parent], []*types.Header{header})
+	_, err := hr.Add(header)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrOrphan, errors.Cause(err))
 	}
+	assert.Empty(t, hr.headers)
 }
 
 func TestRepoAddValid(t *testing.T) {
 
-	// initialize the repository with required maps
-	hr := &Repo{
-		headers:  make(map[types.Hash]*types.Header),
-		pending:  make(map[types.Hash][]*types.Header),
-		children: make(map[types.Hash][]types.Hash),
-	}
-
 	// create entities and set up state
 	hash1 := types.Hash{0x1}
 	hash2 := types.Hash{0x2}
@@ -89,10 +82,11 @@ func TestRepoAddValid(t *testing.T) {
 	header := &types.Header{Hash: hash1, Parent: hash2}
 	parent := &types.Header{Hash: hash2}
 
-	hr.headers[parent.Hash] = parent
+	// initialize the repository with the parent as root
+	hr := NewRepo(parent)
 
 	// try adding header with existing parent and check outcome
-	err := hr.Add(header)
+	_, err := hr.Add(header)
 	assert.Nil(t, err)
 	if assert.Len(t, hr.headers, 2) {
 		assert.Equal(t, hr.headers[header.Hash], header)
@@ -100,45 +94,6 @@ func TestRepoAddValid(t *testing.T) {
 	if assert.Len(t, hr.children, 1) {
 		assert.ElementsMatch(t, hr.children[parent.Hash], []types.Hash{header.Hash})
 	}
-	assert.Empty(t, hr.pending)
-}
-
-func TestRepoAddValidWithPending(t *testing.T) {
-
-	// initialize the repository with required maps
-	hr := &Repo{
-		headers:  make(map[types.Hash]*types.Header),
-		pending:  make(map[types.Hash][]*types.Header),
-		children: make(map[types.Hash][]types.Hash),
-	}
-
-	// create entities and set up state
-	hash1 := types.Hash{0x1}
-	hash2 := types.Hash{0x2}
-	hash3 := types.Hash{0x3}
-	hash4 := types.Hash{0x4}
-
-	header := &types.Header{Hash: hash1, Parent: hash2}
-	parent := &types.Header{Hash: hash2}
-	child1 := &types.Header{Hash: hash3, Parent: hash1}
-	child2 := &types.Header{Hash: hash4, Parent: hash1}
-
-	hr.headers[parent.Hash] = parent
-	hr.pending[header.Hash] = []*types.Header{child1, child2}
-
-	// try adding header with existing parent and pending children and check outcome
-	err := hr.Add(header)
-	assert.Nil(t, err)
-	if assert.Len(t, hr.headers, 4) {
-		assert.Equal(t, hr.headers[hash1], header)
-		assert.Equal(t, hr.headers[hash3], child1)
-		assert.Equal(t, hr.headers[hash4], child2)
-	}
-	if assert.Len(t, hr.children, 2) {
-		assert.ElementsMatch(t, hr.children[parent.Hash], []types.Hash{header.Hash})
-		assert.ElementsMatch(t, hr.children[header.Hash], []types.Hash{child1.Hash, child2.Hash})
-	}
-	assert.Empty(t, hr.pending)
 }
 
 func TestRepoHasExisting(t *testing.T) {
@@ -214,3 +169,61 @@ func TestRepoGetMissing(t *testing.T) {
 		assert.Equal(t, ErrNotExist, errors.Cause(err))
 	}
 }
+
+func TestRepoHeight(t *testing.T) {
+
+	// create entities
+	header0 := &types.Header{Hash: types.Hash{0x0, 0x1}}
+	header1 := &types.Header{Hash: types.Hash{0x1}, Parent: header0.Hash}
+
+	// initialize the repository
+	hr := NewRepo(header0)
+	_, _ = hr.Add(header1)
+
+	// check the heights
+	height, err := hr.Height(header1.Hash)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), height)
+
+	_, err = hr.Height(types.Hash{0x2})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrNotExist, errors.Cause(err))
+	}
+}
+
+func TestRepoConcurrency(t *testing.T) {
+
+	// initialize the repository with a root header
+	root := &types.Header{Hash: types.Hash{0xff}}
+	hr := NewRepo(root)
+
+	// add a separate branch from each goroutine while reading concurrently
+	wg := &sync.WaitGroup{}
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			parent := root.Hash
+			for i := 0; i < 64; i++ {
+				header := &types.Header{Hash: types.Hash{byte(g), byte(i)}, Parent: parent, Diff: 1}
+				_, err := hr.Add(header)
+				assert.Nil(t, err)
+				assert.True(t, hr.Has(header.Hash))
+				_, err = hr.Get(header.Hash)
+				assert.Nil(t, err)
+				_, err = hr.Height(header.Hash)
+				assert.Nil(t, err)
+				hr.Path()
+				hr.Recent(8)
+				parent = header.Hash
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	// check that every header was added and the best path has the full length
+	assert.Len(t, hr.headers, 16*64+1)
+	path, distance := hr.Path()
+	assert.Len(t, path, 65)
+	assert.Equal(t, uint64(64), distance)
+}
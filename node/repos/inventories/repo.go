@@ -18,6 +18,8 @@
 package inventories
 
 import (
+	"sync"
+
 	"github.com/pkg/errors"
 
 	"github.com/alvalor/alvalor-go/types"
@@ -25,6 +27,7 @@ import (
 
 // Repo is a simple implementation of the inventory store.
 type Repo struct {
+	sync.Mutex
 	inventories map[types.Hash]*types.Inventory
 }
 
@@ -38,6 +41,9 @@ func NewRepo() *Repo {
 
 // Add stores a new inventory.
 func (repo *Repo) Add(inv *types.Inventory) error {
+	repo.Lock()
+	defer repo.Unlock()
+
 	_, ok := repo.inventories[inv.Hash]
 	if ok {
 		return errors.Wrap(ErrExist, "inventory already exists")
@@ -48,12 +54,18 @@ func (repo *Repo) Add(inv *types.Inventory) error {
 
 // Has checks if a given inventory is known.
 func (repo *Repo) Has(hash types.Hash) bool {
+	repo.Lock()
+	defer repo.Unlock()
+
 	_, ok := repo.inventories[hash]
 	return ok
 }
 
 // Get retrieves the inventory with the given block hash.
 func (repo *Repo) Get(hash types.Hash) (*types.Inventory, error) {
+	repo.Lock()
+	defer repo.Unlock()
+
 	inv, ok := repo.inventories[hash]
 	if !ok {
 		return nil, errors.Wrap(ErrNotExist, "inventory does not exist")
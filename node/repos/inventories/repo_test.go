@@ -18,6 +18,7 @@
 package inventories
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/alvalor/alvalor-go/types"
@@ -109,3 +110,30 @@ func TestRepoGet(t *testing.T) {
 		assert.Equal(t, ErrNotExist, errors.Cause(err2))
 	}
 }
+
+func TestRepoConcurrency(t *testing.T) {
+
+	// initialize the repository
+	repo := NewRepo()
+
+	// add and read inventories from many goroutines
+	wg := &sync.WaitGroup{}
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 64; i++ {
+				inv := &types.Inventory{Hash: types.Hash{byte(g), byte(i)}}
+				err := repo.Add(inv)
+				assert.Nil(t, err)
+				assert.True(t, repo.Has(inv.Hash))
+				_, err = repo.Get(inv.Hash)
+				assert.Nil(t, err)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	// check conditions
+	assert.Len(t, repo.inventories, 16*64)
+}
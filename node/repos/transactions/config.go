@@ -0,0 +1,90 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package transactions
+
+import "time"
+
+// Config represents the limits of the transaction pool.
+type Config struct {
+	maxCount     uint
+	maxSize      uint
+	maxTxSize    uint
+	maxPerSender uint
+	ttl          time.Duration
+	bump         uint
+}
+
+// DefaultConfig returns the default limits of the transaction pool.
+func DefaultConfig() Config {
+	return Config{
+		maxCount:     50000,
+		maxSize:      64 << 20,
+		maxTxSize:    128 << 10,
+		maxPerSender: 64,
+		ttl:          3 * time.Hour,
+		bump:         10,
+	}
+}
+
+// SetMaxCount allows us to configure the maximum number of transactions in
+// the pool.
+func SetMaxCount(maxCount uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxCount = maxCount
+	}
+}
+
+// SetMaxSize allows us to configure the maximum total encoded size of the
+// transactions in the pool.
+func SetMaxSize(maxSize uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxSize = maxSize
+	}
+}
+
+// SetMaxTxSize allows us to configure the maximum encoded size of a single
+// transaction.
+func SetMaxTxSize(maxTxSize uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxTxSize = maxTxSize
+	}
+}
+
+// SetMaxPerSender allows us to configure the maximum number of transactions
+// from a single sender.
+func SetMaxPerSender(maxPerSender uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxPerSender = maxPerSender
+	}
+}
+
+// SetTTL allows us to configure how long a transaction stays in the pool.
+func SetTTL(ttl time.Duration) func(*Config) {
+	return func(cfg *Config) {
+		cfg.ttl = ttl
+	}
+}
+
+// SetBump allows us to configure by how many percent the fee rate of a
+// transaction has to exceed the one of a conflicting transaction from the same
+// sender with the same nonce to replace it.
+func SetBump(bump uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.bump = bump
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package transactions
+
+import (
+	"container/list"
+	"time"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// entry represents a transaction in the pool together with the information we
+// need to order and evict it.
+type entry struct {
+	tx     *types.Transaction
+	sender string
+	size   uint
+	fee    uint64
+	added  time.Time
+	index  int
+	age    *list.Element
+}
+
+// rate returns the fee per byte paid by the transaction.
+func (e *entry) rate() float64 {
+	return float64(e.fee) / float64(e.size)
+}
+
+// sender returns the account sending the transaction, which is the first
+// account paying a fee or, if there is none, the first account transferring
+// value.
+func sender(tx *types.Transaction) string {
+	if len(tx.Fees) > 0 {
+		return string(tx.Fees[0].From)
+	}
+	if len(tx.Transfers) > 0 {
+		return string(tx.Transfers[0].From)
+	}
+	return ""
+}
+
+// entries is a min-heap of pool entries ordered by fee rate, so that the
+// lowest paying transaction is at the top.
+type entries []*entry
+
+func (es entries) Len() int {
+	return len(es)
+}
+
+func (es entries) Less(i int, j int) bool {
+	return es[i].rate() < es[j].rate()
+}
+
+func (es entries) Swap(i int, j int) {
+	es[i], es[j] = es[j], es[i]
+	es[i].index = i
+	es[j].index = j
+}
+
+func (es *entries) Push(x interface{}) {
+	e := x.(*entry)
+	e.index = len(*es)
+	*es = append(*es, e)
+}
+
+func (es *entries) Pop() interface{} {
+	old := *es
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*es = old[:len(old)-1]
+	e.index = -1
+	return e
+}
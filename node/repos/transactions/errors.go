@@ -21,6 +21,10 @@ import "errors"
 
 // Errors exported by the package.
 var (
-	ErrExist    = errors.New("transaction already exists")
-	ErrNotExist = errors.New("transaction does not exist")
+	ErrExist       = errors.New("transaction already exists")
+	ErrNotExist    = errors.New("transaction does not exist")
+	ErrTooLarge    = errors.New("transaction too large")
+	ErrUnderpriced = errors.New("transaction fee too low")
+	ErrSenderLimit = errors.New("too many transactions from sender")
+	ErrFee         = errors.New("transaction fee invalid")
 )
@@ -18,45 +18,323 @@
 package transactions
 
 import (
-	"github.com/alvalor/alvalor-go/types"
+	"bytes"
+	"container/heap"
+	"container/list"
+	"sort"
+	"sync"
+	"time"
+
 	"github.com/pkg/errors"
+
+	"github.com/alvalor/alvalor-go/types"
 )
 
-// Repo represents the repository for transactions.
+// Repo represents the repository for transactions, which serves as our pool of
+// unconfirmed transactions. It is bounded in count and size; when it is full,
+// the transactions with the lowest fee per byte are evicted first. Entries are
+// also kept in the order they were added, so that expiring them only looks at
+// the ones that are due.
 type Repo struct {
-	txs map[types.Hash]*types.Transaction
+	sync.Mutex
+	cfg       Config
+	codec     Codec
+	validator Validator
+	txs       map[types.Hash]*entry
+	senders   map[string]map[uint64]*entry
+	heap      entries
+	ages      *list.List
+	size      uint
+	evicted   []types.Hash
 }
 
-// NewRepo creates a new repository for transactions.
-func NewRepo() *Repo {
+// NewRepo creates a new repository for transactions, which uses the default
+// limits unless they are modified by the given options.
+func NewRepo(codec Codec, validator Validator, options ...func(*Config)) *Repo {
+	cfg := DefaultConfig()
+	for _, option := range options {
+		option(&cfg)
+	}
 	repo := &Repo{
-		txs: make(map[types.Hash]*types.Transaction),
+		cfg:       cfg,
+		codec:     codec,
+		validator: validator,
+		txs:       make(map[types.Hash]*entry),
+		senders:   make(map[string]map[uint64]*entry),
+		ages:      list.New(),
 	}
 	return repo
 }
 
-// Add adds a transaction to the transaction pool.
+// Add adds a transaction to the transaction pool. The transaction has to be
+// signed by its senders. A transaction with the same sender and nonce as a
+// pending one replaces it only if it pays a sufficiently higher fee rate. If
+// the pool is full, transactions with a lower fee rate are evicted to make
+// room.
 func (repo *Repo) Add(tx *types.Transaction) error {
+	repo.Lock()
+	defer repo.Unlock()
+
 	_, ok := repo.txs[tx.Hash]
 	if ok {
 		return errors.Wrap(ErrExist, "transaction already known")
 	}
 
-	repo.txs[tx.Hash] = tx
+	// only verified senders can replace transactions or fill their quota
+	err := repo.validator.Validate(tx)
+	if err != nil {
+		return errors.Wrap(err, "could not validate transaction")
+	}
+
+	// drop expired transactions before we check the limits
+	repo.expire()
+
+	// determine the encoded size and fee of the transaction
+	buf := &bytes.Buffer{}
+	err = repo.codec.Encode(buf, tx)
+	if err != nil {
+		return errors.Wrap(err, "could not encode transaction")
+	}
+	e := &entry{
+		tx:     tx,
+		sender: sender(tx),
+		size:   uint(buf.Len()),
+		added:  time.Now(),
+		index:  -1,
+	}
+	for _, fee := range tx.Fees {
+		if e.fee+fee.Amount < e.fee {
+			return errors.Wrap(ErrFee, "transaction fee overflows")
+		}
+		e.fee += fee.Amount
+	}
+	if e.size > repo.cfg.maxTxSize || e.size > repo.cfg.maxSize {
+		return errors.Wrapf(ErrTooLarge, "transaction exceeds size limit (%d)", e.size)
+	}
+
+	// check a conflicting transaction is replaced by a higher fee rate
+	nonces := repo.senders[e.sender]
+	conflict, ok := nonces[tx.Nonce]
+	if ok {
+		min := conflict.rate() * float64(100+repo.cfg.bump) / 100
+		if e.rate() < min {
+			return errors.Wrapf(ErrUnderpriced, "replacement fee rate too low (%f < %f)", e.rate(), min)
+		}
+	}
+
+	// check the sender does not exceed its limit
+	if !ok && uint(len(nonces)) >= repo.cfg.maxPerSender {
+		return errors.Wrapf(ErrSenderLimit, "sender limit reached (%d)", len(nonces))
+	}
+
+	// find the transactions we have to evict to make room
+	count := uint(len(repo.txs))
+	size := repo.size
+	if ok {
+		count--
+		size -= conflict.size
+	}
+	var evict []*entry
+	var popped []*entry
+	for count+1 > repo.cfg.maxCount || size+e.size > repo.cfg.maxSize {
+		if repo.heap.Len() == 0 {
+			break
+		}
+		lowest := heap.Pop(&repo.heap).(*entry)
+		popped = append(popped, lowest)
+		if lowest == conflict {
+			continue
+		}
+		if lowest.rate() >= e.rate() {
+			break
+		}
+		evict = append(evict, lowest)
+		count--
+		size -= lowest.size
+	}
+	for _, lowest := range popped {
+		heap.Push(&repo.heap, lowest)
+	}
+	if count+1 > repo.cfg.maxCount || size+e.size > repo.cfg.maxSize {
+		return errors.Wrap(ErrUnderpriced, "fee rate too low for full pool")
+	}
+
+	// remove the replaced and the evicted transactions
+	if ok {
+		repo.evict(conflict)
+	}
+	for _, lowest := range evict {
+		repo.evict(lowest)
+	}
+
+	// insert the new transaction
+	repo.insert(e)
+
 	return nil
 }
 
 // Has checks whether a transaction exists in the transaction pool.
 func (repo *Repo) Has(hash types.Hash) bool {
+	repo.Lock()
+	defer repo.Unlock()
 	_, ok := repo.txs[hash]
 	return ok
 }
 
 // Get retrieves a transaction from the transaction pool.
 func (repo *Repo) Get(hash types.Hash) (*types.Transaction, error) {
-	tx, ok := repo.txs[hash]
+	repo.Lock()
+	defer repo.Unlock()
+	e, ok := repo.txs[hash]
 	if !ok {
 		return nil, errors.Wrap(ErrNotExist, "could not find transaction")
 	}
-	return tx, nil
+	return e.tx, nil
+}
+
+// Remove removes a transaction from the transaction pool.
+func (repo *Repo) Remove(hash types.Hash) error {
+	repo.Lock()
+	defer repo.Unlock()
+	e, ok := repo.txs[hash]
+	if !ok {
+		return errors.Wrap(ErrNotExist, "could not find transaction to remove")
+	}
+	repo.remove(e)
+	return nil
+}
+
+// Count returns the number of transactions in the pool.
+func (repo *Repo) Count() uint {
+	repo.Lock()
+	defer repo.Unlock()
+	repo.expire()
+	return uint(len(repo.txs))
+}
+
+// Size returns the total encoded size of the transactions in the pool.
+func (repo *Repo) Size() uint {
+	repo.Lock()
+	defer repo.Unlock()
+	repo.expire()
+	return repo.size
+}
+
+// Expire removes all transactions that have been in the pool for longer than
+// the configured time to live.
+func (repo *Repo) Expire() {
+	repo.Lock()
+	defer repo.Unlock()
+	repo.expire()
+}
+
+// Evicted returns the hashes of the transactions that were evicted from the
+// pool without being included in a block since the last call. At most as many
+// hashes as the pool can hold are kept, dropping the oldest ones first.
+func (repo *Repo) Evicted() []types.Hash {
+	repo.Lock()
+	defer repo.Unlock()
+	repo.expire()
+	evicted := repo.evicted
+	repo.evicted = nil
+	return evicted
+}
+
+// Best returns up to the given number of transactions with the highest fee
+// rate, for example to build a block. Transactions of the same sender are
+// returned in the order of their nonces.
+func (repo *Repo) Best(n uint) []*types.Transaction {
+	repo.Lock()
+	defer repo.Unlock()
+
+	repo.expire()
+
+	// order all transactions by fee rate
+	best := make([]*entry, 0, len(repo.txs))
+	for _, e := range repo.txs {
+		best = append(best, e)
+	}
+	sort.Slice(best, func(i int, j int) bool {
+		if best[i].rate() != best[j].rate() {
+			return best[i].rate() > best[j].rate()
+		}
+		return best[i].added.Before(best[j].added)
+	})
+	if uint(len(best)) > n {
+		best = best[:n]
+	}
+
+	// order the transactions of each sender by nonce, keeping their positions
+	positions := make(map[string][]int)
+	for i, e := range best {
+		positions[e.sender] = append(positions[e.sender], i)
+	}
+	txs := make([]*types.Transaction, len(best))
+	for _, indices := range positions {
+		sorted := make([]*entry, 0, len(indices))
+		for _, index := range indices {
+			sorted = append(sorted, best[index])
+		}
+		sort.Slice(sorted, func(i int, j int) bool {
+			return sorted[i].tx.Nonce < sorted[j].tx.Nonce
+		})
+		for i, index := range indices {
+			txs[index] = sorted[i].tx
+		}
+	}
+
+	return txs
+}
+
+// insert adds an entry to all indices of the pool.
+func (repo *Repo) insert(e *entry) {
+	repo.txs[e.tx.Hash] = e
+	nonces, ok := repo.senders[e.sender]
+	if !ok {
+		nonces = make(map[uint64]*entry)
+		repo.senders[e.sender] = nonces
+	}
+	nonces[e.tx.Nonce] = e
+	heap.Push(&repo.heap, e)
+	e.age = repo.ages.PushBack(e)
+	repo.size += e.size
+}
+
+// remove removes an entry from all indices of the pool.
+func (repo *Repo) remove(e *entry) {
+	delete(repo.txs, e.tx.Hash)
+	nonces := repo.senders[e.sender]
+	delete(nonces, e.tx.Nonce)
+	if len(nonces) == 0 {
+		delete(repo.senders, e.sender)
+	}
+	heap.Remove(&repo.heap, e.index)
+	repo.ages.Remove(e.age)
+	repo.size -= e.size
+}
+
+// evict removes an entry that was replaced, pushed out or expired and keeps
+// track of its hash.
+func (repo *Repo) evict(e *entry) {
+	repo.remove(e)
+	repo.evicted = append(repo.evicted, e.tx.Hash)
+	if uint(len(repo.evicted)) > repo.cfg.maxCount {
+		repo.evicted = repo.evicted[1:]
+	}
+}
+
+// expire removes the entries that have passed their time to live, starting
+// with the oldest one until we reach one that is still alive.
+func (repo *Repo) expire() {
+	if repo.cfg.ttl == 0 {
+		return
+	}
+	cutoff := time.Now().Add(-repo.cfg.ttl)
+	for repo.ages.Len() > 0 {
+		e := repo.ages.Front().Value.(*entry)
+		if !e.added.Before(cutoff) {
+			return
+		}
+		repo.evict(e)
+	}
 }
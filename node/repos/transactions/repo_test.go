@@ -18,88 +18,105 @@
 package transactions
 
 import (
+	"fmt"
+	"math"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/alvalor/alvalor-go/node/validation"
+	"github.com/alvalor/alvalor-go/store"
 	"github.com/alvalor/alvalor-go/types"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ed25519"
 )
 
+func newKey(name string) (ed25519.PublicKey, ed25519.PrivateKey) {
+	seed := make([]byte, ed25519.SeedSize)
+	copy(seed, name)
+	priv := ed25519.NewKeyFromSeed(seed)
+	return priv.Public().(ed25519.PublicKey), priv
+}
+
+func sign(tx *types.Transaction, priv ed25519.PrivateKey) {
+	tx.Hash = tx.GetHash()
+	tx.Signatures = [][]byte{ed25519.Sign(priv, tx.Hash[:])}
+}
+
+func newTx(from string, nonce uint64, fee uint64) *types.Transaction {
+	pub, priv := newKey(from)
+	tx := &types.Transaction{
+		Fees:  []*types.Fee{{From: pub, Amount: fee}},
+		Nonce: nonce,
+	}
+	sign(tx, priv)
+	return tx
+}
+
 func TestNewRepo(t *testing.T) {
-	repo := NewRepo()
+	repo := NewRepo(store.NewEncoding(), validation.NewTransaction())
 	assert.NotNil(t, repo.txs)
+	assert.NotNil(t, repo.senders)
 }
 
 func TestRepoAdd(t *testing.T) {
 
-	// initialize the repository with required maps
-	repo := &Repo{
-		txs: make(map[types.Hash]*types.Transaction),
-	}
+	// initialize the repository
+	repo := NewRepo(store.NewEncoding(), validation.NewTransaction())
 
 	// create entities and set up state
-	hash1 := types.Hash{0x1}
-	hash2 := types.Hash{0x2}
+	tx1 := newTx("alice", 1, 10)
+	tx2 := newTx("alice", 2, 10)
 
-	tx1 := &types.Transaction{Hash: hash1}
-	tx2 := &types.Transaction{Hash: hash2}
-
-	repo.txs[hash2] = &types.Transaction{}
+	err := repo.Add(tx1)
+	assert.Nil(t, err)
 
 	// execute add
 	err1 := repo.Add(tx1)
 	err2 := repo.Add(tx2)
 
-	assert.Nil(t, err1)
-	if assert.NotNil(t, err2) {
-		assert.Equal(t, ErrExist, errors.Cause(err2))
-	}
-	if assert.Len(t, repo.txs, 2) {
-		assert.Equal(t, tx1, repo.txs[hash1])
-		assert.NotEqual(t, tx2, repo.txs[hash2])
+	// check conditions
+	if assert.NotNil(t, err1) {
+		assert.Equal(t, ErrExist, errors.Cause(err1))
 	}
+	assert.Nil(t, err2)
+	assert.Equal(t, uint(2), repo.Count())
+	assert.True(t, repo.Size() > 0)
 }
 
 func TestRepoHas(t *testing.T) {
 
-	// initialize the repository with required maps
-	repo := &Repo{
-		txs: make(map[types.Hash]*types.Transaction),
-	}
+	// initialize the repository
+	repo := NewRepo(store.NewEncoding(), validation.NewTransaction())
 
 	// create entities and set up state
-	hash1 := types.Hash{0x1}
-	hash2 := types.Hash{0x2}
+	tx1 := newTx("alice", 1, 10)
+	tx2 := newTx("alice", 2, 10)
 
-	repo.txs[hash1] = &types.Transaction{}
-
-	// execute has
-	ok1 := repo.Has(hash1)
-	ok2 := repo.Has(hash2)
+	err := repo.Add(tx1)
+	assert.Nil(t, err)
 
 	// check conditions
-	assert.True(t, ok1)
-	assert.False(t, ok2)
+	assert.True(t, repo.Has(tx1.Hash))
+	assert.False(t, repo.Has(tx2.Hash))
 }
 
 func TestRepoGet(t *testing.T) {
 
-	// initialize the repository with required maps
-	repo := &Repo{
-		txs: make(map[types.Hash]*types.Transaction),
-	}
+	// initialize the repository
+	repo := NewRepo(store.NewEncoding(), validation.NewTransaction())
 
 	// create entities and set up state
-	hash1 := types.Hash{0x1}
-	hash2 := types.Hash{0x2}
+	tx1 := newTx("alice", 1, 10)
+	tx2 := newTx("alice", 2, 10)
 
-	tx1 := &types.Transaction{Hash: hash1}
-
-	repo.txs[hash1] = tx1
+	err := repo.Add(tx1)
+	assert.Nil(t, err)
 
 	// execute get
-	out1, err1 := repo.Get(hash1)
-	_, err2 := repo.Get(hash2)
+	out1, err1 := repo.Get(tx1.Hash)
+	_, err2 := repo.Get(tx2.Hash)
 
 	// check conditions
 	assert.Nil(t, err1)
@@ -109,3 +126,307 @@ func TestRepoGet(t *testing.T) {
 		assert.Equal(t, ErrNotExist, errors.Cause(err2))
 	}
 }
+
+func TestRepoRemove(t *testing.T) {
+
+	// initialize the repository
+	repo := NewRepo(store.NewEncoding(), validation.NewTransaction())
+
+	// create entities and set up state
+	tx1 := newTx("alice", 1, 10)
+	tx2 := newTx("alice", 2, 10)
+
+	err := repo.Add(tx1)
+	assert.Nil(t, err)
+
+	// execute remove
+	err1 := repo.Remove(tx1.Hash)
+	err2 := repo.Remove(tx2.Hash)
+
+	// check conditions
+	assert.Nil(t, err1)
+	assert.False(t, repo.Has(tx1.Hash))
+	assert.Empty(t, repo.senders)
+	assert.Empty(t, repo.heap)
+	assert.Equal(t, uint(0), repo.Size())
+
+	if assert.NotNil(t, err2) {
+		assert.Equal(t, ErrNotExist, errors.Cause(err2))
+	}
+}
+
+func TestRepoEviction(t *testing.T) {
+
+	// initialize the repository
+	repo := NewRepo(store.NewEncoding(), validation.NewTransaction(), SetMaxCount(2))
+
+	// create entities and set up state
+	tx1 := newTx("alice", 1, 10)
+	tx2 := newTx("bob", 1, 20)
+	tx3 := newTx("carol", 1, 30)
+	tx4 := newTx("dave", 1, 5)
+
+	err := repo.Add(tx1)
+	assert.Nil(t, err)
+	err = repo.Add(tx2)
+	assert.Nil(t, err)
+
+	// execute add with higher fee
+	err = repo.Add(tx3)
+
+	// check conditions
+	assert.Nil(t, err)
+	assert.False(t, repo.Has(tx1.Hash))
+	assert.True(t, repo.Has(tx2.Hash))
+	assert.True(t, repo.Has(tx3.Hash))
+	assert.Equal(t, []types.Hash{tx1.Hash}, repo.Evicted())
+
+	// execute add with lower fee
+	err = repo.Add(tx4)
+
+	// check conditions
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrUnderpriced, errors.Cause(err))
+	}
+	assert.False(t, repo.Has(tx4.Hash))
+	assert.Equal(t, uint(2), repo.Count())
+	assert.Empty(t, repo.Evicted())
+}
+
+func TestRepoMaxSize(t *testing.T) {
+
+	// create entities
+	tx1 := newTx("alice", 1, 10)
+	tx2 := newTx("bob", 1, 20)
+	large := newTx("carol", 1, 10)
+	large.Data = make([]byte, 1024)
+	_, priv := newKey("carol")
+	sign(large, priv)
+
+	// initialize the repository with room for about two transactions
+	repo := NewRepo(store.NewEncoding(), validation.NewTransaction(), SetMaxTxSize(512))
+	err := repo.Add(tx1)
+	assert.Nil(t, err)
+	size := repo.Size()
+	repo = NewRepo(store.NewEncoding(), validation.NewTransaction(), SetMaxSize(size+size/2), SetMaxTxSize(512))
+
+	// execute add of a large transaction
+	err = repo.Add(large)
+
+	// check conditions
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrTooLarge, errors.Cause(err))
+	}
+
+	// execute add beyond the size limit
+	err = repo.Add(tx1)
+	assert.Nil(t, err)
+	err = repo.Add(tx2)
+
+	// check conditions
+	assert.Nil(t, err)
+	assert.False(t, repo.Has(tx1.Hash))
+	assert.True(t, repo.Has(tx2.Hash))
+	assert.True(t, repo.Size() <= size+size/2)
+}
+
+func TestRepoReplacement(t *testing.T) {
+
+	// initialize the repository
+	repo := NewRepo(store.NewEncoding(), validation.NewTransaction(), SetBump(10))
+
+	// create entities and set up state
+	original := newTx("alice", 1, 100)
+	cheap := newTx("alice", 1, 105)
+	expensive := newTx("alice", 1, 200)
+
+	err := repo.Add(original)
+	assert.Nil(t, err)
+
+	// execute replacement with insufficient fee
+	err = repo.Add(cheap)
+
+	// check conditions
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrUnderpriced, errors.Cause(err))
+	}
+	assert.True(t, repo.Has(original.Hash))
+
+	// execute replacement with sufficient fee
+	err = repo.Add(expensive)
+
+	// check conditions
+	assert.Nil(t, err)
+	assert.False(t, repo.Has(original.Hash))
+	assert.True(t, repo.Has(expensive.Hash))
+	assert.Equal(t, uint(1), repo.Count())
+	assert.Equal(t, []types.Hash{original.Hash}, repo.Evicted())
+}
+
+func TestRepoForged(t *testing.T) {
+
+	// initialize the repository
+	repo := NewRepo(store.NewEncoding(), validation.NewTransaction(), SetBump(10), SetMaxPerSender(1))
+
+	// create entities and set up state
+	original := newTx("alice", 1, 100)
+	forged := newTx("alice", 1, 200)
+	_, priv := newKey("mallory")
+	sign(forged, priv)
+	unsigned := newTx("bob", 1, 10)
+	unsigned.Signatures = nil
+
+	err := repo.Add(original)
+	assert.Nil(t, err)
+
+	// execute replacement and add with forged or missing signatures
+	err1 := repo.Add(forged)
+	err2 := repo.Add(unsigned)
+
+	// check conditions
+	if assert.NotNil(t, err1) {
+		assert.Equal(t, validation.ErrSignature, errors.Cause(err1))
+	}
+	if assert.NotNil(t, err2) {
+		assert.Equal(t, validation.ErrSignature, errors.Cause(err2))
+	}
+	assert.True(t, repo.Has(original.Hash))
+	assert.Equal(t, uint(1), repo.Count())
+	assert.Empty(t, repo.Evicted())
+}
+
+func TestRepoFeeOverflow(t *testing.T) {
+
+	// initialize the repository
+	repo := NewRepo(store.NewEncoding(), validation.NewTransaction())
+
+	// create entities
+	pub, priv := newKey("alice")
+	tx := &types.Transaction{
+		Fees: []*types.Fee{{From: pub, Amount: math.MaxUint64}, {From: pub, Amount: 2}},
+	}
+	sign(tx, priv)
+
+	// execute add
+	err := repo.Add(tx)
+
+	// check conditions
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrFee, errors.Cause(err))
+	}
+	assert.False(t, repo.Has(tx.Hash))
+}
+
+func TestRepoSenderLimit(t *testing.T) {
+
+	// initialize the repository
+	repo := NewRepo(store.NewEncoding(), validation.NewTransaction(), SetMaxPerSender(2))
+
+	// create entities and set up state
+	tx1 := newTx("alice", 1, 10)
+	tx2 := newTx("alice", 2, 10)
+	tx3 := newTx("alice", 3, 10)
+	tx4 := newTx("bob", 1, 10)
+
+	err := repo.Add(tx1)
+	assert.Nil(t, err)
+	err = repo.Add(tx2)
+	assert.Nil(t, err)
+
+	// execute add
+	err3 := repo.Add(tx3)
+	err4 := repo.Add(tx4)
+
+	// check conditions
+	if assert.NotNil(t, err3) {
+		assert.Equal(t, ErrSenderLimit, errors.Cause(err3))
+	}
+	assert.Nil(t, err4)
+}
+
+func TestRepoExpire(t *testing.T) {
+
+	// initialize the repository
+	repo := NewRepo(store.NewEncoding(), validation.NewTransaction(), SetTTL(10*time.Millisecond))
+
+	// create entities and set up state
+	tx1 := newTx("alice", 1, 10)
+	tx2 := newTx("alice", 2, 10)
+
+	err := repo.Add(tx1)
+	assert.Nil(t, err)
+	time.Sleep(20 * time.Millisecond)
+	err = repo.Add(tx2)
+	assert.Nil(t, err)
+
+	// check conditions
+	assert.False(t, repo.Has(tx1.Hash))
+	assert.True(t, repo.Has(tx2.Hash))
+	assert.Equal(t, uint(1), repo.Count())
+	assert.Equal(t, []types.Hash{tx1.Hash}, repo.Evicted())
+
+	// removed transactions leave the expiry order as well
+	err = repo.Remove(tx2.Hash)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, repo.ages.Len())
+}
+
+func TestRepoBest(t *testing.T) {
+
+	// initialize the repository
+	repo := NewRepo(store.NewEncoding(), validation.NewTransaction())
+
+	// create entities and set up state
+	alice1 := newTx("alice", 1, 10)
+	alice2 := newTx("alice", 2, 40)
+	bob1 := newTx("bob", 1, 30)
+	carol1 := newTx("carol", 1, 20)
+	dave1 := newTx("dave", 1, 5)
+
+	for _, tx := range []*types.Transaction{alice1, alice2, bob1, carol1, dave1} {
+		err := repo.Add(tx)
+		assert.Nil(t, err)
+	}
+
+	// execute best
+	best := repo.Best(4)
+
+	// check conditions
+	assert.Equal(t, []*types.Transaction{alice1, bob1, carol1, alice2}, best)
+}
+
+func TestRepoConcurrency(t *testing.T) {
+
+	// initialize a repository that has to evict transactions
+	repo := NewRepo(store.NewEncoding(), validation.NewTransaction(), SetMaxCount(256))
+
+	// add, read and remove transactions from many goroutines
+	wg := &sync.WaitGroup{}
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			sender := fmt.Sprintf("sender%d", g)
+			for i := 0; i < 64; i++ {
+				tx := newTx(sender, uint64(i), uint64(i+1))
+				_ = repo.Add(tx)
+				repo.Has(tx.Hash)
+				_, _ = repo.Get(tx.Hash)
+				repo.Best(16)
+				repo.Count()
+				repo.Size()
+				if i%4 == 0 {
+					_ = repo.Remove(tx.Hash)
+				}
+				if i%16 == 0 {
+					repo.Expire()
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	// check that the repository stayed within its bounds
+	assert.True(t, repo.Count() <= 256)
+	assert.Equal(t, int(repo.Count()), len(repo.Best(repo.Count())))
+}
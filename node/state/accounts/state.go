@@ -0,0 +1,213 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package accounts
+
+import (
+	"math"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// State represents the account balances resulting from the connected blocks.
+// Blocks are applied and reverted atomically, so a block that would result in
+// a negative balance leaves the state untouched. The miner of each block is
+// credited with the block reward on top of the fees.
+type State struct {
+	sync.Mutex
+	balances map[string]uint64
+	reward   uint64
+}
+
+// NewState creates a new account state without any balances, with the given
+// reward for the miner of each block.
+func NewState(reward uint64) *State {
+	return &State{
+		balances: make(map[string]uint64),
+		reward:   reward,
+	}
+}
+
+// Credit adds the given amount to the balance of an account, for example to
+// allocate the initial balances of the genesis block.
+func (s *State) Credit(account []byte, amount uint64) error {
+	s.Lock()
+	defer s.Unlock()
+	changes := make(map[string]uint64)
+	err := s.credit(changes, account, amount)
+	if err != nil {
+		return err
+	}
+	s.commit(changes)
+	return nil
+}
+
+// Balance returns the balance of an account.
+func (s *State) Balance(account []byte) uint64 {
+	s.Lock()
+	defer s.Unlock()
+	return s.balances[string(account)]
+}
+
+// Valid checks whether the given transaction can be applied to the current
+// account state.
+func (s *State) Valid(tx *types.Transaction) bool {
+	s.Lock()
+	defer s.Unlock()
+	changes := make(map[string]uint64)
+	err := s.apply(changes, nil, tx)
+	return err == nil
+}
+
+// Apply applies the transactions of a block to the account state, crediting
+// the fees and the block reward to the miner of the block.
+func (s *State) Apply(block *types.Block) error {
+	s.Lock()
+	defer s.Unlock()
+	changes := make(map[string]uint64)
+	for _, tx := range block.Transactions {
+		err := s.apply(changes, block.Miner[:], tx)
+		if err != nil {
+			return errors.Wrapf(err, "could not apply transaction (%x)", tx.Hash)
+		}
+	}
+	err := s.credit(changes, block.Miner[:], s.reward)
+	if err != nil {
+		return errors.Wrap(err, "could not credit reward")
+	}
+	s.commit(changes)
+	return nil
+}
+
+// Revert reverts the transactions of a block from the account state, in the
+// reverse order of how they were applied.
+func (s *State) Revert(block *types.Block) error {
+	s.Lock()
+	defer s.Unlock()
+	changes := make(map[string]uint64)
+	err := s.debit(changes, block.Miner[:], s.reward)
+	if err != nil {
+		return errors.Wrap(err, "could not debit reward")
+	}
+	for i := len(block.Transactions) - 1; i >= 0; i-- {
+		tx := block.Transactions[i]
+		err := s.revert(changes, block.Miner[:], tx)
+		if err != nil {
+			return errors.Wrapf(err, "could not revert transaction (%x)", tx.Hash)
+		}
+	}
+	s.commit(changes)
+	return nil
+}
+
+// apply records the balance changes of a transaction.
+func (s *State) apply(changes map[string]uint64, miner []byte, tx *types.Transaction) error {
+	for _, transfer := range tx.Transfers {
+		err := s.debit(changes, transfer.From, transfer.Amount)
+		if err != nil {
+			return errors.Wrap(err, "could not debit transfer")
+		}
+		err = s.credit(changes, transfer.To, transfer.Amount)
+		if err != nil {
+			return errors.Wrap(err, "could not credit transfer")
+		}
+	}
+	for _, fee := range tx.Fees {
+		err := s.debit(changes, fee.From, fee.Amount)
+		if err != nil {
+			return errors.Wrap(err, "could not debit fee")
+		}
+		if miner == nil {
+			continue
+		}
+		err = s.credit(changes, miner, fee.Amount)
+		if err != nil {
+			return errors.Wrap(err, "could not credit fee")
+		}
+	}
+	return nil
+}
+
+// revert records the balance changes to undo a transaction.
+func (s *State) revert(changes map[string]uint64, miner []byte, tx *types.Transaction) error {
+	for i := len(tx.Fees) - 1; i >= 0; i-- {
+		fee := tx.Fees[i]
+		err := s.debit(changes, miner, fee.Amount)
+		if err != nil {
+			return errors.Wrap(err, "could not debit fee")
+		}
+		err = s.credit(changes, fee.From, fee.Amount)
+		if err != nil {
+			return errors.Wrap(err, "could not credit fee")
+		}
+	}
+	for i := len(tx.Transfers) - 1; i >= 0; i-- {
+		transfer := tx.Transfers[i]
+		err := s.debit(changes, transfer.To, transfer.Amount)
+		if err != nil {
+			return errors.Wrap(err, "could not debit transfer")
+		}
+		err = s.credit(changes, transfer.From, transfer.Amount)
+		if err != nil {
+			return errors.Wrap(err, "could not credit transfer")
+		}
+	}
+	return nil
+}
+
+// balance returns the balance of an account, including pending changes.
+func (s *State) balance(changes map[string]uint64, account []byte) uint64 {
+	balance, ok := changes[string(account)]
+	if ok {
+		return balance
+	}
+	return s.balances[string(account)]
+}
+
+// debit records the removal of the amount from the account.
+func (s *State) debit(changes map[string]uint64, account []byte, amount uint64) error {
+	balance := s.balance(changes, account)
+	if balance < amount {
+		return errors.Wrapf(ErrInsufficient, "balance too low (%x: %d < %d)", account, balance, amount)
+	}
+	changes[string(account)] = balance - amount
+	return nil
+}
+
+// credit records the addition of the amount to the account.
+func (s *State) credit(changes map[string]uint64, account []byte, amount uint64) error {
+	balance := s.balance(changes, account)
+	if balance > math.MaxUint64-amount {
+		return errors.Wrapf(ErrOverflow, "balance too high (%x: %d + %d)", account, balance, amount)
+	}
+	changes[string(account)] = balance + amount
+	return nil
+}
+
+// commit writes the recorded changes to the account balances.
+func (s *State) commit(changes map[string]uint64) {
+	for account, balance := range changes {
+		if balance == 0 {
+			delete(s.balances, account)
+			continue
+		}
+		s.balances[account] = balance
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package accounts
+
+import (
+	"testing"
+
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateApplyRevert(t *testing.T) {
+
+	// initialize parameters
+	alice := []byte("alice")
+	bob := []byte("bob")
+	miner := types.Hash{0x1}
+
+	// initialize entities
+	tx1 := &types.Transaction{
+		Transfers: []*types.Transfer{{From: alice, To: bob, Amount: 60}},
+		Fees:      []*types.Fee{{From: alice, Amount: 10}},
+	}
+	tx2 := &types.Transaction{
+		Transfers: []*types.Transfer{{From: bob, To: alice, Amount: 20}},
+	}
+	block := &types.Block{Header: &types.Header{Miner: miner}, Transactions: []*types.Transaction{tx1, tx2}}
+
+	// initialize state
+	s := NewState(5)
+	err := s.Credit(alice, 100)
+	assert.Nil(t, err)
+
+	// execute apply
+	err = s.Apply(block)
+
+	// check conditions
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(50), s.Balance(alice))
+	assert.Equal(t, uint64(40), s.Balance(bob))
+	assert.Equal(t, uint64(15), s.Balance(miner[:]))
+
+	// execute revert
+	err = s.Revert(block)
+
+	// check conditions
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(100), s.Balance(alice))
+	assert.Equal(t, uint64(0), s.Balance(bob))
+	assert.Equal(t, uint64(0), s.Balance(miner[:]))
+	assert.Len(t, s.balances, 1)
+}
+
+func TestStateApplyInsufficient(t *testing.T) {
+
+	// initialize parameters
+	alice := []byte("alice")
+	bob := []byte("bob")
+
+	// initialize entities
+	tx1 := &types.Transaction{
+		Transfers: []*types.Transfer{{From: alice, To: bob, Amount: 60}},
+	}
+	tx2 := &types.Transaction{
+		Transfers: []*types.Transfer{{From: alice, To: bob, Amount: 60}},
+	}
+	block := &types.Block{Header: &types.Header{}, Transactions: []*types.Transaction{tx1, tx2}}
+
+	// initialize state
+	s := NewState(0)
+	err := s.Credit(alice, 100)
+	assert.Nil(t, err)
+
+	// check validity
+	assert.True(t, s.Valid(tx1))
+
+	// execute apply
+	err = s.Apply(block)
+
+	// check conditions
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrInsufficient, errors.Cause(err))
+	}
+	assert.Equal(t, uint64(100), s.Balance(alice))
+	assert.Equal(t, uint64(0), s.Balance(bob))
+}
+
+func TestStateValid(t *testing.T) {
+
+	// initialize parameters
+	alice := []byte("alice")
+	bob := []byte("bob")
+
+	// initialize entities
+	valid := &types.Transaction{
+		Transfers: []*types.Transfer{{From: alice, To: bob, Amount: 90}},
+		Fees:      []*types.Fee{{From: alice, Amount: 10}},
+	}
+	invalid := &types.Transaction{
+		Transfers: []*types.Transfer{{From: alice, To: bob, Amount: 91}},
+		Fees:      []*types.Fee{{From: alice, Amount: 10}},
+	}
+
+	// initialize state
+	s := NewState(0)
+	err := s.Credit(alice, 100)
+	assert.Nil(t, err)
+
+	// check conditions
+	assert.True(t, s.Valid(valid))
+	assert.False(t, s.Valid(invalid))
+	assert.Equal(t, uint64(100), s.Balance(alice))
+}
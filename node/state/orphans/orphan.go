@@ -0,0 +1,43 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package orphans
+
+import (
+	"time"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// Orphan represents a header with unknown parent, together with the address of
+// the peer that sent it.
+type Orphan struct {
+	Address string
+	Header  *types.Header
+	added   time.Time
+}
+
+// Metrics contains the current number of orphans and counters for what
+// happened to orphans over the lifetime of the pool.
+type Metrics struct {
+	Orphans   uint
+	Added     uint
+	Connected uint
+	Expired   uint
+	Evicted   uint
+	Rejected  uint
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package orphans
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/pkg/errors"
+)
+
+// Pool represents a bounded pool of headers with unknown parents. The pool has
+// a maximum size, a quota per peer and a maximum age for orphans. When the
+// pool is full, the oldest orphan is evicted.
+type Pool struct {
+	sync.Mutex
+	max     uint
+	quota   uint
+	expiry  time.Duration
+	order   *list.List
+	orphans map[types.Hash]*list.Element
+	parents map[types.Hash][]types.Hash
+	peers   map[string]uint
+	metrics Metrics
+}
+
+// NewPool creates a new orphan pool with the given maximum size, per-peer quota
+// and expiry duration.
+func NewPool(max uint, quota uint, expiry time.Duration) *Pool {
+	return &Pool{
+		max:     max,
+		quota:   quota,
+		expiry:  expiry,
+		order:   list.New(),
+		orphans: make(map[types.Hash]*list.Element),
+		parents: make(map[types.Hash][]types.Hash),
+		peers:   make(map[string]uint),
+	}
+}
+
+// Add adds a header with unknown parent, received from the given peer, to the
+// pool.
+func (p *Pool) Add(address string, header *types.Header) error {
+	p.Lock()
+	defer p.Unlock()
+
+	p.expire()
+
+	_, ok := p.orphans[header.Hash]
+	if ok {
+		return errors.Wrap(ErrExist, "orphan already known")
+	}
+
+	if p.peers[address] >= p.quota {
+		p.metrics.Rejected++
+		return errors.Wrapf(ErrQuota, "orphan quota reached for peer (%v)", address)
+	}
+
+	if uint(p.order.Len()) >= p.max {
+		p.remove(p.order.Front())
+		p.metrics.Evicted++
+	}
+
+	orphan := &Orphan{
+		Address: address,
+		Header:  header,
+		added:   time.Now(),
+	}
+	p.orphans[header.Hash] = p.order.PushBack(orphan)
+	p.parents[header.Parent] = append(p.parents[header.Parent], header.Hash)
+	p.peers[address]++
+	p.metrics.Added++
+
+	return nil
+}
+
+// Has checks whether the header with the given hash is in the pool.
+func (p *Pool) Has(hash types.Hash) bool {
+	p.Lock()
+	defer p.Unlock()
+	_, ok := p.orphans[hash]
+	return ok
+}
+
+// Take removes all orphans that have the given hash as parent from the pool and
+// returns them.
+func (p *Pool) Take(parent types.Hash) []Orphan {
+	p.Lock()
+	defer p.Unlock()
+
+	p.expire()
+
+	hashes := append([]types.Hash(nil), p.parents[parent]...)
+	var taken []Orphan
+	for _, hash := range hashes {
+		elem, ok := p.orphans[hash]
+		if !ok {
+			continue
+		}
+		taken = append(taken, *elem.Value.(*Orphan))
+		p.remove(elem)
+		p.metrics.Connected++
+	}
+
+	return taken
+}
+
+// Count returns the number of orphans in the pool.
+func (p *Pool) Count() uint {
+	p.Lock()
+	defer p.Unlock()
+	p.expire()
+	return uint(p.order.Len())
+}
+
+// Metrics returns the current orphan metrics.
+func (p *Pool) Metrics() Metrics {
+	p.Lock()
+	defer p.Unlock()
+	p.expire()
+	metrics := p.metrics
+	metrics.Orphans = uint(p.order.Len())
+	return metrics
+}
+
+// expire removes all orphans that are older than the expiry duration.
+func (p *Pool) expire() {
+	cutoff := time.Now().Add(-p.expiry)
+	for elem := p.order.Front(); elem != nil; elem = p.order.Front() {
+		if elem.Value.(*Orphan).added.After(cutoff) {
+			break
+		}
+		p.remove(elem)
+		p.metrics.Expired++
+	}
+}
+
+// remove removes the orphan of the given list element from all indexes.
+func (p *Pool) remove(elem *list.Element) {
+	orphan := elem.Value.(*Orphan)
+	p.order.Remove(elem)
+	delete(p.orphans, orphan.Header.Hash)
+	siblings := p.parents[orphan.Header.Parent]
+	for i, hash := range siblings {
+		if hash == orphan.Header.Hash {
+			siblings = append(siblings[:i], siblings[i+1:]...)
+			break
+		}
+	}
+	if len(siblings) == 0 {
+		delete(p.parents, orphan.Header.Parent)
+	} else {
+		p.parents[orphan.Header.Parent] = siblings
+	}
+	p.peers[orphan.Address]--
+	if p.peers[orphan.Address] == 0 {
+		delete(p.peers, orphan.Address)
+	}
+}
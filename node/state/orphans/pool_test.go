@@ -0,0 +1,129 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package orphans
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoolAdd(t *testing.T) {
+	address := "192.0.2.100:1337"
+	header := &types.Header{Hash: types.Hash{0x1}, Parent: types.Hash{0x2}}
+	pool := NewPool(10, 10, time.Minute)
+
+	err := pool.Add(address, header)
+	assert.Nil(t, err)
+	assert.True(t, pool.Has(header.Hash))
+	assert.Equal(t, uint(1), pool.peers[address])
+	assert.Equal(t, []types.Hash{header.Hash}, pool.parents[header.Parent])
+
+	err = pool.Add(address, header)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrExist, errors.Cause(err))
+	}
+}
+
+func TestPoolQuota(t *testing.T) {
+	address1 := "192.0.2.100:1337"
+	address2 := "192.0.2.200:1337"
+	header1 := &types.Header{Hash: types.Hash{0x1}}
+	header2 := &types.Header{Hash: types.Hash{0x2}}
+	header3 := &types.Header{Hash: types.Hash{0x3}}
+	pool := NewPool(10, 1, time.Minute)
+
+	err := pool.Add(address1, header1)
+	assert.Nil(t, err)
+
+	err = pool.Add(address1, header2)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrQuota, errors.Cause(err))
+	}
+
+	err = pool.Add(address2, header3)
+	assert.Nil(t, err)
+
+	metrics := pool.Metrics()
+	assert.Equal(t, uint(2), metrics.Orphans)
+	assert.Equal(t, uint(2), metrics.Added)
+	assert.Equal(t, uint(1), metrics.Rejected)
+}
+
+func TestPoolEviction(t *testing.T) {
+	address := "192.0.2.100:1337"
+	header1 := &types.Header{Hash: types.Hash{0x1}}
+	header2 := &types.Header{Hash: types.Hash{0x2}}
+	header3 := &types.Header{Hash: types.Hash{0x3}}
+	pool := NewPool(2, 10, time.Minute)
+
+	_ = pool.Add(address, header1)
+	_ = pool.Add(address, header2)
+	_ = pool.Add(address, header3)
+
+	assert.False(t, pool.Has(header1.Hash))
+	assert.True(t, pool.Has(header2.Hash))
+	assert.True(t, pool.Has(header3.Hash))
+	assert.Equal(t, uint(2), pool.peers[address])
+	assert.Equal(t, uint(1), pool.Metrics().Evicted)
+}
+
+func TestPoolExpiry(t *testing.T) {
+	address := "192.0.2.100:1337"
+	header1 := &types.Header{Hash: types.Hash{0x1}, Parent: types.Hash{0x3}}
+	header2 := &types.Header{Hash: types.Hash{0x2}, Parent: types.Hash{0x3}}
+	pool := NewPool(10, 10, time.Minute)
+
+	_ = pool.Add(address, header1)
+	_ = pool.Add(address, header2)
+	pool.order.Front().Value.(*Orphan).added = time.Now().Add(-2 * time.Minute)
+
+	assert.Equal(t, uint(1), pool.Metrics().Orphans)
+	taken := pool.Take(types.Hash{0x3})
+	if assert.Len(t, taken, 1) {
+		assert.Equal(t, header2, taken[0].Header)
+	}
+	assert.Equal(t, uint(1), pool.Metrics().Expired)
+	assert.Empty(t, pool.peers)
+}
+
+func TestPoolTake(t *testing.T) {
+	address1 := "192.0.2.100:1337"
+	address2 := "192.0.2.200:1337"
+	parent := types.Hash{0x9}
+	header1 := &types.Header{Hash: types.Hash{0x1}, Parent: parent}
+	header2 := &types.Header{Hash: types.Hash{0x2}, Parent: parent}
+	header3 := &types.Header{Hash: types.Hash{0x3}, Parent: header1.Hash}
+	pool := NewPool(10, 10, time.Minute)
+
+	_ = pool.Add(address1, header1)
+	_ = pool.Add(address2, header2)
+	_ = pool.Add(address1, header3)
+
+	taken := pool.Take(parent)
+	if assert.Len(t, taken, 2) {
+		assert.Equal(t, Orphan{Address: address1, Header: header1}, Orphan{Address: taken[0].Address, Header: taken[0].Header})
+		assert.Equal(t, Orphan{Address: address2, Header: header2}, Orphan{Address: taken[1].Address, Header: taken[1].Header})
+	}
+	assert.Equal(t, uint(1), pool.Count())
+	assert.NotContains(t, pool.parents, parent)
+	assert.Equal(t, uint(2), pool.Metrics().Connected)
+}
@@ -17,21 +17,196 @@
 
 package path
 
-import "github.com/alvalor/alvalor-go/types"
+import (
+	"sync"
 
-// State represents the state of the currently followed path.
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// State represents the state of the currently followed path. Besides the path
+// itself, it keeps the position of each hash on it and of the first block that
+// is not connected yet, so that following a new branch and finding the next
+// block to connect don't have to go through the whole path.
 type State struct {
-	current []types.Hash
+	sync.Mutex
+	current   []types.Hash
+	index     map[types.Hash]int
+	connected map[types.Hash]struct{}
+	next      int
 }
 
-// Current returns the current path.
+// Current returns a copy of the current path.
 func (st *State) Current() []types.Hash {
-	return st.current
+	st.Lock()
+	defer st.Unlock()
+
+	current := make([]types.Hash, len(st.current))
+	copy(current, st.current)
+	return current
 }
 
 // Set sets the path to be followed and returns the deltas between old and new.
+// Blocks that are already connected to our blockchain are not started again.
 func (st *State) Set(path []types.Hash) ([]types.Hash, []types.Hash) {
+	st.Lock()
+	defer st.Unlock()
+
 	cancel, start := Diff(st.current, path)
-	st.current = path
-	return cancel, start
+	st.replace(0, path)
+	return cancel, st.pending(start)
+}
+
+// Fork returns the last hash of the given branch that is on the current path,
+// together with the hashes of the current path after it, which would be
+// cancelled by switching to the branch. The first hash of the branch has to be
+// on the current path, unless it is empty.
+func (st *State) Fork(branch []types.Hash) (types.Hash, []types.Hash, bool) {
+	st.Lock()
+	defer st.Unlock()
+
+	if len(st.current) == 0 {
+		return types.ZeroHash, nil, true
+	}
+	pos, _, ok := st.fork(branch)
+	if !ok {
+		return types.ZeroHash, nil, false
+	}
+	cancel := make([]types.Hash, len(st.current)-pos-1)
+	copy(cancel, st.current[pos+1:])
+	return st.current[pos], cancel, true
+}
+
+// Switch replaces the current path after the first hash of the given branch,
+// which has to be on it, with the rest of the branch and returns the deltas
+// between old and new. An empty path is simply set to the branch. Blocks that
+// are already connected to our blockchain are not started again.
+func (st *State) Switch(branch []types.Hash) ([]types.Hash, []types.Hash, bool) {
+	st.Lock()
+	defer st.Unlock()
+
+	if len(st.current) == 0 {
+		st.replace(0, branch)
+		return nil, st.pending(branch), true
+	}
+	pos, skip, ok := st.fork(branch)
+	if !ok {
+		return nil, nil, false
+	}
+	cancel := make([]types.Hash, len(st.current)-pos-1)
+	copy(cancel, st.current[pos+1:])
+	start := make([]types.Hash, len(branch)-skip)
+	copy(start, branch[skip:])
+	st.replace(pos+1, start)
+	return cancel, st.pending(start), true
+}
+
+// Next returns the first hash of the current path whose block is not connected
+// to our blockchain yet.
+func (st *State) Next() (types.Hash, bool) {
+	st.Lock()
+	defer st.Unlock()
+
+	if st.next >= len(st.current) {
+		return types.ZeroHash, false
+	}
+	return st.current[st.next], true
+}
+
+// Connect marks the block with the given hash as connected to our blockchain.
+func (st *State) Connect(hash types.Hash) {
+	st.Lock()
+	defer st.Unlock()
+
+	if st.connected == nil {
+		st.connected = make(map[types.Hash]struct{})
+	}
+	st.connected[hash] = struct{}{}
+	st.advance()
+}
+
+// Disconnect marks the block with the given hash as no longer connected to our
+// blockchain.
+func (st *State) Disconnect(hash types.Hash) {
+	st.Lock()
+	defer st.Unlock()
+
+	delete(st.connected, hash)
+	pos, ok := st.index[hash]
+	if ok && pos < st.next {
+		st.next = pos
+	}
+}
+
+// Connected checks whether the block with the given hash is connected to our
+// blockchain.
+func (st *State) Connected(hash types.Hash) bool {
+	st.Lock()
+	defer st.Unlock()
+
+	_, ok := st.connected[hash]
+	return ok
+}
+
+// fork returns the position of the last hash of the branch that is on the
+// current path, skipping the hashes the branch shares with it after its first
+// one, and how many hashes of the branch it covers.
+func (st *State) fork(branch []types.Hash) (int, int, bool) {
+	if len(branch) == 0 {
+		return 0, 0, false
+	}
+	pos, ok := st.index[branch[0]]
+	if !ok {
+		return 0, 0, false
+	}
+	skip := 1
+	for skip < len(branch) && pos+1 < len(st.current) && st.current[pos+1] == branch[skip] {
+		pos++
+		skip++
+	}
+	return pos, skip, true
+}
+
+// replace replaces the current path from the given position onwards with the
+// given hashes.
+func (st *State) replace(pos int, hashes []types.Hash) {
+	if st.index == nil {
+		st.index = make(map[types.Hash]int)
+	}
+	for _, hash := range st.current[pos:] {
+		delete(st.index, hash)
+	}
+	st.current = append(st.current[:pos], hashes...)
+	for i, hash := range hashes {
+		st.index[hash] = pos + i
+	}
+	if st.next > pos {
+		st.next = pos
+	}
+	st.advance()
+}
+
+// advance moves the position of the next block to connect past the connected
+// blocks.
+func (st *State) advance() {
+	for st.next < len(st.current) {
+		_, ok := st.connected[st.current[st.next]]
+		if !ok {
+			return
+		}
+		st.next++
+	}
+}
+
+// pending filters the given hashes down to the ones whose blocks are not
+// connected to our blockchain.
+func (st *State) pending(hashes []types.Hash) []types.Hash {
+	var pending []types.Hash
+	for _, hash := range hashes {
+		_, ok := st.connected[hash]
+		if ok {
+			continue
+		}
+		pending = append(pending, hash)
+	}
+	return pending
 }
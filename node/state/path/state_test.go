@@ -16,3 +16,147 @@
 // along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
 
 package path
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateConnect(t *testing.T) {
+
+	// initialize parameters
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+	hash3 := types.Hash{0x3}
+
+	// initialize state
+	st := &State{}
+
+	// execute connect
+	st.Connect(hash1)
+
+	// check conditions
+	assert.True(t, st.Connected(hash1))
+	assert.False(t, st.Connected(hash2))
+
+	// execute set
+	cancel, start := st.Set([]types.Hash{hash1, hash2, hash3})
+
+	// check conditions
+	assert.Empty(t, cancel)
+	assert.Equal(t, []types.Hash{hash2, hash3}, start)
+	assert.Equal(t, []types.Hash{hash1, hash2, hash3}, st.Current())
+
+	// execute disconnect
+	st.Disconnect(hash1)
+
+	// check conditions
+	assert.False(t, st.Connected(hash1))
+}
+
+func TestStateSwitch(t *testing.T) {
+
+	// initialize parameters
+	hash0 := types.Hash{0x0}
+	hashA1 := types.Hash{0xa1}
+	hashA2 := types.Hash{0xa2}
+	hashB1 := types.Hash{0xb1}
+	hashB2 := types.Hash{0xb2}
+
+	// initialize state
+	st := &State{}
+
+	// execute switch on empty path
+	cancel, start, ok := st.Switch([]types.Hash{hash0, hashA1, hashA2})
+
+	// check conditions
+	assert.True(t, ok)
+	assert.Empty(t, cancel)
+	assert.Equal(t, []types.Hash{hash0, hashA1, hashA2}, start)
+
+	// execute fork with a branch sharing part of the path
+	ancestor, cancel, ok := st.Fork([]types.Hash{hash0, hashA1, hashB1, hashB2})
+
+	// check conditions
+	assert.True(t, ok)
+	assert.Equal(t, hashA1, ancestor)
+	assert.Equal(t, []types.Hash{hashA2}, cancel)
+
+	// execute switch to the branch
+	cancel, start, ok = st.Switch([]types.Hash{hash0, hashA1, hashB1, hashB2})
+
+	// check conditions
+	assert.True(t, ok)
+	assert.Equal(t, []types.Hash{hashA2}, cancel)
+	assert.Equal(t, []types.Hash{hashB1, hashB2}, start)
+	assert.Equal(t, []types.Hash{hash0, hashA1, hashB1, hashB2}, st.Current())
+
+	// execute switch from a hash not on the path
+	_, _, ok = st.Switch([]types.Hash{hashA2})
+
+	// check conditions
+	assert.False(t, ok)
+	assert.Equal(t, []types.Hash{hash0, hashA1, hashB1, hashB2}, st.Current())
+}
+
+func TestStateNext(t *testing.T) {
+
+	// initialize parameters
+	hash0 := types.Hash{0x0}
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+
+	// initialize state
+	st := &State{}
+	st.Connect(hash0)
+	st.Set([]types.Hash{hash0, hash1, hash2})
+
+	// check the first block that is not connected
+	next, ok := st.Next()
+	assert.True(t, ok)
+	assert.Equal(t, hash1, next)
+
+	// connecting blocks moves it forward
+	st.Connect(hash1)
+	st.Connect(hash2)
+	_, ok = st.Next()
+	assert.False(t, ok)
+
+	// disconnecting blocks moves it back
+	st.Disconnect(hash2)
+	st.Disconnect(hash1)
+	next, ok = st.Next()
+	assert.True(t, ok)
+	assert.Equal(t, hash1, next)
+}
+
+func TestStateConcurrency(t *testing.T) {
+
+	// initialize state
+	st := &State{}
+
+	// set paths and connect blocks from many goroutines
+	wg := &sync.WaitGroup{}
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 64; i++ {
+				hash := types.Hash{byte(g), byte(i)}
+				st.Connect(hash)
+				assert.True(t, st.Connected(hash))
+				st.Set([]types.Hash{hash, {byte(g)}})
+				st.Current()
+				st.Disconnect(hash)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	// check conditions
+	assert.Len(t, st.Current(), 2)
+	assert.Empty(t, st.connected)
+}
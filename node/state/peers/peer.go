@@ -24,4 +24,5 @@ type Peer struct {
 	active bool
 	yes    map[types.Hash]struct{}
 	no     map[types.Hash]struct{}
+	known  []types.Hash
 }
@@ -24,6 +24,11 @@ import (
 	"github.com/pkg/errors"
 )
 
+// maxKnown is the maximum number of entities we remember as known by a single
+// peer; once it is exceeded, the entities received the longest ago are
+// forgotten first.
+const maxKnown = 65536
+
 // State represents the state of all peers.
 type State struct {
 	sync.Mutex
@@ -76,7 +81,34 @@ func (s *State) Received(address string, hash types.Hash) error {
 		return errors.Wrap(ErrNotExist, "peer for message not found")
 	}
 
-	p.yes[hash] = struct{}{}
+	_, ok = p.yes[hash]
+	if !ok {
+		p.yes[hash] = struct{}{}
+		p.known = append(p.known, hash)
+	}
+	delete(p.no, hash)
+
+	// forget the oldest entities if the peer sent us too many
+	for len(p.known) > maxKnown {
+		delete(p.yes, p.known[0])
+		p.known = p.known[1:]
+	}
+
+	return nil
+}
+
+// Missing marks an entity as not available from a given peer.
+func (s *State) Missing(address string, hash types.Hash) error {
+	s.Lock()
+	defer s.Unlock()
+
+	p, ok := s.peers[address]
+	if !ok {
+		return errors.Wrap(ErrNotExist, "peer for missing entity not found")
+	}
+
+	p.no[hash] = struct{}{}
+	delete(p.yes, hash)
 	return nil
 }
 
@@ -100,6 +132,9 @@ func (s *State) Seen(address string) ([]types.Hash, error) {
 
 // Addresses will find the peers according to the given filters.
 func (s *State) Addresses(filters ...FilterFunc) []string {
+	s.Lock()
+	defer s.Unlock()
+
 	var addresses []string
 Outer:
 	for address, p := range s.peers {
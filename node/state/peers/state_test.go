@@ -18,6 +18,8 @@
 package peers
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/alvalor/alvalor-go/types"
@@ -97,6 +99,54 @@ func TestStateReceived(t *testing.T) {
 	}
 }
 
+func TestStateMissing(t *testing.T) {
+
+	address1 := "192.0.2.100:1337"
+	address2 := "192.0.2.200:1337"
+	hash1 := types.Hash{0x1}
+	state := &State{peers: make(map[string]*Peer)}
+
+	err := state.Missing(address1, hash1)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrNotExist, errors.Cause(err))
+	}
+
+	state.peers[address2] = &Peer{yes: make(map[types.Hash]struct{}), no: make(map[types.Hash]struct{})}
+	state.peers[address2].yes[hash1] = struct{}{}
+	err = state.Missing(address2, hash1)
+	assert.Nil(t, err)
+	assert.NotContains(t, state.peers[address2].yes, hash1)
+	if assert.Len(t, state.peers[address2].no, 1) {
+		assert.Contains(t, state.peers[address2].no, hash1)
+	}
+
+	err = state.Received(address2, hash1)
+	assert.Nil(t, err)
+	assert.Contains(t, state.peers[address2].yes, hash1)
+	assert.NotContains(t, state.peers[address2].no, hash1)
+}
+
+func TestStateReceivedMaxKnown(t *testing.T) {
+
+	address := "192.0.2.100:1337"
+	state := &State{peers: make(map[string]*Peer)}
+	state.Active(address)
+
+	last := types.Hash{}
+	for i := 0; i <= maxKnown; i++ {
+		last = types.Hash{byte(i), byte(i >> 8), byte(i >> 16)}
+		err := state.Received(address, last)
+		assert.Nil(t, err)
+	}
+
+	p := state.peers[address]
+	assert.Len(t, p.yes, maxKnown)
+	assert.Len(t, p.known, maxKnown)
+	assert.NotContains(t, p.yes, types.Hash{0x0})
+	assert.Contains(t, p.yes, types.Hash{0x1})
+	assert.Contains(t, p.yes, last)
+}
+
 func TestStateSeen(t *testing.T) {
 
 	address1 := "192.0.2.100:1337"
@@ -213,3 +263,33 @@ func TestStateAddressesCount(t *testing.T) {
 		assert.Equal(t, uint(len(vector.addresses)), count, name)
 	}
 }
+
+func TestStateConcurrency(t *testing.T) {
+
+	// initialize the state
+	state := NewState()
+
+	// update and query the peers from many goroutines
+	wg := &sync.WaitGroup{}
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			address := fmt.Sprintf("192.0.2.%d:1337", g)
+			state.Active(address)
+			for i := 0; i < 64; i++ {
+				hash := types.Hash{byte(g), byte(i)}
+				err := state.Received(address, hash)
+				assert.Nil(t, err)
+				_ = state.Addresses(IsActive(true), HasEntity(EntityYes, hash))
+				_ = state.Count(HasEntity(EntityNo, hash))
+				err = state.Missing(address, hash)
+				assert.Nil(t, err)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	// check conditions
+	assert.Equal(t, uint(16), state.Count(IsActive(true)))
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package requests
+
+import (
+	"sync"
+	"time"
+
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/pkg/errors"
+)
+
+// State represents the state of pending header requests, with at most one
+// request in flight per peer.
+type State struct {
+	sync.Mutex
+	requests map[string]*Request
+}
+
+// NewState creates a new state for header requests.
+func NewState() *State {
+	return &State{
+		requests: make(map[string]*Request),
+	}
+}
+
+// Start marks a header request as pending for the given peer.
+func (s *State) Start(address string, locators []types.Hash, max uint32) error {
+	s.Lock()
+	defer s.Unlock()
+
+	_, ok := s.requests[address]
+	if ok {
+		return errors.Wrap(ErrExist, "header request already pending")
+	}
+
+	s.requests[address] = &Request{
+		Locators: locators,
+		Max:      max,
+		Sent:     time.Now(),
+	}
+	return nil
+}
+
+// Finish removes the pending header request for the given peer and returns it.
+func (s *State) Finish(address string) (*Request, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	req, ok := s.requests[address]
+	if !ok {
+		return nil, errors.Wrap(ErrNotExist, "header request not found")
+	}
+	delete(s.requests, address)
+	return req, nil
+}
+
+// Stalled removes all header requests that have been pending for longer than
+// the given timeout and returns the addresses of the peers they were sent to.
+func (s *State) Stalled(timeout time.Duration) []string {
+	s.Lock()
+	defer s.Unlock()
+
+	cutoff := time.Now().Add(-timeout)
+	var addresses []string
+	for address, req := range s.requests {
+		if req.Sent.After(cutoff) {
+			continue
+		}
+		delete(s.requests, address)
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+// Count returns the number of pending header requests.
+func (s *State) Count() uint {
+	s.Lock()
+	defer s.Unlock()
+	return uint(len(s.requests))
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package requests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewState(t *testing.T) {
+	state := NewState()
+	assert.NotNil(t, state.requests)
+}
+
+func TestStateStart(t *testing.T) {
+	address := "192.0.2.100:1337"
+	locators := []types.Hash{{0x1}, {0x2}}
+	state := &State{requests: make(map[string]*Request)}
+
+	err := state.Start(address, locators, 100)
+	assert.Nil(t, err)
+	if assert.Contains(t, state.requests, address) {
+		req := state.requests[address]
+		assert.Equal(t, locators, req.Locators)
+		assert.Equal(t, uint32(100), req.Max)
+	}
+
+	err = state.Start(address, locators, 100)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrExist, errors.Cause(err))
+	}
+}
+
+func TestStateFinish(t *testing.T) {
+	address := "192.0.2.100:1337"
+	req := &Request{Max: 100}
+	state := &State{requests: make(map[string]*Request)}
+
+	_, err := state.Finish(address)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrNotExist, errors.Cause(err))
+	}
+
+	state.requests[address] = req
+
+	finished, err := state.Finish(address)
+	assert.Nil(t, err)
+	assert.Equal(t, req, finished)
+	assert.NotContains(t, state.requests, address)
+}
+
+func TestStateStalled(t *testing.T) {
+	address1 := "192.0.2.100:1337"
+	address2 := "192.0.2.200:1337"
+	state := &State{requests: make(map[string]*Request)}
+	state.requests[address1] = &Request{Sent: time.Now().Add(-time.Minute)}
+	state.requests[address2] = &Request{Sent: time.Now()}
+
+	stalled := state.Stalled(30 * time.Second)
+	assert.Equal(t, []string{address1}, stalled)
+	assert.NotContains(t, state.requests, address1)
+	assert.Contains(t, state.requests, address2)
+	assert.Equal(t, uint(1), state.Count())
+}
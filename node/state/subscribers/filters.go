@@ -19,26 +19,47 @@ package subscribers
 
 import "github.com/alvalor/alvalor-go/types"
 
-type msgType uint8
+// Type represents the type of an event, as used for filtering.
+type Type uint8
 
+// List of event types.
 const (
-	hdrType msgType = iota
-	txType
+	TypeHeader Type = iota
+	TypeTransaction
+	TypeEvicted
+	TypeBlock
+	TypeDisconnected
+	TypeReorg
+	TypeProgress
+	TypeSync
 )
 
-func isType(types ...msgType) func(interface{}) bool {
-	return func(msg interface{}) bool {
-		switch msg.(type) {
+// IsType returns a filter that matches events of the given types.
+func IsType(types ...Type) func(interface{}) bool {
+	return func(event interface{}) bool {
+		switch event.(type) {
 		case *Header:
-			return containsType(types, hdrType)
+			return containsType(types, TypeHeader)
 		case *Transaction:
-			return containsType(types, txType)
+			return containsType(types, TypeTransaction)
+		case *Evicted:
+			return containsType(types, TypeEvicted)
+		case *Block:
+			return containsType(types, TypeBlock)
+		case *Disconnected:
+			return containsType(types, TypeDisconnected)
+		case *Reorg:
+			return containsType(types, TypeReorg)
+		case *Progress:
+			return containsType(types, TypeProgress)
+		case *Sync:
+			return containsType(types, TypeSync)
 		}
 		return false
 	}
 }
 
-func containsType(types []msgType, inputType msgType) bool {
+func containsType(types []Type, inputType Type) bool {
 	for _, curType := range types {
 		if inputType == curType {
 			return true
@@ -47,15 +68,23 @@ func containsType(types []msgType, inputType msgType) bool {
 	return false
 }
 
-func hasHash(hashes ...types.Hash) func(interface{}) bool {
-	return func(msg interface{}) bool {
-		tx, ok := msg.(*Transaction)
-		if ok && containsHash(hashes, tx.hash) {
-			return true
-		}
-		hdr, ok := msg.(*Header)
-		if ok && containsHash(hashes, hdr.hash) {
-			return true
+// HasHash returns a filter that matches events for an entity with one of the
+// given hashes. Reorg events match on their old and new best hash.
+func HasHash(hashes ...types.Hash) func(interface{}) bool {
+	return func(event interface{}) bool {
+		switch e := event.(type) {
+		case *Header:
+			return containsHash(hashes, e.Hash)
+		case *Transaction:
+			return containsHash(hashes, e.Hash)
+		case *Evicted:
+			return containsHash(hashes, e.Hash)
+		case *Block:
+			return containsHash(hashes, e.Hash)
+		case *Disconnected:
+			return containsHash(hashes, e.Hash)
+		case *Reorg:
+			return containsHash(hashes, e.Old) || containsHash(hashes, e.New)
 		}
 		return false
 	}
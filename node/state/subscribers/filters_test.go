@@ -0,0 +1,57 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package subscribers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+func TestIsType(t *testing.T) {
+
+	// initialize filter
+	filter := IsType(TypeTransaction, TypeEvicted)
+
+	// check conditions
+	assert.True(t, filter(&Transaction{}))
+	assert.True(t, filter(&Evicted{}))
+	assert.False(t, filter(&Header{}))
+	assert.False(t, filter(&Reorg{}))
+	assert.False(t, filter(&Sync{}))
+	assert.False(t, filter("invalid"))
+}
+
+func TestHasHash(t *testing.T) {
+
+	// initialize parameters
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+
+	// initialize filter
+	filter := HasHash(hash1)
+
+	// check conditions
+	assert.True(t, filter(&Transaction{Hash: hash1}))
+	assert.True(t, filter(&Block{Hash: hash1}))
+	assert.True(t, filter(&Reorg{Old: hash2, New: hash1}))
+	assert.False(t, filter(&Header{Hash: hash2}))
+	assert.False(t, filter(&Progress{}))
+}
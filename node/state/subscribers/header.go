@@ -19,7 +19,7 @@ package subscribers
 
 import "github.com/alvalor/alvalor-go/types"
 
-// Header event
+// Header event for a header accepted into our headers repository.
 type Header struct {
-	hash types.Hash
+	Hash types.Hash
 }
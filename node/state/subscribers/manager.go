@@ -18,57 +18,163 @@
 package subscribers
 
 import (
-	"errors"
+	"sync"
 	"time"
 
+	multierror "github.com/hashicorp/go-multierror"
+
 	"github.com/alvalor/alvalor-go/types"
 )
 
-// Manager represents a manager for event notifications.
+// Manager represents a manager for event notifications. Each subscriber has
+// its own buffer and routine, so that events are delivered in order to every
+// subscriber without a slow subscriber holding up the others.
 type Manager struct {
-	stream  chan interface{}
+	sync.Mutex
+	wg      sync.WaitGroup
+	buffer  uint
 	timeout time.Duration
-	subs    map[chan<- interface{}][]func(interface{}) bool
+	subs    map[chan<- interface{}]*subscriber
 }
 
-// NewManager creates a new event manager.
+// NewManager creates a new event manager with the given default buffer size
+// per subscriber and the time we wait for subscribers that block when full.
 func NewManager(buffer uint, timeout time.Duration) *Manager {
 	mgr := &Manager{
-		stream:  make(chan interface{}, buffer),
+		buffer:  buffer,
 		timeout: timeout,
-		subs:    make(map[chan<- interface{}][]func(interface{}) bool),
+		subs:    make(map[chan<- interface{}]*subscriber),
 	}
 	return mgr
 }
 
-// Subscribe adds a subscriber to the event output.
+// Subscribe adds a subscriber to the event output, which receives the events
+// matching any of the given filters, or all events if there are none. When it
+// falls behind, its oldest buffered events are dropped.
 func (mgr *Manager) Subscribe(sub chan<- interface{}, filters ...func(interface{}) bool) {
-	mgr.subs[sub] = filters
+	mgr.SubscribeWith(sub, mgr.buffer, PolicyDropOldest, filters...)
+}
+
+// SubscribeWith adds a subscriber to the event output with the given buffer
+// size and policy for when the buffer is full. Subscribing an existing channel
+// again replaces its subscription. The buffer holds at least one event, as
+// dropping the oldest event from an empty buffer would never make room.
+func (mgr *Manager) SubscribeWith(sub chan<- interface{}, buffer uint, policy Policy, filters ...func(interface{}) bool) {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	if buffer == 0 {
+		buffer = 1
+	}
+
+	mgr.remove(sub)
+	s := &subscriber{
+		channel: sub,
+		filters: filters,
+		policy:  policy,
+		buffer:  make(chan interface{}, buffer),
+		stop:    make(chan struct{}),
+	}
+	mgr.subs[sub] = s
+	mgr.wg.Add(1)
+	go func() {
+		defer mgr.wg.Done()
+		s.deliver()
+	}()
 }
 
-// Unsubscribe removes a subscriber from the event output.
+// Unsubscribe removes a subscriber from the event output; events still in its
+// buffer are discarded.
 func (mgr *Manager) Unsubscribe(sub chan<- interface{}) {
-	delete(mgr.subs, sub)
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	mgr.remove(sub)
+}
+
+// Stop removes all subscribers and waits for their routines to finish.
+func (mgr *Manager) Stop() {
+	mgr.Lock()
+	for sub := range mgr.subs {
+		mgr.remove(sub)
+	}
+	mgr.Unlock()
+	mgr.wg.Wait()
 }
 
-// Header triggers a new header event.
+// Header creates a new event for a header accepted into our repository.
 func (mgr *Manager) Header(hash types.Hash) error {
-	return mgr.event(Header{hash: hash})
+	return mgr.event(&Header{Hash: hash})
 }
 
-// Transaction creates a new transaction event.
+// Transaction creates a new event for a transaction accepted into our pool.
 func (mgr *Manager) Transaction(hash types.Hash) error {
-	return mgr.event(Transaction{hash: hash})
+	return mgr.event(&Transaction{Hash: hash})
+}
+
+// Evicted creates a new event for a transaction evicted from our pool.
+func (mgr *Manager) Evicted(hash types.Hash) error {
+	return mgr.event(&Evicted{Hash: hash})
+}
+
+// Block creates a new event for a block connected to the blockchain.
+func (mgr *Manager) Block(hash types.Hash) error {
+	return mgr.event(&Block{Hash: hash})
+}
+
+// Disconnected creates a new event for a block disconnected from the
+// blockchain.
+func (mgr *Manager) Disconnected(hash types.Hash) error {
+	return mgr.event(&Disconnected{Hash: hash})
+}
+
+// Reorg creates a new event for a change of the best path, where depth is the
+// number of blocks that were disconnected from the old path.
+func (mgr *Manager) Reorg(old types.Hash, new types.Hash, ancestor types.Hash, depth uint) error {
+	return mgr.event(&Reorg{Old: old, New: new, Ancestor: ancestor, Depth: depth})
+}
+
+// Progress creates a new event for the progress of the synchronization.
+func (mgr *Manager) Progress(height uint64, best uint64) error {
+	return mgr.event(&Progress{Height: height, Best: best})
 }
 
-// event submits the event to the channel.
+// Sync creates a new event for a change of the synchronization phase.
+func (mgr *Manager) Sync(phase string, height uint64, best uint64) error {
+	return mgr.event(&Sync{Phase: phase, Height: height, Best: best})
+}
+
+// event submits the event to the buffers of all matching subscribers. We only
+// hold the lock to collect the subscribers, so that waiting on a blocking
+// subscriber does not hold up subscribing, unsubscribing or stopping.
 func (mgr *Manager) event(event interface{}) error {
-	select {
-	case mgr.stream <- event:
-	case <-time.After(mgr.timeout):
-		return errors.New("subscriber stalling")
+	mgr.Lock()
+	subs := make([]*subscriber, 0, len(mgr.subs))
+	for _, sub := range mgr.subs {
+		if !sub.match(event) {
+			continue
+		}
+		subs = append(subs, sub)
+	}
+	mgr.Unlock()
+
+	var result *multierror.Error
+	for _, sub := range subs {
+		err := sub.push(event, mgr.timeout)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
 	}
-	return nil
+
+	return result.ErrorOrNil()
 }
 
-// TODO: add processing for each subscriber (see stream.go)
+// remove stops the routine of a subscriber and forgets about it.
+func (mgr *Manager) remove(sub chan<- interface{}) {
+	s, ok := mgr.subs[sub]
+	if !ok {
+		return
+	}
+	close(s.stop)
+	delete(mgr.subs, sub)
+}
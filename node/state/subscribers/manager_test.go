@@ -0,0 +1,213 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package subscribers
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+func TestManagerDelivery(t *testing.T) {
+
+	// initialize parameters
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+
+	// initialize manager
+	mgr := NewManager(16, time.Second)
+	all := make(chan interface{}, 16)
+	blocks := make(chan interface{}, 16)
+	mgr.Subscribe(all)
+	mgr.Subscribe(blocks, IsType(TypeBlock, TypeDisconnected))
+
+	// execute events
+	_ = mgr.Header(hash1)
+	_ = mgr.Block(hash1)
+	_ = mgr.Transaction(hash2)
+	_ = mgr.Disconnected(hash1)
+	_ = mgr.Progress(1, 2)
+	_ = mgr.Sync("synced", 2, 2)
+
+	// check conditions
+	expected := []interface{}{
+		&Header{Hash: hash1},
+		&Block{Hash: hash1},
+		&Transaction{Hash: hash2},
+		&Disconnected{Hash: hash1},
+		&Progress{Height: 1, Best: 2},
+		&Sync{Phase: "synced", Height: 2, Best: 2},
+	}
+	for _, event := range expected {
+		assert.Equal(t, event, receive(all))
+	}
+	assert.Equal(t, &Block{Hash: hash1}, receive(blocks))
+	assert.Equal(t, &Disconnected{Hash: hash1}, receive(blocks))
+
+	mgr.Stop()
+	assert.Empty(t, all)
+	assert.Empty(t, blocks)
+}
+
+func TestManagerUnsubscribe(t *testing.T) {
+
+	// initialize parameters
+	hash := types.Hash{0x1}
+
+	// initialize manager
+	mgr := NewManager(16, time.Second)
+	sub := make(chan interface{}, 16)
+	mgr.Subscribe(sub)
+
+	// execute unsubscribe
+	mgr.Unsubscribe(sub)
+	err := mgr.Header(hash)
+	mgr.Stop()
+
+	// check conditions
+	assert.Nil(t, err)
+	assert.Empty(t, sub)
+	assert.Empty(t, mgr.subs)
+}
+
+func TestManagerStalling(t *testing.T) {
+
+	// initialize parameters
+	hash := types.Hash{0x1}
+
+	// initialize manager with a subscriber that never reads
+	mgr := NewManager(16, 100*time.Millisecond)
+	sub := make(chan interface{})
+	mgr.SubscribeWith(sub, 1, PolicyBlock)
+
+	// fill the buffer of the subscriber, while its routine waits on the channel
+	err1 := mgr.Header(hash)
+	err2 := mgr.Header(hash)
+	err3 := mgr.Header(hash)
+
+	// check conditions
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.NotNil(t, err3)
+
+	mgr.Stop()
+}
+
+func TestManagerZeroBuffer(t *testing.T) {
+
+	// initialize parameters
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+
+	// initialize manager with a subscriber that never reads
+	mgr := NewManager(16, time.Second)
+	sub := make(chan interface{})
+	mgr.SubscribeWith(sub, 0, PolicyDropOldest)
+
+	// execute events beyond the buffer
+	err1 := mgr.Header(hash1)
+	err2 := mgr.Header(hash2)
+	err3 := mgr.Header(hash2)
+
+	// check conditions
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Nil(t, err3)
+	assert.Equal(t, 1, cap(mgr.subs[sub].buffer))
+
+	mgr.Stop()
+}
+
+func TestManagerUnsubscribeBlocking(t *testing.T) {
+
+	// initialize parameters
+	hash := types.Hash{0x1}
+
+	// initialize manager with a subscriber that never reads
+	mgr := NewManager(16, time.Minute)
+	sub := make(chan interface{})
+	mgr.SubscribeWith(sub, 1, PolicyBlock)
+	_ = mgr.Header(hash)
+	_ = mgr.Header(hash)
+
+	// execute an event that blocks on the full buffer
+	done := make(chan error)
+	go func() {
+		done <- mgr.Header(hash)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	mgr.Unsubscribe(sub)
+
+	// check conditions
+	select {
+	case err := <-done:
+		assert.Nil(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("event still blocked after unsubscribe")
+	}
+
+	mgr.Stop()
+}
+
+func TestManagerConcurrency(t *testing.T) {
+
+	// initialize manager
+	mgr := NewManager(1024, time.Second)
+	all := make(chan interface{}, 1024)
+	mgr.SubscribeWith(all, 1024, PolicyBlock)
+
+	// subscribe, unsubscribe and emit events from many goroutines
+	wg := &sync.WaitGroup{}
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 16; i++ {
+				sub := make(chan interface{})
+				mgr.Subscribe(sub)
+				err := mgr.Header(types.Hash{byte(i)})
+				assert.Nil(t, err)
+				mgr.Unsubscribe(sub)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// check conditions
+	count := 0
+	for count < 16*16 {
+		assert.NotNil(t, receive(all))
+		count++
+	}
+	mgr.Stop()
+	assert.Len(t, mgr.subs, 0)
+}
+
+// receive waits for the next event on the subscriber channel.
+func receive(sub <-chan interface{}) interface{} {
+	select {
+	case event := <-sub:
+		return event
+	case <-time.After(time.Second):
+		return nil
+	}
+}
@@ -17,42 +17,93 @@
 
 package subscribers
 
-//
-// func handleStream(log zerolog.Logger, wg *sync.WaitGroup, stream <-chan interface{}, subscribers <-chan *subscriber) {
-// 	defer wg.Done()
-//
-// 	log = log.With().Str("component", "stream").Logger()
-// 	log.Debug().Msg("subscriber routine started")
-// 	defer log.Debug().Msg("subscriber routine stopped")
-//
-// 	var subs []*subscriber
-//
-// Loop:
-// 	for {
-// 		select {
-//
-// 		case sub, ok := <-subscribers:
-// 			if !ok {
-// 				break Loop
-// 			}
-// 			subs = append(subs, sub)
-//
-// 		case event, ok := <-stream:
-// 			if !ok {
-// 				break Loop
-// 			}
-// 			for _, sub := range subs {
-// 				for _, filter := range sub.filters {
-// 					if filter(event) {
-// 						continue Loop
-// 					}
-// 				}
-// 				select {
-// 				case sub.channel <- event:
-// 				case <-time.After(10 * time.Millisecond):
-// 					log.Warn().Msg("subscriber is stalling")
-// 				}
-// 			}
-// 		}
-// 	}
-// }
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Policy describes what happens to an event when the buffer of a subscriber
+// is full.
+type Policy uint8
+
+// List of possible policies for full subscriber buffers.
+const (
+	PolicyDropOldest Policy = iota
+	PolicyDropNewest
+	PolicyBlock
+)
+
+// subscriber buffers the events for a subscriber channel, so that a slow
+// subscriber does not hold up the node.
+type subscriber struct {
+	channel chan<- interface{}
+	filters []func(interface{}) bool
+	policy  Policy
+	buffer  chan interface{}
+	stop    chan struct{}
+}
+
+// match checks whether the subscriber wants the event; without filters, it
+// receives all events.
+func (sub *subscriber) match(event interface{}) bool {
+	if len(sub.filters) == 0 {
+		return true
+	}
+	for _, filter := range sub.filters {
+		if filter(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// push adds an event to the buffer of the subscriber according to its policy.
+// A subscriber that is removed while we block on its buffer no longer holds up
+// the event.
+func (sub *subscriber) push(event interface{}, timeout time.Duration) error {
+	switch sub.policy {
+	case PolicyDropNewest:
+		select {
+		case sub.buffer <- event:
+		default:
+		}
+	case PolicyBlock:
+		select {
+		case sub.buffer <- event:
+		case <-sub.stop:
+		case <-time.After(timeout):
+			return errors.Wrap(ErrStalling, "could not buffer event")
+		}
+	default:
+		for {
+			select {
+			case sub.buffer <- event:
+				return nil
+			default:
+			}
+			select {
+			case <-sub.buffer:
+			default:
+			}
+		}
+	}
+	return nil
+}
+
+// deliver forwards the buffered events to the subscriber channel until the
+// subscriber is removed.
+func (sub *subscriber) deliver() {
+	for {
+		select {
+		case <-sub.stop:
+			return
+		case event := <-sub.buffer:
+			select {
+			case sub.channel <- event:
+			case <-sub.stop:
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package subscribers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriberMatch(t *testing.T) {
+
+	// initialize entities
+	header := &Header{}
+	block := &Block{}
+	all := &subscriber{}
+	blocks := &subscriber{filters: []func(interface{}) bool{IsType(TypeBlock)}}
+
+	// check conditions
+	assert.True(t, all.match(header))
+	assert.True(t, all.match(block))
+	assert.False(t, blocks.match(header))
+	assert.True(t, blocks.match(block))
+}
+
+func TestSubscriberPushDropOldest(t *testing.T) {
+
+	// initialize subscriber
+	sub := &subscriber{policy: PolicyDropOldest, buffer: make(chan interface{}, 2)}
+
+	// execute push beyond the buffer
+	for i := 0; i < 4; i++ {
+		err := sub.push(i, 0)
+		assert.Nil(t, err)
+	}
+
+	// check conditions
+	assert.Equal(t, 2, <-sub.buffer)
+	assert.Equal(t, 3, <-sub.buffer)
+}
+
+func TestSubscriberPushDropNewest(t *testing.T) {
+
+	// initialize subscriber
+	sub := &subscriber{policy: PolicyDropNewest, buffer: make(chan interface{}, 2)}
+
+	// execute push beyond the buffer
+	for i := 0; i < 4; i++ {
+		err := sub.push(i, 0)
+		assert.Nil(t, err)
+	}
+
+	// check conditions
+	assert.Equal(t, 0, <-sub.buffer)
+	assert.Equal(t, 1, <-sub.buffer)
+}
+
+func TestSubscriberPushBlock(t *testing.T) {
+
+	// initialize subscriber
+	sub := &subscriber{policy: PolicyBlock, buffer: make(chan interface{}, 1)}
+
+	// execute push beyond the buffer
+	err1 := sub.push(0, time.Millisecond)
+	err2 := sub.push(1, time.Millisecond)
+
+	// check conditions
+	assert.Nil(t, err1)
+	if assert.NotNil(t, err2) {
+		assert.Equal(t, ErrStalling, errors.Cause(err2))
+	}
+	assert.Equal(t, 0, <-sub.buffer)
+}
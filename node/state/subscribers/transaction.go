@@ -19,7 +19,7 @@ package subscribers
 
 import "github.com/alvalor/alvalor-go/types"
 
-// Transaction event
+// Transaction event for a transaction accepted into our memory pool.
 type Transaction struct {
-	hash types.Hash
+	Hash types.Hash
 }
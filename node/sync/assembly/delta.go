@@ -0,0 +1,40 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package assembly
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/alvalor/alvalor-go/trie"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// Delta computes the transaction root of a block from the hashes of its
+// transactions, as committed to in the Delta field of the block header.
+func Delta(hashes []types.Hash) (types.Hash, error) {
+	var delta types.Hash
+	t := trie.NewBin()
+	for _, hash := range hashes {
+		err := t.Put(hash[:], hash[:])
+		if err != nil {
+			return delta, errors.Wrapf(ErrDuplicate, "could not insert transaction hash (%x)", hash)
+		}
+	}
+	copy(delta[:], t.Hash())
+	return delta, nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package assembly
+
+import (
+	"testing"
+
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelta(t *testing.T) {
+
+	// initialize parameters
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+	hash3 := types.Hash{0x3}
+
+	// execute delta
+	delta1, err1 := Delta([]types.Hash{hash1, hash2})
+	delta2, err2 := Delta([]types.Hash{hash2, hash1})
+	delta3, err3 := Delta([]types.Hash{hash1, hash3})
+	_, err4 := Delta([]types.Hash{hash1, hash1})
+
+	// check conditions
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Nil(t, err3)
+	assert.Equal(t, delta1, delta2)
+	assert.NotEqual(t, delta1, delta3)
+	assert.NotEqual(t, types.Hash{}, delta1)
+
+	if assert.NotNil(t, err4) {
+		assert.Equal(t, ErrDuplicate, errors.Cause(err4))
+	}
+}
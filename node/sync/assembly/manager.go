@@ -18,18 +18,36 @@
 package assembly
 
 import (
-	"github.com/alvalor/alvalor-go/types"
 	"github.com/pkg/errors"
+
+	"github.com/alvalor/alvalor-go/types"
 )
 
-// Manager is the manager to assemble and validate blocks.
+// Manager is the manager to assemble and validate blocks. Valid blocks are
+// staged for connection on the followed path, which only commits them to the
+// blockchain once they were applied to the account state.
 type Manager struct {
 	headers      Headers
 	inventories  Inventories
 	transactions Transactions
+	validator    Validator
+	paths        Paths
 }
 
-// Validate will assemble the block from our database and validate it.
+// NewManager creates a new manager to assemble blocks.
+func NewManager(headers Headers, inventories Inventories, transactions Transactions, validator Validator, paths Paths) *Manager {
+	am := &Manager{
+		headers:      headers,
+		inventories:  inventories,
+		transactions: transactions,
+		validator:    validator,
+		paths:        paths,
+	}
+	return am
+}
+
+// Validate will assemble the block from our database, validate it and stage it
+// for connection on the followed path.
 func (am *Manager) Validate(hash types.Hash) error {
 
 	// retrieve the header
@@ -45,7 +63,7 @@ func (am *Manager) Validate(hash types.Hash) error {
 	}
 
 	// build block
-	block := types.Block{
+	block := &types.Block{
 		Header: header,
 	}
 	for _, txHash := range inv.Hashes {
@@ -56,7 +74,45 @@ func (am *Manager) Validate(hash types.Hash) error {
 		block.Transactions = append(block.Transactions, tx)
 	}
 
-	// TODO: validate block
+	// validate the block
+	err = am.check(block, inv)
+	if err != nil {
+		return errors.Wrap(err, "could not validate block")
+	}
+
+	// stage the block for connection on the followed path
+	err = am.paths.Connect(block)
+	if err != nil {
+		return errors.Wrap(err, "could not connect block")
+	}
+
+	return nil
+}
+
+// check validates the assembled block against its inventory.
+func (am *Manager) check(block *types.Block, inv *types.Inventory) error {
+
+	// check the inventory belongs to the block
+	if inv.Hash != block.Hash {
+		return errors.Wrapf(ErrInventory, "inventory hash mismatch (%x != %x)", inv.Hash, block.Hash)
+	}
+
+	// check the transaction root matches the header
+	delta, err := Delta(inv.Hashes)
+	if err != nil {
+		return errors.Wrap(err, "could not compute transaction root")
+	}
+	if delta != block.Delta {
+		return errors.Wrapf(ErrDelta, "transaction root mismatch (%x != %x)", delta, block.Delta)
+	}
+
+	// check each transaction matches its hash and is signed by its senders
+	for _, tx := range block.Transactions {
+		err = am.validator.Validate(tx)
+		if err != nil {
+			return errors.Wrapf(ErrTransaction, "invalid transaction (%x): %v", tx.Hash, err)
+		}
+	}
 
 	return nil
 }
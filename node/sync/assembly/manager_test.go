@@ -0,0 +1,201 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package assembly
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/alvalor/alvalor-go/node/validation"
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/ed25519"
+)
+
+func newBlock(txs ...*types.Transaction) (*types.Header, *types.Inventory) {
+	header := &types.Header{}
+	inv := &types.Inventory{}
+	for _, tx := range txs {
+		tx.Hash = tx.GetHash()
+		inv.Hashes = append(inv.Hashes, tx.Hash)
+	}
+	header.Delta, _ = Delta(inv.Hashes)
+	header.Hash = header.GetHash()
+	inv.Hash = header.Hash
+	return header, inv
+}
+
+func TestManagerValidateSuccess(t *testing.T) {
+
+	// initialize entities
+	tx1 := &types.Transaction{Nonce: 1}
+	tx2 := &types.Transaction{Nonce: 2}
+	header, inv := newBlock(tx1, tx2)
+	block := &types.Block{Header: header, Transactions: []*types.Transaction{tx1, tx2}}
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	inventories := &InventoriesMock{}
+	transactions := &TransactionsMock{}
+	validator := &ValidatorMock{}
+	paths := &PathsMock{}
+
+	// initialize manager
+	am := NewManager(headers, inventories, transactions, validator, paths)
+
+	// program mocks
+	headers.On("Get", header.Hash).Return(header, nil)
+	inventories.On("Get", header.Hash).Return(inv, nil)
+	transactions.On("Get", tx1.Hash).Return(tx1, nil)
+	transactions.On("Get", tx2.Hash).Return(tx2, nil)
+	validator.On("Validate", mock.Anything).Return(nil)
+	paths.On("Connect", mock.Anything).Return(nil)
+
+	// execute validate
+	err := am.Validate(header.Hash)
+
+	// check conditions
+	assert.Nil(t, err)
+
+	if paths.AssertNumberOfCalls(t, "Connect", 1) {
+		paths.AssertCalled(t, "Connect", block)
+	}
+}
+
+func TestManagerValidateInvalid(t *testing.T) {
+
+	// initialize entities
+	tx1 := &types.Transaction{Nonce: 1}
+	tx2 := &types.Transaction{Nonce: 2}
+	header, inv := newBlock(tx1, tx2)
+	forged := &types.Transaction{Hash: tx2.Hash, Nonce: 3}
+	delta := *header
+	delta.Delta = types.Hash{0x1}
+	other := &types.Inventory{Hash: types.Hash{0x1}, Hashes: inv.Hashes}
+
+	// initialize vectors
+	vectors := map[string]struct {
+		header *types.Header
+		inv    *types.Inventory
+		tx     *types.Transaction
+		err    error
+	}{
+		"inventory_mismatch": {header: header, inv: other, tx: tx2, err: ErrInventory},
+		"delta_mismatch":     {header: &delta, inv: inv, tx: tx2, err: ErrDelta},
+		"transaction_forged": {header: header, inv: inv, tx: forged, err: ErrTransaction},
+	}
+
+	for name, vector := range vectors {
+
+		// initialize mocks
+		headers := &HeadersMock{}
+		inventories := &InventoriesMock{}
+		transactions := &TransactionsMock{}
+		validator := &ValidatorMock{}
+		paths := &PathsMock{}
+
+		// initialize manager
+		am := NewManager(headers, inventories, transactions, validator, paths)
+
+		// program mocks
+		headers.On("Get", mock.Anything).Return(vector.header, nil)
+		inventories.On("Get", mock.Anything).Return(vector.inv, nil)
+		transactions.On("Get", tx1.Hash).Return(tx1, nil)
+		transactions.On("Get", tx2.Hash).Return(vector.tx, nil)
+		validator.On("Validate", forged).Return(errors.New("forged"))
+		validator.On("Validate", mock.Anything).Return(nil)
+
+		// execute validate
+		err := am.Validate(header.Hash)
+
+		// check conditions
+		if assert.NotNil(t, err, name) {
+			assert.Equal(t, vector.err, errors.Cause(err), name)
+		}
+
+		paths.AssertNumberOfCalls(t, "Connect", 0)
+	}
+}
+
+func TestManagerValidateConnectFails(t *testing.T) {
+
+	// initialize entities
+	tx := &types.Transaction{Nonce: 1}
+	header, inv := newBlock(tx)
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	inventories := &InventoriesMock{}
+	transactions := &TransactionsMock{}
+	validator := &ValidatorMock{}
+	paths := &PathsMock{}
+
+	// initialize manager
+	am := NewManager(headers, inventories, transactions, validator, paths)
+
+	// program mocks
+	headers.On("Get", mock.Anything).Return(header, nil)
+	inventories.On("Get", mock.Anything).Return(inv, nil)
+	transactions.On("Get", mock.Anything).Return(tx, nil)
+	validator.On("Validate", mock.Anything).Return(nil)
+	paths.On("Connect", mock.Anything).Return(errors.New(""))
+
+	// execute validate
+	err := am.Validate(header.Hash)
+
+	// check conditions
+	assert.NotNil(t, err)
+}
+
+func TestManagerValidateForged(t *testing.T) {
+
+	// initialize entities
+	pub1, priv1, _ := ed25519.GenerateKey(bytes.NewReader(bytes.Repeat([]byte{1}, ed25519.SeedSize)))
+	pub2, _, _ := ed25519.GenerateKey(bytes.NewReader(bytes.Repeat([]byte{2}, ed25519.SeedSize)))
+	tx1 := &types.Transaction{Fees: []*types.Fee{{From: pub1, Amount: 1}}, Nonce: 1}
+	tx2 := &types.Transaction{Transfers: []*types.Transfer{{From: pub2, To: pub1, Amount: 100}}, Nonce: 2}
+	header, inv := newBlock(tx1, tx2)
+	tx1.Signatures = [][]byte{ed25519.Sign(priv1, tx1.Hash[:])}
+	tx2.Signatures = [][]byte{ed25519.Sign(priv1, tx2.Hash[:])}
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	inventories := &InventoriesMock{}
+	transactions := &TransactionsMock{}
+	paths := &PathsMock{}
+
+	// initialize manager with the real transaction validation
+	am := NewManager(headers, inventories, transactions, validation.NewTransaction(), paths)
+
+	// program mocks
+	headers.On("Get", header.Hash).Return(header, nil)
+	inventories.On("Get", header.Hash).Return(inv, nil)
+	transactions.On("Get", tx1.Hash).Return(tx1, nil)
+	transactions.On("Get", tx2.Hash).Return(tx2, nil)
+	paths.On("Connect", mock.Anything).Return(nil)
+
+	// execute validate
+	err := am.Validate(header.Hash)
+
+	// check conditions
+	assert.Equal(t, ErrTransaction, errors.Cause(err))
+
+	paths.AssertNumberOfCalls(t, "Connect", 0)
+}
@@ -0,0 +1,303 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package compact
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+	"github.com/alvalor/alvalor-go/node/repos/transactions"
+	"github.com/alvalor/alvalor-go/node/state/peers"
+	"github.com/alvalor/alvalor-go/node/sync/assembly"
+	"github.com/alvalor/alvalor-go/node/sync/download"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// Manager propagates new blocks as compact blocks and reconstructs the
+// compact blocks we receive from the transactions in our memory pool. Missing
+// transactions are requested from the peer who sent the compact block; if the
+// short IDs are ambiguous or the reconstruction fails for any other reason, we
+// fall back to downloading the full inventory.
+type Manager struct {
+	sync.Mutex
+	net          Network
+	peers        Peers
+	transactions Transactions
+	inventories  Inventories
+	download     Download
+	paths        Paths
+	timeout      time.Duration
+	max          uint
+	quota        uint
+	partials     map[types.Hash]*Partial
+	pending      map[string]uint
+}
+
+// NewManager creates a new compact block manager with the given timeout for
+// the delivery of missing transactions. We keep at most max partial blocks at
+// the same time, of which at most quota can come from the same peer.
+func NewManager(net Network, peers Peers, transactions Transactions, inventories Inventories, download Download, paths Paths, timeout time.Duration, max uint, quota uint) *Manager {
+	return &Manager{
+		net:          net,
+		peers:        peers,
+		transactions: transactions,
+		inventories:  inventories,
+		download:     download,
+		paths:        paths,
+		timeout:      timeout,
+		max:          max,
+		quota:        quota,
+		partials:     make(map[types.Hash]*Partial),
+		pending:      make(map[string]uint),
+	}
+}
+
+// Propagate sends the compact version of the given block to all peers that
+// don't have it yet.
+func (mgr *Manager) Propagate(header *types.Header, inv *types.Inventory) error {
+
+	// compute the short IDs with a fresh salt
+	nonce := rand.Uint64()
+	key := Key(header.Hash, nonce)
+	shortIDs := make([]uint64, 0, len(inv.Hashes))
+	for _, hash := range inv.Hashes {
+		shortIDs = append(shortIDs, ShortID(key, hash))
+	}
+
+	// send the compact block to the peers who don't have the block
+	compact := &message.Compact{
+		Header:   header,
+		Nonce:    nonce,
+		ShortIDs: shortIDs,
+	}
+	addresses := mgr.peers.Addresses(peers.HasEntity(peers.EntityYes, header.Hash))
+	err := mgr.net.Broadcast(compact, addresses...)
+	if err != nil {
+		return errors.Wrap(err, "could not broadcast compact block")
+	}
+
+	return nil
+}
+
+// Reconstruct tries to reconstruct the block from the given compact block,
+// received from the peer with the given address, and requests the missing
+// transactions from the peer.
+func (mgr *Manager) Reconstruct(address string, compact *message.Compact) error {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	// check if we are already reconstructing the block
+	hash := compact.Header.Hash
+	_, ok := mgr.partials[hash]
+	if ok {
+		return errors.Wrap(ErrExist, "block reconstruction already pending")
+	}
+
+	// check we don't wait for too many partial blocks; the full block is still
+	// downloaded once we sync the path of the header
+	if mgr.pending[address] >= mgr.quota {
+		return errors.Wrapf(ErrQuota, "reconstruction quota reached for peer (%v)", address)
+	}
+	if uint(len(mgr.partials)) >= mgr.max {
+		return errors.Wrap(ErrFull, "too many pending block reconstructions")
+	}
+
+	// create lookup of short IDs; duplicates can't be resolved
+	key := Key(hash, compact.Nonce)
+	lookup := make(map[uint64]int, len(compact.ShortIDs))
+	for index, shortID := range compact.ShortIDs {
+		_, ok := lookup[shortID]
+		if ok {
+			return mgr.fallback(hash)
+		}
+		lookup[shortID] = index
+	}
+
+	// match the transactions from our pool to the short IDs
+	var zero types.Hash
+	hashes := make([]types.Hash, len(compact.ShortIDs))
+	for _, tx := range mgr.transactions.Best(mgr.transactions.Count()) {
+		index, ok := lookup[ShortID(key, tx.Hash)]
+		if !ok {
+			continue
+		}
+		if hashes[index] != zero {
+			return mgr.fallback(hash)
+		}
+		hashes[index] = tx.Hash
+	}
+
+	// collect the indexes of the transactions we are missing
+	var missing []uint32
+	for index, txHash := range hashes {
+		if txHash == zero {
+			missing = append(missing, uint32(index))
+		}
+	}
+
+	// if we have all transactions, we can finish right away
+	if len(missing) == 0 {
+		return mgr.finish(compact.Header, hashes)
+	}
+
+	// otherwise, request the missing transactions from the peer
+	err := mgr.net.Send(address, &message.GetBlockTxs{Hash: hash, Indexes: missing})
+	if err != nil {
+		return errors.Wrap(err, "could not request missing transactions")
+	}
+
+	// remember the partial block until the transactions arrive
+	mgr.partials[hash] = &Partial{
+		Address:  address,
+		Header:   compact.Header,
+		Key:      key,
+		ShortIDs: compact.ShortIDs,
+		Hashes:   hashes,
+		Missing:  missing,
+		Deadline: time.Now().Add(mgr.timeout),
+	}
+	mgr.pending[address]++
+
+	return nil
+}
+
+// Fill completes the reconstruction of a partial block with the missing
+// transactions delivered by the peer with the given address.
+func (mgr *Manager) Fill(address string, blockTxs *message.BlockTxs) error {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	// check if we are waiting for the transactions from this peer
+	partial, ok := mgr.partials[blockTxs.Hash]
+	if !ok || partial.Address != address {
+		return errors.Wrap(ErrNotExist, "no pending block reconstruction")
+	}
+
+	// if the peer did not deliver exactly what we requested, fall back
+	if len(blockTxs.Transactions) != len(partial.Missing) {
+		return mgr.fallback(blockTxs.Hash)
+	}
+	for i, tx := range blockTxs.Transactions {
+		tx.Hash = tx.GetHash()
+		index := partial.Missing[i]
+		if ShortID(partial.Key, tx.Hash) != partial.ShortIDs[index] {
+			return mgr.fallback(blockTxs.Hash)
+		}
+	}
+
+	// add the transactions to our pool and complete the block
+	for i, tx := range blockTxs.Transactions {
+		err := mgr.transactions.Add(tx)
+		if err != nil && errors.Cause(err) != transactions.ErrExist {
+			return errors.Wrapf(err, "could not add block transaction (%x)", tx.Hash)
+		}
+		partial.Hashes[partial.Missing[i]] = tx.Hash
+	}
+	mgr.remove(blockTxs.Hash)
+
+	return mgr.finish(partial.Header, partial.Hashes)
+}
+
+// NotFound falls back to a full inventory download if the peer with the
+// given address could not deliver the missing transactions of a block.
+func (mgr *Manager) NotFound(address string, hash types.Hash) error {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	partial, ok := mgr.partials[hash]
+	if !ok || partial.Address != address {
+		return errors.Wrap(ErrNotExist, "no pending block reconstruction")
+	}
+
+	return mgr.fallback(hash)
+}
+
+// Check falls back to a full inventory download for all partial blocks whose
+// missing transactions were not delivered in time.
+func (mgr *Manager) Check() error {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	var result *multierror.Error
+	now := time.Now()
+	for hash, partial := range mgr.partials {
+		if now.Before(partial.Deadline) {
+			continue
+		}
+		err := mgr.fallback(hash)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+// finish checks the reconstructed transaction hashes against the header and
+// stores the inventory of the block.
+func (mgr *Manager) finish(header *types.Header, hashes []types.Hash) error {
+
+	// a short ID might have matched the wrong transaction
+	delta, err := assembly.Delta(hashes)
+	if err != nil || delta != header.Delta {
+		return mgr.fallback(header.Hash)
+	}
+
+	// store the inventory and signal it like a downloaded one
+	inv := &types.Inventory{Hash: header.Hash, Hashes: hashes}
+	err = mgr.inventories.Add(inv)
+	if err != nil {
+		return errors.Wrap(err, "could not store reconstructed inventory")
+	}
+	err = mgr.paths.Signal(header.Hash)
+	if err != nil {
+		return errors.Wrap(err, "could not signal reconstructed inventory")
+	}
+
+	return nil
+}
+
+// fallback drops the reconstruction of a block and starts the download of its
+// full inventory instead.
+func (mgr *Manager) fallback(hash types.Hash) error {
+	mgr.remove(hash)
+	err := mgr.download.StartInv(hash)
+	if err != nil && errors.Cause(err) != download.ErrExist {
+		return errors.Wrap(err, "could not start inventory download")
+	}
+	return nil
+}
+
+// remove drops the partial block with the given hash, if there is one.
+func (mgr *Manager) remove(hash types.Hash) {
+	partial, ok := mgr.partials[hash]
+	if !ok {
+		return
+	}
+	delete(mgr.partials, hash)
+	if mgr.pending[partial.Address] <= 1 {
+		delete(mgr.pending, partial.Address)
+		return
+	}
+	mgr.pending[partial.Address]--
+}
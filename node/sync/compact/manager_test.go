@@ -0,0 +1,429 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package compact
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+	"github.com/alvalor/alvalor-go/node/sync/assembly"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// block creates a header and the transactions of a block for testing.
+func block(t *testing.T, n int) (*types.Header, []*types.Transaction) {
+	var txs []*types.Transaction
+	var hashes []types.Hash
+	for i := 0; i < n; i++ {
+		tx := &types.Transaction{Nonce: uint64(i + 1)}
+		tx.Hash = tx.GetHash()
+		txs = append(txs, tx)
+		hashes = append(hashes, tx.Hash)
+	}
+	delta, err := assembly.Delta(hashes)
+	require.Nil(t, err)
+	header := &types.Header{Delta: delta}
+	header.Hash = header.GetHash()
+	return header, txs
+}
+
+// compress creates the compact block for the given block.
+func compress(header *types.Header, txs []*types.Transaction, nonce uint64) *message.Compact {
+	key := Key(header.Hash, nonce)
+	compact := &message.Compact{Header: header, Nonce: nonce}
+	for _, tx := range txs {
+		compact.ShortIDs = append(compact.ShortIDs, ShortID(key, tx.Hash))
+	}
+	return compact
+}
+
+// hashes returns the hashes of the given transactions.
+func hashes(txs []*types.Transaction) []types.Hash {
+	var hashes []types.Hash
+	for _, tx := range txs {
+		hashes = append(hashes, tx.Hash)
+	}
+	return hashes
+}
+
+func TestManagerPropagate(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	header, txs := block(t, 3)
+	inv := &types.Inventory{Hash: header.Hash, Hashes: hashes(txs)}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	peers := &PeersMock{}
+
+	// initialize manager
+	mgr := NewManager(net, peers, nil, nil, nil, nil, time.Second, 16, 4)
+
+	// program mocks
+	peers.On("Addresses", mock.Anything).Return([]string{address})
+	net.On("Broadcast", mock.Anything, mock.Anything).Return(nil)
+
+	// execute propagate
+	err := mgr.Propagate(header, inv)
+
+	// check conditions
+	assert.Nil(t, err)
+
+	if net.AssertNumberOfCalls(t, "Broadcast", 1) {
+		net.AssertCalled(t, "Broadcast", mock.Anything, []string{address})
+		compact := net.Calls[0].Arguments.Get(0).(*message.Compact)
+		assert.Equal(t, header, compact.Header)
+		assert.Equal(t, compress(header, txs, compact.Nonce), compact)
+	}
+}
+
+func TestManagerReconstructComplete(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	header, txs := block(t, 3)
+	compact := compress(header, txs, 1337)
+	extra := &types.Transaction{Nonce: 1337}
+	extra.Hash = extra.GetHash()
+	pool := []*types.Transaction{txs[2], extra, txs[0], txs[1]}
+	inv := &types.Inventory{Hash: header.Hash, Hashes: hashes(txs)}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	transactions := &TransactionsMock{}
+	inventories := &InventoriesMock{}
+	paths := &PathsMock{}
+
+	// initialize manager
+	mgr := NewManager(net, nil, transactions, inventories, nil, paths, time.Second, 16, 4)
+
+	// program mocks
+	transactions.On("Count").Return(uint(len(pool)))
+	transactions.On("Best", mock.Anything).Return(pool)
+	inventories.On("Add", mock.Anything).Return(nil)
+	paths.On("Signal", mock.Anything).Return(nil)
+
+	// execute reconstruct
+	err := mgr.Reconstruct(address, compact)
+
+	// check conditions
+	assert.Nil(t, err)
+
+	net.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+	inventories.AssertCalled(t, "Add", inv)
+	paths.AssertCalled(t, "Signal", header.Hash)
+	assert.Empty(t, mgr.partials)
+}
+
+func TestManagerReconstructMissing(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	header, txs := block(t, 3)
+	compact := compress(header, txs, 1337)
+	pool := []*types.Transaction{txs[0], txs[2]}
+	getBlockTxs := &message.GetBlockTxs{Hash: header.Hash, Indexes: []uint32{1}}
+	blockTxs := &message.BlockTxs{Hash: header.Hash, Transactions: []*types.Transaction{txs[1]}}
+	inv := &types.Inventory{Hash: header.Hash, Hashes: hashes(txs)}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	transactions := &TransactionsMock{}
+	inventories := &InventoriesMock{}
+	paths := &PathsMock{}
+
+	// initialize manager
+	mgr := NewManager(net, nil, transactions, inventories, nil, paths, time.Second, 16, 4)
+
+	// program mocks
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+	transactions.On("Count").Return(uint(len(pool)))
+	transactions.On("Best", mock.Anything).Return(pool)
+	transactions.On("Add", mock.Anything).Return(nil)
+	inventories.On("Add", mock.Anything).Return(nil)
+	paths.On("Signal", mock.Anything).Return(nil)
+
+	// execute reconstruct
+	err := mgr.Reconstruct(address, compact)
+
+	// check conditions
+	assert.Nil(t, err)
+
+	net.AssertCalled(t, "Send", address, getBlockTxs)
+	inventories.AssertNotCalled(t, "Add", mock.Anything)
+	assert.Contains(t, mgr.partials, header.Hash)
+	assert.Equal(t, uint(1), mgr.pending[address])
+
+	// execute fill
+	err = mgr.Fill(address, blockTxs)
+
+	// check conditions
+	assert.Nil(t, err)
+
+	transactions.AssertCalled(t, "Add", txs[1])
+	inventories.AssertCalled(t, "Add", inv)
+	paths.AssertCalled(t, "Signal", header.Hash)
+	assert.Empty(t, mgr.partials)
+	assert.Empty(t, mgr.pending)
+}
+
+func TestManagerReconstructLimits(t *testing.T) {
+
+	// initialize parameters
+	address1 := "192.0.2.1"
+	address2 := "192.0.2.2"
+
+	// initialize entities
+	header, txs := block(t, 1)
+	compact := compress(header, txs, 1337)
+
+	// initialize mocks
+	transactions := &TransactionsMock{}
+
+	// initialize manager
+	mgr := NewManager(nil, nil, transactions, nil, nil, nil, time.Second, 2, 1)
+	mgr.partials[types.Hash{0x1}] = &Partial{Address: address1}
+	mgr.pending[address1] = 1
+
+	// execute reconstruct beyond the peer quota
+	err := mgr.Reconstruct(address1, compact)
+
+	// check conditions
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrQuota, errors.Cause(err))
+	}
+
+	// execute reconstruct beyond the total limit
+	mgr.partials[types.Hash{0x2}] = &Partial{Address: "192.0.2.3"}
+	mgr.pending["192.0.2.3"] = 1
+	err = mgr.Reconstruct(address2, compact)
+
+	// check conditions
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrFull, errors.Cause(err))
+	}
+	transactions.AssertNotCalled(t, "Best", mock.Anything)
+}
+
+func TestManagerReconstructExist(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	header, txs := block(t, 1)
+	compact := compress(header, txs, 1337)
+
+	// initialize manager
+	mgr := NewManager(nil, nil, nil, nil, nil, nil, time.Second, 16, 4)
+	mgr.partials[header.Hash] = &Partial{}
+
+	// execute reconstruct
+	err := mgr.Reconstruct(address, compact)
+
+	// check conditions
+	assert.Equal(t, ErrExist, errors.Cause(err))
+}
+
+func TestManagerReconstructDuplicateShortIDs(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	header, txs := block(t, 2)
+	compact := compress(header, txs, 1337)
+	compact.ShortIDs[1] = compact.ShortIDs[0]
+
+	// initialize mocks
+	download := &DownloadMock{}
+	inventories := &InventoriesMock{}
+
+	// initialize manager
+	mgr := NewManager(nil, nil, nil, inventories, download, nil, time.Second, 16, 4)
+
+	// program mocks
+	download.On("StartInv", mock.Anything).Return(nil)
+
+	// execute reconstruct
+	err := mgr.Reconstruct(address, compact)
+
+	// check conditions
+	assert.Nil(t, err)
+
+	download.AssertCalled(t, "StartInv", header.Hash)
+	inventories.AssertNotCalled(t, "Add", mock.Anything)
+}
+
+func TestManagerReconstructDeltaMismatch(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	header, txs := block(t, 2)
+	header.Delta = types.Hash{0x1}
+	compact := compress(header, txs, 1337)
+
+	// initialize mocks
+	transactions := &TransactionsMock{}
+	inventories := &InventoriesMock{}
+	download := &DownloadMock{}
+
+	// initialize manager
+	mgr := NewManager(nil, nil, transactions, inventories, download, nil, time.Second, 16, 4)
+
+	// program mocks
+	transactions.On("Count").Return(uint(len(txs)))
+	transactions.On("Best", mock.Anything).Return(txs)
+	download.On("StartInv", mock.Anything).Return(nil)
+
+	// execute reconstruct
+	err := mgr.Reconstruct(address, compact)
+
+	// check conditions
+	assert.Nil(t, err)
+
+	download.AssertCalled(t, "StartInv", header.Hash)
+	inventories.AssertNotCalled(t, "Add", mock.Anything)
+}
+
+func TestManagerFillMismatch(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	header, txs := block(t, 2)
+	compact := compress(header, txs, 1337)
+	other := &types.Transaction{Nonce: 1337}
+	blockTxs := &message.BlockTxs{Hash: header.Hash, Transactions: []*types.Transaction{other}}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	transactions := &TransactionsMock{}
+	download := &DownloadMock{}
+
+	// initialize manager
+	mgr := NewManager(net, nil, transactions, nil, download, nil, time.Second, 16, 4)
+
+	// program mocks
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+	transactions.On("Count").Return(uint(1))
+	transactions.On("Best", mock.Anything).Return(txs[:1])
+	download.On("StartInv", mock.Anything).Return(nil)
+
+	// execute reconstruct and fill
+	err := mgr.Reconstruct(address, compact)
+	require.Nil(t, err)
+	err = mgr.Fill(address, blockTxs)
+
+	// check conditions
+	assert.Nil(t, err)
+
+	transactions.AssertNotCalled(t, "Add", mock.Anything)
+	download.AssertCalled(t, "StartInv", header.Hash)
+	assert.Empty(t, mgr.partials)
+}
+
+func TestManagerFillNotExist(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize entities
+	blockTxs := &message.BlockTxs{Hash: types.Hash{0x1}}
+
+	// initialize manager
+	mgr := NewManager(nil, nil, nil, nil, nil, nil, time.Second, 16, 4)
+
+	// execute fill
+	err := mgr.Fill(address, blockTxs)
+
+	// check conditions
+	assert.Equal(t, ErrNotExist, errors.Cause(err))
+}
+
+func TestManagerNotFound(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	hash := types.Hash{0x1}
+
+	// initialize mocks
+	download := &DownloadMock{}
+
+	// initialize manager
+	mgr := NewManager(nil, nil, nil, nil, download, nil, time.Second, 16, 4)
+	mgr.partials[hash] = &Partial{Address: address}
+
+	// program mocks
+	download.On("StartInv", mock.Anything).Return(nil)
+
+	// execute not found
+	err := mgr.NotFound(address, hash)
+
+	// check conditions
+	assert.Nil(t, err)
+
+	download.AssertCalled(t, "StartInv", hash)
+	assert.Empty(t, mgr.partials)
+}
+
+func TestManagerCheck(t *testing.T) {
+
+	// initialize parameters
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+
+	// initialize mocks
+	download := &DownloadMock{}
+
+	// initialize manager
+	mgr := NewManager(nil, nil, nil, nil, download, nil, time.Second, 16, 4)
+	mgr.partials[hash1] = &Partial{Deadline: time.Now().Add(-time.Second)}
+	mgr.partials[hash2] = &Partial{Deadline: time.Now().Add(time.Minute)}
+
+	// program mocks
+	download.On("StartInv", mock.Anything).Return(nil)
+
+	// execute check
+	err := mgr.Check()
+
+	// check conditions
+	assert.Nil(t, err)
+
+	if download.AssertNumberOfCalls(t, "StartInv", 1) {
+		download.AssertCalled(t, "StartInv", hash1)
+	}
+	assert.NotContains(t, mgr.partials, hash1)
+	assert.Contains(t, mgr.partials, hash2)
+}
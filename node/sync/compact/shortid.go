@@ -0,0 +1,49 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package compact
+
+import (
+	"encoding/binary"
+
+	"golang.org/x/crypto/blake2s"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// ShortIDLength is the number of bytes of the salted transaction hash we keep
+// as short ID.
+const ShortIDLength = 6
+
+// Key derives the salt for the short IDs of a block from its hash and the
+// nonce chosen by the sender, so that collisions can't be precomputed.
+func Key(hash types.Hash, nonce uint64) []byte {
+	data := make([]byte, len(hash)+8)
+	copy(data, hash[:])
+	binary.LittleEndian.PutUint64(data[len(hash):], nonce)
+	key := blake2s.Sum256(data)
+	return key[:]
+}
+
+// ShortID computes the short ID of a transaction hash with the given key.
+func ShortID(key []byte, hash types.Hash) uint64 {
+	h, _ := blake2s.New256(key)
+	_, _ = h.Write(hash[:])
+	data := make([]byte, 8)
+	copy(data, h.Sum(nil)[:ShortIDLength])
+	return binary.LittleEndian.Uint64(data)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package compact
+
+import (
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/stretchr/testify/mock"
+)
+
+// TransactionsMock mocks the transaction pool interface.
+type TransactionsMock struct {
+	mock.Mock
+}
+
+// Add mocks the add function of the transaction pool interface.
+func (tm *TransactionsMock) Add(tx *types.Transaction) error {
+	args := tm.Called(tx)
+	return args.Error(0)
+}
+
+// Count mocks the count function of the transaction pool interface.
+func (tm *TransactionsMock) Count() uint {
+	args := tm.Called()
+	return args.Get(0).(uint)
+}
+
+// Best mocks the best function of the transaction pool interface.
+func (tm *TransactionsMock) Best(n uint) []*types.Transaction {
+	args := tm.Called(n)
+	var txs []*types.Transaction
+	if args.Get(0) != nil {
+		txs = args.Get(0).([]*types.Transaction)
+	}
+	return txs
+}
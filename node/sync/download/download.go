@@ -0,0 +1,34 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package download
+
+import (
+	"time"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// Download represents a pending download of an entity from a peer.
+type Download struct {
+	Hash     types.Hash
+	Address  string
+	Sent     time.Time
+	Deadline time.Time
+	Attempts uint
+	Tried    map[string]struct{}
+}
@@ -23,4 +23,5 @@ import "errors"
 var (
 	ErrExist    = errors.New("download already exists")
 	ErrNotExist = errors.New("download does not exist")
+	ErrNoPeers  = errors.New("no peers available for download")
 )
@@ -19,7 +19,9 @@ package download
 
 import (
 	"sync"
+	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 
 	"github.com/alvalor/alvalor-go/node/handlers/message"
@@ -27,105 +29,388 @@ import (
 	"github.com/alvalor/alvalor-go/types"
 )
 
-// Manager implement a simple download manager.
+// maxBackoff is the maximum number of times the timeout is doubled for retries.
+const maxBackoff = 4
+
+// Manager implement a simple download manager. Each download has a deadline;
+// if the peer does not deliver in time, the download is retried with another
+// peer and an exponentially growing deadline. The delivery latency of each
+// peer is tracked and used to select the peers to download from. Transactions
+// are requested in batches, grouped by the peer they are downloaded from.
 type Manager struct {
 	sync.Mutex
-	net   Network
-	peers Peers
-	invs  map[types.Hash]string
-	txs   map[types.Hash]string
+	net        Network
+	peers      Peers
+	timeout    time.Duration
+	maxPending uint
+	invs       map[types.Hash]*Download
+	txs        map[types.Hash]*Download
+	latency    map[string]time.Duration
+}
+
+// NewManager creates a new download manager with the given download timeout
+// and maximum number of pending downloads per peer.
+func NewManager(net Network, peers Peers, timeout time.Duration, maxPending uint) *Manager {
+	return &Manager{
+		net:        net,
+		peers:      peers,
+		timeout:    timeout,
+		maxPending: maxPending,
+		invs:       make(map[types.Hash]*Download),
+		txs:        make(map[types.Hash]*Download),
+		latency:    make(map[string]time.Duration),
+	}
 }
 
 // StartInv starts the download of a block inventory.
 func (mgr *Manager) StartInv(hash types.Hash) error {
+	mgr.Lock()
+	defer mgr.Unlock()
 
 	// if we are already downloading the inventory, skip
 	_, ok := mgr.invs[hash]
 	if ok {
-		return errors.New("inventory download already pending")
+		return errors.Wrap(ErrExist, "inventory download already pending")
 	}
 
-	// get all active peers that have the desired entity
-	has := mgr.peers.Addresses(peers.IsActive(true), peers.HasEntity(true, hash))
-	may := mgr.peers.Addresses(peers.IsActive(true), peers.MayEntity(hash))
-	if len(has) == 0 && len(may) == 0 {
-		return errors.New("no active peers with entity available")
+	// pick the best candidate for the download
+	dl := &Download{Hash: hash, Tried: make(map[string]struct{})}
+	err := mgr.pick(dl, mgr.count())
+	if err != nil {
+		return errors.Wrap(err, "could not pick peer for inventory download")
 	}
 
-	// send the request to the best candidate
-	address := Select(has, may, mgr.count())
-	msg := &message.GetInv{Hash: hash}
-	err := mgr.net.Send(address, msg)
+	// send the request to the candidate
+	err = mgr.net.Send(dl.Address, &message.GetInv{Hash: hash})
 	if err != nil {
 		return errors.Wrap(err, "could not send inventory request")
 	}
 
 	// mark the request as pending for this peer
-	mgr.invs[hash] = address
-
-	// TODO: add timeout & retry functionality
+	mgr.invs[hash] = dl
 
 	return nil
 }
 
 // StartTx starts the download of a transaction.
 func (mgr *Manager) StartTx(hash types.Hash) error {
+	mgr.Lock()
+	defer mgr.Unlock()
 
 	// if we are already downloading the transaction, skip
 	_, ok := mgr.txs[hash]
 	if ok {
-		return errors.New("inventory download already pending")
+		return errors.Wrap(ErrExist, "transaction download already pending")
 	}
 
-	// get all active peers that have the desired entity
-	has := mgr.peers.Addresses(peers.IsActive(true), peers.HasEntity(true, hash))
-	may := mgr.peers.Addresses(peers.IsActive(true), peers.MayEntity(hash))
-	if len(has) == 0 && len(may) == 0 {
-		return errors.New("no active peers with entity available")
+	return mgr.startTxs([]types.Hash{hash})
+}
+
+// StartTxs starts the download of a number of transactions, skipping the ones
+// that are already pending. The transactions are requested in batches from the
+// best candidates.
+func (mgr *Manager) StartTxs(hashes []types.Hash) error {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	// skip the transactions we are already downloading
+	var missing []types.Hash
+	for _, hash := range hashes {
+		_, ok := mgr.txs[hash]
+		if ok {
+			continue
+		}
+		missing = append(missing, hash)
+	}
+
+	return mgr.startTxs(missing)
+}
+
+// startTxs picks the best candidate for each transaction and requests them.
+func (mgr *Manager) startTxs(hashes []types.Hash) error {
+
+	// pick the best candidate for each transaction
+	var result *multierror.Error
+	var dls []*Download
+	count := mgr.count()
+	for _, hash := range hashes {
+		dl := &Download{Hash: hash, Tried: make(map[string]struct{})}
+		err := mgr.pick(dl, count)
+		if err != nil {
+			result = multierror.Append(result, errors.Wrapf(err, "could not pick peer for transaction download (%x)", hash))
+			continue
+		}
+		dls = append(dls, dl)
 	}
 
-	// send the request to the best candidate
-	address := Select(has, may, mgr.count())
-	msg := &message.GetTx{Hash: hash}
-	err := mgr.net.Send(address, msg)
+	// send the requests and mark the sent ones as pending
+	sent, err := mgr.request(dls)
 	if err != nil {
-		return errors.Wrap(err, "could not send inventory request")
+		result = multierror.Append(result, err)
+	}
+	for _, dl := range sent {
+		mgr.txs[dl.Hash] = dl
 	}
 
-	// mark the request as pending for this peer
-	mgr.txs[hash] = address
+	return result.ErrorOrNil()
+}
 
-	// TODO: add timeout & retry functionality
+// request sends batched requests for the given transaction downloads to their
+// respective peers and returns the downloads that were successfully sent.
+func (mgr *Manager) request(dls []*Download) ([]*Download, error) {
+
+	// group the downloads by peer
+	groups := make(map[string][]*Download)
+	for _, dl := range dls {
+		groups[dl.Address] = append(groups[dl.Address], dl)
+	}
+
+	// send the requests to each peer in batches
+	var result *multierror.Error
+	var sent []*Download
+	for address, group := range groups {
+		for len(group) > 0 {
+			n := len(group)
+			if n > message.MaxBatchTxs {
+				n = message.MaxBatchTxs
+			}
+			hashes := make([]types.Hash, 0, n)
+			for _, dl := range group[:n] {
+				hashes = append(hashes, dl.Hash)
+			}
+			err := mgr.net.Send(address, &message.Request{Hashes: hashes})
+			if err != nil {
+				result = multierror.Append(result, errors.Wrapf(err, "could not send transaction request (%s)", address))
+			} else {
+				sent = append(sent, group[:n]...)
+			}
+			group = group[n:]
+		}
+	}
+
+	return sent, result.ErrorOrNil()
+}
+
+// Check retries all downloads that have passed their deadline with a
+// different peer, doubling the deadline for each attempt.
+func (mgr *Manager) Check() error {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	now := time.Now()
+	count := mgr.count()
+	var result *multierror.Error
+	for hash, dl := range mgr.invs {
+		if now.Before(dl.Deadline) {
+			continue
+		}
+		err := mgr.retry(dl, count)
+		if err != nil {
+			result = multierror.Append(result, errors.Wrapf(err, "could not retry inventory download (%x)", hash))
+			continue
+		}
+		err = mgr.net.Send(dl.Address, &message.GetInv{Hash: hash})
+		if err != nil {
+			result = multierror.Append(result, errors.Wrapf(err, "could not send inventory request (%x)", hash))
+		}
+	}
+	var dls []*Download
+	for hash, dl := range mgr.txs {
+		if now.Before(dl.Deadline) {
+			continue
+		}
+		err := mgr.retry(dl, count)
+		if err != nil {
+			result = multierror.Append(result, errors.Wrapf(err, "could not retry transaction download (%x)", hash))
+			continue
+		}
+		dls = append(dls, dl)
+	}
+	_, err := mgr.request(dls)
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
+
+	return result.ErrorOrNil()
+}
+
+// retry penalizes the peer of a timed out download and reroutes it.
+func (mgr *Manager) retry(dl *Download, count map[string]uint) error {
+
+	// count the timeout as a very slow delivery for the peer
+	mgr.sample(dl.Address, 2*mgr.timeout)
+
+	return mgr.reroute(dl, count)
+}
+
+// reroute picks a new candidate for the download, preferring peers that we
+// did not try yet. If the request fails, the download is retried later.
+func (mgr *Manager) reroute(dl *Download, count map[string]uint) error {
+
+	// if we tried all peers with the entity, start over with all of them
+	dl.Attempts++
+	err := mgr.pick(dl, count)
+	if errors.Cause(err) == ErrNoPeers {
+		dl.Tried = make(map[string]struct{})
+		err = mgr.pick(dl, count)
+	}
+	if err != nil {
+		dl.Deadline = time.Now().Add(mgr.backoff(dl.Attempts))
+		return err
+	}
+
+	return nil
+}
+
+// pick assigns the download to the best candidate that was not tried yet and
+// that has capacity for more pending downloads.
+func (mgr *Manager) pick(dl *Download, count map[string]uint) error {
+
+	// get all active peers that have the desired entity
+	has := mgr.filter(mgr.peers.Addresses(peers.IsActive(true), peers.HasEntity(peers.EntityYes, dl.Hash)), dl, count)
+	may := mgr.filter(mgr.peers.Addresses(peers.IsActive(true), peers.HasEntity(peers.EntityMaybe, dl.Hash)), dl, count)
+	if len(has) == 0 && len(may) == 0 {
+		return errors.Wrap(ErrNoPeers, "no active peers with entity available")
+	}
+
+	// update the download to the new peer and deadline
+	address := Select(has, may, count, mgr.latency)
+	now := time.Now()
+	dl.Address = address
+	dl.Sent = now
+	dl.Deadline = now.Add(mgr.backoff(dl.Attempts))
+	dl.Tried[address] = struct{}{}
+	count[address]++
 
 	return nil
 }
 
+// filter removes the candidates that were already tried for the download or
+// that have too many pending downloads.
+func (mgr *Manager) filter(candidates []string, dl *Download, count map[string]uint) []string {
+	var filtered []string
+	for _, candidate := range candidates {
+		_, tried := dl.Tried[candidate]
+		if tried || count[candidate] >= mgr.maxPending {
+			continue
+		}
+		filtered = append(filtered, candidate)
+	}
+	return filtered
+}
+
+// backoff returns the timeout for the given attempt.
+func (mgr *Manager) backoff(attempts uint) time.Duration {
+	if attempts > maxBackoff {
+		attempts = maxBackoff
+	}
+	return mgr.timeout << attempts
+}
+
+// sample adds a delivery latency sample to the moving average of the peer.
+func (mgr *Manager) sample(address string, latency time.Duration) {
+	average, ok := mgr.latency[address]
+	if !ok {
+		mgr.latency[address] = latency
+		return
+	}
+	mgr.latency[address] = (3*average + latency) / 4
+}
+
 // counts returns the number of pending downloads per address.
 func (mgr *Manager) count() map[string]uint {
 	count := make(map[string]uint)
-	for _, address := range mgr.invs {
-		count[address]++
+	for _, dl := range mgr.invs {
+		count[dl.Address]++
 	}
-	for _, address := range mgr.txs {
-		count[address]++
+	for _, dl := range mgr.txs {
+		count[dl.Address]++
 	}
 	return count
 }
 
+// Received marks the download of the entity with the given hash as delivered
+// by the given peer and updates the delivery latency of the peer.
+func (mgr *Manager) Received(address string, hash types.Hash) {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	dl, ok := mgr.invs[hash]
+	if ok {
+		delete(mgr.invs, hash)
+	} else {
+		dl, ok = mgr.txs[hash]
+		delete(mgr.txs, hash)
+	}
+	if !ok || dl.Address != address {
+		return
+	}
+
+	mgr.sample(address, time.Since(dl.Sent))
+}
+
+// NotFound immediately reroutes the download of the entity with the given hash
+// if it was pending with the peer that doesn't have it.
+func (mgr *Manager) NotFound(address string, hash types.Hash) error {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	dl, ok := mgr.invs[hash]
+	if ok && dl.Address == address {
+		err := mgr.reroute(dl, mgr.count())
+		if err != nil {
+			return errors.Wrap(err, "could not reroute inventory download")
+		}
+		return mgr.net.Send(dl.Address, &message.GetInv{Hash: hash})
+	}
+	dl, ok = mgr.txs[hash]
+	if ok && dl.Address == address {
+		err := mgr.reroute(dl, mgr.count())
+		if err != nil {
+			return errors.Wrap(err, "could not reroute transaction download")
+		}
+		_, err = mgr.request([]*Download{dl})
+		return err
+	}
+
+	return errors.Wrap(ErrNotExist, "download for peer not found")
+}
+
+// Latency returns the average delivery latency of the given peer.
+func (mgr *Manager) Latency(address string) (time.Duration, bool) {
+	mgr.Lock()
+	defer mgr.Unlock()
+	latency, ok := mgr.latency[address]
+	return latency, ok
+}
+
 // HasInv checks whether we are currently trying to download an inventory.
 func (mgr *Manager) HasInv(hash types.Hash) bool {
+	mgr.Lock()
+	defer mgr.Unlock()
 	_, ok := mgr.invs[hash]
 	return ok
 }
 
 // HasTx checks whether we are currently trying to download a transaction.
 func (mgr *Manager) HasTx(hash types.Hash) bool {
+	mgr.Lock()
+	defer mgr.Unlock()
 	_, ok := mgr.txs[hash]
 	return ok
 }
 
+// Pending returns the number of pending inventory and transaction downloads.
+func (mgr *Manager) Pending() (uint, uint) {
+	mgr.Lock()
+	defer mgr.Unlock()
+	return uint(len(mgr.invs)), uint(len(mgr.txs))
+}
+
 // CancelInv cancels the download of a block inventory.
 func (mgr *Manager) CancelInv(hash types.Hash) error {
+	mgr.Lock()
+	defer mgr.Unlock()
 
 	// find which peer is currently pending for this download
 	_, ok := mgr.invs[hash]
@@ -133,8 +418,6 @@ func (mgr *Manager) CancelInv(hash types.Hash) error {
 		return errors.Wrap(ErrNotExist, "inventory download not found")
 	}
 
-	// TODO: cancel timeout and abort download
-
 	// remove the pending entry
 	delete(mgr.invs, hash)
 
@@ -143,15 +426,15 @@ func (mgr *Manager) CancelInv(hash types.Hash) error {
 
 // CancelTx cancels the download of a block inventory.
 func (mgr *Manager) CancelTx(hash types.Hash) error {
+	mgr.Lock()
+	defer mgr.Unlock()
 
 	// find which peer is currently pending for this download
 	_, ok := mgr.txs[hash]
 	if !ok {
-		return errors.New("transaction download not found")
+		return errors.Wrap(ErrNotExist, "transaction download not found")
 	}
 
-	// TODO: cancel timeout and abort download
-
 	// remove the pending entry
 	delete(mgr.txs, hash)
 
@@ -19,7 +19,9 @@ package download
 
 import (
 	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/alvalor/alvalor-go/node/handlers/message"
 	"github.com/alvalor/alvalor-go/types"
@@ -46,15 +48,11 @@ func TestManagerStartInvValid(t *testing.T) {
 	peers := &PeersMock{}
 
 	// initialize manager
-	mgr := Manager{
-		net:   net,
-		peers: peers,
-		invs:  make(map[types.Hash]string),
-	}
+	mgr := NewManager(net, peers, time.Second, 8)
 
 	// initialize state
-	mgr.invs[hash2] = address2
-	mgr.invs[hash3] = address3
+	mgr.invs[hash2] = &Download{Address: address2}
+	mgr.invs[hash3] = &Download{Address: address3}
 
 	// program mocks
 	peers.On("Addresses", mock.Anything, mock.Anything).Return(addresses)
@@ -71,8 +69,12 @@ func TestManagerStartInvValid(t *testing.T) {
 	}
 
 	if assert.Contains(t, mgr.invs, hash1) {
-		assert.Equal(t, address1, mgr.invs[hash1])
+		assert.Equal(t, address1, mgr.invs[hash1].Address)
 	}
+
+	invs, txs := mgr.Pending()
+	assert.Equal(t, uint(3), invs)
+	assert.Equal(t, uint(0), txs)
 }
 
 func TestManagerStartInvExisting(t *testing.T) {
@@ -93,16 +95,12 @@ func TestManagerStartInvExisting(t *testing.T) {
 	peers := &PeersMock{}
 
 	// initialize manager
-	mgr := Manager{
-		net:   net,
-		peers: peers,
-		invs:  make(map[types.Hash]string),
-	}
+	mgr := NewManager(net, peers, time.Second, 8)
 
 	// initialize state
-	mgr.invs[hash1] = address1
-	mgr.invs[hash2] = address2
-	mgr.invs[hash3] = address3
+	mgr.invs[hash1] = &Download{Address: address1}
+	mgr.invs[hash2] = &Download{Address: address2}
+	mgr.invs[hash3] = &Download{Address: address3}
 
 	// program mocks
 	peers.On("Addresses", mock.Anything, mock.Anything).Return(addresses)
@@ -133,15 +131,11 @@ func TestManagerStartInvNoPeers(t *testing.T) {
 	peers := &PeersMock{}
 
 	// initialize manager
-	mgr := Manager{
-		net:   net,
-		peers: peers,
-		invs:  make(map[types.Hash]string),
-	}
+	mgr := NewManager(net, peers, time.Second, 8)
 
 	// initialize state
-	mgr.invs[hash2] = address2
-	mgr.invs[hash3] = address3
+	mgr.invs[hash2] = &Download{Address: address2}
+	mgr.invs[hash3] = &Download{Address: address3}
 
 	// program mocks
 	peers.On("Addresses", mock.Anything, mock.Anything).Return(nil)
@@ -177,15 +171,11 @@ func TestManagerStartInvSendFails(t *testing.T) {
 	peers := &PeersMock{}
 
 	// initialize manager
-	mgr := Manager{
-		net:   net,
-		peers: peers,
-		invs:  make(map[types.Hash]string),
-	}
+	mgr := NewManager(net, peers, time.Second, 8)
 
 	// initialize state
-	mgr.invs[hash2] = address2
-	mgr.invs[hash3] = address3
+	mgr.invs[hash2] = &Download{Address: address2}
+	mgr.invs[hash3] = &Download{Address: address3}
 
 	// program mocks
 	peers.On("Addresses", mock.Anything, mock.Anything).Return(addresses)
@@ -215,14 +205,10 @@ func TestDownloadCancelInvValid(t *testing.T) {
 	peers := &PeersMock{}
 
 	// initialize manager
-	mgr := Manager{
-		net:   net,
-		peers: peers,
-		invs:  make(map[types.Hash]string),
-	}
+	mgr := NewManager(net, peers, time.Second, 8)
 
 	// initialize state
-	mgr.invs[hash] = address
+	mgr.invs[hash] = &Download{Address: address}
 
 	// execute cancel
 	err := mgr.CancelInv(hash)
@@ -243,11 +229,7 @@ func TestDownloadCancelInvMissing(t *testing.T) {
 	peers := &PeersMock{}
 
 	// initialize manager
-	mgr := Manager{
-		net:   net,
-		peers: peers,
-		invs:  make(map[types.Hash]string),
-	}
+	mgr := NewManager(net, peers, time.Second, 8)
 
 	// execute cancel
 	err := mgr.CancelInv(hash)
@@ -255,3 +237,458 @@ func TestDownloadCancelInvMissing(t *testing.T) {
 	// check conditions
 	assert.NotNil(t, err)
 }
+
+func TestManagerStartInvMaxPending(t *testing.T) {
+
+	// initialize parameters
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+	hash3 := types.Hash{0x3}
+	address1 := "192.0.2.1"
+	address2 := "192.0.2.2"
+
+	// initialize entities
+	addresses := []string{address1, address2}
+	request := &message.GetInv{Hash: hash1}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	peers := &PeersMock{}
+
+	// initialize manager
+	mgr := NewManager(net, peers, time.Second, 1)
+
+	// initialize state
+	mgr.latency[address1] = time.Millisecond
+	mgr.latency[address2] = time.Second
+	mgr.invs[hash2] = &Download{Address: address1}
+
+	// program mocks
+	peers.On("Addresses", mock.Anything, mock.Anything).Return(addresses)
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute start
+	err := mgr.StartInv(hash1)
+
+	// assert conditions
+	assert.Nil(t, err)
+
+	if net.AssertNumberOfCalls(t, "Send", 1) {
+		net.AssertCalled(t, "Send", address2, request)
+	}
+
+	// execute start with all peers at capacity
+	err = mgr.StartInv(hash3)
+
+	// assert conditions
+	assert.NotNil(t, err)
+
+	assert.NotContains(t, mgr.invs, hash3)
+}
+
+func TestManagerStartInvLatency(t *testing.T) {
+
+	// initialize parameters
+	hash := types.Hash{0x1}
+	address1 := "192.0.2.1"
+	address2 := "192.0.2.2"
+
+	// initialize entities
+	addresses := []string{address1, address2}
+	request := &message.GetInv{Hash: hash}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	peers := &PeersMock{}
+
+	// initialize manager
+	mgr := NewManager(net, peers, time.Second, 8)
+
+	// initialize state
+	mgr.latency[address1] = time.Second
+	mgr.latency[address2] = time.Millisecond
+
+	// program mocks
+	peers.On("Addresses", mock.Anything, mock.Anything).Return(addresses)
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute start
+	err := mgr.StartInv(hash)
+
+	// assert conditions
+	assert.Nil(t, err)
+
+	if net.AssertNumberOfCalls(t, "Send", 1) {
+		net.AssertCalled(t, "Send", address2, request)
+	}
+}
+
+func TestManagerCheckRetry(t *testing.T) {
+
+	// initialize parameters
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+	address1 := "192.0.2.1"
+	address2 := "192.0.2.2"
+
+	// initialize entities
+	addresses := []string{address1, address2}
+	request := &message.GetInv{Hash: hash1}
+	expired := &Download{
+		Hash:     hash1,
+		Address:  address1,
+		Deadline: time.Now().Add(-time.Second),
+		Tried:    map[string]struct{}{address1: {}},
+	}
+	pending := &Download{
+		Hash:     hash2,
+		Address:  address1,
+		Deadline: time.Now().Add(time.Minute),
+		Tried:    map[string]struct{}{address1: {}},
+	}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	peers := &PeersMock{}
+
+	// initialize manager
+	mgr := NewManager(net, peers, time.Second, 8)
+
+	// initialize state
+	mgr.invs[hash1] = expired
+	mgr.invs[hash2] = pending
+
+	// program mocks
+	peers.On("Addresses", mock.Anything, mock.Anything).Return(addresses)
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute check
+	err := mgr.Check()
+
+	// assert conditions
+	assert.Nil(t, err)
+
+	if net.AssertNumberOfCalls(t, "Send", 1) {
+		net.AssertCalled(t, "Send", address2, request)
+	}
+
+	assert.Equal(t, address2, expired.Address)
+	assert.Equal(t, uint(1), expired.Attempts)
+	assert.True(t, expired.Deadline.After(time.Now().Add(time.Second)))
+	assert.Equal(t, 2*time.Second, mgr.latency[address1])
+}
+
+func TestManagerCheckAllTried(t *testing.T) {
+
+	// initialize parameters
+	hash := types.Hash{0x1}
+	address := "192.0.2.1"
+
+	// initialize entities
+	addresses := []string{address}
+	request := &message.Request{Hashes: []types.Hash{hash}}
+	expired := &Download{
+		Hash:     hash,
+		Address:  address,
+		Deadline: time.Now().Add(-time.Second),
+		Attempts: 1,
+		Tried:    map[string]struct{}{address: {}},
+	}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	peers := &PeersMock{}
+
+	// initialize manager
+	mgr := NewManager(net, peers, time.Second, 8)
+
+	// initialize state
+	mgr.txs[hash] = expired
+
+	// program mocks
+	peers.On("Addresses", mock.Anything, mock.Anything).Return(addresses)
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute check
+	err := mgr.Check()
+
+	// assert conditions
+	assert.Nil(t, err)
+
+	if net.AssertNumberOfCalls(t, "Send", 1) {
+		net.AssertCalled(t, "Send", address, request)
+	}
+
+	assert.Equal(t, uint(2), expired.Attempts)
+	assert.True(t, expired.Deadline.After(time.Now().Add(3*time.Second)))
+}
+
+func TestManagerCheckNoPeers(t *testing.T) {
+
+	// initialize parameters
+	hash := types.Hash{0x1}
+	address := "192.0.2.1"
+
+	// initialize entities
+	expired := &Download{
+		Hash:     hash,
+		Address:  address,
+		Deadline: time.Now().Add(-time.Second),
+		Tried:    map[string]struct{}{address: {}},
+	}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	peers := &PeersMock{}
+
+	// initialize manager
+	mgr := NewManager(net, peers, time.Second, 8)
+
+	// initialize state
+	mgr.invs[hash] = expired
+
+	// program mocks
+	peers.On("Addresses", mock.Anything, mock.Anything).Return(nil)
+
+	// execute check
+	err := mgr.Check()
+
+	// assert conditions
+	assert.NotNil(t, err)
+
+	net.AssertNumberOfCalls(t, "Send", 0)
+
+	assert.Contains(t, mgr.invs, hash)
+	assert.True(t, expired.Deadline.After(time.Now()))
+}
+
+func TestManagerReceived(t *testing.T) {
+
+	// initialize parameters
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+	address1 := "192.0.2.1"
+	address2 := "192.0.2.2"
+
+	// initialize mocks
+	net := &NetworkMock{}
+	peers := &PeersMock{}
+
+	// initialize manager
+	mgr := NewManager(net, peers, time.Second, 8)
+
+	// initialize state
+	mgr.invs[hash1] = &Download{Address: address1, Sent: time.Now().Add(-100 * time.Millisecond)}
+	mgr.txs[hash2] = &Download{Address: address1, Sent: time.Now()}
+
+	// execute received
+	mgr.Received(address1, hash1)
+	mgr.Received(address2, hash2)
+
+	// assert conditions
+	assert.NotContains(t, mgr.invs, hash1)
+	assert.NotContains(t, mgr.txs, hash2)
+
+	latency, ok := mgr.Latency(address1)
+	if assert.True(t, ok) {
+		assert.True(t, latency >= 100*time.Millisecond)
+	}
+
+	_, ok = mgr.Latency(address2)
+	assert.False(t, ok)
+}
+
+func TestManagerNotFound(t *testing.T) {
+
+	// initialize parameters
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+	address1 := "192.0.2.1"
+	address2 := "192.0.2.2"
+
+	// initialize entities
+	addresses := []string{address1, address2}
+	request := &message.Request{Hashes: []types.Hash{hash1}}
+	pending := &Download{
+		Hash:     hash1,
+		Address:  address1,
+		Deadline: time.Now().Add(time.Minute),
+		Tried:    map[string]struct{}{address1: {}},
+	}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	peers := &PeersMock{}
+
+	// initialize manager
+	mgr := NewManager(net, peers, time.Second, 8)
+
+	// initialize state
+	mgr.txs[hash1] = pending
+
+	// program mocks
+	peers.On("Addresses", mock.Anything, mock.Anything).Return(addresses)
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute not found from the wrong peer
+	err := mgr.NotFound(address2, hash1)
+
+	// assert conditions
+	assert.NotNil(t, err)
+
+	net.AssertNumberOfCalls(t, "Send", 0)
+
+	// execute not found for a hash we don't download
+	err = mgr.NotFound(address1, hash2)
+
+	// assert conditions
+	assert.NotNil(t, err)
+
+	// execute not found from the pending peer
+	err = mgr.NotFound(address1, hash1)
+
+	// assert conditions
+	assert.Nil(t, err)
+
+	if net.AssertNumberOfCalls(t, "Send", 1) {
+		net.AssertCalled(t, "Send", address2, request)
+	}
+
+	assert.Equal(t, address2, pending.Address)
+	assert.NotContains(t, mgr.latency, address1)
+}
+
+func TestManagerStartTxsBatches(t *testing.T) {
+
+	// initialize parameters
+	address1 := "192.0.2.1"
+	address2 := "192.0.2.2"
+	pending := types.Hash{0xff}
+
+	// initialize entities
+	addresses := []string{address1, address2}
+	hashes := make([]types.Hash, 0, 2*message.MaxBatchTxs+1)
+	for i := 0; i < cap(hashes)-1; i++ {
+		hashes = append(hashes, types.Hash{byte(i >> 8), byte(i)})
+	}
+	hashes = append(hashes, pending)
+
+	// initialize mocks
+	net := &NetworkMock{}
+	peers := &PeersMock{}
+
+	// initialize manager
+	mgr := NewManager(net, peers, time.Second, 4*message.MaxBatchTxs)
+
+	// initialize state
+	mgr.txs[pending] = &Download{Address: address1}
+
+	// program mocks
+	peers.On("Addresses", mock.Anything, mock.Anything).Return(addresses)
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute start
+	err := mgr.StartTxs(hashes)
+
+	// assert conditions
+	assert.Nil(t, err)
+
+	assert.Len(t, mgr.txs, len(hashes))
+
+	requested := make(map[types.Hash]string)
+	for _, call := range net.Calls {
+		address := call.Arguments.Get(0).(string)
+		request := call.Arguments.Get(1).(*message.Request)
+		assert.True(t, len(request.Hashes) <= message.MaxBatchTxs)
+		for _, hash := range request.Hashes {
+			requested[hash] = address
+		}
+	}
+	assert.Len(t, requested, len(hashes)-1)
+	assert.NotContains(t, requested, pending)
+	for hash, address := range requested {
+		assert.Equal(t, address, mgr.txs[hash].Address)
+	}
+}
+
+func TestManagerStartTxsSendFails(t *testing.T) {
+
+	// initialize parameters
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+	address1 := "192.0.2.1"
+	address2 := "192.0.2.2"
+
+	// initialize entities
+	addresses := []string{address1, address2}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	peers := &PeersMock{}
+
+	// initialize manager
+	mgr := NewManager(net, peers, time.Second, 1)
+
+	// program mocks
+	peers.On("Addresses", mock.Anything, mock.Anything).Return(addresses)
+	net.On("Send", address1, mock.Anything).Return(errors.New(""))
+	net.On("Send", address2, mock.Anything).Return(nil)
+
+	// execute start
+	err := mgr.StartTxs([]types.Hash{hash1, hash2})
+
+	// assert conditions
+	assert.NotNil(t, err)
+
+	net.AssertNumberOfCalls(t, "Send", 2)
+
+	if assert.Len(t, mgr.txs, 1) {
+		for _, dl := range mgr.txs {
+			assert.Equal(t, address2, dl.Address)
+		}
+	}
+}
+
+func TestManagerConcurrency(t *testing.T) {
+
+	// initialize parameters
+	addresses := []string{"192.0.2.1", "192.0.2.2", "192.0.2.3"}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	peers := &PeersMock{}
+
+	// initialize manager
+	mgr := NewManager(net, peers, time.Millisecond, 1024)
+
+	// program mocks
+	peers.On("Addresses", mock.Anything, mock.Anything).Return(addresses)
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// start, receive, retry and cancel downloads from many goroutines
+	wg := &sync.WaitGroup{}
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 32; i++ {
+				hash := types.Hash{byte(g), byte(i)}
+				_ = mgr.StartInv(hash)
+				_ = mgr.StartTxs([]types.Hash{hash})
+				mgr.HasInv(hash)
+				mgr.HasTx(hash)
+				_ = mgr.Check()
+				mgr.Received(addresses[i%len(addresses)], hash)
+				_ = mgr.NotFound(addresses[i%len(addresses)], hash)
+				mgr.Latency(addresses[i%len(addresses)])
+				_ = mgr.CancelInv(hash)
+				_ = mgr.CancelTx(hash)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	// check conditions
+	assert.Empty(t, mgr.invs)
+	assert.Empty(t, mgr.txs)
+}
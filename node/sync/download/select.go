@@ -17,11 +17,13 @@
 
 package download
 
-import "math"
+import "time"
 
 // Select will return the best download candidate from a list of candidates
-// who certainly have or possibly have an entity.
-func Select(has []string, may []string, count map[string]uint) string {
+// who certainly have or possibly have an entity. The best candidate is the one
+// we expect to deliver first, based on its number of pending downloads and its
+// average delivery latency.
+func Select(has []string, may []string, count map[string]uint, latency map[string]time.Duration) string {
 
 	// decide whether we select from certain or potential candidates
 	candidates := has
@@ -29,14 +31,15 @@ func Select(has []string, may []string, count map[string]uint) string {
 		candidates = may
 	}
 
-	// select the available peer with the least amount of pending download
+	// select the available peer with the lowest expected delivery time
 	var address string
-	best := uint(math.MaxUint32)
+	var best time.Duration
 	for _, candidate := range candidates {
-		if count[candidate] >= best {
+		score := time.Duration(count[candidate]+1) * (latency[candidate] + time.Millisecond)
+		if address != "" && score >= best {
 			continue
 		}
-		best = count[candidate]
+		best = score
 		address = candidate
 	}
 
@@ -25,6 +25,7 @@ type Download interface {
 	HasTx(hash types.Hash) bool
 	StartInv(hash types.Hash) error
 	StartTx(hash types.Hash) error
+	StartTxs(hashes []types.Hash) error
 	CancelInv(hash types.Hash) error
 	CancelTx(hash types.Hash) error
 }
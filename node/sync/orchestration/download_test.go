@@ -0,0 +1,75 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package orchestration
+
+import (
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/stretchr/testify/mock"
+)
+
+// DownloadMock mocks the download manager interface.
+type DownloadMock struct {
+	mock.Mock
+}
+
+// HasInv mocks the has inventory function of the download manager interface.
+func (dm *DownloadMock) HasInv(hash types.Hash) bool {
+	args := dm.Called(hash)
+	return args.Bool(0)
+}
+
+// HasTx mocks the has transaction function of the download manager interface.
+func (dm *DownloadMock) HasTx(hash types.Hash) bool {
+	args := dm.Called(hash)
+	return args.Bool(0)
+}
+
+// StartInv mocks the start inventory function of the download manager
+// interface.
+func (dm *DownloadMock) StartInv(hash types.Hash) error {
+	args := dm.Called(hash)
+	return args.Error(0)
+}
+
+// StartTx mocks the start transaction function of the download manager
+// interface.
+func (dm *DownloadMock) StartTx(hash types.Hash) error {
+	args := dm.Called(hash)
+	return args.Error(0)
+}
+
+// StartTxs mocks the start transactions function of the download manager
+// interface.
+func (dm *DownloadMock) StartTxs(hashes []types.Hash) error {
+	args := dm.Called(hashes)
+	return args.Error(0)
+}
+
+// CancelInv mocks the cancel inventory function of the download manager
+// interface.
+func (dm *DownloadMock) CancelInv(hash types.Hash) error {
+	args := dm.Called(hash)
+	return args.Error(0)
+}
+
+// CancelTx mocks the cancel transaction function of the download manager
+// interface.
+func (dm *DownloadMock) CancelTx(hash types.Hash) error {
+	args := dm.Called(hash)
+	return args.Error(0)
+}
@@ -18,12 +18,17 @@
 package orchestration
 
 import (
+	"sync"
+
 	"github.com/alvalor/alvalor-go/types"
 	"github.com/pkg/errors"
 )
 
-// Manager organizes the block downloads.
+// Manager organizes the block downloads. Once all entities of a block are
+// available, it starts the validation of the block outside of its lock, as
+// the validation can lead back to the manager through the reorg engine.
 type Manager struct {
+	sync.Mutex
 	pending      map[types.Hash]struct{}
 	templates    map[types.Hash]map[types.Hash]bool
 	mapping      map[types.Hash]types.Hash
@@ -33,8 +38,24 @@ type Manager struct {
 	transactions Transactions
 }
 
+// NewManager creates a new manager to organize block downloads.
+func NewManager(download Download, assembly Assembly, inventories Inventories, transactions Transactions) *Manager {
+	om := &Manager{
+		pending:      make(map[types.Hash]struct{}),
+		templates:    make(map[types.Hash]map[types.Hash]bool),
+		mapping:      make(map[types.Hash]types.Hash),
+		download:     download,
+		assembly:     assembly,
+		inventories:  inventories,
+		transactions: transactions,
+	}
+	return om
+}
+
 // Collect starts collecting all entities required to assemble a block.
 func (om *Manager) Collect(hash types.Hash) error {
+	om.Lock()
+	defer om.Unlock()
 
 	// check if we are already downloading this block
 	_, ok := om.pending[hash]
@@ -68,6 +89,8 @@ func (om *Manager) Collect(hash types.Hash) error {
 
 // Suspend suspends the assembly of the block with the given hash.
 func (om *Manager) Suspend(hash types.Hash) error {
+	om.Lock()
+	defer om.Unlock()
 
 	// check if we are currently collecting for the given hash
 	_, ok := om.pending[hash]
@@ -109,23 +132,35 @@ func (om *Manager) Suspend(hash types.Hash) error {
 
 // Inventory notifies the block assembler that an inventory was received.
 func (om *Manager) Inventory(hash types.Hash) error {
+	complete, err := om.inventory(hash)
+	if err != nil || !complete {
+		return err
+	}
+	return om.validate(hash)
+}
+
+// inventory creates the download template for a received inventory and
+// returns whether the block is already complete.
+func (om *Manager) inventory(hash types.Hash) (bool, error) {
+	om.Lock()
+	defer om.Unlock()
 
 	// check if we are actually waiting for the inventory
 	_, ok := om.pending[hash]
 	if !ok {
-		return errors.Wrap(ErrNotExist, "no pending block assembly")
+		return false, errors.Wrap(ErrNotExist, "no pending block assembly")
 	}
 
 	// check if we already have the template
 	_, ok = om.templates[hash]
 	if ok {
-		return errors.Wrap(ErrExist, "block template already exists")
+		return false, errors.Wrap(ErrExist, "block template already exists")
 	}
 
 	// retrieve the inventory
 	inv, err := om.inventories.Get(hash)
 	if err != nil {
-		return errors.Wrap(err, "could not get inventory to create template")
+		return false, errors.Wrap(err, "could not get inventory to create template")
 	}
 
 	// create and save the download template
@@ -140,55 +175,85 @@ func (om *Manager) Inventory(hash types.Hash) error {
 		}
 	}
 
-	// TODO: move following to another function
+	// save the template for the block
+	om.templates[hash] = template
 
-	// start the transaction downloads that are not pending
+	// if we already have all transactions, the block is complete
+	var missing []types.Hash
 	for txHash, ok := range template {
 		if ok {
 			continue
 		}
-		err := om.download.StartTx(txHash)
-		if err != nil {
-			return errors.Wrapf(err, "could not start transaction download (%v)", txHash)
-		}
+		missing = append(missing, txHash)
+	}
+	if len(missing) == 0 {
+		om.complete(hash)
+		return true, nil
 	}
 
-	// save the template for the block
-	om.templates[hash] = template
+	// start the transaction downloads that are not pending in batches
+	err = om.download.StartTxs(missing)
+	if err != nil {
+		return false, errors.Wrap(err, "could not start transaction downloads")
+	}
 
-	return nil
+	return false, nil
 }
 
 // Transaction notifies the block downloader when a transaction is received.
 func (om *Manager) Transaction(hash types.Hash) error {
+	blkHash, complete, err := om.transaction(hash)
+	if err != nil || !complete {
+		return err
+	}
+	return om.validate(blkHash)
+}
+
+// transaction marks a transaction of a block template as received and returns
+// the block hash and whether the block is complete.
+func (om *Manager) transaction(hash types.Hash) (types.Hash, bool, error) {
+	om.Lock()
+	defer om.Unlock()
 
 	// check if we are waiting for the given transaction
 	blkHash, ok := om.mapping[hash]
 	if !ok {
-		return errors.Wrap(ErrNotExist, "not waiting for transaction download")
+		return blkHash, false, errors.Wrap(ErrNotExist, "not waiting for transaction download")
 	}
 
 	// retrieve the block template
 	template, ok := om.templates[blkHash]
 	if !ok {
-		return errors.Wrap(ErrNotExist, "block template missing for transaction")
+		return blkHash, false, errors.Wrap(ErrNotExist, "block template missing for transaction")
 	}
 
 	// set the given transaction to received
+	delete(om.mapping, hash)
 	template[hash] = true
 
 	// if still transactions missing, do nothing
 	for _, ok := range template {
 		if !ok {
-			return nil
+			return blkHash, false, nil
 		}
 	}
 
-	// at this point, start block validation
+	om.complete(blkHash)
+	return blkHash, true, nil
+}
+
+// complete cleans up the collection state of a block once all of its
+// entities are available.
+func (om *Manager) complete(hash types.Hash) {
+	delete(om.pending, hash)
+	delete(om.templates, hash)
+}
+
+// validate starts the validation of a complete block.
+func (om *Manager) validate(hash types.Hash) error {
 	err := om.assembly.Validate(hash)
 	if err != nil {
 		return errors.Wrap(err, "could not validate block")
 	}
-
 	return nil
 }
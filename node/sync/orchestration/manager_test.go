@@ -0,0 +1,205 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package orchestration
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+func TestManagerCollect(t *testing.T) {
+
+	// initialize parameters
+	hash := types.Hash{0x1}
+
+	// initialize mocks
+	download := &DownloadMock{}
+
+	// initialize manager
+	om := NewManager(download, nil, nil, nil)
+
+	// program mocks
+	download.On("HasInv", mock.Anything).Return(false)
+	download.On("StartInv", mock.Anything).Return(nil)
+
+	// execute collect
+	err1 := om.Collect(hash)
+	err2 := om.Collect(hash)
+
+	// check conditions
+	assert.Nil(t, err1)
+	assert.Equal(t, ErrExist, errors.Cause(err2))
+
+	download.AssertNumberOfCalls(t, "StartInv", 1)
+	assert.Contains(t, om.pending, hash)
+}
+
+func TestManagerInventoryComplete(t *testing.T) {
+
+	// initialize parameters
+	hash := types.Hash{0x1}
+	txHash := types.Hash{0x2}
+
+	// initialize entities
+	inv := &types.Inventory{Hash: hash, Hashes: []types.Hash{txHash}}
+
+	// initialize mocks
+	download := &DownloadMock{}
+	assembly := &AssemblyMock{}
+	inventories := &InventoriesMock{}
+	transactions := &TransactionsMock{}
+
+	// initialize manager
+	om := NewManager(download, assembly, inventories, transactions)
+	om.pending[hash] = struct{}{}
+
+	// program mocks
+	inventories.On("Get", mock.Anything).Return(inv, nil)
+	transactions.On("Has", mock.Anything).Return(true)
+	assembly.On("Validate", mock.Anything).Return(nil)
+
+	// execute inventory
+	err := om.Inventory(hash)
+
+	// check conditions
+	assert.Nil(t, err)
+
+	download.AssertNotCalled(t, "StartTxs", mock.Anything)
+	assembly.AssertCalled(t, "Validate", hash)
+	assert.Empty(t, om.pending)
+	assert.Empty(t, om.templates)
+}
+
+func TestManagerInventoryTransactions(t *testing.T) {
+
+	// initialize parameters
+	hash := types.Hash{0x1}
+	txHash1 := types.Hash{0x2}
+	txHash2 := types.Hash{0x3}
+
+	// initialize entities
+	inv := &types.Inventory{Hash: hash, Hashes: []types.Hash{txHash1, txHash2}}
+
+	// initialize mocks
+	download := &DownloadMock{}
+	assembly := &AssemblyMock{}
+	inventories := &InventoriesMock{}
+	transactions := &TransactionsMock{}
+
+	// initialize manager
+	om := NewManager(download, assembly, inventories, transactions)
+	om.pending[hash] = struct{}{}
+
+	// program mocks
+	inventories.On("Get", mock.Anything).Return(inv, nil)
+	transactions.On("Has", txHash1).Return(true)
+	transactions.On("Has", txHash2).Return(false)
+	download.On("StartTxs", mock.Anything).Return(nil)
+	assembly.On("Validate", mock.Anything).Return(nil)
+
+	// execute inventory
+	err := om.Inventory(hash)
+
+	// check conditions
+	assert.Nil(t, err)
+
+	download.AssertCalled(t, "StartTxs", []types.Hash{txHash2})
+	assembly.AssertNotCalled(t, "Validate", mock.Anything)
+
+	// execute transaction
+	err = om.Transaction(txHash2)
+
+	// check conditions
+	assert.Nil(t, err)
+
+	assembly.AssertCalled(t, "Validate", hash)
+	assert.Empty(t, om.pending)
+	assert.Empty(t, om.templates)
+	assert.Empty(t, om.mapping)
+}
+
+func TestManagerTransactionUnknown(t *testing.T) {
+
+	// initialize manager
+	om := NewManager(nil, nil, nil, nil)
+
+	// execute transaction
+	err := om.Transaction(types.Hash{0x1})
+
+	// check conditions
+	assert.Equal(t, ErrNotExist, errors.Cause(err))
+}
+
+func TestManagerConcurrency(t *testing.T) {
+
+	// initialize mocks
+	download := &DownloadMock{}
+	assembly := &AssemblyMock{}
+	inventories := &InventoriesMock{}
+	transactions := &TransactionsMock{}
+
+	// initialize manager
+	om := NewManager(download, assembly, inventories, transactions)
+
+	// program mocks
+	for g := 0; g < 16; g++ {
+		for i := 0; i < 16; i++ {
+			hash := types.Hash{byte(g), byte(i)}
+			inv := &types.Inventory{Hash: hash, Hashes: []types.Hash{{byte(g), byte(i), 0x1}, {byte(g), byte(i), 0x2}}}
+			inventories.On("Get", hash).Return(inv, nil)
+		}
+	}
+	download.On("HasInv", mock.Anything).Return(false)
+	download.On("StartInv", mock.Anything).Return(nil)
+	download.On("StartTxs", mock.Anything).Return(nil)
+	transactions.On("Has", mock.Anything).Return(false)
+	assembly.On("Validate", mock.Anything).Return(nil)
+
+	// collect blocks and deliver their entities from many goroutines
+	wg := &sync.WaitGroup{}
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 16; i++ {
+				hash := types.Hash{byte(g), byte(i)}
+				err := om.Collect(hash)
+				assert.Nil(t, err)
+				err = om.Inventory(hash)
+				assert.Nil(t, err)
+				err = om.Transaction(types.Hash{byte(g), byte(i), 0x1})
+				assert.Nil(t, err)
+				err = om.Transaction(types.Hash{byte(g), byte(i), 0x2})
+				assert.Nil(t, err)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	// check conditions
+	assembly.AssertNumberOfCalls(t, "Validate", 16*16)
+	assert.Empty(t, om.pending)
+	assert.Empty(t, om.templates)
+	assert.Empty(t, om.mapping)
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package progress
+
+import (
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// HeadersMock mocks the headers repository interface.
+type HeadersMock struct {
+	mock.Mock
+}
+
+// Recent mocks the recent function of the headers repository interface.
+func (hm *HeadersMock) Recent(n uint) ([]types.Hash, uint64) {
+	args := hm.Called(n)
+	var path []types.Hash
+	if args.Get(0) != nil {
+		path = args.Get(0).([]types.Hash)
+	}
+	return path, args.Get(1).(uint64)
+}
+
+// Height mocks the height function of the headers repository interface.
+func (hm *HeadersMock) Height(hash types.Hash) (uint64, error) {
+	args := hm.Called(hash)
+	return args.Get(0).(uint64), args.Error(1)
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package progress
+
+// Phase represents the phase of the synchronization with our peers.
+type Phase uint8
+
+// List of synchronization phases.
+const (
+	PhaseIdle Phase = iota
+	PhaseHeaders
+	PhaseBlocks
+	PhaseSynced
+)
+
+// String returns the name of the phase.
+func (phase Phase) String() string {
+	switch phase {
+	case PhaseIdle:
+		return "idle"
+	case PhaseHeaders:
+		return "headers"
+	case PhaseBlocks:
+		return "blocks"
+	case PhaseSynced:
+		return "synced"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package progress
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// smoothing is the weight of the latest measurement in the moving averages of
+// the header and block rates.
+const smoothing = 0.3
+
+// Tracker keeps track of how far we are behind our peers and how fast we are
+// catching up. It moves through the synchronization phases as we first catch
+// up with the best path of our peers, then download its blocks, and lets
+// subscribers know about every change of phase.
+type Tracker struct {
+	sync.Mutex
+	headers   Headers
+	downloads Downloads
+	events    Events
+	now       func() time.Time
+	peers     map[string]uint64
+	height    uint64
+	last      time.Time
+	status    Status
+}
+
+// NewTracker creates a new progress tracker.
+func NewTracker(headers Headers, downloads Downloads, events Events) *Tracker {
+	return &Tracker{
+		headers:   headers,
+		downloads: downloads,
+		events:    events,
+		now:       time.Now,
+		peers:     make(map[string]uint64),
+	}
+}
+
+// Peer records the distance of the best path of a peer, as reported in its
+// status message.
+func (tr *Tracker) Peer(address string, distance uint64) {
+	tr.Lock()
+	defer tr.Unlock()
+
+	tr.peers[address] = distance
+}
+
+// Forget removes a peer that disconnected.
+func (tr *Tracker) Forget(address string) {
+	tr.Lock()
+	defer tr.Unlock()
+
+	delete(tr.peers, address)
+}
+
+// Connected records the height of the last block connected to our blockchain.
+func (tr *Tracker) Connected(height uint64) {
+	tr.Lock()
+	defer tr.Unlock()
+
+	tr.height = height
+}
+
+// Disconnected records that the block at the given height was disconnected
+// from our blockchain, so that it now ends at its parent.
+func (tr *Tracker) Disconnected(height uint64) {
+	tr.Lock()
+	defer tr.Unlock()
+
+	if height == 0 {
+		tr.height = 0
+		return
+	}
+	tr.height = height - 1
+}
+
+// Status returns the synchronization progress as of the last update.
+func (tr *Tracker) Status() Status {
+	tr.Lock()
+	defer tr.Unlock()
+
+	return tr.status
+}
+
+// Update refreshes the synchronization progress and publishes an event if we
+// moved to another phase. It should be called on a regular interval.
+func (tr *Tracker) Update() error {
+	previous, status, err := tr.update()
+	if err != nil {
+		return err
+	}
+	if status.Phase == previous {
+		return nil
+	}
+	err = tr.events.Sync(status.Phase.String(), status.Height, status.Best)
+	if err != nil {
+		return errors.Wrap(err, "could not publish sync event")
+	}
+	return nil
+}
+
+// update computes the new status and returns it with the previous phase.
+func (tr *Tracker) update() (Phase, Status, error) {
+	tr.Lock()
+	defer tr.Unlock()
+
+	// determine our best header
+	path, distance := tr.headers.Recent(1)
+	var best uint64
+	if len(path) > 0 {
+		var err error
+		best, err = tr.headers.Height(path[0])
+		if err != nil {
+			return tr.status.Phase, tr.status, errors.Wrap(err, "could not get height of best header")
+		}
+	}
+
+	// determine the best path known by our peers
+	var target uint64
+	for _, peer := range tr.peers {
+		if peer > target {
+			target = peer
+		}
+	}
+
+	// update the moving averages of headers and blocks per second
+	now := tr.now()
+	previous := tr.status
+	status := Status{
+		Peers:      uint(len(tr.peers)),
+		Target:     target,
+		Distance:   distance,
+		Height:     tr.height,
+		Best:       best,
+		HeaderRate: previous.HeaderRate,
+		BlockRate:  previous.BlockRate,
+	}
+	status.Invs, status.Txs = tr.downloads.Pending()
+	if !tr.last.IsZero() {
+		elapsed := now.Sub(tr.last).Seconds()
+		if elapsed > 0 {
+			status.HeaderRate = average(previous.HeaderRate, previous.Best, best, elapsed)
+			status.BlockRate = average(previous.BlockRate, previous.Height, tr.height, elapsed)
+		}
+	}
+	tr.last = now
+
+	// determine the phase we are in
+	switch {
+	case len(tr.peers) == 0:
+		status.Phase = PhaseIdle
+	case target > distance:
+		status.Phase = PhaseHeaders
+	case tr.height < best:
+		status.Phase = PhaseBlocks
+	default:
+		status.Phase = PhaseSynced
+	}
+	tr.status = status
+
+	return previous.Phase, status, nil
+}
+
+// average updates the moving average of a rate with the progress made from
+// one height to another in the elapsed number of seconds.
+func average(rate float64, from uint64, to uint64, elapsed float64) float64 {
+	var progress float64
+	if to > from {
+		progress = float64(to - from)
+	}
+	return (1-smoothing)*rate + smoothing*progress/elapsed
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package progress
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+func TestTrackerPhases(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	tip := types.Hash{0x1}
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	downloads := &DownloadsMock{}
+	events := &EventsMock{}
+
+	// initialize tracker
+	tr := NewTracker(headers, downloads, events)
+
+	// program mocks
+	headers.On("Recent", uint(1)).Return([]types.Hash{tip}, uint64(100))
+	headers.On("Height", tip).Return(uint64(10), nil)
+	downloads.On("Pending").Return(uint(2), uint(3))
+	events.On("Sync", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	// execute updates through all phases
+	err := tr.Update()
+	assert.Nil(t, err)
+	assert.Equal(t, PhaseIdle, tr.Status().Phase)
+
+	tr.Peer(address, 200)
+	err = tr.Update()
+	assert.Nil(t, err)
+	assert.Equal(t, PhaseHeaders, tr.Status().Phase)
+
+	tr.Peer(address, 100)
+	err = tr.Update()
+	assert.Nil(t, err)
+	assert.Equal(t, PhaseBlocks, tr.Status().Phase)
+
+	tr.Connected(10)
+	err = tr.Update()
+	assert.Nil(t, err)
+	assert.Equal(t, PhaseSynced, tr.Status().Phase)
+
+	err = tr.Update()
+	assert.Nil(t, err)
+
+	tr.Forget(address)
+	err = tr.Update()
+	assert.Nil(t, err)
+
+	// check conditions
+	if events.AssertNumberOfCalls(t, "Sync", 4) {
+		assert.Equal(t, "headers", events.Calls[0].Arguments.Get(0))
+		assert.Equal(t, "blocks", events.Calls[1].Arguments.Get(0))
+		events.AssertCalled(t, "Sync", "synced", uint64(10), uint64(10))
+		assert.Equal(t, "idle", events.Calls[3].Arguments.Get(0))
+	}
+
+	status := tr.Status()
+	assert.Equal(t, uint(2), status.Invs)
+	assert.Equal(t, uint(3), status.Txs)
+	assert.Equal(t, uint64(100), status.Distance)
+	assert.Equal(t, uint64(10), status.Best)
+}
+
+func TestTrackerDisconnected(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	tip := types.Hash{0x1}
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	downloads := &DownloadsMock{}
+	events := &EventsMock{}
+
+	// initialize tracker
+	tr := NewTracker(headers, downloads, events)
+
+	// program mocks
+	headers.On("Recent", uint(1)).Return([]types.Hash{tip}, uint64(100))
+	headers.On("Height", tip).Return(uint64(10), nil)
+	downloads.On("Pending").Return(uint(0), uint(0))
+	events.On("Sync", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	// execute a disconnect after we synced
+	tr.Peer(address, 100)
+	tr.Connected(10)
+	err := tr.Update()
+	assert.Nil(t, err)
+	assert.Equal(t, PhaseSynced, tr.Status().Phase)
+
+	tr.Disconnected(10)
+	err = tr.Update()
+	assert.Nil(t, err)
+
+	// check conditions
+	status := tr.Status()
+	assert.Equal(t, PhaseBlocks, status.Phase)
+	assert.Equal(t, uint64(9), status.Height)
+}
+
+func TestTrackerRates(t *testing.T) {
+
+	// initialize parameters
+	tip1 := types.Hash{0x1}
+	tip2 := types.Hash{0x2}
+	now := time.Unix(1000, 0)
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	downloads := &DownloadsMock{}
+	events := &EventsMock{}
+
+	// initialize tracker
+	tr := NewTracker(headers, downloads, events)
+	tr.now = func() time.Time { return now }
+
+	// program mocks
+	headers.On("Recent", uint(1)).Return([]types.Hash{tip1}, uint64(100)).Once()
+	headers.On("Recent", uint(1)).Return([]types.Hash{tip2}, uint64(200))
+	headers.On("Height", tip1).Return(uint64(0), nil)
+	headers.On("Height", tip2).Return(uint64(100), nil)
+	downloads.On("Pending").Return(uint(0), uint(0))
+
+	// execute updates one second apart
+	err := tr.Update()
+	assert.Nil(t, err)
+	now = now.Add(time.Second)
+	tr.Connected(10)
+	err = tr.Update()
+	assert.Nil(t, err)
+
+	// check conditions
+	status := tr.Status()
+	assert.InDelta(t, smoothing*100, status.HeaderRate, 0.001)
+	assert.InDelta(t, smoothing*10, status.BlockRate, 0.001)
+}
+
+func TestTrackerHeightFails(t *testing.T) {
+
+	// initialize parameters
+	tip := types.Hash{0x1}
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	downloads := &DownloadsMock{}
+	events := &EventsMock{}
+
+	// initialize tracker
+	tr := NewTracker(headers, downloads, events)
+
+	// program mocks
+	headers.On("Recent", uint(1)).Return([]types.Hash{tip}, uint64(100))
+	headers.On("Height", tip).Return(uint64(0), errors.New(""))
+
+	// execute update
+	err := tr.Update()
+
+	// check conditions
+	assert.NotNil(t, err)
+	events.AssertNotCalled(t, "Sync", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package reconcile
+
+// Config represents the parameters of the mempool reconciliation.
+type Config struct {
+	falsePositive float64
+	maxFilterSize uint
+	maxPush       uint
+}
+
+// DefaultConfig returns the default parameters of the mempool reconciliation.
+func DefaultConfig() Config {
+	return Config{
+		falsePositive: 0.01,
+		maxFilterSize: 256 << 10,
+		maxPush:       4096,
+	}
+}
+
+// SetFalsePositive allows us to configure the false positive rate of the bloom
+// filters we send. A lower rate means peers miss fewer of our transactions,
+// but results in bigger filters.
+func SetFalsePositive(falsePositive float64) func(*Config) {
+	return func(cfg *Config) {
+		cfg.falsePositive = falsePositive
+	}
+}
+
+// SetMaxFilterSize allows us to configure the maximum size in bytes of the
+// bloom filters we send. If our pool has more transactions than fit into a
+// filter of this size, only the ones with the highest fees are included.
+func SetMaxFilterSize(maxFilterSize uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxFilterSize = maxFilterSize
+	}
+}
+
+// SetMaxPush allows us to configure the maximum number of transactions we push
+// to a peer in response to its bloom filter.
+func SetMaxPush(maxPush uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxPush = maxPush
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package reconcile
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+	"github.com/willf/bloom"
+
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// Reconciler helps peers converge on the same set of pending transactions. It
+// summarizes our pool in a bloom filter and uses the filters of other peers to
+// find the transactions they are missing.
+type Reconciler struct {
+	cfg  Config
+	net  Network
+	pool Pool
+}
+
+// NewReconciler creates a new reconciler for the given transaction pool, which
+// uses the default parameters unless they are modified by the given options.
+func NewReconciler(net Network, pool Pool, options ...func(*Config)) *Reconciler {
+	cfg := DefaultConfig()
+	for _, option := range options {
+		option(&cfg)
+	}
+	return &Reconciler{cfg: cfg, net: net, pool: pool}
+}
+
+// Filter returns a bloom filter of the hashes of the transactions in our pool.
+// If there are more transactions than fit into the maximum filter size at the
+// configured false positive rate, only the ones with the highest fees are
+// included.
+func (rec *Reconciler) Filter() *bloom.BloomFilter {
+
+	// determine how many transactions fit into the filter
+	count := rec.pool.Count()
+	max := capacity(rec.cfg.maxFilterSize*8, rec.cfg.falsePositive)
+	if count > max {
+		count = max
+	}
+	if count == 0 {
+		count = 1
+	}
+
+	// add the best transactions to the filter
+	filter := bloom.NewWithEstimates(count, rec.cfg.falsePositive)
+	for _, tx := range rec.pool.Best(count) {
+		filter.Add(tx.Hash[:])
+	}
+
+	return filter
+}
+
+// Missing returns the transactions of our pool that are not in the given bloom
+// filter of a peer, up to the configured maximum and with the highest fees
+// first.
+func (rec *Reconciler) Missing(filter *bloom.BloomFilter) []*types.Transaction {
+	var missing []*types.Transaction
+	for _, tx := range rec.pool.Best(rec.pool.Count()) {
+		if uint(len(missing)) >= rec.cfg.maxPush {
+			break
+		}
+		if filter.Test(tx.Hash[:]) {
+			continue
+		}
+		missing = append(missing, tx)
+	}
+	return missing
+}
+
+// Announce sends the bloom filter of our pool to all peers, so they can push
+// us the transactions we are missing.
+func (rec *Reconciler) Announce() error {
+	err := rec.net.Broadcast(&message.Mempool{Bloom: rec.Filter()})
+	if err != nil {
+		return errors.Wrap(err, "could not broadcast mempool filter")
+	}
+	return nil
+}
+
+// capacity returns the number of elements a bloom filter with the given number
+// of bits can hold at the given false positive rate.
+func capacity(bits uint, falsePositive float64) uint {
+	return uint(float64(bits) * math.Ln2 * math.Ln2 / -math.Log(falsePositive))
+}
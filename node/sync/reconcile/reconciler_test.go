@@ -0,0 +1,140 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/willf/bloom"
+
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+func TestReconcilerFilter(t *testing.T) {
+
+	// initialize entities
+	tx1 := &types.Transaction{Hash: types.Hash{0x1}}
+	tx2 := &types.Transaction{Hash: types.Hash{0x2}}
+	tx3 := &types.Transaction{Hash: types.Hash{0x3}}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	pool := &PoolMock{}
+
+	// initialize reconciler
+	rec := NewReconciler(net, pool)
+
+	// program mocks
+	pool.On("Count").Return(uint(2))
+	pool.On("Best", mock.Anything).Return([]*types.Transaction{tx1, tx2})
+
+	// execute filter
+	filter := rec.Filter()
+
+	// check conditions
+	pool.AssertCalled(t, "Best", uint(2))
+
+	assert.True(t, filter.Test(tx1.Hash[:]))
+	assert.True(t, filter.Test(tx2.Hash[:]))
+	assert.False(t, filter.Test(tx3.Hash[:]))
+}
+
+func TestReconcilerFilterMaxSize(t *testing.T) {
+
+	// initialize mocks
+	net := &NetworkMock{}
+	pool := &PoolMock{}
+
+	// initialize reconciler
+	rec := NewReconciler(net, pool, SetMaxFilterSize(128), SetFalsePositive(0.01))
+
+	// program mocks
+	pool.On("Count").Return(uint(100000))
+	pool.On("Best", mock.Anything).Return(nil)
+
+	// execute filter
+	filter := rec.Filter()
+
+	// check conditions
+	max := capacity(128*8, 0.01)
+	pool.AssertCalled(t, "Best", max)
+
+	assert.True(t, max < 1000)
+	assert.True(t, filter.Cap() <= 128*8+64)
+}
+
+func TestReconcilerMissing(t *testing.T) {
+
+	// initialize entities
+	tx1 := &types.Transaction{Hash: types.Hash{0x1}}
+	tx2 := &types.Transaction{Hash: types.Hash{0x2}}
+	tx3 := &types.Transaction{Hash: types.Hash{0x3}}
+	tx4 := &types.Transaction{Hash: types.Hash{0x4}}
+	filter := bloom.NewWithEstimates(10, 0.0001)
+	filter.Add(tx1.Hash[:])
+	filter.Add(tx3.Hash[:])
+
+	// initialize mocks
+	net := &NetworkMock{}
+	pool := &PoolMock{}
+
+	// initialize reconciler
+	rec := NewReconciler(net, pool, SetMaxPush(1))
+
+	// program mocks
+	pool.On("Count").Return(uint(4))
+	pool.On("Best", mock.Anything).Return([]*types.Transaction{tx1, tx2, tx3, tx4})
+
+	// execute missing
+	missing := rec.Missing(filter)
+
+	// check conditions
+	assert.Equal(t, []*types.Transaction{tx2}, missing)
+}
+
+func TestReconcilerAnnounce(t *testing.T) {
+
+	// initialize entities
+	tx := &types.Transaction{Hash: types.Hash{0x1}}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	pool := &PoolMock{}
+
+	// initialize reconciler
+	rec := NewReconciler(net, pool)
+
+	// program mocks
+	pool.On("Count").Return(uint(1))
+	pool.On("Best", mock.Anything).Return([]*types.Transaction{tx})
+	net.On("Broadcast", mock.Anything, mock.Anything).Return(nil)
+
+	// execute announce
+	err := rec.Announce()
+
+	// check conditions
+	assert.Nil(t, err)
+
+	if net.AssertNumberOfCalls(t, "Broadcast", 1) {
+		msg := net.Calls[0].Arguments.Get(0).(*message.Mempool)
+		assert.True(t, msg.Bloom.Test(tx.Hash[:]))
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package relay
+
+import (
+	"time"
+
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+)
+
+// Config represents the parameters of the transaction relay.
+type Config struct {
+	interval    time.Duration
+	maxAnnounce uint
+	maxQueue    uint
+}
+
+// DefaultConfig returns the default parameters of the transaction relay.
+func DefaultConfig() Config {
+	return Config{
+		interval:    500 * time.Millisecond,
+		maxAnnounce: message.MaxBatchTxs,
+		maxQueue:    16384,
+	}
+}
+
+// SetInterval allows us to configure the average interval between two
+// announcements to the same peer. The actual delays are randomized for each
+// peer, so that the origin of a transaction can't be inferred from timing.
+func SetInterval(interval time.Duration) func(*Config) {
+	return func(cfg *Config) {
+		cfg.interval = interval
+	}
+}
+
+// SetMaxAnnounce allows us to configure the maximum number of hashes we
+// announce to a peer in a single message.
+func SetMaxAnnounce(maxAnnounce uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxAnnounce = maxAnnounce
+	}
+}
+
+// SetMaxQueue allows us to configure the maximum number of hashes we queue
+// for a single peer. Hashes beyond this limit are not announced to the peer,
+// which can still pick them up through mempool reconciliation.
+func SetMaxQueue(maxQueue uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.maxQueue = maxQueue
+	}
+}
@@ -15,11 +15,12 @@
 // You should have received a copy of the GNU Affero General Public License
 // along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
 
-package entity
+package relay
 
 import "github.com/alvalor/alvalor-go/node/state/peers"
 
-// Peers represents an interface for the state of peers.
+// Peers represents an interface to get access to the state of currently
+// connected peers.
 type Peers interface {
 	Addresses(filters ...peers.FilterFunc) []string
 }
@@ -0,0 +1,160 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package relay
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+	"github.com/alvalor/alvalor-go/node/state/peers"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// Relay propagates new transactions by announcing their hashes to our peers,
+// who can then request the bodies they don't have yet. Each peer has its own
+// queue of hashes, which is flushed after a randomized delay, so that hashes
+// are announced in batches and peers can't tell which transactions originated
+// with us.
+type Relay struct {
+	sync.Mutex
+	net    Network
+	peers  Peers
+	cfg    Config
+	queues map[string]map[types.Hash]struct{}
+	next   map[string]time.Time
+}
+
+// NewRelay creates a new transaction relay.
+func NewRelay(net Network, peers Peers, options ...func(*Config)) *Relay {
+	cfg := DefaultConfig()
+	for _, option := range options {
+		option(&cfg)
+	}
+	rel := &Relay{
+		net:    net,
+		peers:  peers,
+		cfg:    cfg,
+		queues: make(map[string]map[types.Hash]struct{}),
+		next:   make(map[string]time.Time),
+	}
+	return rel
+}
+
+// Announce queues the hash of a transaction for announcement to all active
+// peers that are not known to have it already.
+func (rel *Relay) Announce(hash types.Hash) {
+	rel.Lock()
+	defer rel.Unlock()
+
+	// create lookup of the peers that already have the transaction
+	lookup := make(map[string]struct{})
+	for _, address := range rel.peers.Addresses(peers.HasEntity(peers.EntityYes, hash)) {
+		lookup[address] = struct{}{}
+	}
+
+	// queue the hash for all other active peers
+	now := time.Now()
+	for _, address := range rel.peers.Addresses(peers.IsActive(true)) {
+		_, ok := lookup[address]
+		if ok {
+			continue
+		}
+		queue, ok := rel.queues[address]
+		if !ok {
+			queue = make(map[types.Hash]struct{})
+			rel.queues[address] = queue
+		}
+		if uint(len(queue)) >= rel.cfg.maxQueue {
+			continue
+		}
+		queue[hash] = struct{}{}
+		_, ok = rel.next[address]
+		if !ok {
+			rel.next[address] = now.Add(rel.delay())
+		}
+	}
+}
+
+// Trickle sends the queued announcements to all peers whose delay has passed
+// and drops the queues of peers that are no longer active. It should be
+// called on a short interval.
+func (rel *Relay) Trickle() error {
+	rel.Lock()
+	defer rel.Unlock()
+
+	// drop the queues of peers that are gone
+	active := make(map[string]struct{})
+	for _, address := range rel.peers.Addresses(peers.IsActive(true)) {
+		active[address] = struct{}{}
+	}
+	for address := range rel.queues {
+		_, ok := active[address]
+		if !ok {
+			delete(rel.queues, address)
+			delete(rel.next, address)
+		}
+	}
+
+	// announce queued hashes to each peer that is due
+	var result *multierror.Error
+	now := time.Now()
+	for address, queue := range rel.queues {
+		if len(queue) == 0 || now.Before(rel.next[address]) {
+			continue
+		}
+		hashes := rel.pop(queue)
+		rel.next[address] = now.Add(rel.delay())
+		err := rel.net.Send(address, &message.Announce{Hashes: hashes})
+		if err != nil {
+			result = multierror.Append(result, errors.Wrapf(err, "could not send announcement (%s)", address))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+// pop removes up to the maximum number of hashes from the queue and returns
+// them in random order.
+func (rel *Relay) pop(queue map[types.Hash]struct{}) []types.Hash {
+	hashes := make([]types.Hash, 0, len(queue))
+	for hash := range queue {
+		hashes = append(hashes, hash)
+	}
+	for i := len(hashes) - 1; i > 0; i-- {
+		j := rand.Intn(i + 1)
+		hashes[i], hashes[j] = hashes[j], hashes[i]
+	}
+	if uint(len(hashes)) > rel.cfg.maxAnnounce {
+		hashes = hashes[:rel.cfg.maxAnnounce]
+	}
+	for _, hash := range hashes {
+		delete(queue, hash)
+	}
+	return hashes
+}
+
+// delay returns a random delay following an exponential distribution with the
+// configured interval as mean, so that announcements follow a poisson process.
+func (rel *Relay) delay() time.Duration {
+	return time.Duration(rand.ExpFloat64() * float64(rel.cfg.interval))
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package relay
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+func TestRelayAnnounceTrickle(t *testing.T) {
+
+	// initialize parameters
+	address1 := "192.0.2.1"
+	address2 := "192.0.2.2"
+	address3 := "192.0.2.3"
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+	active := []string{address1, address2, address3}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	peers := &PeersMock{}
+
+	// initialize relay
+	rel := NewRelay(net, peers, SetInterval(0))
+
+	// program mocks
+	peers.On("Addresses", mock.Anything).Return([]string{address1}).Once()
+	peers.On("Addresses", mock.Anything).Return(active).Once()
+	peers.On("Addresses", mock.Anything).Return(nil).Once()
+	peers.On("Addresses", mock.Anything).Return(active).Once()
+	peers.On("Addresses", mock.Anything).Return(active).Once()
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute announce and trickle
+	rel.Announce(hash1)
+	rel.Announce(hash2)
+	err := rel.Trickle()
+
+	// check conditions
+	assert.Nil(t, err)
+
+	if net.AssertNumberOfCalls(t, "Send", 3) {
+		net.AssertCalled(t, "Send", address1, &message.Announce{Hashes: []types.Hash{hash2}})
+		for _, call := range net.Calls {
+			address := call.Arguments.String(0)
+			if address == address1 {
+				continue
+			}
+			msg := call.Arguments.Get(1).(*message.Announce)
+			assert.ElementsMatch(t, []types.Hash{hash1, hash2}, msg.Hashes)
+		}
+	}
+}
+
+func TestRelayTrickleDelay(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+	hash := types.Hash{0x1}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	peers := &PeersMock{}
+
+	// initialize relay
+	rel := NewRelay(net, peers, SetInterval(time.Hour))
+
+	// program mocks
+	peers.On("Addresses", mock.Anything).Return(nil).Once()
+	peers.On("Addresses", mock.Anything).Return([]string{address})
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute announce and trickle
+	rel.Announce(hash)
+	err := rel.Trickle()
+
+	// check conditions
+	assert.Nil(t, err)
+
+	net.AssertNumberOfCalls(t, "Send", 0)
+	assert.Len(t, rel.queues[address], 1)
+}
+
+func TestRelayTrickleMaxAnnounce(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize mocks
+	net := &NetworkMock{}
+	peers := &PeersMock{}
+
+	// initialize relay
+	rel := NewRelay(net, peers, SetInterval(0), SetMaxAnnounce(2))
+
+	// program mocks
+	peers.On("Addresses", mock.Anything).Return(nil).Once()
+	peers.On("Addresses", mock.Anything).Return([]string{address}).Once()
+	peers.On("Addresses", mock.Anything).Return(nil).Once()
+	peers.On("Addresses", mock.Anything).Return([]string{address}).Once()
+	peers.On("Addresses", mock.Anything).Return(nil).Once()
+	peers.On("Addresses", mock.Anything).Return([]string{address}).Once()
+	peers.On("Addresses", mock.Anything).Return([]string{address})
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute announce and trickle
+	rel.Announce(types.Hash{0x1})
+	rel.Announce(types.Hash{0x2})
+	rel.Announce(types.Hash{0x3})
+	err1 := rel.Trickle()
+	err2 := rel.Trickle()
+	err3 := rel.Trickle()
+
+	// check conditions
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Nil(t, err3)
+
+	if net.AssertNumberOfCalls(t, "Send", 2) {
+		assert.Len(t, net.Calls[0].Arguments.Get(1).(*message.Announce).Hashes, 2)
+		assert.Len(t, net.Calls[1].Arguments.Get(1).(*message.Announce).Hashes, 1)
+	}
+}
+
+func TestRelayAnnounceMaxQueue(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize mocks
+	net := &NetworkMock{}
+	peers := &PeersMock{}
+
+	// initialize relay
+	rel := NewRelay(net, peers, SetMaxQueue(1))
+
+	// program mocks
+	peers.On("Addresses", mock.Anything).Return(nil).Once()
+	peers.On("Addresses", mock.Anything).Return([]string{address}).Once()
+	peers.On("Addresses", mock.Anything).Return(nil).Once()
+	peers.On("Addresses", mock.Anything).Return([]string{address}).Once()
+
+	// execute announce
+	rel.Announce(types.Hash{0x1})
+	rel.Announce(types.Hash{0x2})
+
+	// check conditions
+	assert.Len(t, rel.queues[address], 1)
+}
+
+func TestRelayTrickleInactive(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize mocks
+	net := &NetworkMock{}
+	peers := &PeersMock{}
+
+	// initialize relay
+	rel := NewRelay(net, peers, SetInterval(0))
+
+	// program mocks
+	peers.On("Addresses", mock.Anything).Return(nil).Once()
+	peers.On("Addresses", mock.Anything).Return([]string{address}).Once()
+	peers.On("Addresses", mock.Anything).Return(nil)
+	net.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	// execute announce and trickle
+	rel.Announce(types.Hash{0x1})
+	err := rel.Trickle()
+
+	// check conditions
+	assert.Nil(t, err)
+
+	net.AssertNumberOfCalls(t, "Send", 0)
+	assert.NotContains(t, rel.queues, address)
+	assert.NotContains(t, rel.next, address)
+}
+
+func TestRelayTrickleSendFails(t *testing.T) {
+
+	// initialize parameters
+	address := "192.0.2.1"
+
+	// initialize mocks
+	net := &NetworkMock{}
+	peers := &PeersMock{}
+
+	// initialize relay
+	rel := NewRelay(net, peers, SetInterval(0))
+
+	// program mocks
+	peers.On("Addresses", mock.Anything).Return(nil).Once()
+	peers.On("Addresses", mock.Anything).Return([]string{address})
+	net.On("Send", mock.Anything, mock.Anything).Return(errors.New("could not send"))
+
+	// execute announce and trickle
+	rel.Announce(types.Hash{0x1})
+	err := rel.Trickle()
+
+	// check conditions
+	assert.NotNil(t, err)
+}
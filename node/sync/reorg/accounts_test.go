@@ -0,0 +1,46 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package reorg
+
+import (
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/stretchr/testify/mock"
+)
+
+// AccountsMock mocks the account state interface.
+type AccountsMock struct {
+	mock.Mock
+}
+
+// Apply mocks the apply function of the account state interface.
+func (am *AccountsMock) Apply(block *types.Block) error {
+	args := am.Called(block)
+	return args.Error(0)
+}
+
+// Revert mocks the revert function of the account state interface.
+func (am *AccountsMock) Revert(block *types.Block) error {
+	args := am.Called(block)
+	return args.Error(0)
+}
+
+// Valid mocks the valid function of the account state interface.
+func (am *AccountsMock) Valid(tx *types.Transaction) bool {
+	args := am.Called(tx)
+	return args.Bool(0)
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package reorg
+
+import (
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/stretchr/testify/mock"
+)
+
+// BlocksMock mocks the blockchain database interface.
+type BlocksMock struct {
+	mock.Mock
+}
+
+// AddBlock mocks the block storage function of the blockchain database
+// interface.
+func (bm *BlocksMock) AddBlock(block *types.Block) error {
+	args := bm.Called(block)
+	return args.Error(0)
+}
+
+// BlockByHash mocks the block retrieval function of the blockchain database
+// interface.
+func (bm *BlocksMock) BlockByHash(hash types.Hash) (*types.Block, error) {
+	args := bm.Called(hash)
+	var block *types.Block
+	if args.Get(0) != nil {
+		block = args.Get(0).(*types.Block)
+	}
+	return block, args.Error(1)
+}
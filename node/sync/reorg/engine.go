@@ -0,0 +1,293 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package reorg
+
+import (
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/alvalor/alvalor-go/node/sync/orchestration"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// Engine keeps the connected blocks in line with the best path of headers.
+// When the best path changes, it disconnects the blocks of the old branch,
+// rolling back the account state and returning their transactions to the
+// pool, and connects the blocks of the new branch in order as they become
+// available. Blocks that can't be applied to the account state are invalid;
+// the header repository then switches the best path away from them.
+type Engine struct {
+	sync.Mutex
+	path         Path
+	headers      Headers
+	blocks       Blocks
+	accounts     Accounts
+	transactions Transactions
+	collector    Collector
+	events       Events
+	maxDepth     uint
+	ready        map[types.Hash]*types.Block
+}
+
+// NewEngine creates a new reorganization engine with the given root block as
+// connected and the given maximum number of blocks to disconnect on a change
+// of the best path. A maximum depth of zero means it is not enforced.
+func NewEngine(path Path, headers Headers, blocks Blocks, accounts Accounts, transactions Transactions, collector Collector, events Events, root types.Hash, maxDepth uint) *Engine {
+	path.Switch([]types.Hash{root})
+	path.Connect(root)
+	e := &Engine{
+		path:         path,
+		headers:      headers,
+		blocks:       blocks,
+		accounts:     accounts,
+		transactions: transactions,
+		collector:    collector,
+		events:       events,
+		maxDepth:     maxDepth,
+		ready:        make(map[types.Hash]*types.Block),
+	}
+	return e
+}
+
+// Follow switches the engine to a new best path, given as the branch from the
+// point where it forks off the followed path up to the new best header. Only
+// the blocks after the fork point are looked at, so following a new header
+// doesn't depend on the length of the path.
+func (e *Engine) Follow(branch []types.Hash) error {
+	e.Lock()
+	defer e.Unlock()
+
+	return e.follow(branch)
+}
+
+// follow switches to a new best path without locking the engine.
+func (e *Engine) follow(branch []types.Hash) error {
+
+	// check how many connected blocks we would have to disconnect
+	ancestor, cancel, ok := e.path.Fork(branch)
+	if !ok {
+		return errors.Wrap(ErrFork, "branch does not fork off followed path")
+	}
+	depth := uint(0)
+	for _, hash := range cancel {
+		if e.path.Connected(hash) {
+			depth++
+		}
+	}
+	if e.maxDepth > 0 && depth > e.maxDepth {
+		err := e.refuse(branch, ancestor)
+		if err != nil {
+			return errors.Wrap(err, "could not refuse branch")
+		}
+		return errors.Wrapf(ErrDepth, "too many blocks to disconnect (%d > %d)", depth, e.maxDepth)
+	}
+
+	// switch to the new path
+	cancel, start, _ := e.path.Switch(branch)
+
+	// disconnect the old branch from the tip and cancel obsolete downloads; if
+	// we can't disconnect a block, we can't revert its ancestors either, as the
+	// account state would no longer match any path
+	var result *multierror.Error
+	for i := len(cancel) - 1; i >= 0; i-- {
+		hash := cancel[i]
+		delete(e.ready, hash)
+		if !e.path.Connected(hash) {
+			err := e.collector.Suspend(hash)
+			if err != nil && errors.Cause(err) != orchestration.ErrNotExist {
+				result = multierror.Append(result, errors.Wrapf(err, "could not suspend block collection (%x)", hash))
+			}
+			continue
+		}
+		err := e.disconnect(hash)
+		if err != nil {
+			return errors.Wrapf(err, "could not disconnect block (%x)", hash)
+		}
+		err = e.events.Disconnected(hash)
+		if err != nil {
+			result = multierror.Append(result, errors.Wrapf(err, "could not publish disconnected event (%x)", hash))
+		}
+	}
+
+	// reuse the blocks of the new branch we already have, collect the others
+	for _, hash := range start {
+		block, err := e.blocks.BlockByHash(hash)
+		if err == nil {
+			e.ready[hash] = block
+			continue
+		}
+		err = e.collector.Collect(hash)
+		if err != nil && errors.Cause(err) != orchestration.ErrExist {
+			result = multierror.Append(result, errors.Wrapf(err, "could not start block collection (%x)", hash))
+		}
+	}
+
+	// connect the available blocks of the new branch in order
+	err := e.advance()
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
+
+	// let subscribers know if we switched to another branch
+	if len(cancel) > 0 && len(branch) > 0 {
+		err = e.events.Reorg(cancel[len(cancel)-1], branch[len(branch)-1], ancestor, depth)
+		if err != nil {
+			result = multierror.Append(result, errors.Wrap(err, "could not publish reorg event"))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+// Connect connects a block that was assembled and validated, as soon as all of
+// its predecessors on the best path are connected.
+func (e *Engine) Connect(block *types.Block) error {
+	e.Lock()
+	defer e.Unlock()
+
+	e.ready[block.Hash] = block
+
+	return e.advance()
+}
+
+// advance connects the available blocks that follow the last connected block
+// on the best path. A block is only dropped once it is connected, so a block
+// that failed to be stored is retried on the next advance, or once it is found
+// to be invalid, in which case we follow the path without it.
+func (e *Engine) advance() error {
+	for {
+		hash, ok := e.path.Next()
+		if !ok {
+			return nil
+		}
+		block, ok := e.ready[hash]
+		if !ok {
+			return nil
+		}
+		err := e.connect(block)
+		if errors.Cause(err) == ErrInvalid {
+			delete(e.ready, hash)
+			return e.reject(hash)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "could not connect block (%x)", hash)
+		}
+		delete(e.ready, hash)
+	}
+}
+
+// reject marks an invalid block in the header repository and follows the best
+// path that replaces the one it was on.
+func (e *Engine) reject(hash types.Hash) error {
+	branch, err := e.headers.Invalidate(hash)
+	if err != nil {
+		return errors.Wrapf(err, "could not invalidate block (%x)", hash)
+	}
+	if len(branch) == 0 {
+		return nil
+	}
+	err = e.follow(branch)
+	if err != nil {
+		return errors.Wrapf(err, "could not follow path without invalid block (%x)", hash)
+	}
+	return nil
+}
+
+// refuse invalidates the branch that would have made us disconnect too many
+// blocks, so that the header repository goes back to the path we follow, and
+// follows the branch it switched to instead.
+func (e *Engine) refuse(branch []types.Hash, ancestor types.Hash) error {
+	for i := 0; i < len(branch)-1; i++ {
+		if branch[i] != ancestor {
+			continue
+		}
+		return e.reject(branch[i+1])
+	}
+	return nil
+}
+
+// connect applies a block to the account state, commits it to the blockchain
+// and marks it as connected. Blocks that don't apply to the account state are
+// never committed.
+func (e *Engine) connect(block *types.Block) error {
+
+	// apply the block transactions to the account state; if we can't, the
+	// block is invalid
+	err := e.accounts.Apply(block)
+	if err != nil {
+		return errors.Wrapf(ErrInvalid, "could not apply block to accounts (%v)", err)
+	}
+
+	// commit the block to the blockchain, or roll it back if we can't
+	err = e.blocks.AddBlock(block)
+	if err != nil {
+		rerr := e.accounts.Revert(block)
+		if rerr != nil {
+			return errors.Wrap(rerr, "could not revert uncommitted block")
+		}
+		return errors.Wrap(err, "could not add block to blockchain")
+	}
+
+	// mark the block as connected
+	e.path.Connect(block.Hash)
+
+	// the transactions are now part of the blockchain
+	for _, tx := range block.Transactions {
+		_ = e.transactions.Remove(tx.Hash)
+	}
+
+	// let subscribers know about the connected block
+	err = e.events.Block(block.Hash)
+	if err != nil {
+		return errors.Wrap(err, "could not publish block event")
+	}
+
+	return nil
+}
+
+// disconnect rolls a block back from the account state and returns its
+// transactions that are still valid to the transaction pool.
+func (e *Engine) disconnect(hash types.Hash) error {
+
+	// retrieve the block from the blockchain
+	block, err := e.blocks.BlockByHash(hash)
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve block")
+	}
+
+	// roll back the block transactions from the account state
+	err = e.accounts.Revert(block)
+	if err != nil {
+		return errors.Wrap(err, "could not revert block from accounts")
+	}
+
+	// mark the block as disconnected
+	e.path.Disconnect(hash)
+
+	// return the transactions that are still valid to the pool
+	for _, tx := range block.Transactions {
+		if !e.accounts.Valid(tx) {
+			continue
+		}
+		_ = e.transactions.Add(tx)
+	}
+
+	return nil
+}
@@ -0,0 +1,527 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package reorg
+
+import (
+	"testing"
+
+	"github.com/alvalor/alvalor-go/node/state/path"
+	"github.com/alvalor/alvalor-go/node/sync/orchestration"
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newBlock(hash types.Hash, parent types.Hash, txs ...*types.Transaction) *types.Block {
+	return &types.Block{
+		Header:       &types.Header{Hash: hash, Parent: parent},
+		Transactions: txs,
+	}
+}
+
+func TestEngineConnectInOrder(t *testing.T) {
+
+	// initialize parameters
+	root := types.Hash{0x0}
+	hash1 := types.Hash{0x1}
+	hash2 := types.Hash{0x2}
+
+	// initialize entities
+	tx := &types.Transaction{Hash: types.Hash{0xa}}
+	block1 := newBlock(hash1, root, tx)
+	block2 := newBlock(hash2, hash1)
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	blocks := &BlocksMock{}
+	accounts := &AccountsMock{}
+	transactions := &TransactionsMock{}
+	collector := &CollectorMock{}
+	events := &EventsMock{}
+
+	// initialize engine
+	st := &path.State{}
+	e := NewEngine(st, headers, blocks, accounts, transactions, collector, events, root, 0)
+
+	// program mocks
+	blocks.On("BlockByHash", mock.Anything).Return(nil, errors.New(""))
+	collector.On("Collect", mock.Anything).Return(nil)
+	accounts.On("Apply", mock.Anything).Return(nil)
+	blocks.On("AddBlock", mock.Anything).Return(nil)
+	transactions.On("Remove", mock.Anything).Return(nil)
+	events.On("Block", mock.Anything).Return(nil)
+
+	// execute follow
+	err := e.Follow([]types.Hash{root, hash1, hash2})
+
+	// check conditions
+	assert.Nil(t, err)
+
+	if collector.AssertNumberOfCalls(t, "Collect", 2) {
+		collector.AssertCalled(t, "Collect", hash1)
+		collector.AssertCalled(t, "Collect", hash2)
+	}
+
+	events.AssertNumberOfCalls(t, "Reorg", 0)
+
+	// execute connect out of order
+	err = e.Connect(block2)
+
+	// check conditions
+	assert.Nil(t, err)
+	assert.False(t, st.Connected(hash2))
+
+	accounts.AssertNumberOfCalls(t, "Apply", 0)
+
+	// execute connect of the missing block
+	err = e.Connect(block1)
+
+	// check conditions
+	assert.Nil(t, err)
+	assert.True(t, st.Connected(hash1))
+	assert.True(t, st.Connected(hash2))
+
+	if accounts.AssertNumberOfCalls(t, "Apply", 2) {
+		assert.Equal(t, block1, accounts.Calls[0].Arguments.Get(0))
+		assert.Equal(t, block2, accounts.Calls[1].Arguments.Get(0))
+	}
+
+	if transactions.AssertNumberOfCalls(t, "Remove", 1) {
+		transactions.AssertCalled(t, "Remove", tx.Hash)
+	}
+
+	if events.AssertNumberOfCalls(t, "Block", 2) {
+		events.AssertCalled(t, "Block", hash1)
+		events.AssertCalled(t, "Block", hash2)
+	}
+}
+
+func TestEngineFollowReorg(t *testing.T) {
+
+	// initialize parameters
+	root := types.Hash{0x0}
+	hashA1 := types.Hash{0xa1}
+	hashA2 := types.Hash{0xa2}
+	hashB1 := types.Hash{0xb1}
+	hashB2 := types.Hash{0xb2}
+	hashB3 := types.Hash{0xb3}
+
+	// initialize entities
+	valid := &types.Transaction{Hash: types.Hash{0x1}}
+	invalid := &types.Transaction{Hash: types.Hash{0x2}}
+	blockA1 := newBlock(hashA1, root, valid)
+	blockA2 := newBlock(hashA2, hashA1, invalid)
+	blockB1 := newBlock(hashB1, root)
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	blocks := &BlocksMock{}
+	accounts := &AccountsMock{}
+	transactions := &TransactionsMock{}
+	collector := &CollectorMock{}
+	events := &EventsMock{}
+
+	// initialize engine
+	st := &path.State{}
+	e := NewEngine(st, headers, blocks, accounts, transactions, collector, events, root, 0)
+
+	// initialize state
+	st.Set([]types.Hash{root, hashA1, hashA2})
+	st.Connect(hashA1)
+	st.Connect(hashA2)
+
+	// program mocks
+	blocks.On("BlockByHash", hashA1).Return(blockA1, nil)
+	blocks.On("BlockByHash", hashA2).Return(blockA2, nil)
+	blocks.On("BlockByHash", hashB1).Return(blockB1, nil)
+	blocks.On("BlockByHash", mock.Anything).Return(nil, errors.New(""))
+	accounts.On("Revert", mock.Anything).Return(nil)
+	accounts.On("Apply", mock.Anything).Return(nil)
+	blocks.On("AddBlock", mock.Anything).Return(nil)
+	accounts.On("Valid", valid).Return(true)
+	accounts.On("Valid", invalid).Return(false)
+	transactions.On("Add", mock.Anything).Return(nil)
+	collector.On("Collect", mock.Anything).Return(nil)
+	events.On("Block", mock.Anything).Return(nil)
+	events.On("Disconnected", mock.Anything).Return(nil)
+	events.On("Reorg", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	// execute follow
+	err := e.Follow([]types.Hash{root, hashB1, hashB2, hashB3})
+
+	// check conditions
+	assert.Nil(t, err)
+	assert.False(t, st.Connected(hashA1))
+	assert.False(t, st.Connected(hashA2))
+	assert.True(t, st.Connected(hashB1))
+	assert.False(t, st.Connected(hashB2))
+
+	if accounts.AssertNumberOfCalls(t, "Revert", 2) {
+		assert.Equal(t, blockA2, accounts.Calls[0].Arguments.Get(0))
+	}
+
+	if transactions.AssertNumberOfCalls(t, "Add", 1) {
+		transactions.AssertCalled(t, "Add", valid)
+	}
+
+	if collector.AssertNumberOfCalls(t, "Collect", 2) {
+		collector.AssertCalled(t, "Collect", hashB2)
+		collector.AssertCalled(t, "Collect", hashB3)
+	}
+
+	if accounts.AssertNumberOfCalls(t, "Apply", 1) {
+		accounts.AssertCalled(t, "Apply", blockB1)
+	}
+
+	if events.AssertNumberOfCalls(t, "Disconnected", 2) {
+		assert.Equal(t, hashA2, events.Calls[0].Arguments.Get(0))
+		assert.Equal(t, hashA1, events.Calls[1].Arguments.Get(0))
+	}
+
+	if events.AssertNumberOfCalls(t, "Reorg", 1) {
+		events.AssertCalled(t, "Reorg", hashA2, hashB3, root, uint(2))
+	}
+}
+
+func TestEngineFollowSuspend(t *testing.T) {
+
+	// initialize parameters
+	root := types.Hash{0x0}
+	hashA1 := types.Hash{0xa1}
+	hashA2 := types.Hash{0xa2}
+	hashB1 := types.Hash{0xb1}
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	blocks := &BlocksMock{}
+	accounts := &AccountsMock{}
+	transactions := &TransactionsMock{}
+	collector := &CollectorMock{}
+	events := &EventsMock{}
+
+	// initialize engine
+	st := &path.State{}
+	e := NewEngine(st, headers, blocks, accounts, transactions, collector, events, root, 0)
+
+	// initialize state
+	st.Set([]types.Hash{root, hashA1, hashA2})
+
+	// program mocks
+	blocks.On("BlockByHash", mock.Anything).Return(nil, errors.New(""))
+	collector.On("Collect", mock.Anything).Return(nil)
+	collector.On("Suspend", hashA1).Return(nil)
+	collector.On("Suspend", hashA2).Return(errors.Wrap(orchestration.ErrNotExist, "not collecting"))
+	events.On("Reorg", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	// execute follow
+	err := e.Follow([]types.Hash{root, hashB1})
+
+	// check conditions
+	assert.Nil(t, err)
+
+	if collector.AssertNumberOfCalls(t, "Suspend", 2) {
+		collector.AssertCalled(t, "Suspend", hashA1)
+		collector.AssertCalled(t, "Suspend", hashA2)
+	}
+
+	accounts.AssertNumberOfCalls(t, "Revert", 0)
+
+	if events.AssertNumberOfCalls(t, "Reorg", 1) {
+		events.AssertCalled(t, "Reorg", hashA2, hashB1, root, uint(0))
+	}
+}
+
+func TestEngineFollowMaxDepth(t *testing.T) {
+
+	// initialize parameters
+	root := types.Hash{0x0}
+	hashA1 := types.Hash{0xa1}
+	hashA2 := types.Hash{0xa2}
+	hashB1 := types.Hash{0xb1}
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	blocks := &BlocksMock{}
+	accounts := &AccountsMock{}
+	transactions := &TransactionsMock{}
+	collector := &CollectorMock{}
+	events := &EventsMock{}
+
+	// initialize engine
+	st := &path.State{}
+	e := NewEngine(st, headers, blocks, accounts, transactions, collector, events, root, 1)
+
+	// initialize state
+	st.Set([]types.Hash{root, hashA1, hashA2})
+	st.Connect(hashA1)
+	st.Connect(hashA2)
+
+	// program mocks
+	headers.On("Invalidate", hashB1).Return([]types.Hash{root, hashA1, hashA2}, nil)
+
+	// execute follow
+	err := e.Follow([]types.Hash{root, hashB1})
+
+	// check conditions
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrDepth, errors.Cause(err))
+	}
+
+	assert.Equal(t, []types.Hash{root, hashA1, hashA2}, st.Current())
+	assert.True(t, st.Connected(hashA2))
+
+	if headers.AssertNumberOfCalls(t, "Invalidate", 1) {
+		headers.AssertCalled(t, "Invalidate", hashB1)
+	}
+
+	accounts.AssertNumberOfCalls(t, "Revert", 0)
+	events.AssertNumberOfCalls(t, "Reorg", 0)
+}
+
+func TestEngineFollowDisconnectFails(t *testing.T) {
+
+	// initialize parameters
+	root := types.Hash{0x0}
+	hashA1 := types.Hash{0xa1}
+	hashA2 := types.Hash{0xa2}
+	hashB1 := types.Hash{0xb1}
+
+	// initialize entities
+	blockA1 := newBlock(hashA1, root)
+	blockA2 := newBlock(hashA2, hashA1)
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	blocks := &BlocksMock{}
+	accounts := &AccountsMock{}
+	transactions := &TransactionsMock{}
+	collector := &CollectorMock{}
+	events := &EventsMock{}
+
+	// initialize engine
+	st := &path.State{}
+	e := NewEngine(st, headers, blocks, accounts, transactions, collector, events, root, 0)
+
+	// initialize state
+	st.Set([]types.Hash{root, hashA1, hashA2})
+	st.Connect(hashA1)
+	st.Connect(hashA2)
+
+	// program mocks
+	blocks.On("BlockByHash", hashA1).Return(blockA1, nil)
+	blocks.On("BlockByHash", hashA2).Return(blockA2, nil)
+	accounts.On("Revert", blockA2).Return(errors.New(""))
+	accounts.On("Revert", blockA1).Return(nil)
+	events.On("Disconnected", mock.Anything).Return(nil)
+	events.On("Reorg", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	// execute follow
+	err := e.Follow([]types.Hash{root, hashB1})
+
+	// check conditions
+	assert.NotNil(t, err)
+	assert.True(t, st.Connected(hashA1))
+	assert.True(t, st.Connected(hashA2))
+
+	if accounts.AssertNumberOfCalls(t, "Revert", 1) {
+		accounts.AssertCalled(t, "Revert", blockA2)
+	}
+
+	events.AssertNumberOfCalls(t, "Disconnected", 0)
+	events.AssertNumberOfCalls(t, "Reorg", 0)
+}
+
+func TestEngineConnectApplyFails(t *testing.T) {
+
+	// initialize parameters
+	root := types.Hash{0x0}
+	hash1 := types.Hash{0x1}
+
+	// initialize entities
+	block1 := newBlock(hash1, root)
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	blocks := &BlocksMock{}
+	accounts := &AccountsMock{}
+	transactions := &TransactionsMock{}
+	collector := &CollectorMock{}
+	events := &EventsMock{}
+
+	// initialize engine
+	st := &path.State{}
+	e := NewEngine(st, headers, blocks, accounts, transactions, collector, events, root, 0)
+
+	// initialize state
+	st.Set([]types.Hash{root, hash1})
+
+	// program mocks
+	accounts.On("Apply", mock.Anything).Return(errors.New(""))
+	headers.On("Invalidate", mock.Anything).Return(nil, nil)
+
+	// execute connect
+	err := e.Connect(block1)
+
+	// check conditions
+	assert.Nil(t, err)
+	assert.False(t, st.Connected(hash1))
+	assert.NotContains(t, e.ready, hash1)
+
+	if headers.AssertNumberOfCalls(t, "Invalidate", 1) {
+		headers.AssertCalled(t, "Invalidate", hash1)
+	}
+
+	blocks.AssertNumberOfCalls(t, "AddBlock", 0)
+	events.AssertNumberOfCalls(t, "Block", 0)
+}
+
+func TestEngineConnectAddFails(t *testing.T) {
+
+	// initialize parameters
+	root := types.Hash{0x0}
+	hash1 := types.Hash{0x1}
+
+	// initialize entities
+	block1 := newBlock(hash1, root)
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	blocks := &BlocksMock{}
+	accounts := &AccountsMock{}
+	transactions := &TransactionsMock{}
+	collector := &CollectorMock{}
+	events := &EventsMock{}
+
+	// initialize engine
+	st := &path.State{}
+	e := NewEngine(st, headers, blocks, accounts, transactions, collector, events, root, 0)
+
+	// initialize state
+	st.Set([]types.Hash{root, hash1})
+
+	// program mocks
+	accounts.On("Apply", mock.Anything).Return(nil)
+	accounts.On("Revert", mock.Anything).Return(nil)
+	blocks.On("AddBlock", mock.Anything).Return(errors.New(""))
+
+	// execute connect
+	err := e.Connect(block1)
+
+	// check conditions
+	assert.NotNil(t, err)
+	assert.False(t, st.Connected(hash1))
+	assert.Contains(t, e.ready, hash1)
+
+	if accounts.AssertNumberOfCalls(t, "Revert", 1) {
+		accounts.AssertCalled(t, "Revert", block1)
+	}
+
+	headers.AssertNumberOfCalls(t, "Invalidate", 0)
+	events.AssertNumberOfCalls(t, "Block", 0)
+}
+
+func TestEngineConnectInvalidReroute(t *testing.T) {
+
+	// initialize parameters
+	root := types.Hash{0x0}
+	hashA1 := types.Hash{0xa1}
+	hashA2 := types.Hash{0xa2}
+	hashB1 := types.Hash{0xb1}
+
+	// initialize entities
+	blockA1 := newBlock(hashA1, root)
+	blockB1 := newBlock(hashB1, root)
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	blocks := &BlocksMock{}
+	accounts := &AccountsMock{}
+	transactions := &TransactionsMock{}
+	collector := &CollectorMock{}
+	events := &EventsMock{}
+
+	// initialize engine
+	st := &path.State{}
+	e := NewEngine(st, headers, blocks, accounts, transactions, collector, events, root, 0)
+
+	// initialize state
+	st.Set([]types.Hash{root, hashA1, hashA2})
+
+	// program mocks
+	headers.On("Invalidate", hashA1).Return([]types.Hash{root, hashB1}, nil)
+	blocks.On("BlockByHash", hashB1).Return(blockB1, nil)
+	blocks.On("AddBlock", mock.Anything).Return(nil)
+	collector.On("Suspend", mock.Anything).Return(nil)
+	accounts.On("Apply", blockA1).Return(errors.New(""))
+	accounts.On("Apply", blockB1).Return(nil)
+	events.On("Block", mock.Anything).Return(nil)
+	events.On("Reorg", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	// execute connect of the invalid block
+	err := e.Connect(blockA1)
+
+	// check conditions
+	assert.Nil(t, err)
+	assert.Equal(t, []types.Hash{root, hashB1}, st.Current())
+	assert.False(t, st.Connected(hashA1))
+	assert.True(t, st.Connected(hashB1))
+
+	if collector.AssertNumberOfCalls(t, "Suspend", 2) {
+		collector.AssertCalled(t, "Suspend", hashA1)
+		collector.AssertCalled(t, "Suspend", hashA2)
+	}
+
+	if events.AssertNumberOfCalls(t, "Reorg", 1) {
+		events.AssertCalled(t, "Reorg", hashA2, hashB1, root, uint(0))
+	}
+}
+
+func TestEngineFollowFork(t *testing.T) {
+
+	// initialize parameters
+	root := types.Hash{0x0}
+	hashA1 := types.Hash{0xa1}
+	hashB1 := types.Hash{0xb1}
+	hashB2 := types.Hash{0xb2}
+
+	// initialize mocks
+	headers := &HeadersMock{}
+	blocks := &BlocksMock{}
+	accounts := &AccountsMock{}
+	transactions := &TransactionsMock{}
+	collector := &CollectorMock{}
+	events := &EventsMock{}
+
+	// initialize engine
+	st := &path.State{}
+	e := NewEngine(st, headers, blocks, accounts, transactions, collector, events, root, 0)
+
+	// initialize state
+	st.Set([]types.Hash{root, hashA1})
+
+	// execute follow from a fork point we are not on
+	err := e.Follow([]types.Hash{hashB1, hashB2})
+
+	// check conditions
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrFork, errors.Cause(err))
+	}
+
+	assert.Equal(t, []types.Hash{root, hashA1}, st.Current())
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package reorg
+
+import (
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/stretchr/testify/mock"
+)
+
+// EventsMock mocks the event manager interface.
+type EventsMock struct {
+	mock.Mock
+}
+
+// Block mocks the block function of the event manager interface.
+func (em *EventsMock) Block(hash types.Hash) error {
+	args := em.Called(hash)
+	return args.Error(0)
+}
+
+// Disconnected mocks the disconnected function of the event manager interface.
+func (em *EventsMock) Disconnected(hash types.Hash) error {
+	args := em.Called(hash)
+	return args.Error(0)
+}
+
+// Reorg mocks the reorg function of the event manager interface.
+func (em *EventsMock) Reorg(old types.Hash, new types.Hash, ancestor types.Hash, depth uint) error {
+	args := em.Called(old, new, ancestor, depth)
+	return args.Error(0)
+}
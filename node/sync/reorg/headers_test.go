@@ -0,0 +1,39 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package reorg
+
+import (
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/stretchr/testify/mock"
+)
+
+// HeadersMock mocks the header repository interface.
+type HeadersMock struct {
+	mock.Mock
+}
+
+// Invalidate mocks the invalidate function of the header repository
+// interface.
+func (hm *HeadersMock) Invalidate(hash types.Hash) ([]types.Hash, error) {
+	args := hm.Called(hash)
+	var branch []types.Hash
+	if args.Get(0) != nil {
+		branch = args.Get(0).([]types.Hash)
+	}
+	return branch, args.Error(1)
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package status
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// Broadcaster keeps our peers up to date with the tip of our best path. It
+// broadcasts our status whenever our best path changes, and again after a
+// refresh interval, so that peers can request the headers they are missing
+// without waiting for them to be relayed.
+type Broadcaster struct {
+	sync.Mutex
+	net     Network
+	headers Headers
+	genesis types.Hash
+	cfg     Config
+	now     func() time.Time
+	last    types.Hash
+	next    time.Time
+}
+
+// NewBroadcaster creates a new status broadcaster for the path starting at the
+// given genesis hash.
+func NewBroadcaster(net Network, headers Headers, genesis types.Hash, options ...func(*Config)) *Broadcaster {
+	cfg := DefaultConfig()
+	for _, option := range options {
+		option(&cfg)
+	}
+	bc := &Broadcaster{
+		net:     net,
+		headers: headers,
+		genesis: genesis,
+		cfg:     cfg,
+		now:     time.Now,
+	}
+	return bc
+}
+
+// Check broadcasts our status if the tip of our best path changed since the
+// last broadcast, or if the refresh interval has passed. It should be called
+// on a short interval, which bounds the rate of updates while we catch up.
+func (bc *Broadcaster) Check() error {
+	bc.Lock()
+	defer bc.Unlock()
+
+	// check whether our peers are due for an update
+	path, distance := bc.headers.Recent(1)
+	if len(path) == 0 {
+		return nil
+	}
+	tip := path[0]
+	now := bc.now()
+	if tip == bc.last && now.Before(bc.next) {
+		return nil
+	}
+
+	// broadcast the tip of our best path
+	height, err := bc.headers.Height(tip)
+	if err != nil {
+		return errors.Wrap(err, "could not get height of best header")
+	}
+	status := &message.Status{
+		Hash:     tip,
+		Height:   height,
+		Distance: distance,
+		Genesis:  bc.genesis,
+	}
+	err = bc.net.Broadcast(status)
+	if err != nil {
+		return errors.Wrap(err, "could not broadcast status")
+	}
+
+	bc.last = tip
+	bc.next = now.Add(bc.cfg.refresh)
+
+	return nil
+}
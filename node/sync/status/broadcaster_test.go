@@ -0,0 +1,134 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package status
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alvalor/alvalor-go/node/handlers/message"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+func TestBroadcasterCheckChanged(t *testing.T) {
+
+	// initialize parameters
+	genesis := types.Hash{0x0}
+	tip1 := types.Hash{0x1}
+	tip2 := types.Hash{0x2}
+	now := time.Unix(1000, 0)
+
+	// initialize entities
+	status1 := &message.Status{Hash: tip1, Height: 1, Distance: 100, Genesis: genesis}
+	status2 := &message.Status{Hash: tip2, Height: 2, Distance: 200, Genesis: genesis}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	headers := &HeadersMock{}
+
+	// initialize broadcaster
+	bc := NewBroadcaster(net, headers, genesis)
+	bc.now = func() time.Time { return now }
+
+	// program mocks
+	headers.On("Recent", uint(1)).Return([]types.Hash{tip1}, uint64(100)).Twice()
+	headers.On("Recent", uint(1)).Return([]types.Hash{tip2}, uint64(200))
+	headers.On("Height", tip1).Return(uint64(1), nil)
+	headers.On("Height", tip2).Return(uint64(2), nil)
+	net.On("Broadcast", mock.Anything, mock.Anything).Return(nil)
+
+	// execute checks before and after the tip changes
+	err := bc.Check()
+	assert.Nil(t, err)
+	err = bc.Check()
+	assert.Nil(t, err)
+	err = bc.Check()
+	assert.Nil(t, err)
+
+	// check conditions
+	if net.AssertNumberOfCalls(t, "Broadcast", 2) {
+		assert.Equal(t, status1, net.Calls[0].Arguments.Get(0))
+		assert.Equal(t, status2, net.Calls[1].Arguments.Get(0))
+	}
+}
+
+func TestBroadcasterCheckRefresh(t *testing.T) {
+
+	// initialize parameters
+	genesis := types.Hash{0x0}
+	tip := types.Hash{0x1}
+	now := time.Unix(1000, 0)
+
+	// initialize mocks
+	net := &NetworkMock{}
+	headers := &HeadersMock{}
+
+	// initialize broadcaster
+	bc := NewBroadcaster(net, headers, genesis, SetRefresh(time.Minute))
+	bc.now = func() time.Time { return now }
+
+	// program mocks
+	headers.On("Recent", uint(1)).Return([]types.Hash{tip}, uint64(100))
+	headers.On("Height", tip).Return(uint64(1), nil)
+	net.On("Broadcast", mock.Anything, mock.Anything).Return(nil)
+
+	// execute checks before and after the refresh interval
+	err := bc.Check()
+	assert.Nil(t, err)
+	now = now.Add(30 * time.Second)
+	err = bc.Check()
+	assert.Nil(t, err)
+	now = now.Add(30 * time.Second)
+	err = bc.Check()
+	assert.Nil(t, err)
+
+	// check conditions
+	net.AssertNumberOfCalls(t, "Broadcast", 2)
+}
+
+func TestBroadcasterCheckFails(t *testing.T) {
+
+	// initialize parameters
+	genesis := types.Hash{0x0}
+	tip := types.Hash{0x1}
+
+	// initialize mocks
+	net := &NetworkMock{}
+	headers := &HeadersMock{}
+
+	// initialize broadcaster
+	bc := NewBroadcaster(net, headers, genesis)
+
+	// program mocks
+	headers.On("Recent", uint(1)).Return([]types.Hash{tip}, uint64(100))
+	headers.On("Height", tip).Return(uint64(1), nil)
+	net.On("Broadcast", mock.Anything, mock.Anything).Return(errors.New("could not broadcast"))
+
+	// execute checks, retrying after the failure
+	err := bc.Check()
+	assert.NotNil(t, err)
+	err = bc.Check()
+	assert.NotNil(t, err)
+
+	// check conditions
+	net.AssertNumberOfCalls(t, "Broadcast", 2)
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package status
+
+import "time"
+
+// Config represents the parameters of the status broadcaster.
+type Config struct {
+	refresh time.Duration
+}
+
+// DefaultConfig returns the default parameters of the status broadcaster.
+func DefaultConfig() Config {
+	return Config{
+		refresh: 30 * time.Second,
+	}
+}
+
+// SetRefresh allows us to configure the interval after which we broadcast our
+// status again, even if our best path did not change, so that peers who missed
+// an update eventually learn about it.
+func SetRefresh(refresh time.Duration) func(*Config) {
+	return func(cfg *Config) {
+		cfg.refresh = refresh
+	}
+}
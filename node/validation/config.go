@@ -0,0 +1,82 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package validation
+
+import (
+	"time"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// Config represents the consensus parameters used to validate headers.
+type Config struct {
+	future      time.Duration
+	median      uint
+	interval    uint64
+	spacing     time.Duration
+	checkpoints map[uint64]types.Hash
+}
+
+// DefaultConfig returns the default consensus parameters.
+func DefaultConfig() Config {
+	return Config{
+		future:      2 * time.Hour,
+		median:      11,
+		interval:    2016,
+		spacing:     10 * time.Minute,
+		checkpoints: make(map[uint64]types.Hash),
+	}
+}
+
+// SetFuture allows us to configure how far in the future a header time can be.
+func SetFuture(future time.Duration) func(*Config) {
+	return func(cfg *Config) {
+		cfg.future = future
+	}
+}
+
+// SetMedian allows us to configure the number of ancestors used to compute the
+// median time past.
+func SetMedian(median uint) func(*Config) {
+	return func(cfg *Config) {
+		cfg.median = median
+	}
+}
+
+// SetInterval allows us to configure the number of headers between difficulty
+// retargets. Zero disables difficulty checks.
+func SetInterval(interval uint64) func(*Config) {
+	return func(cfg *Config) {
+		cfg.interval = interval
+	}
+}
+
+// SetSpacing allows us to configure the targeted time between headers.
+func SetSpacing(spacing time.Duration) func(*Config) {
+	return func(cfg *Config) {
+		cfg.spacing = spacing
+	}
+}
+
+// SetCheckpoints allows us to configure the header hashes expected at given
+// heights.
+func SetCheckpoints(checkpoints map[uint64]types.Hash) func(*Config) {
+	return func(cfg *Config) {
+		cfg.checkpoints = checkpoints
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package validation
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// maxTarget is the proof of work target for a difficulty of one.
+var maxTarget = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// Header validates headers against the consensus rules, using the chain of
+// already known headers for contextual checks.
+type Header struct {
+	chain Chain
+	cfg   Config
+	now   func() time.Time
+}
+
+// NewHeader creates a new header validator.
+func NewHeader(chain Chain, options ...func(*Config)) *Header {
+	cfg := DefaultConfig()
+	for _, option := range options {
+		option(&cfg)
+	}
+	return &Header{
+		chain: chain,
+		cfg:   cfg,
+		now:   time.Now,
+	}
+}
+
+// Validate checks the given header against the consensus rules. It first runs
+// the checks that don't need any context, so that headers with unknown parents
+// can be rejected early if they are invalid. Headers with unknown parents that
+// pass these checks result in ErrParent.
+func (hv *Header) Validate(header *types.Header) error {
+
+	// the hash has to match the contents of the header
+	if header.Hash != header.GetHash() {
+		return errors.Wrap(ErrHash, "invalid header hash")
+	}
+
+	// the hash has to meet the target defined by the difficulty
+	if header.Diff == 0 {
+		return errors.Wrap(ErrWork, "zero header difficulty")
+	}
+	target := new(big.Int).Div(maxTarget, new(big.Int).SetUint64(header.Diff))
+	if new(big.Int).SetBytes(header.Hash[:]).Cmp(target) > 0 {
+		return errors.Wrap(ErrWork, "header hash above target")
+	}
+
+	// the time can't be too far in the future
+	if header.Time.After(hv.now().Add(hv.cfg.future)) {
+		return errors.Wrapf(ErrFuture, "header time too late (%v)", header.Time)
+	}
+
+	// for the contextual checks, we need the parent
+	parent, err := hv.chain.Get(header.Parent)
+	if err != nil {
+		return errors.Wrap(ErrParent, "could not get header parent")
+	}
+	height, err := hv.chain.Height(header.Parent)
+	if err != nil {
+		return errors.Wrap(ErrParent, "could not get header parent height")
+	}
+	height++
+
+	// the time has to be after the median time of the previous headers
+	median, err := hv.median(parent)
+	if err != nil {
+		return errors.Wrap(err, "could not get median time past")
+	}
+	if !header.Time.After(median) {
+		return errors.Wrapf(ErrPast, "header time too early (%v <= %v)", header.Time, median)
+	}
+
+	// the difficulty has to follow the retarget rules
+	diff, err := hv.difficulty(parent, height)
+	if err != nil {
+		return errors.Wrap(err, "could not get expected difficulty")
+	}
+	if header.Diff != diff {
+		return errors.Wrapf(ErrDifficulty, "wrong header difficulty (%d != %d)", header.Diff, diff)
+	}
+
+	// the hash has to match a checkpoint at the same height
+	checkpoint, ok := hv.cfg.checkpoints[height]
+	if ok && checkpoint != header.Hash {
+		return errors.Wrapf(ErrCheckpoint, "header does not match checkpoint (%d)", height)
+	}
+
+	return nil
+}
+
+// median returns the median time of the given header and its ancestors.
+func (hv *Header) median(header *types.Header) (time.Time, error) {
+	var times []time.Time
+	for uint(len(times)) < hv.cfg.median {
+		times = append(times, header.Time)
+		if header.Parent == types.ZeroHash {
+			break
+		}
+		var err error
+		header, err = hv.chain.Get(header.Parent)
+		if err != nil {
+			return time.Time{}, errors.Wrap(err, "could not get ancestor")
+		}
+	}
+	sort.Slice(times, func(i int, j int) bool {
+		return times[i].Before(times[j])
+	})
+	return times[len(times)/2], nil
+}
+
+// difficulty returns the expected difficulty of a header with the given parent
+// and height. The difficulty stays the same within a retarget interval and is
+// adjusted at its end, so the headers of the last interval would have taken the
+// targeted spacing on average. The adjustment is limited to a factor of four.
+func (hv *Header) difficulty(parent *types.Header, height uint64) (uint64, error) {
+
+	// if we are not at a retarget height, the difficulty remains the same
+	if hv.cfg.interval == 0 || height%hv.cfg.interval != 0 {
+		return parent.Diff, nil
+	}
+
+	// find the last header before the interval
+	first := parent
+	for i := uint64(0); i < hv.cfg.interval && first.Parent != types.ZeroHash; i++ {
+		var err error
+		first, err = hv.chain.Get(first.Parent)
+		if err != nil {
+			return 0, errors.Wrap(err, "could not get ancestor")
+		}
+	}
+
+	// compute the actual timespan and limit it
+	expected := hv.cfg.spacing * time.Duration(hv.cfg.interval)
+	actual := parent.Time.Sub(first.Time)
+	if actual < expected/4 {
+		actual = expected / 4
+	}
+	if actual > expected*4 {
+		actual = expected * 4
+	}
+
+	// scale the difficulty by the ratio of expected and actual timespan
+	diff := new(big.Int).SetUint64(parent.Diff)
+	diff.Mul(diff, big.NewInt(int64(expected)))
+	diff.Div(diff, big.NewInt(int64(actual)))
+	if !diff.IsUint64() || diff.Uint64() == 0 {
+		return 0, errors.Wrap(ErrDifficulty, "difficulty out of range")
+	}
+
+	return diff.Uint64(), nil
+}
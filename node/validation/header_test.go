@@ -0,0 +1,174 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package validation
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/alvalor/alvalor-go/node/repos/headers"
+	"github.com/alvalor/alvalor-go/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newHeader(parent *types.Header, diff uint64, offset time.Duration) *types.Header {
+	header := &types.Header{Diff: diff}
+	if parent != nil {
+		header.Parent = parent.Hash
+		header.Time = parent.Time.Add(offset)
+	} else {
+		header.Time = time.Unix(1500000000, 0)
+	}
+	header.Hash = header.GetHash()
+	if diff == 0 || diff > 1<<16 {
+		return header
+	}
+	target := new(big.Int).Div(maxTarget, new(big.Int).SetUint64(diff))
+	for new(big.Int).SetBytes(header.Hash[:]).Cmp(target) > 0 {
+		header.Nonce++
+		header.Hash = header.GetHash()
+	}
+	return header
+}
+
+func newChain(length int, diff uint64) (*headers.Repo, []*types.Header) {
+	root := newHeader(nil, diff, 0)
+	repo := headers.NewRepo(root)
+	chain := []*types.Header{root}
+	for i := 1; i < length; i++ {
+		header := newHeader(chain[i-1], diff, time.Minute)
+		_, _ = repo.Add(header)
+		chain = append(chain, header)
+	}
+	return repo, chain
+}
+
+func newValidator(repo *headers.Repo, chain []*types.Header, options ...func(*Config)) *Header {
+	hv := NewHeader(repo, options...)
+	hv.now = func() time.Time { return chain[len(chain)-1].Time }
+	return hv
+}
+
+func TestValidateValid(t *testing.T) {
+	repo, chain := newChain(3, 1)
+	hv := newValidator(repo, chain)
+	header := newHeader(chain[2], 1, time.Minute)
+	assert.Nil(t, hv.Validate(header))
+}
+
+func TestValidateHash(t *testing.T) {
+	repo, chain := newChain(3, 1)
+	hv := newValidator(repo, chain)
+	header := newHeader(chain[2], 1, time.Minute)
+	header.Nonce += 1 << 32
+	err := hv.Validate(header)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrHash, errors.Cause(err))
+	}
+}
+
+func TestValidateWork(t *testing.T) {
+	repo, chain := newChain(3, 1)
+	hv := newValidator(repo, chain, SetInterval(0))
+
+	header := newHeader(chain[2], 0, time.Minute)
+	err := hv.Validate(header)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrWork, errors.Cause(err))
+	}
+
+	header = newHeader(chain[2], math.MaxUint64, time.Minute)
+	err = hv.Validate(header)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrWork, errors.Cause(err))
+	}
+}
+
+func TestValidateFuture(t *testing.T) {
+	repo, chain := newChain(3, 1)
+	hv := newValidator(repo, chain, SetFuture(time.Hour))
+	header := newHeader(chain[2], 1, 2*time.Hour)
+	err := hv.Validate(header)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrFuture, errors.Cause(err))
+	}
+}
+
+func TestValidateParent(t *testing.T) {
+	repo, chain := newChain(3, 1)
+	hv := newValidator(repo, chain)
+	header := newHeader(&types.Header{Hash: types.Hash{0x1}, Time: chain[2].Time}, 1, time.Minute)
+	err := hv.Validate(header)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrParent, errors.Cause(err))
+	}
+}
+
+func TestValidatePast(t *testing.T) {
+	repo, chain := newChain(5, 1)
+	hv := newValidator(repo, chain, SetMedian(5))
+
+	// the median of the last five headers is the third one
+	header := newHeader(chain[4], 1, -2*time.Minute)
+	err := hv.Validate(header)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrPast, errors.Cause(err))
+	}
+
+	header = newHeader(chain[4], 1, -time.Minute-time.Second)
+	assert.Nil(t, hv.Validate(header))
+}
+
+func TestValidateDifficulty(t *testing.T) {
+	repo, chain := newChain(4, 100)
+	hv := newValidator(repo, chain, SetInterval(4), SetSpacing(time.Minute))
+
+	// the last three headers took three minutes instead of four
+	header := newHeader(chain[3], 100, time.Minute)
+	err := hv.Validate(header)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrDifficulty, errors.Cause(err))
+	}
+
+	header = newHeader(chain[3], 133, time.Minute)
+	assert.Nil(t, hv.Validate(header))
+
+	// outside of retarget heights, the difficulty has to stay the same
+	header = newHeader(chain[2], 133, time.Minute)
+	err = hv.Validate(header)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrDifficulty, errors.Cause(err))
+	}
+}
+
+func TestValidateCheckpoint(t *testing.T) {
+	repo, chain := newChain(3, 1)
+	checkpoints := map[uint64]types.Hash{3: {0x1}}
+	hv := newValidator(repo, chain, SetCheckpoints(checkpoints))
+	header := newHeader(chain[2], 1, time.Minute)
+	err := hv.Validate(header)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ErrCheckpoint, errors.Cause(err))
+	}
+
+	checkpoints[3] = header.Hash
+	assert.Nil(t, hv.Validate(header))
+}
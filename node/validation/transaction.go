@@ -0,0 +1,66 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package validation
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// Transaction validates transactions on their own, which means that it checks
+// their hash and that every sender signed them. An account is identified by
+// its public key, so the senders can be verified without any context.
+type Transaction struct{}
+
+// NewTransaction creates a new transaction validator.
+func NewTransaction() *Transaction {
+	return &Transaction{}
+}
+
+// Validate checks the hash of the given transaction and the signatures of all
+// of its senders.
+func (tv *Transaction) Validate(tx *types.Transaction) error {
+
+	// the hash has to match the contents of the transaction
+	if tx.Hash != tx.GetHash() {
+		return errors.Wrap(ErrTransaction, "invalid transaction hash")
+	}
+
+	// someone has to pay for the transaction
+	senders := tx.Senders()
+	if len(senders) == 0 {
+		return errors.Wrap(ErrSender, "transaction without sender")
+	}
+
+	// each sender has to sign the transaction hash, in order
+	if len(tx.Signatures) != len(senders) {
+		return errors.Wrapf(ErrSignature, "signature count mismatch (%v != %v)", len(tx.Signatures), len(senders))
+	}
+	for i, sender := range senders {
+		if len(sender) != ed25519.PublicKeySize {
+			return errors.Wrapf(ErrSender, "invalid sender (%x)", sender)
+		}
+		if !ed25519.Verify(ed25519.PublicKey(sender), tx.Hash[:], tx.Signatures[i]) {
+			return errors.Wrapf(ErrSignature, "invalid signature (%x)", sender)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package validation
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+func newKey(seed byte) (ed25519.PublicKey, ed25519.PrivateKey) {
+	pub, priv, _ := ed25519.GenerateKey(bytes.NewReader(bytes.Repeat([]byte{seed}, ed25519.SeedSize)))
+	return pub, priv
+}
+
+func TestValidateTransaction(t *testing.T) {
+	pub1, priv1 := newKey(1)
+	pub2, priv2 := newKey(2)
+	tx := &types.Transaction{
+		Transfers: []*types.Transfer{{From: pub1, To: pub2, Amount: 10}},
+		Fees:      []*types.Fee{{From: pub2, Amount: 1}, {From: pub1, Amount: 1}},
+		Nonce:     1,
+	}
+	tx.Hash = tx.GetHash()
+	tx.Signatures = [][]byte{ed25519.Sign(priv1, tx.Hash[:]), ed25519.Sign(priv2, tx.Hash[:])}
+
+	tv := NewTransaction()
+	err := tv.Validate(tx)
+	assert.Nil(t, err)
+}
+
+func TestValidateTransactionHash(t *testing.T) {
+	pub, priv := newKey(1)
+	tx := &types.Transaction{Fees: []*types.Fee{{From: pub, Amount: 1}}}
+	tx.Hash = tx.GetHash()
+	tx.Signatures = [][]byte{ed25519.Sign(priv, tx.Hash[:])}
+	tx.Fees[0].Amount = 2
+
+	tv := NewTransaction()
+	err := tv.Validate(tx)
+	assert.Equal(t, ErrTransaction, errors.Cause(err))
+}
+
+func TestValidateTransactionSender(t *testing.T) {
+	tx := &types.Transaction{Data: []byte{1, 2, 3}}
+	tx.Hash = tx.GetHash()
+
+	tv := NewTransaction()
+	err := tv.Validate(tx)
+	assert.Equal(t, ErrSender, errors.Cause(err))
+
+	tx = &types.Transaction{Fees: []*types.Fee{{From: []byte{1, 2, 3}, Amount: 1}}}
+	tx.Hash = tx.GetHash()
+	tx.Signatures = [][]byte{{1, 2, 3}}
+	err = tv.Validate(tx)
+	assert.Equal(t, ErrSender, errors.Cause(err))
+}
+
+func TestValidateTransactionSignature(t *testing.T) {
+	pub1, _ := newKey(1)
+	pub2, priv2 := newKey(2)
+	_, forger := newKey(3)
+	tx := &types.Transaction{
+		Transfers: []*types.Transfer{{From: pub1, To: pub2, Amount: 10}},
+		Fees:      []*types.Fee{{From: pub2, Amount: 1}},
+	}
+	tx.Hash = tx.GetHash()
+
+	tv := NewTransaction()
+	tx.Signatures = [][]byte{ed25519.Sign(priv2, tx.Hash[:])}
+	err := tv.Validate(tx)
+	assert.Equal(t, ErrSignature, errors.Cause(err))
+
+	tx.Signatures = [][]byte{ed25519.Sign(forger, tx.Hash[:]), ed25519.Sign(priv2, tx.Hash[:])}
+	err = tv.Validate(tx)
+	assert.Equal(t, ErrSignature, errors.Cause(err))
+}
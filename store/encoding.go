@@ -0,0 +1,322 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package store
+
+import (
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// SchemaVersion is the version of the storage encoding written by this code.
+// It is written as prefix of every record, so that older records can still be
+// identified after the encoding changed. It has to be increased whenever the
+// encoding of any entity changes, together with a migration that upgrades the
+// existing records.
+const SchemaVersion uint16 = 1
+
+// List of entity types stored in the record header.
+const (
+	typeHeader uint8 = iota + 1
+	typeTransaction
+	typeInventory
+)
+
+// Encoding is the storage encoding for entities. It is independent of the
+// network codec, so that changes to the wire format don't affect the data we
+// already persisted.
+type Encoding struct{}
+
+// NewEncoding creates a new storage encoding.
+func NewEncoding() Encoding {
+	return Encoding{}
+}
+
+// Encode will write the record for the given entity, prefixed by the schema
+// version and entity type.
+func (e Encoding) Encode(w io.Writer, entity interface{}) error {
+	var typ uint8
+	switch entity.(type) {
+	case *types.Header:
+		typ = typeHeader
+	case *types.Transaction:
+		typ = typeTransaction
+	case *types.Inventory:
+		typ = typeInventory
+	default:
+		return errors.Wrapf(ErrType, "could not encode entity (%T)", entity)
+	}
+	enc := &encoder{w: w}
+	enc.uint16(SchemaVersion)
+	enc.uint8(typ)
+	switch e := entity.(type) {
+	case *types.Header:
+		enc.header(e)
+	case *types.Transaction:
+		enc.transaction(e)
+	case *types.Inventory:
+		enc.inventory(e)
+	}
+	if enc.err != nil {
+		return errors.Wrap(enc.err, "could not write record")
+	}
+	return nil
+}
+
+// Decode will read a record and return the entity it contains.
+func (e Encoding) Decode(r io.Reader) (interface{}, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read record")
+	}
+	dec := &decoder{data: data}
+	version := dec.uint16()
+	typ := dec.uint8()
+	if dec.err != nil {
+		return nil, errors.Wrap(dec.err, "could not read record header")
+	}
+	if version != SchemaVersion {
+		return nil, errors.Wrapf(ErrVersion, "could not decode record (%v)", version)
+	}
+	var entity interface{}
+	switch typ {
+	case typeHeader:
+		entity = dec.header()
+	case typeTransaction:
+		entity = dec.transaction()
+	case typeInventory:
+		entity = dec.inventory()
+	default:
+		return nil, errors.Wrapf(ErrType, "could not decode record (%v)", typ)
+	}
+	if dec.err != nil {
+		return nil, errors.Wrap(dec.err, "could not read record")
+	}
+	return entity, nil
+}
+
+// Version returns the schema version of the given record.
+func Version(record []byte) (uint16, error) {
+	if len(record) < 2 {
+		return 0, errors.New("record too short")
+	}
+	return binary.LittleEndian.Uint16(record), nil
+}
+
+type encoder struct {
+	w   io.Writer
+	err error
+}
+
+func (enc *encoder) write(data []byte) {
+	if enc.err != nil {
+		return
+	}
+	_, enc.err = enc.w.Write(data)
+}
+
+func (enc *encoder) uint8(v uint8) {
+	enc.write([]byte{v})
+}
+
+func (enc *encoder) uint16(v uint16) {
+	buf := make([]byte, 2)
+	binary.LittleEndian.PutUint16(buf, v)
+	enc.write(buf)
+}
+
+func (enc *encoder) uint32(v uint32) {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, v)
+	enc.write(buf)
+}
+
+func (enc *encoder) uint64(v uint64) {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, v)
+	enc.write(buf)
+}
+
+func (enc *encoder) bytes(v []byte) {
+	enc.uint64(uint64(len(v)))
+	enc.write(v)
+}
+
+func (enc *encoder) hash(v types.Hash) {
+	enc.write(v[:])
+}
+
+func (enc *encoder) header(hdr *types.Header) {
+	enc.hash(hdr.Parent)
+	enc.hash(hdr.State)
+	enc.hash(hdr.Delta)
+	enc.hash(hdr.Miner)
+	enc.uint64(hdr.Diff)
+	enc.uint64(hdr.Nonce)
+	enc.uint64(uint64(hdr.Time.Unix()))
+	enc.uint32(uint32(hdr.Time.Nanosecond()))
+}
+
+func (enc *encoder) transaction(tx *types.Transaction) {
+	enc.uint64(uint64(len(tx.Transfers)))
+	for _, transfer := range tx.Transfers {
+		enc.bytes(transfer.From)
+		enc.bytes(transfer.To)
+		enc.uint64(transfer.Amount)
+	}
+	enc.uint64(uint64(len(tx.Fees)))
+	for _, fee := range tx.Fees {
+		enc.bytes(fee.From)
+		enc.uint64(fee.Amount)
+	}
+	enc.bytes(tx.Data)
+	enc.uint64(tx.Nonce)
+	enc.uint64(uint64(len(tx.Signatures)))
+	for _, sig := range tx.Signatures {
+		enc.bytes(sig)
+	}
+}
+
+func (enc *encoder) inventory(inv *types.Inventory) {
+	enc.hash(inv.Hash)
+	enc.uint64(uint64(len(inv.Hashes)))
+	for _, hash := range inv.Hashes {
+		enc.hash(hash)
+	}
+}
+
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (dec *decoder) read(n uint64) []byte {
+	if dec.err != nil {
+		return make([]byte, n)
+	}
+	if uint64(len(dec.data)) < n {
+		dec.err = io.ErrUnexpectedEOF
+		return make([]byte, n)
+	}
+	data := dec.data[:n]
+	dec.data = dec.data[n:]
+	return data
+}
+
+func (dec *decoder) uint8() uint8 {
+	return dec.read(1)[0]
+}
+
+func (dec *decoder) uint16() uint16 {
+	return binary.LittleEndian.Uint16(dec.read(2))
+}
+
+func (dec *decoder) uint32() uint32 {
+	return binary.LittleEndian.Uint32(dec.read(4))
+}
+
+func (dec *decoder) uint64() uint64 {
+	return binary.LittleEndian.Uint64(dec.read(8))
+}
+
+// count reads a list length and makes sure the remaining data could hold that
+// many elements of the given minimum size.
+func (dec *decoder) count(size uint64) int {
+	n := dec.uint64()
+	if dec.err == nil && n > uint64(len(dec.data))/size {
+		dec.err = errors.Errorf("invalid list length (%v)", n)
+	}
+	if dec.err != nil {
+		return 0
+	}
+	return int(n)
+}
+
+func (dec *decoder) bytes() []byte {
+	n := dec.uint64()
+	if dec.err == nil && n > uint64(len(dec.data)) {
+		dec.err = io.ErrUnexpectedEOF
+	}
+	if dec.err != nil || n == 0 {
+		return nil
+	}
+	data := make([]byte, n)
+	copy(data, dec.read(n))
+	return data
+}
+
+func (dec *decoder) hash() types.Hash {
+	var hash types.Hash
+	copy(hash[:], dec.read(32))
+	return hash
+}
+
+func (dec *decoder) header() *types.Header {
+	hdr := &types.Header{}
+	hdr.Parent = dec.hash()
+	hdr.State = dec.hash()
+	hdr.Delta = dec.hash()
+	hdr.Miner = dec.hash()
+	hdr.Diff = dec.uint64()
+	hdr.Nonce = dec.uint64()
+	seconds := int64(dec.uint64())
+	nanos := int64(dec.uint32())
+	hdr.Time = time.Unix(seconds, nanos).UTC()
+	return hdr
+}
+
+func (dec *decoder) transaction() *types.Transaction {
+	tx := &types.Transaction{}
+	n := dec.count(24)
+	for i := 0; i < n && dec.err == nil; i++ {
+		transfer := &types.Transfer{}
+		transfer.From = dec.bytes()
+		transfer.To = dec.bytes()
+		transfer.Amount = dec.uint64()
+		tx.Transfers = append(tx.Transfers, transfer)
+	}
+	n = dec.count(16)
+	for i := 0; i < n && dec.err == nil; i++ {
+		fee := &types.Fee{}
+		fee.From = dec.bytes()
+		fee.Amount = dec.uint64()
+		tx.Fees = append(tx.Fees, fee)
+	}
+	tx.Data = dec.bytes()
+	tx.Nonce = dec.uint64()
+	n = dec.count(8)
+	for i := 0; i < n && dec.err == nil; i++ {
+		tx.Signatures = append(tx.Signatures, dec.bytes())
+	}
+	return tx
+}
+
+func (dec *decoder) inventory() *types.Inventory {
+	inv := &types.Inventory{}
+	inv.Hash = dec.hash()
+	n := dec.count(32)
+	for i := 0; i < n && dec.err == nil; i++ {
+		inv.Hashes = append(inv.Hashes, dec.hash())
+	}
+	return inv
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package store
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+func TestEncodingHeader(t *testing.T) {
+	enc := NewEncoding()
+	header := &types.Header{
+		Parent: types.Hash{1},
+		State:  types.Hash{2},
+		Delta:  types.Hash{3},
+		Miner:  types.Hash{4},
+		Diff:   5,
+		Nonce:  6,
+		Time:   time.Unix(7, 8).UTC(),
+	}
+
+	buf := &bytes.Buffer{}
+	err := enc.Encode(buf, header)
+	assert.Nil(t, err)
+
+	version, err := Version(buf.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, SchemaVersion, version)
+
+	entity, err := enc.Decode(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, header, entity)
+}
+
+func TestEncodingHeaderZeroTime(t *testing.T) {
+	enc := NewEncoding()
+	header := &types.Header{Parent: types.Hash{1}}
+
+	buf := &bytes.Buffer{}
+	err := enc.Encode(buf, header)
+	assert.Nil(t, err)
+
+	entity, err := enc.Decode(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, header, entity)
+}
+
+func TestEncodingTransaction(t *testing.T) {
+	enc := NewEncoding()
+	tx := &types.Transaction{
+		Transfers:  []*types.Transfer{{From: []byte{10}, To: []byte{11}, Amount: 1000}},
+		Fees:       []*types.Fee{{From: []byte{13}, Amount: 1300}},
+		Data:       []byte{14, 15, 16},
+		Nonce:      17,
+		Signatures: [][]byte{{18, 19, 20}, {21}},
+	}
+
+	buf := &bytes.Buffer{}
+	err := enc.Encode(buf, tx)
+	assert.Nil(t, err)
+
+	entity, err := enc.Decode(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, tx, entity)
+}
+
+func TestEncodingInventory(t *testing.T) {
+	enc := NewEncoding()
+	inv := &types.Inventory{
+		Hash:   types.Hash{1},
+		Hashes: []types.Hash{{2}, {3}, {4}},
+	}
+
+	buf := &bytes.Buffer{}
+	err := enc.Encode(buf, inv)
+	assert.Nil(t, err)
+
+	entity, err := enc.Decode(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, inv, entity)
+}
+
+func TestEncodingErrors(t *testing.T) {
+	enc := NewEncoding()
+
+	err := enc.Encode(&bytes.Buffer{}, "entity")
+	assert.Equal(t, ErrType, errors.Cause(err))
+
+	_, err = enc.Decode(bytes.NewReader([]byte{0, 0, typeHeader}))
+	assert.Equal(t, ErrVersion, errors.Cause(err))
+
+	_, err = enc.Decode(bytes.NewReader([]byte{1, 0, 255}))
+	assert.Equal(t, ErrType, errors.Cause(err))
+
+	_, err = enc.Decode(bytes.NewReader([]byte{1, 0, typeInventory, 1, 2, 3}))
+	assert.NotNil(t, err)
+
+	record := []byte{1, 0, typeTransaction, 255, 255, 255, 255, 255, 255, 255, 255}
+	_, err = enc.Decode(bytes.NewReader(record))
+	assert.NotNil(t, err)
+}
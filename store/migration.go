@@ -0,0 +1,312 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// MetaKey is the key of the database metadata record, which holds the schema
+// version of the database as a whole.
+var MetaKey = []byte("meta:version")
+
+// errStop is used to stop the iteration over the database early.
+var errStop = errors.New("stop iteration")
+
+// Database represents the key-value database as needed to run migrations.
+type Database interface {
+	KV
+	Iterate(prefix []byte, fn func(key []byte, val []byte) error) error
+}
+
+// Migration upgrades the database from the previous schema version to the
+// given one.
+type Migration struct {
+	Version uint16
+	Name    string
+	Apply   func(db Database) error
+}
+
+// Migrate brings the database up to the current schema version by applying all
+// migrations for versions after the one recorded in the database metadata, in
+// order. An empty database is simply marked with the current version. In
+// dry-run mode, all changes are applied to an in-memory overlay and only logged,
+// so the database itself is not modified.
+func Migrate(log zerolog.Logger, db Database, dryRun bool, migrations ...Migration) error {
+
+	// configure logger
+	log = log.With().Str("component", "migration").Bool("dry_run", dryRun).Logger()
+
+	// get the schema version of the database
+	version, fresh, err := schemaVersion(db)
+	if err != nil {
+		return errors.Wrap(err, "could not get database version")
+	}
+
+	// a new database doesn't need any migrations
+	if fresh {
+		log.Info().Uint16("version", SchemaVersion).Msg("initializing database version")
+		if dryRun {
+			return nil
+		}
+		err = db.Put(MetaKey, encodeVersion(SchemaVersion))
+		if err != nil {
+			return errors.Wrap(err, "could not initialize database version")
+		}
+		return nil
+	}
+	if version > SchemaVersion {
+		return errors.Wrapf(ErrVersion, "database version newer than supported (%v > %v)", version, SchemaVersion)
+	}
+	if version == SchemaVersion {
+		log.Debug().Uint16("version", version).Msg("database up to date")
+		return nil
+	}
+
+	// in dry-run mode, we apply all changes to an overlay
+	target := db
+	var ov *overlay
+	if dryRun {
+		ov = newOverlay(db)
+		target = ov
+	}
+
+	// apply all the migrations we need in order
+	sort.Slice(migrations, func(i int, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	for _, migration := range migrations {
+		if migration.Version <= version {
+			continue
+		}
+		if migration.Version != version+1 {
+			return errors.Errorf("missing migration to version %v", version+1)
+		}
+		log.Info().Uint16("version", migration.Version).Str("name", migration.Name).Msg("applying migration")
+		err = migration.Apply(target)
+		if err != nil {
+			return errors.Wrapf(err, "could not apply migration to version %v", migration.Version)
+		}
+		version = migration.Version
+		err = target.Put(MetaKey, encodeVersion(version))
+		if err != nil {
+			return errors.Wrap(err, "could not update database version")
+		}
+	}
+	if version != SchemaVersion {
+		return errors.Errorf("missing migration to version %v", version+1)
+	}
+
+	if ov != nil {
+		log.Info().Int("num_puts", len(ov.puts)).Int("num_dels", len(ov.dels)).Msg("dry-run finished")
+		return nil
+	}
+
+	log.Info().Uint16("version", version).Msg("database migrated")
+
+	return nil
+}
+
+// schemaVersion returns the schema version of the database. A database without
+// metadata is either new, in which case it is reported as fresh, or it was
+// created before we had versioning, which is version zero.
+func schemaVersion(db Database) (uint16, bool, error) {
+
+	ok, err := db.Has(MetaKey)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "could not check metadata")
+	}
+	if ok {
+		val, err := db.Get(MetaKey)
+		if err != nil {
+			return 0, false, errors.Wrap(err, "could not get metadata")
+		}
+		if len(val) != 2 {
+			return 0, false, errors.New("invalid metadata")
+		}
+		return binary.LittleEndian.Uint16(val), false, nil
+	}
+
+	// check if there are any records at all
+	empty := true
+	err = db.Iterate(nil, func(key []byte, val []byte) error {
+		empty = false
+		return errStop
+	})
+	if err != nil && errors.Cause(err) != errStop {
+		return 0, false, errors.Wrap(err, "could not check for records")
+	}
+
+	return 0, empty, nil
+}
+
+func encodeVersion(version uint16) []byte {
+	val := make([]byte, 2)
+	binary.LittleEndian.PutUint16(val, version)
+	return val
+}
+
+// Reencode creates the migration to the first versioned schema, which converts
+// all records with the given prefixes from the network codec they were stored
+// with to the storage encoding. Only keys made of the prefix and a hash are
+// converted, as other records, such as the block indices keyed by the bare
+// block hash, may start with the same byte. Records are rewritten one at a
+// time, so records that were already converted before an interrupted migration
+// are skipped when it runs again.
+func Reencode(legacy Codec, prefixes ...string) Migration {
+	return Migration{
+		Version: 1,
+		Name:    "storage encoding",
+		Apply: func(db Database) error {
+			enc := NewEncoding()
+			for _, prefix := range prefixes {
+				records := make(map[string][]byte)
+				err := db.Iterate([]byte(prefix), func(key []byte, val []byte) error {
+					if len(key) != len(prefix)+len(types.Hash{}) {
+						return nil
+					}
+					records[string(key)] = val
+					return nil
+				})
+				if err != nil {
+					return errors.Wrapf(err, "could not collect records (%v)", prefix)
+				}
+				for key, val := range records {
+					if converted(enc, val) {
+						continue
+					}
+					entity, err := legacy.Decode(bytes.NewReader(val))
+					if err != nil {
+						return errors.Wrapf(err, "could not decode legacy record (%x)", key)
+					}
+					buf := &bytes.Buffer{}
+					err = enc.Encode(buf, entity)
+					if err != nil {
+						return errors.Wrapf(err, "could not encode record (%x)", key)
+					}
+					err = db.Put([]byte(key), buf.Bytes())
+					if err != nil {
+						return errors.Wrapf(err, "could not put record (%x)", key)
+					}
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// converted checks whether a record is already stored with the current
+// storage encoding.
+func converted(enc Encoding, val []byte) bool {
+	version, err := Version(val)
+	if err != nil || version != SchemaVersion {
+		return false
+	}
+	_, err = enc.Decode(bytes.NewReader(val))
+	return err == nil
+}
+
+// overlay wraps a database and keeps all changes in memory, so that migrations
+// can be run without modifying the database.
+type overlay struct {
+	Database
+	puts map[string][]byte
+	dels map[string]struct{}
+}
+
+func newOverlay(db Database) *overlay {
+	return &overlay{
+		Database: db,
+		puts:     make(map[string][]byte),
+		dels:     make(map[string]struct{}),
+	}
+}
+
+func (ov *overlay) Put(key []byte, val []byte) error {
+	delete(ov.dels, string(key))
+	ov.puts[string(key)] = val
+	return nil
+}
+
+func (ov *overlay) Has(key []byte) (bool, error) {
+	_, ok := ov.dels[string(key)]
+	if ok {
+		return false, nil
+	}
+	_, ok = ov.puts[string(key)]
+	if ok {
+		return true, nil
+	}
+	return ov.Database.Has(key)
+}
+
+func (ov *overlay) Get(key []byte) ([]byte, error) {
+	_, ok := ov.dels[string(key)]
+	if ok {
+		return nil, errors.New("key deleted")
+	}
+	val, ok := ov.puts[string(key)]
+	if ok {
+		return val, nil
+	}
+	return ov.Database.Get(key)
+}
+
+func (ov *overlay) Del(key []byte) error {
+	delete(ov.puts, string(key))
+	ov.dels[string(key)] = struct{}{}
+	return nil
+}
+
+func (ov *overlay) Iterate(prefix []byte, fn func(key []byte, val []byte) error) error {
+	records := make(map[string][]byte)
+	err := ov.Database.Iterate(prefix, func(key []byte, val []byte) error {
+		records[string(key)] = val
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for key, val := range ov.puts {
+		if bytes.HasPrefix([]byte(key), prefix) {
+			records[key] = val
+		}
+	}
+	for key := range ov.dels {
+		delete(records, key)
+	}
+	keys := make([]string, 0, len(records))
+	for key := range records {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		err = fn([]byte(key), records[key])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
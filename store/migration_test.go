@@ -0,0 +1,175 @@
+// Copyright (c) 2017 The Alvalor Authors
+//
+// This file is part of Alvalor.
+//
+// Alvalor is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alvalor is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with Alvalor.  If not, see <http://www.gnu.org/licenses/>.
+
+package store
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alvalor/alvalor-go/kv"
+	"github.com/alvalor/alvalor-go/types"
+)
+
+// legacyCodec is a stand-in for the network codec that used to be used to
+// store entities; it stores transactions as their data only.
+type legacyCodec struct{}
+
+func (legacyCodec) Encode(w io.Writer, i interface{}) error {
+	_, err := w.Write(i.(*types.Transaction).Data)
+	return err
+}
+
+func (legacyCodec) Decode(r io.Reader) (interface{}, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Transaction{Data: data}, nil
+}
+
+func TestMigrateFresh(t *testing.T) {
+	log := zerolog.New(ioutil.Discard)
+	db := kv.NewMemory()
+
+	err := Migrate(log, db, true, Reencode(legacyCodec{}, "t"))
+	assert.Nil(t, err)
+	ok, _ := db.Has(MetaKey)
+	assert.False(t, ok)
+
+	err = Migrate(log, db, false, Reencode(legacyCodec{}, "t"))
+	assert.Nil(t, err)
+	val, err := db.Get(MetaKey)
+	assert.Nil(t, err)
+	assert.Equal(t, encodeVersion(SchemaVersion), val)
+}
+
+func TestMigrateReencode(t *testing.T) {
+	log := zerolog.New(ioutil.Discard)
+	db := kv.NewMemory()
+	hash := types.Hash{1}
+	key := append([]byte("t"), hash[:]...)
+	_ = db.Put(key, []byte{1, 2, 3})
+	_ = db.Put([]byte("other"), []byte{4, 5, 6})
+	index := types.Hash{'t', 2}
+	_ = db.Put(index[:], []byte{7, 8, 9})
+
+	err := Migrate(log, db, true, Reencode(legacyCodec{}, "t"))
+	assert.Nil(t, err)
+	val, _ := db.Get(key)
+	assert.Equal(t, []byte{1, 2, 3}, val)
+	ok, _ := db.Has(MetaKey)
+	assert.False(t, ok)
+
+	err = Migrate(log, db, false, Reencode(legacyCodec{}, "t"))
+	assert.Nil(t, err)
+	val, _ = db.Get(MetaKey)
+	assert.Equal(t, encodeVersion(SchemaVersion), val)
+	val, _ = db.Get([]byte("other"))
+	assert.Equal(t, []byte{4, 5, 6}, val)
+	val, _ = db.Get(index[:])
+	assert.Equal(t, []byte{7, 8, 9}, val)
+
+	s := New(db, NewEncoding(), "t")
+	entity, err := s.Retrieve(hash)
+	assert.Nil(t, err)
+	assert.Equal(t, &types.Transaction{Data: []byte{1, 2, 3}}, entity)
+}
+
+func TestMigrateInterrupted(t *testing.T) {
+	log := zerolog.New(ioutil.Discard)
+	db := kv.NewMemory()
+	hash1 := types.Hash{1}
+	hash2 := types.Hash{2}
+	key1 := append([]byte("t"), hash1[:]...)
+	key2 := append([]byte("t"), hash2[:]...)
+	_ = db.Put(key1, []byte{1, 2, 3})
+
+	// a record that was converted before the migration was interrupted
+	buf := &bytes.Buffer{}
+	err := NewEncoding().Encode(buf, &types.Transaction{Data: []byte{4, 5, 6}})
+	assert.Nil(t, err)
+	_ = db.Put(key2, buf.Bytes())
+
+	err = Migrate(log, db, false, Reencode(legacyCodec{}, "t"))
+	assert.Nil(t, err)
+
+	s := New(db, NewEncoding(), "t")
+	entity, err := s.Retrieve(hash1)
+	assert.Nil(t, err)
+	assert.Equal(t, &types.Transaction{Data: []byte{1, 2, 3}}, entity)
+	entity, err = s.Retrieve(hash2)
+	assert.Nil(t, err)
+	assert.Equal(t, &types.Transaction{Data: []byte{4, 5, 6}}, entity)
+}
+
+func TestOverlayIterate(t *testing.T) {
+	db := kv.NewMemory()
+	_ = db.Put([]byte("a1"), []byte{1})
+	_ = db.Put([]byte("a2"), []byte{2})
+	_ = db.Put([]byte("b1"), []byte{3})
+
+	ov := newOverlay(db)
+	_ = ov.Put([]byte("a2"), []byte{4})
+	_ = ov.Put([]byte("a3"), []byte{5})
+	_ = ov.Put([]byte("b2"), []byte{6})
+	_ = ov.Del([]byte("a1"))
+
+	var keys []string
+	var vals [][]byte
+	err := ov.Iterate([]byte("a"), func(key []byte, val []byte) error {
+		keys = append(keys, string(key))
+		vals = append(vals, val)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a2", "a3"}, keys)
+	assert.Equal(t, [][]byte{{4}, {5}}, vals)
+
+	val, _ := db.Get([]byte("a2"))
+	assert.Equal(t, []byte{2}, val)
+}
+
+func TestMigrateVersions(t *testing.T) {
+	log := zerolog.New(ioutil.Discard)
+	db := kv.NewMemory()
+	_ = db.Put([]byte("other"), []byte{4, 5, 6})
+
+	err := Migrate(log, db, false)
+	assert.NotNil(t, err)
+
+	_ = db.Put(MetaKey, encodeVersion(SchemaVersion+1))
+	err = Migrate(log, db, false, Reencode(legacyCodec{}, "t"))
+	assert.Equal(t, ErrVersion, errors.Cause(err))
+
+	_ = db.Put(MetaKey, encodeVersion(SchemaVersion))
+	err = Migrate(log, db, false)
+	assert.Nil(t, err)
+
+	_ = db.Put([]byte("t1"), []byte{1, 2, 3})
+	_ = db.Put(MetaKey, encodeVersion(SchemaVersion))
+	err = Migrate(log, db, false, Reencode(legacyCodec{}, "t"))
+	assert.Nil(t, err)
+	val, _ := db.Get([]byte("t1"))
+	assert.True(t, bytes.Equal([]byte{1, 2, 3}, val))
+}
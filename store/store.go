@@ -19,12 +19,20 @@ package store
 
 import (
 	"bytes"
+	"sync"
 
 	"github.com/pkg/errors"
 
 	"github.com/alvalor/alvalor-go/types"
 )
 
+// buffers is a pool of buffers used to encode entities before saving them.
+var buffers = sync.Pool{
+	New: func() interface{} {
+		return &bytes.Buffer{}
+	},
+}
+
 // Store represents a store to store entities by unique ID.
 type Store struct {
 	kv     KV
@@ -42,9 +50,12 @@ func New(kv KV, codec Codec, prefix string) *Store {
 	}
 }
 
-// Save will put a new entity into the store.
+// Save will put a new entity into the store. The key-value store has to copy
+// the data if it keeps it beyond the call, as the encoding buffer is reused.
 func (s *Store) Save(hash types.Hash, entity interface{}) error {
-	buf := &bytes.Buffer{}
+	buf := buffers.Get().(*bytes.Buffer)
+	defer buffers.Put(buf)
+	buf.Reset()
 	err := s.codec.Encode(buf, entity)
 	if err != nil {
 		return errors.Wrap(err, "could not encode entity")
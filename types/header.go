@@ -49,6 +49,6 @@ func (hdr Header) GetHash() Hash {
 	binary.LittleEndian.PutUint64(data[8:16], uint64(hdr.Time.Unix()))
 	binary.LittleEndian.PutUint64(data[16:], hdr.Nonce)
 	_, _ = h.Write(data)
-	copy(hdr.Hash[:], h.Sum(nil))
+	copy(hash[:], h.Sum(nil))
 	return hash
 }
@@ -56,3 +56,26 @@ func (tx *Transaction) GetHash() Hash {
 	copy(hash[:], tmp)
 	return hash
 }
+
+// Senders returns the distinct accounts that transfer or pay fees from their
+// balance, in order of their first appearance in the transfers, then the fees.
+// Each of them has to sign the transaction hash, in the same order.
+func (tx *Transaction) Senders() [][]byte {
+	var senders [][]byte
+	seen := make(map[string]struct{})
+	add := func(from []byte) {
+		_, ok := seen[string(from)]
+		if ok {
+			return
+		}
+		seen[string(from)] = struct{}{}
+		senders = append(senders, from)
+	}
+	for _, transfer := range tx.Transfers {
+		add(transfer.From)
+	}
+	for _, fee := range tx.Fees {
+		add(fee.From)
+	}
+	return senders
+}